@@ -0,0 +1,478 @@
+package callgraph
+
+import (
+	"strconv"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// EdgeKind는 호출 간선이 어떻게 찾아졌는지를 나타냄
+type EdgeKind uint8
+
+const (
+	// f(), pkg.F(), func() {...}() 처럼 호출 대상이 바로 보이는 호출
+	DirectCall EdgeKind = iota
+	// 함수 값을 담은 변수나 매개변수를 통한 호출. 그 변수에 흘러들 수 있는 모든 함수로 향함
+	VarCall
+	// x.m() 호출. 수신자의 타입을 모르므로 이름이 m인 모든 메서드로 향함
+	MethodCall
+	// seq.Map(xs, f) 처럼 빌트인에 넘겨져 빌트인이 부르는 함수
+	CallbackCall
+)
+
+func (k EdgeKind) String() string {
+	switch k {
+	case DirectCall:
+		return "direct"
+	case VarCall:
+		return "var"
+	case MethodCall:
+		return "method"
+	case CallbackCall:
+		return "callback"
+	default:
+		return "unknown"
+	}
+}
+
+// Graph는 프로그램의 정적 호출 그래프임
+type Graph struct {
+	// 소스 순서의 함수들. 함수 선언, 메서드, 지역 함수, 함수 리터럴이 각각 하나의 Func임
+	Funcs []*Func
+	// 강한 연결 요소들. 부르는 쪽보다 불리는 쪽이 먼저 오는 순서임
+	SCCs   [][]*Func
+	byName map[string]*Func
+}
+
+// Func는 호출 그래프의 노드임
+type Func struct {
+	// f, 메서드라면 T.m, 함수 리터럴이라면 f.func1, 지역 함수라면 f.g (cfg와 같은 이름)
+	// BuildProgram에서 main이 아닌 패키지의 함수는 geo.Area 처럼 패키지 이름이 앞에 붙음
+	Name string
+	// 위치로 쓰는 선언의 id. 함수 리터럴이라면 그것을 담은 가장 가까운 선언의 id
+	DeclId parser.Id
+	// 함수 선언이라면 그 선언, 함수 리터럴이라면 nil
+	DeclOrNil *parser.FuncDecl
+	// 함수 리터럴이라면 그 리터럴, 함수 선언이라면 nil
+	FexpOrNil *parser.Fexp
+	Out       []*Edge
+	In        []*Edge
+	// 본문이 직접 일으키는 효과
+	OwnEffects Effect
+	// 부르는 함수들의 효과까지 합친 효과
+	Effects Effect
+	// 속한 강한 연결 요소의 Graph.SCCs에서의 번호
+	SCC int
+	// 자기 자신을 (다른 함수를 거쳐서라도) 부를 수 있는지 여부
+	Recursive bool
+
+	params []parser.Param
+	// 본문에서 선언된 이름들의 IdId. 중첩된 함수의 선언은 포함하지 않음
+	owned map[parser.IdId]bool
+	// 다음 함수 리터럴의 순번
+	fexpCount int
+	// 전역 변수 초기식의 함수 리터럴에 이름을 붙이기 위한 자리이며, 그래프에 들어가지 않음
+	initOnly bool
+}
+
+// Edge는 호출 지점 하나에서 불릴 수 있는 함수 하나로의 간선임
+type Edge struct {
+	Caller *Func
+	Callee *Func
+	// 호출 지점의 식별자. 호출 대상에 이름이 없다면 Caller의 DeclId
+	Site parser.Id
+	Kind EdgeKind
+}
+
+// Func는 이름이 name인 함수를 리턴함
+func (g *Graph) Func(name string) (*Func, bool) {
+	f, ok := g.byName[name]
+	return f, ok
+}
+
+// RecursiveSCCs는 재귀를 이루는 강한 연결 요소들을 리턴함. 자기 자신만 부르는 함수도 포함됨
+func (g *Graph) RecursiveSCCs() [][]*Func {
+	out := [][]*Func{}
+	for _, scc := range g.SCCs {
+		if scc[0].Recursive {
+			out = append(out, scc)
+		}
+	}
+	return out
+}
+
+// Callees는 f가 부를 수 있는 함수들을 처음 나타난 순서대로 중복 없이 리턴함
+func (f *Func) Callees() []*Func {
+	out := []*Func{}
+	seen := map[*Func]bool{}
+	for _, e := range f.Out {
+		if !seen[e.Callee] {
+			seen[e.Callee] = true
+			out = append(out, e.Callee)
+		}
+	}
+	return out
+}
+
+// Callers는 f를 부를 수 있는 함수들을 중복 없이 리턴함
+func (f *Func) Callers() []*Func {
+	out := []*Func{}
+	seen := map[*Func]bool{}
+	for _, e := range f.In {
+		if !seen[e.Caller] {
+			seen[e.Caller] = true
+			out = append(out, e.Caller)
+		}
+	}
+	return out
+}
+
+// IsPure는 f와 f가 부를 수 있는 함수들이 아무 효과도 일으키지 않는지 리턴함
+func (f *Func) IsPure() bool {
+	return f.Effects == 0
+}
+
+// callbackBuiltins는 인자로 받은 함수를 부르는 빌트인들임
+var callbackBuiltins = map[string]bool{
+	"seq.Map": true, "seq.Filter": true, "seq.Reduce": true, "seq.FlatMap": true, "seq.SortBy": true,
+	"seq.GroupBy": true, "seq.Any": true, "seq.All": true, "compose": true,
+}
+
+// effectBuiltins는 부르는 것만으로 효과를 일으키는 빌트인들임
+var effectBuiltins = map[string]Effect{
+	"print":  Print,
+	"scan":   Scan,
+	"args":   Env,
+	"getenv": Env,
+	"panic":  Panic,
+	"exit":   Exit,
+}
+
+// callSite는 호출 지점 하나임
+type callSite struct {
+	caller *Func
+	// 호출되는 식
+	callee parser.Expr
+	args   []parser.Expr
+	site   parser.Id
+}
+
+// flow는 target 변수에 src 식의 값이 들어가는 것을 뜻함
+type flow struct {
+	target parser.IdId
+	src    parser.Expr
+}
+
+type builder struct {
+	g     *Graph
+	table resolver.ResolveTable
+	// 함수 선언의 IdId -> 노드
+	funcByDecl map[parser.IdId]*Func
+	funcByFexp map[*parser.Fexp]*Func
+	// 메서드 이름 -> 그 이름의 메서드들
+	methods map[string][]*Func
+	// 전역 변수의 IdId
+	globals map[parser.IdId]bool
+	// 함수 값을 담을 수 있는 변수, 매개변수의 IdId
+	vars  map[parser.IdId]bool
+	sites []callSite
+	flows []flow
+	// 변수 IdId -> 그 변수에 흘러들 수 있는 함수들
+	varFuncs map[parser.IdId]map[*Func]bool
+}
+
+// Build는 단일 패키지의 호출 그래프를 만들고, 재귀와 효과를 분석함
+func Build(pkg *parser.PackageAST, table resolver.ResolveTable) *Graph {
+	return BuildProgram([]resolver.PackageSource{{Path: "main", AST: pkg}}, table)
+}
+
+// BuildProgram은 ResolveProgram으로 함께 리졸빙한 패키지들의 호출 그래프를 만듦
+func BuildProgram(pkgs []resolver.PackageSource, table resolver.ResolveTable) *Graph {
+	b := &builder{
+		g:          &Graph{byName: map[string]*Func{}},
+		table:      table,
+		funcByDecl: map[parser.IdId]*Func{},
+		funcByFexp: map[*parser.Fexp]*Func{},
+		methods:    map[string][]*Func{},
+		globals:    map[parser.IdId]bool{},
+		vars:       map[parser.IdId]bool{},
+		varFuncs:   map[parser.IdId]map[*Func]bool{},
+	}
+	// 함수 선언은 앞에서 부를 수 있으므로, 노드를 먼저 모두 만듦
+	for _, pkg := range pkgs {
+		prefix := ""
+		if pkg.AST.NameOrNil != nil && pkg.AST.NameOrNil.Name != "main" {
+			prefix = pkg.AST.NameOrNil.Name + "."
+		}
+		for _, decl := range pkg.AST.DeclsOrNil {
+			switch d := decl.(type) {
+			case *parser.FuncDecl:
+				b.newDeclFunc(prefix+funcName(d), d)
+			case *parser.VarDecl:
+				for _, id := range d.Ids {
+					b.globals[id.IdId] = true
+					b.vars[id.IdId] = true
+				}
+			}
+		}
+	}
+	for _, pkg := range pkgs {
+		for _, decl := range pkg.AST.DeclsOrNil {
+			switch d := decl.(type) {
+			case *parser.FuncDecl:
+				b.walkFunc(b.funcByDecl[d.Id.IdId])
+			case *parser.VarDecl:
+				// 초기식은 어느 함수에도 속하지 않으므로, 그 안의 호출은 간선이 되지 않음
+				// 초기식의 함수 리터럴은 v.func1 처럼 이름 붙임
+				init := &Func{Name: d.Ids[0].Name, DeclId: d.Ids[0], owned: map[parser.IdId]bool{}, initOnly: true}
+				if pkg.AST.NameOrNil != nil && pkg.AST.NameOrNil.Name != "main" {
+					init.Name = pkg.AST.NameOrNil.Name + "." + init.Name
+				}
+				b.walkExprs(init, d.ExprsOrNil)
+				b.addFlows(d.Ids, d.ExprsOrNil)
+			case *parser.ConstDecl:
+				for _, spec := range d.Specs {
+					b.walkExprs(nil, spec.ExprsOrNil)
+				}
+			}
+		}
+	}
+	b.solveFlows()
+	b.connect()
+	b.g.analyze()
+	return b.g
+}
+
+// funcName은 함수 선언의 이름이며, 메서드라면 리시버 타입 이름을 앞에 붙임
+func funcName(decl *parser.FuncDecl) string {
+	if decl.ReceiverOrNil == nil {
+		return decl.Id.Name
+	}
+	t := decl.ReceiverOrNil.Type
+	if t.NameOrNil != nil {
+		return t.NameOrNil.Name + "." + decl.Id.Name
+	}
+	return t.String() + "." + decl.Id.Name
+}
+
+func (b *builder) addFunc(f *Func) *Func {
+	b.g.Funcs = append(b.g.Funcs, f)
+	b.g.byName[f.Name] = f
+	return f
+}
+
+func (b *builder) newDeclFunc(name string, decl *parser.FuncDecl) *Func {
+	params := decl.ParamsOrNil
+	if decl.ReceiverOrNil != nil {
+		params = append([]parser.Param{*decl.ReceiverOrNil}, params...)
+	}
+	f := &Func{Name: name, DeclId: decl.Id, DeclOrNil: decl, params: params, owned: map[parser.IdId]bool{}}
+	b.funcByDecl[decl.Id.IdId] = f
+	if decl.ReceiverOrNil != nil {
+		b.methods[decl.Id.Name] = append(b.methods[decl.Id.Name], f)
+	}
+	return f
+}
+
+// walkFunc는 f를 그래프에 넣고, 매개변수를 선언하고 본문을 방문함
+// 방문하는 순서대로 넣으므로 Graph.Funcs는 소스 순서가 됨
+func (b *builder) walkFunc(f *Func) {
+	b.addFunc(f)
+	for _, param := range f.params {
+		b.declare(f, param.Id)
+	}
+	if f.DeclOrNil != nil {
+		b.walk(f, &f.DeclOrNil.Block)
+		return
+	}
+	b.walk(f, &f.FexpOrNil.Block)
+}
+
+// declare는 id를 f가 선언한 변수로 기록함
+func (b *builder) declare(f *Func, id parser.Id) {
+	if id.Name == "_" {
+		return
+	}
+	f.owned[id.IdId] = true
+	b.vars[id.IdId] = true
+}
+
+// addFlows는 ids에 같은 개수의 exprs가 대입되는 흐름을 기록함
+func (b *builder) addFlows(ids []parser.Id, exprs []parser.Expr) {
+	if len(ids) != len(exprs) {
+		return
+	}
+	for i, id := range ids {
+		if ref, ok := b.table[id.IdId]; ok {
+			b.flows = append(b.flows, flow{target: ref.RefIdNodeId, src: exprs[i]})
+		}
+	}
+}
+
+func (b *builder) walkExprs(f *Func, exprs []parser.Expr) {
+	for _, expr := range exprs {
+		b.walk(f, expr)
+	}
+}
+
+// walk는 node 안의 호출 지점, 전역 읽기와 쓰기, 선언과 함수 리터럴을 모음
+// f가 nil이면 상수 초기식처럼 어느 함수에도 속하지 않는 식임
+func (b *builder) walk(f *Func, node parser.Node) {
+	parser.Inspect(node, func(n parser.Node) bool {
+		return b.visit(f, n)
+	})
+}
+
+func (b *builder) visit(f *Func, node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.FuncDecl:
+		// 지역 함수는 f.g 라는 새 노드가 되며, 본문은 그 노드에서 방문함
+		b.declare(f, n.Id)
+		b.walkFunc(b.newDeclFunc(f.Name+"."+n.Id.Name, n))
+		return false
+	case *parser.Fexp:
+		b.walkFexp(f, n)
+		return false
+	case *parser.VarDecl:
+		for _, id := range n.Ids {
+			b.declare(f, id)
+		}
+		b.addFlows(n.Ids, n.ExprsOrNil)
+	case *parser.ShortDecl:
+		for _, id := range n.Ids {
+			if ref, ok := b.table[id.IdId]; ok && ref.RefIdNodeId == id.IdId {
+				b.declare(f, id)
+			}
+		}
+		b.addFlows(n.Ids, n.Exprs)
+	case *parser.Assign:
+		b.walkAssignTargets(f, n)
+	case *parser.ForRange:
+		for _, v := range n.Vars {
+			b.declare(f, v)
+		}
+	case *parser.TypeSwitch:
+		if n.BindingOrNil != nil {
+			b.declare(f, *n.BindingOrNil)
+		}
+	case *parser.Binary:
+		// x |> f 에서 오른쪽이 호출식이 아니라면 오른쪽 값이 x로 불림
+		// 양쪽 안의 호출을 먼저 기록해 간선이 소스 순서가 되게 함
+		if _, isCall := n.RightExpr.(*parser.Call); n.Op == parser.Pipe && !isCall {
+			b.walk(f, n.LeftExpr)
+			b.walk(f, n.RightExpr)
+			if b.records(f) {
+				b.sites = append(b.sites, callSite{caller: f, callee: n.RightExpr, args: []parser.Expr{n.LeftExpr}, site: b.siteOf(f, n.RightExpr)})
+			}
+			return false
+		}
+	case *parser.Call:
+		// 인자 안의 호출보다 먼저 기록해 간선이 소스 순서가 되게 함
+		if b.records(f) {
+			args := []parser.Expr{}
+			if len(n.ArgsList) > 0 {
+				args = n.ArgsList[0]
+			}
+			b.sites = append(b.sites, callSite{caller: f, callee: &n.PrimaryOrNil, args: args, site: b.siteOf(f, &n.PrimaryOrNil)})
+			// f(a)(b) 의 두 번째 호출은 f가 리턴한 함수를 부르므로 대상을 알 수 없음
+			if len(n.ArgsList) > 1 {
+				f.OwnEffects |= UnknownCall
+			}
+		}
+	case *parser.Selector:
+		b.readRef(f, n.Field)
+	case *parser.Primary:
+		if n.PrimaryKind == parser.IdPrimary {
+			b.readRef(f, *n.IdOrNil)
+		}
+	case *parser.Match:
+		if f != nil {
+			for i := range n.Arms {
+				b.declarePattern(f, &n.Arms[i].Pattern)
+			}
+		}
+	}
+	return true
+}
+
+// walkAssignTargets는 대입되는 변수가 전역이거나, 바깥 함수의 변수인지 기록함
+func (b *builder) walkAssignTargets(f *Func, n *parser.Assign) {
+	for i, id := range n.Ids {
+		ref, ok := b.table[id.IdId]
+		if !ok {
+			continue
+		}
+		switch {
+		case b.globals[ref.RefIdNodeId]:
+			f.OwnEffects |= WriteGlobal
+		case ref.Kind == resolver.RefLocal && !f.owned[ref.RefIdNodeId]:
+			f.OwnEffects |= WriteCaptured
+		}
+		if len(n.FieldPathAt(i)) == 0 && i < len(n.Exprs) && len(n.Ids) == len(n.Exprs) {
+			b.flows = append(b.flows, flow{target: ref.RefIdNodeId, src: n.Exprs[i]})
+		}
+	}
+}
+
+// declarePattern은 패턴이 묶는 이름을 f가 선언한 변수로 기록함
+func (b *builder) declarePattern(f *Func, pat *parser.Pattern) {
+	switch pat.PatternKind {
+	case parser.BindPattern:
+		b.declare(f, *pat.IdOrNil)
+	case parser.VariantPattern:
+		for i := range pat.Args {
+			b.declarePattern(f, &pat.Args[i])
+		}
+	}
+}
+
+// records는 f 안의 호출과 효과를 기록해야 하는지 리턴함
+func (b *builder) records(f *Func) bool {
+	return f != nil && !f.initOnly
+}
+
+// readRef는 id가 전역 변수를 읽는다면 f에 기록함
+func (b *builder) readRef(f *Func, id parser.Id) {
+	if !b.records(f) {
+		return
+	}
+	if ref, ok := b.table[id.IdId]; ok && b.globals[ref.RefIdNodeId] {
+		f.OwnEffects |= ReadGlobal
+	}
+}
+
+// walkFexp는 함수 리터럴을 outer 안의 새 노드로 만들어 방문함
+func (b *builder) walkFexp(outer *Func, fexp *parser.Fexp) {
+	if outer == nil {
+		return
+	}
+	outer.fexpCount++
+	f := &Func{
+		Name:      outer.Name + ".func" + strconv.Itoa(outer.fexpCount),
+		DeclId:    outer.DeclId,
+		FexpOrNil: fexp,
+		params:    fexp.ParamsOrNil,
+		owned:     map[parser.IdId]bool{},
+	}
+	b.funcByFexp[fexp] = f
+	b.walkFunc(f)
+}
+
+// siteOf는 호출되는 식의 이름을 호출 지점으로 씀. 이름이 없다면 caller의 선언 id임
+func (b *builder) siteOf(caller *Func, callee parser.Expr) parser.Id {
+	switch n := callee.(type) {
+	case *parser.Primary:
+		switch n.PrimaryKind {
+		case parser.IdPrimary:
+			return *n.IdOrNil
+		case parser.ExprPrimary:
+			return b.siteOf(caller, n.ExprOrNil)
+		}
+	case *parser.Selector:
+		return n.Field
+	case *parser.Index:
+		return b.siteOf(caller, n.Object)
+	}
+	return caller.DeclId
+}
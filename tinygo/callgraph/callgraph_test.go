@@ -0,0 +1,282 @@
+package callgraph
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/loader"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+func buildForTest(t *testing.T, input string) (*Graph, *parser.PackageAST) {
+	t.Helper()
+	lx := lexer.NewLexer()
+	lx.Set(input)
+	pkg, err := parser.NewParser(lx).ParsePackage()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	table, _, _, _, err := resolver.Resolve(pkg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	return Build(pkg, table), pkg
+}
+
+// summarize는 함수마다 "이름 [효과] rec -> 불리는함수(종류) ..." 한 줄로 그래프를 요약함
+func summarize(g *Graph) []string {
+	lines := []string{}
+	for _, f := range g.Funcs {
+		line := f.Name + " [" + f.Effects.String() + "]"
+		if f.Recursive {
+			line += " rec"
+		}
+		if len(f.Out) > 0 {
+			calls := []string{}
+			for _, e := range f.Out {
+				calls = append(calls, e.Callee.Name+"("+e.Kind.String()+")")
+			}
+			line += " -> " + strings.Join(calls, " ")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestBuild(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "direct_calls_and_effects",
+			input: "var count int = 0;\n" +
+				"func add(a int, b int) int { return a + b; }\n" +
+				"func bump() { count = count + 1; }\n" +
+				"func show(n int) { print(n); }\n" +
+				"func main() { show(add(1, 2)); bump(); }",
+			want: []string{
+				"add [pure]",
+				"bump [read-global,write-global]",
+				"show [print]",
+				"main [print,read-global,write-global] -> show(direct) add(direct) bump(direct)",
+			},
+		},
+		{
+			name: "recursion",
+			input: "func fact(n int) int { if n == 0 { return 1; } return n * fact(n - 1); }\n" +
+				"func even(n int) bool { if n == 0 { return true; } return odd(n - 1); }\n" +
+				"func odd(n int) bool { if n == 0 { panic(\"neg\"); } return even(n - 1); }\n" +
+				"func main() { print(fact(3)); print(even(4)); }",
+			want: []string{
+				"fact [pure] rec -> fact(direct)",
+				"even [panic] rec -> odd(direct)",
+				"odd [panic] rec -> even(direct)",
+				"main [print,panic] -> fact(direct) even(direct)",
+			},
+		},
+		{
+			// 변수와 매개변수에 흘러든 함수 값, 빌트인에 넘긴 함수, 바로 부르는 함수 리터럴
+			name: "closures_through_variables",
+			input: "func double(n int) int { return n * 2; }\n" +
+				"func loud(n int) int { print(n); return n; }\n" +
+				"func apply(f func(int) int, n int) int { return f(n); }\n" +
+				"func main() {\n" +
+				"\tg := double;\n" +
+				"\tg = loud;\n" +
+				"\tprint(apply(g, 1));\n" +
+				"\th := func(x int) int { return x + 1; };\n" +
+				"\tys := seq.Map([]int{1}, h);\n" +
+				"\tz := func() int { print(ys); return 0; }();\n" +
+				"\tprint(z);\n" +
+				"}",
+			want: []string{
+				"double [pure]",
+				"loud [print]",
+				"apply [print] -> double(var) loud(var)",
+				"main [print] -> apply(direct) main.func1(callback) main.func2(direct)",
+				"main.func1 [pure]",
+				"main.func2 [print]",
+			},
+		},
+		{
+			name: "captured_writes_and_local_funcs",
+			input: "func counter() func() int {\n" +
+				"\tn := 0;\n" +
+				"\treturn func() int { n = n + 1; return n; };\n" +
+				"}\n" +
+				"func outer() int {\n" +
+				"\tfunc inner(x int) int { return x; }\n" +
+				"\treturn inner(1);\n" +
+				"}\n" +
+				"func main() { c := counter(); print(c()); print(outer()); }",
+			want: []string{
+				"counter [pure]",
+				"counter.func1 [write-captured]",
+				"outer [pure] -> outer.inner(direct)",
+				"outer.inner [pure]",
+				"main [print,unknown-call] -> counter(direct) outer(direct)",
+			},
+		},
+		{
+			name: "process_builtins",
+			input: "func home() string { return getenv(\"HOME\"); }\n" +
+				"func quit(code int) { exit(code); }\n" +
+				"func main() { if len(args()) < 2 { quit(2); } print(home()); }",
+			want: []string{
+				"home [env]",
+				"quit [exit]",
+				"main [print,env,exit] -> quit(direct) home(direct)",
+			},
+		},
+		{
+			// 수신자의 타입을 모르므로 같은 이름의 메서드 모두로 향함
+			name: "method_calls",
+			input: "type A struct { n int; }\n" +
+				"type B struct { n int; }\n" +
+				"func (a A) Get() int { return a.n; }\n" +
+				"func (b B) Get() int { print(b.n); return b.n; }\n" +
+				"func main() { a := A{n: 1}; print(a.Get()); }",
+			want: []string{
+				"A.Get [pure]",
+				"B.Get [print]",
+				"main [print] -> A.Get(method) B.Get(method)",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, _ := buildForTest(t, tc.input)
+			got := summarize(g)
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Fatalf("graph mismatch:\n--- got ---\n%s\n--- want ---\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestGraph_Queries(t *testing.T) {
+	g, _ := buildForTest(t, "func ping(n int) { if n > 0 { pong(n - 1); } }\n"+
+		"func pong(n int) { ping(n); ping(n - 1); }\n"+
+		"func main() { ping(3); }")
+	main, ok := g.Func("main")
+	if !ok {
+		t.Fatalf("main not found")
+	}
+	if !main.IsPure() {
+		t.Fatalf("main should be pure, got %s", main.Effects)
+	}
+	names := func(fs []*Func) string {
+		out := []string{}
+		for _, f := range fs {
+			out = append(out, f.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	ping, _ := g.Func("ping")
+	if got := names(ping.Callers()); got != "pong,main" {
+		t.Fatalf("callers of ping = %s", got)
+	}
+	pong, _ := g.Func("pong")
+	if got := names(pong.Callees()); got != "ping" {
+		t.Fatalf("callees of pong = %s", got)
+	}
+	recursive := g.RecursiveSCCs()
+	if len(recursive) != 1 || names(recursive[0]) != "ping,pong" {
+		t.Fatalf("recursive sccs = %v", recursive)
+	}
+	// 불리는 쪽의 요소가 먼저 옴
+	if ping.SCC >= main.SCC {
+		t.Fatalf("scc order: ping %d, main %d", ping.SCC, main.SCC)
+	}
+}
+
+func TestGraph_DOT(t *testing.T) {
+	g, _ := buildForTest(t, "func f(n int) int { if n == 0 { return 0; } return f(n - 1); }\n"+
+		"func main() { h := f; print(h(1)); print(f(2)); print(f(3)); }")
+	want := "digraph callgraph {\n" +
+		"\tnode [shape=box, fontname=monospace];\n" +
+		"\tn0 [label=\"f\\npure\", style=bold];\n" +
+		"\tn1 [label=\"main\\nprint\"];\n" +
+		"\tn0 -> n0;\n" +
+		"\tn1 -> n0 [label=\"var\", style=dashed];\n" +
+		"\tn1 -> n0;\n" +
+		"}\n"
+	if got := g.DOT(); got != want {
+		t.Fatalf("dot mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestGraph_JSON(t *testing.T) {
+	g, pkg := buildForTest(t, "func f() { print(1); }\nfunc main() {\n\tf();\n}")
+	out, err := g.JSON(pkg)
+	if err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+	want := `{
+  "funcs": [
+    {
+      "name": "f",
+      "pos": "1:6",
+      "pure": false,
+      "effects": [
+        "print"
+      ],
+      "own_effects": [
+        "print"
+      ],
+      "recursive": false,
+      "calls": []
+    },
+    {
+      "name": "main",
+      "pos": "2:6",
+      "pure": false,
+      "effects": [
+        "print"
+      ],
+      "own_effects": [],
+      "recursive": false,
+      "calls": [
+        {
+          "callee": "f",
+          "kind": "direct",
+          "pos": "3:2"
+        }
+      ]
+    }
+  ],
+  "recursive": []
+}`
+	if string(out) != want {
+		t.Fatalf("json mismatch:\n--- got ---\n%s\n--- want ---\n%s", out, want)
+	}
+}
+
+func TestBuildProgram(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.tgo":  {Data: []byte("package main;\nimport \"geo\";\nfunc main() {\n\tprint(geo.Area(2));\n}\n")},
+		"geo/a.tgo": {Data: []byte("package geo;\nvar Scale int = 3;\nfunc Area(n int) int { return square(n) * Scale; }\nfunc square(n int) int { return n * n; }\n")},
+	}
+	pkgs, err := loader.NewLoader(fsys).Load()
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	table, _, _, _, err := resolver.ResolveProgram(pkgs)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	got := summarize(BuildProgram(pkgs, table))
+	want := []string{
+		"geo.Area [read-global] -> geo.square(direct)",
+		"geo.square [pure]",
+		"main [print,read-global] -> geo.Area(direct)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("graph mismatch:\n--- got ---\n%s\n--- want ---\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,142 @@
+package callgraph
+
+import "strings"
+
+// Effect는 함수가 일으킬 수 있는 효과들의 집합임
+type Effect uint16
+
+const (
+	// print를 부름
+	Print Effect = 1 << iota
+	// scan을 부름
+	Scan
+	// args, getenv로 프로그램 인자나 환경 변수를 읽음
+	Env
+	// panic을 부름. 0으로 나누기 같은 런타임 에러는 포함하지 않음
+	Panic
+	// exit을 부름
+	Exit
+	// 전역 변수를 읽음
+	ReadGlobal
+	// 전역 변수나 그 필드에 대입함
+	WriteGlobal
+	// 클로저가 바깥 함수의 지역 변수에 대입함
+	WriteCaptured
+	// 대상을 알 수 없는 함수 값을 부름
+	UnknownCall
+)
+
+var effectNames = []struct {
+	effect Effect
+	name   string
+}{
+	{Print, "print"},
+	{Scan, "scan"},
+	{Env, "env"},
+	{Panic, "panic"},
+	{Exit, "exit"},
+	{ReadGlobal, "read-global"},
+	{WriteGlobal, "write-global"},
+	{WriteCaptured, "write-captured"},
+	{UnknownCall, "unknown-call"},
+}
+
+// Names는 효과들의 이름을 정해진 순서로 리턴함
+func (e Effect) Names() []string {
+	names := []string{}
+	for _, en := range effectNames {
+		if e&en.effect != 0 {
+			names = append(names, en.name)
+		}
+	}
+	return names
+}
+
+func (e Effect) String() string {
+	if e == 0 {
+		return "pure"
+	}
+	return strings.Join(e.Names(), ",")
+}
+
+// analyze는 강한 연결 요소를 구해 재귀를 표시하고, 불리는 쪽부터 효과를 전파함
+func (g *Graph) analyze() {
+	g.SCCs = tarjan(g.Funcs)
+	for i, scc := range g.SCCs {
+		recursive := len(scc) > 1
+		var effects Effect
+		for _, f := range scc {
+			f.SCC = i
+			effects |= f.OwnEffects
+		}
+		for _, f := range scc {
+			for _, e := range f.Out {
+				if e.Callee == f {
+					recursive = true
+				}
+				// 다른 요소는 SCCs에서 앞에 오므로 이미 효과가 정해져 있음
+				effects |= e.Callee.Effects
+			}
+		}
+		for _, f := range scc {
+			f.Recursive = recursive
+			f.Effects = effects
+		}
+	}
+}
+
+// tarjan은 강한 연결 요소들을 불리는 쪽이 먼저 오는 순서로 리턴함
+// 요소 안의 함수들은 소스 순서임
+func tarjan(funcs []*Func) [][]*Func {
+	index := map[*Func]int{}
+	low := map[*Func]int{}
+	onStack := map[*Func]bool{}
+	stack := []*Func{}
+	sccs := [][]*Func{}
+	order := map[*Func]int{}
+	for i, f := range funcs {
+		order[f] = i
+	}
+	var visit func(f *Func)
+	visit = func(f *Func) {
+		index[f] = len(index)
+		low[f] = index[f]
+		stack = append(stack, f)
+		onStack[f] = true
+		for _, e := range f.Out {
+			next := e.Callee
+			if _, seen := index[next]; !seen {
+				visit(next)
+				low[f] = min(low[f], low[next])
+			} else if onStack[next] {
+				low[f] = min(low[f], index[next])
+			}
+		}
+		if low[f] != index[f] {
+			return
+		}
+		scc := []*Func{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == f {
+				break
+			}
+		}
+		// 요소 안은 소스 순서로 정렬함
+		for i := 1; i < len(scc); i++ {
+			for j := i; j > 0 && order[scc[j]] < order[scc[j-1]]; j-- {
+				scc[j], scc[j-1] = scc[j-1], scc[j]
+			}
+		}
+		sccs = append(sccs, scc)
+	}
+	for _, f := range funcs {
+		if _, seen := index[f]; !seen {
+			visit(f)
+		}
+	}
+	return sccs
+}
@@ -0,0 +1,109 @@
+package callgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// DOT은 호출 그래프를 Graphviz DOT 형식으로 출력함
+// 노드 레이블은 함수 이름과 효과이며, 재귀하는 함수는 굵게 그림
+// 같은 두 함수 사이의 같은 종류의 간선은 하나로 합치며, direct가 아닌 간선은 점선에 종류를 적음
+func (g *Graph) DOT() string {
+	lines := []string{"digraph callgraph {", "\tnode [shape=box, fontname=monospace];"}
+	index := map[*Func]int{}
+	for i, f := range g.Funcs {
+		index[f] = i
+		attr := ""
+		if f.Recursive {
+			attr = ", style=bold"
+		}
+		lines = append(lines, fmt.Sprintf("\tn%d [label=%s%s];", i, dotQuote(f.Name+"\n"+f.Effects.String()), attr))
+	}
+	for _, f := range g.Funcs {
+		seen := map[string]bool{}
+		for _, e := range f.Out {
+			key := fmt.Sprintf("%d %d", index[e.Callee], e.Kind)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			attr := ""
+			if e.Kind != DirectCall {
+				attr = fmt.Sprintf(" [label=%q, style=dashed]", e.Kind.String())
+			}
+			lines = append(lines, fmt.Sprintf("\tn%d -> n%d%s;", index[f], index[e.Callee], attr))
+		}
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// jsonGraph, jsonFunc, jsonCall은 JSON 출력의 모양임
+type jsonGraph struct {
+	Funcs []jsonFunc `json:"funcs"`
+	// 재귀를 이루는 강한 연결 요소들의 함수 이름
+	Recursive [][]string `json:"recursive"`
+}
+
+type jsonFunc struct {
+	Name       string     `json:"name"`
+	Pos        string     `json:"pos,omitempty"`
+	Pure       bool       `json:"pure"`
+	Effects    []string   `json:"effects"`
+	OwnEffects []string   `json:"own_effects"`
+	Recursive  bool       `json:"recursive"`
+	Calls      []jsonCall `json:"calls"`
+}
+
+type jsonCall struct {
+	Callee string `json:"callee"`
+	Kind   string `json:"kind"`
+	Pos    string `json:"pos,omitempty"`
+}
+
+// JSON은 호출 그래프를 도구가 읽을 수 있는 JSON으로 출력함
+// positions는 위치를 적기 위한 패키지들이며, 없으면 위치를 생략함
+func (g *Graph) JSON(positions ...*parser.PackageAST) ([]byte, error) {
+	posOf := func(id parser.Id) string {
+		for _, pkg := range positions {
+			if pos := pkg.PosOf(id); pos.IsValid() {
+				return pos.String()
+			}
+		}
+		return ""
+	}
+	out := jsonGraph{Funcs: []jsonFunc{}, Recursive: [][]string{}}
+	for _, f := range g.Funcs {
+		jf := jsonFunc{
+			Name:       f.Name,
+			Pos:        posOf(f.DeclId),
+			Pure:       f.IsPure(),
+			Effects:    f.Effects.Names(),
+			OwnEffects: f.OwnEffects.Names(),
+			Recursive:  f.Recursive,
+			Calls:      []jsonCall{},
+		}
+		for _, e := range f.Out {
+			jf.Calls = append(jf.Calls, jsonCall{Callee: e.Callee.Name, Kind: e.Kind.String(), Pos: posOf(e.Site)})
+		}
+		out.Funcs = append(out.Funcs, jf)
+	}
+	for _, scc := range g.RecursiveSCCs() {
+		names := []string{}
+		for _, f := range scc {
+			names = append(names, f.Name)
+		}
+		out.Recursive = append(out.Recursive, names)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
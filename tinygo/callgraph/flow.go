@@ -0,0 +1,211 @@
+package callgraph
+
+import (
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// valueOf는 expr을 평가한 값이 될 수 있는 함수들과, 값을 그대로 넘겨받는 변수를 리턴함
+// 함수 이름, 함수 리터럴, 함수 값을 담은 변수만 따라가며, 그 밖의 식은 함수 값을 만들지 않는 것으로 봄
+func (b *builder) valueOf(expr parser.Expr) ([]*Func, []parser.IdId) {
+	switch n := expr.(type) {
+	case *parser.Primary:
+		switch n.PrimaryKind {
+		case parser.IdPrimary:
+			return b.valueOfRef(*n.IdOrNil)
+		case parser.ExprPrimary:
+			return b.valueOf(n.ExprOrNil)
+		case parser.ValuePrimary:
+			if n.ValueOrNil != nil && n.ValueOrNil.ValueKind == parser.FexpValue {
+				if f, ok := b.funcByFexp[n.ValueOrNil.FexpOrNil]; ok {
+					return []*Func{f}, nil
+				}
+			}
+		}
+	case *parser.Selector:
+		// 다른 패키지의 멤버만 리졸브 테이블에 있음
+		if _, ok := b.table[n.Field.IdId]; ok {
+			return b.valueOfRef(n.Field)
+		}
+	case *parser.Index:
+		// f[int] 처럼 인스턴스화한 제네릭 함수
+		if n.TypeArgsOrNil != nil {
+			return b.valueOf(n.Object)
+		}
+	}
+	return nil, nil
+}
+
+func (b *builder) valueOfRef(id parser.Id) ([]*Func, []parser.IdId) {
+	ref, ok := b.table[id.IdId]
+	if !ok || ref.Kind == resolver.RefBuiltin {
+		return nil, nil
+	}
+	if f, ok := b.funcByDecl[ref.RefIdNodeId]; ok {
+		return []*Func{f}, nil
+	}
+	if b.vars[ref.RefIdNodeId] {
+		return nil, []parser.IdId{ref.RefIdNodeId}
+	}
+	return nil, nil
+}
+
+// addVarFuncs는 target 변수에 흘러들 수 있는 함수들에 funcs를 더하고, 늘었는지 리턴함
+func (b *builder) addVarFuncs(target parser.IdId, funcs []*Func) bool {
+	set, ok := b.varFuncs[target]
+	if !ok {
+		set = map[*Func]bool{}
+		b.varFuncs[target] = set
+	}
+	changed := false
+	for _, f := range funcs {
+		if !set[f] {
+			set[f] = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// funcsOf는 expr의 값이 될 수 있는 함수들을 지금까지 알려진 변수 흐름으로 구함
+func (b *builder) funcsOf(expr parser.Expr) []*Func {
+	funcs, vars := b.valueOf(expr)
+	for _, v := range vars {
+		for f := range b.varFuncs[v] {
+			funcs = append(funcs, f)
+		}
+	}
+	return funcs
+}
+
+// solveFlows는 대입과 인자 전달로 함수 값이 변수, 매개변수에 흘러드는 것을 고정점까지 전파함
+// 흐름은 순서를 보지 않으므로, 한 번이라도 대입될 수 있는 함수는 언제나 그 변수에 있을 수 있는 것으로 봄
+func (b *builder) solveFlows() {
+	for changed := true; changed; {
+		changed = false
+		for _, fl := range b.flows {
+			if b.addVarFuncs(fl.target, b.funcsOf(fl.src)) {
+				changed = true
+			}
+		}
+		for _, site := range b.sites {
+			for _, callee := range b.calleesOf(site) {
+				for i, param := range callee.Callee.params {
+					if i < len(site.args) && b.addVarFuncs(param.Id.IdId, b.funcsOf(site.args[i])) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// calleesOf는 호출 지점에서 불릴 수 있는 함수들로의 간선을 만듦. Caller는 채우지 않음
+func (b *builder) calleesOf(site callSite) []*Edge {
+	edges := []*Edge{}
+	add := func(f *Func, kind EdgeKind) {
+		edges = append(edges, &Edge{Callee: f, Site: site.site, Kind: kind})
+	}
+	if name, ok := b.builtinOf(site.callee); ok {
+		if callbackBuiltins[name] {
+			for _, arg := range site.args {
+				for _, f := range b.funcsOf(arg) {
+					add(f, CallbackCall)
+				}
+			}
+		}
+		return edges
+	}
+	funcs, vars := b.valueOf(site.callee)
+	for _, f := range funcs {
+		add(f, DirectCall)
+	}
+	for _, v := range vars {
+		for _, f := range b.g.Funcs {
+			if b.varFuncs[v][f] {
+				add(f, VarCall)
+			}
+		}
+	}
+	if sel, ok := selectorOf(site.callee); ok && len(funcs) == 0 && len(vars) == 0 {
+		for _, m := range b.methods[sel.Field.Name] {
+			add(m, MethodCall)
+		}
+	}
+	return edges
+}
+
+// builtinOf는 호출되는 식이 빌트인 함수나 빌트인 모듈의 멤버라면 그 이름을 리턴함
+func (b *builder) builtinOf(callee parser.Expr) (string, bool) {
+	switch n := callee.(type) {
+	case *parser.Primary:
+		switch n.PrimaryKind {
+		case parser.IdPrimary:
+			if ref, ok := b.table[n.IdOrNil.IdId]; ok && ref.Kind == resolver.RefBuiltin {
+				return ref.Name, true
+			}
+		case parser.ExprPrimary:
+			return b.builtinOf(n.ExprOrNil)
+		}
+	case *parser.Selector:
+		if name, ok := b.builtinOf(n.Object); ok {
+			return name + "." + n.Field.Name, true
+		}
+	}
+	return "", false
+}
+
+func selectorOf(expr parser.Expr) (*parser.Selector, bool) {
+	switch n := expr.(type) {
+	case *parser.Selector:
+		return n, true
+	case *parser.Primary:
+		if n.PrimaryKind == parser.ExprPrimary {
+			return selectorOf(n.ExprOrNil)
+		}
+	}
+	return nil, false
+}
+
+// connect는 모든 호출 지점의 간선을 잇고, 빌트인 호출과 대상을 모르는 호출의 효과를 기록함
+func (b *builder) connect() {
+	for _, site := range b.sites {
+		if name, ok := b.builtinOf(site.callee); ok {
+			site.caller.OwnEffects |= effectBuiltins[name]
+		}
+		edges := b.calleesOf(site)
+		if len(edges) == 0 && b.mayCallUnknown(site.callee) {
+			site.caller.OwnEffects |= UnknownCall
+		}
+		for _, e := range edges {
+			e.Caller = site.caller
+			site.caller.Out = append(site.caller.Out, e)
+			e.Callee.In = append(e.Callee.In, e)
+		}
+	}
+}
+
+// mayCallUnknown은 간선을 찾지 못한 호출이 알 수 없는 함수를 부르는지 리턴함
+// 빌트인, 합 타입의 변형 생성자처럼 함수가 아닌 것을 부르는 것은 알 수 없는 호출이 아님
+func (b *builder) mayCallUnknown(callee parser.Expr) bool {
+	if _, ok := b.builtinOf(callee); ok {
+		return false
+	}
+	funcs, vars := b.valueOf(callee)
+	if len(funcs) > 0 || len(vars) > 0 {
+		return true
+	}
+	switch n := callee.(type) {
+	case *parser.Primary:
+		switch n.PrimaryKind {
+		case parser.IdPrimary:
+			return false
+		case parser.ExprPrimary:
+			return b.mayCallUnknown(n.ExprOrNil)
+		}
+	case *parser.Selector:
+		_, inTable := b.table[n.Field.IdId]
+		return !inTable
+	}
+	return true
+}
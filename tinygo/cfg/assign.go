@@ -0,0 +1,205 @@
+package cfg
+
+import (
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// idSet은 선언 id의 집합임
+type idSet map[parser.IdId]bool
+
+func (s idSet) clone() idSet {
+	copied := make(idSet, len(s))
+	for id := range s {
+		copied[id] = true
+	}
+	return copied
+}
+
+// definiteAssignment는 지점마다 반드시 대입된 변수들을 구하는 전진 분석임
+// 추적하는 변수는 초기식 없이 var로 선언된 지역 변수뿐이고, 나머지는 선언하면서 값을 가짐
+type definiteAssignment struct {
+	table resolver.ResolveTable
+	// 추적하는 변수들의 선언 id
+	tracked idSet
+}
+
+func (a definiteAssignment) Direction() Direction { return Forward }
+func (a definiteAssignment) Boundary() idSet      { return idSet{} }
+
+// Top은 모든 변수가 대입된 상태임. 닿을 수 없는 블록에서는 아무것도 보고하지 않게 됨
+func (a definiteAssignment) Top() idSet { return a.tracked.clone() }
+
+func (a definiteAssignment) Meet(x, y idSet) idSet {
+	met := idSet{}
+	for id := range x {
+		if y[id] {
+			met[id] = true
+		}
+	}
+	return met
+}
+
+func (a definiteAssignment) Transfer(block *Block, fact idSet) idSet {
+	fact = fact.clone()
+	for _, node := range block.Nodes {
+		a.step(node, fact)
+	}
+	return fact
+}
+
+func (a definiteAssignment) Equal(x, y idSet) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for id := range x {
+		if !y[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// step은 노드 하나를 지난 뒤의 사실로 fact를 고침
+// 루프 안에서 다시 실행되는 초기식 없는 var 선언은 변수를 대입되지 않은 상태로 되돌림
+func (a definiteAssignment) step(node parser.Node, fact idSet) {
+	if decl, ok := node.(*parser.VarDecl); ok && len(decl.ExprsOrNil) == 0 {
+		for _, id := range decl.Ids {
+			delete(fact, id.IdId)
+		}
+		return
+	}
+	for _, id := range assignedIds(node) {
+		if declId, ok := a.declOf(id); ok {
+			fact[declId] = true
+		}
+	}
+}
+
+// declOf는 id가 추적하는 변수를 가리킨다면 그 선언 id를 리턴함
+func (a definiteAssignment) declOf(id parser.Id) (parser.IdId, bool) {
+	ref, ok := a.table[id.IdId]
+	if !ok || ref.Kind != resolver.RefLocal || !a.tracked[ref.RefIdNodeId] {
+		return 0, false
+	}
+	return ref.RefIdNodeId, true
+}
+
+// checkUseBeforeAssign은 대입되지 않았을 수 있는 변수를 읽는 곳을, 변수마다 처음 한 곳만 보고함
+func checkUseBeforeAssign(g *Graph, table resolver.ResolveTable) []Diagnostic {
+	analysis := definiteAssignment{table: table, tracked: trackedVars(g, table)}
+	if len(analysis.tracked) == 0 {
+		return nil
+	}
+	result := Solve[idSet](g, analysis)
+	diagnostics := []Diagnostic{}
+	reported := idSet{}
+	report := func(fact idSet, node parser.Node) {
+		for _, id := range usedIds(node) {
+			declId, ok := analysis.declOf(id)
+			if !ok || fact[declId] || reported[declId] {
+				continue
+			}
+			reported[declId] = true
+			diagnostics = append(diagnostics, Diagnostic{Func: g.Name, At: id, Msg: "variable " + id.Name + " used before assignment"})
+		}
+	}
+	for _, block := range g.Blocks {
+		fact := result.In[block.Index].clone()
+		for _, node := range block.Nodes {
+			report(fact, node)
+			analysis.step(node, fact)
+		}
+		if block.CondOrNil != nil {
+			report(fact, block.CondOrNil)
+		}
+	}
+	return diagnostics
+}
+
+// trackedVars는 초기식 없이 선언된 지역 변수들 중, 대입하기 전에 읽는 것이 실수일 만한 것들을 모음
+// var는 zero value로 초기화되므로, 그 값을 쓰려는 관용적인 변수는 빼야 함
+// - 한 번도 대입하지 않는 변수는 zero value 자체가 목적임 (var z T; return z)
+// - total = total + x 처럼 자기 값으로 갱신하는 변수는 zero value에서 누적하는 것임
+// - 중첩된 함수가 참조하는 변수는 그 안의 읽기, 대입이 언제 실행될지 알 수 없음
+func trackedVars(g *Graph, table resolver.ResolveTable) idSet {
+	declared := idSet{}
+	nodes := []parser.Node{}
+	for _, block := range g.Blocks {
+		for _, node := range block.Nodes {
+			if decl, ok := node.(*parser.VarDecl); ok && len(decl.ExprsOrNil) == 0 {
+				for _, id := range decl.Ids {
+					declared[id.IdId] = true
+				}
+			}
+			nodes = append(nodes, node)
+		}
+		if block.CondOrNil != nil {
+			nodes = append(nodes, block.CondOrNil)
+		}
+	}
+	if len(declared) == 0 {
+		return declared
+	}
+	declOf := func(id parser.Id) parser.IdId {
+		if ref, ok := table[id.IdId]; ok && ref.Kind == resolver.RefLocal {
+			return ref.RefIdNodeId
+		}
+		return -1
+	}
+	assigned, excluded := idSet{}, idSet{}
+	for _, node := range nodes {
+		if _, ok := node.(*parser.ForRange); ok {
+			// 블록의 ForRange 노드는 range 변수의 대입만을 뜻하고, 본문은 다른 블록에 있음
+			for _, id := range assignedIds(node) {
+				assigned[declOf(id)] = true
+			}
+			continue
+		}
+		reads := idSet{}
+		for _, id := range usedIds(node) {
+			reads[declOf(id)] = true
+		}
+		for _, id := range assignedIds(node) {
+			assigned[declOf(id)] = true
+			if reads[declOf(id)] {
+				excluded[declOf(id)] = true
+			}
+		}
+		parser.Inspect(node, func(n parser.Node) bool {
+			switch fn := n.(type) {
+			case *parser.Fexp:
+				forEachRefId(&fn.Block, func(id parser.Id) { excluded[declOf(id)] = true })
+				return false
+			case *parser.FuncDecl:
+				forEachRefId(&fn.Block, func(id parser.Id) { excluded[declOf(id)] = true })
+				return false
+			}
+			return true
+		})
+	}
+	tracked := idSet{}
+	for id := range declared {
+		if assigned[id] && !excluded[id] {
+			tracked[id] = true
+		}
+	}
+	return tracked
+}
+
+// forEachRefId는 body 안에서 변수를 읽거나 대입하는 식별자마다 visit을 호출함
+func forEachRefId(body *parser.Block, visit func(parser.Id)) {
+	parser.Inspect(body, func(n parser.Node) bool {
+		switch n := n.(type) {
+		case *parser.Primary:
+			if n.PrimaryKind == parser.IdPrimary {
+				visit(*n.IdOrNil)
+			}
+		case *parser.Assign:
+			for _, id := range n.Ids {
+				visit(id)
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,500 @@
+package cfg
+
+import (
+	"strconv"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// Graph는 함수 본문 하나의 제어 흐름 그래프임
+// 블록은 만들어진 순서, 즉 소스에 나타난 순서대로 Blocks에 놓임
+type Graph struct {
+	// f, 메서드라면 T.m, 함수 리터럴이라면 f.func1 처럼 감싼 함수 이름에 순번을 붙임
+	Name string
+	// 진단 위치로 쓰는 선언의 id. 함수 리터럴이라면 그것을 담은 가장 가까운 선언의 id
+	DeclId           parser.Id
+	ReturnTypesOrNil []parser.Type
+	IsGenerator      bool
+
+	Entry  *Block
+	Exit   *Block
+	Blocks []*Block
+	// 본문의 끝에 닿아 암묵적으로 리턴하는 블록. 본문이 항상 return 등으로 끝난다면 nil
+	FallOffOrNil *Block
+}
+
+// Block은 중간에 분기도, 합류도 없는 노드들의 나열임
+type Block struct {
+	Index int
+	// entry, exit, if.then, for.body 처럼 블록이 만들어진 자리
+	Kind string
+	// 순서대로 실행되는 문장과 식
+	// if, for, switch 같은 복합 문장은 들어가지 않고, 그 헤더의 문장과 식이 나뉘어 들어감
+	// ForRange 노드는 range 변수에 다음 값을 대입하는 것을 뜻함
+	Nodes []parser.Node
+	// nil이 아니라면 블록 끝에서 이 조건으로 분기함. Succs[0]이 참, Succs[1]이 거짓일 때의 후속 블록임
+	// switch 절의 조건이라면, 태그가 있을 때 태그와 같은지를 뜻함
+	CondOrNil parser.Expr
+	Succs     []*Block
+	Preds     []*Block
+	// return, break 등의 바로 뒤에 만들어진 블록이라면 그 문장 이름
+	after string
+}
+
+// jumpTarget은 break, continue가 향하는 블록임
+// switch는 break만 받으므로 continueOrNil이 nil임
+type jumpTarget struct {
+	breakTo       *Block
+	continueOrNil *Block
+}
+
+type builder struct {
+	g       *Graph
+	table   resolver.ResolveTable
+	current *Block
+	targets []jumpTarget
+}
+
+// BuildFunc는 함수 선언의 그래프를 만듦
+func BuildFunc(decl *parser.FuncDecl, table resolver.ResolveTable) *Graph {
+	name := decl.Id.Name
+	if decl.ReceiverOrNil != nil {
+		name = typeText(decl.ReceiverOrNil.Type) + "." + name
+	}
+	return build(name, decl.Id, decl.ReturnTypesOrNil, decl.IsGenerator, &decl.Block, table)
+}
+
+// BuildFexp는 함수 리터럴의 그래프를 만듦. declId는 진단 위치로 쓰임
+func BuildFexp(name string, declId parser.Id, fexp *parser.Fexp, table resolver.ResolveTable) *Graph {
+	return build(name, declId, fexp.ReturnTypesOrNil, fexp.IsGenerator, &fexp.Block, table)
+}
+
+// BuildPackage는 패키지의 모든 함수, 메서드와 그 안의 함수 리터럴, 지역 함수의 그래프를 소스 순서대로 만듦
+// 전역 변수의 초기식에 쓰인 함수 리터럴도 포함됨
+func BuildPackage(pkg *parser.PackageAST, table resolver.ResolveTable) []*Graph {
+	graphs := []*Graph{}
+	for _, decl := range pkg.DeclsOrNil {
+		switch node := decl.(type) {
+		case *parser.FuncDecl:
+			graphs = appendWithNested(graphs, BuildFunc(node, table), node.Id, &node.Block, table)
+		case *parser.VarDecl:
+			// 초기식 자체는 그래프가 없고, 그 안의 함수 리터럴만 그래프를 가짐
+			for _, expr := range node.ExprsOrNil {
+				graphs = appendNested(graphs, node.Ids[0].Name, node.Ids[0], expr, table)
+			}
+		}
+	}
+	return graphs
+}
+
+// appendWithNested는 g와, 본문 body 안에 중첩된 함수들의 그래프를 덧붙임
+func appendWithNested(graphs []*Graph, g *Graph, declId parser.Id, body *parser.Block, table resolver.ResolveTable) []*Graph {
+	graphs = append(graphs, g)
+	return appendNested(graphs, g.Name, declId, body, table)
+}
+
+// appendNested는 body 안에 바로 중첩된 함수 리터럴, 지역 함수의 그래프를 재귀적으로 덧붙임
+func appendNested(graphs []*Graph, outer string, declId parser.Id, body parser.Node, table resolver.ResolveTable) []*Graph {
+	count := 0
+	parser.Inspect(body, func(node parser.Node) bool {
+		if node == body {
+			return true
+		}
+		switch fn := node.(type) {
+		case *parser.Fexp:
+			count++
+			name := outer + ".func" + strconv.Itoa(count)
+			graphs = appendWithNested(graphs, BuildFexp(name, declId, fn, table), declId, &fn.Block, table)
+			return false
+		case *parser.FuncDecl:
+			g := BuildFunc(fn, table)
+			g.Name = outer + "." + g.Name
+			graphs = appendWithNested(graphs, g, fn.Id, &fn.Block, table)
+			return false
+		}
+		return true
+	})
+	return graphs
+}
+
+func build(name string, declId parser.Id, returnTypes []parser.Type, isGenerator bool, body *parser.Block, table resolver.ResolveTable) *Graph {
+	b := &builder{
+		g: &Graph{
+			Name:             name,
+			DeclId:           declId,
+			ReturnTypesOrNil: returnTypes,
+			IsGenerator:      isGenerator,
+		},
+		table: table,
+	}
+	b.g.Entry = b.newBlock("entry")
+	b.current = b.g.Entry
+	b.buildBlock(body)
+	b.g.Exit = b.newBlock("exit")
+	b.g.FallOffOrNil = b.current
+	b.addEdge(b.current, b.g.Exit)
+	// 리턴, 브레이크 등이 향하던 자리를 exit으로 이음
+	for _, block := range b.g.Blocks {
+		for i, succ := range block.Succs {
+			if succ == nil {
+				block.Succs[i] = b.g.Exit
+				b.g.Exit.Preds = append(b.g.Exit.Preds, block)
+			}
+		}
+	}
+	b.prune()
+	return b.g
+}
+
+func (b *builder) newBlock(kind string) *Block {
+	block := &Block{Index: len(b.g.Blocks), Kind: kind}
+	b.g.Blocks = append(b.g.Blocks, block)
+	return block
+}
+
+// addEdge는 from -> to 간선을 만듦. to가 nil이라면 아직 만들어지지 않은 exit으로 향함
+func (b *builder) addEdge(from, to *Block) {
+	from.Succs = append(from.Succs, to)
+	if to != nil {
+		to.Preds = append(to.Preds, from)
+	}
+}
+
+// jump는 현재 블록을 to로 보내고, 뒤따르는 문장을 위한 도달할 수 없는 블록을 시작함
+func (b *builder) jump(to *Block, after string) {
+	b.addEdge(b.current, to)
+	b.current = b.newBlock("unreachable")
+	b.current.after = after
+}
+
+// startBlock은 현재 블록을 next로 잇고 next에서 이어 만듦
+func (b *builder) startBlock(next *Block) {
+	b.addEdge(b.current, next)
+	b.current = next
+}
+
+func (b *builder) add(node parser.Node) {
+	b.current.Nodes = append(b.current.Nodes, node)
+}
+
+func (b *builder) buildBlock(block *parser.Block) {
+	for _, stmt := range block.StmtsOrNil {
+		b.buildStmt(stmt)
+	}
+}
+
+func (b *builder) buildStmt(stmt parser.Stmt) {
+	switch node := stmt.(type) {
+	case *parser.Block:
+		b.buildBlock(node)
+	case *parser.If:
+		b.buildIf(node)
+	case *parser.ForBexp:
+		b.buildForBexp(node)
+	case *parser.ForWithAssign:
+		b.buildForWithAssign(node)
+	case *parser.ForRange:
+		b.buildForRange(node)
+	case *parser.Switch:
+		b.buildSwitch(node)
+	case *parser.TypeSwitch:
+		b.buildTypeSwitch(node)
+	case *parser.Return:
+		b.addSimple(node)
+		b.jump(nil, "return")
+	case *parser.Break:
+		if target, ok := b.breakTarget(); ok {
+			b.jump(target, "break")
+		}
+	case *parser.Continue:
+		if target, ok := b.continueTarget(); ok {
+			b.jump(target, "continue")
+		}
+	case *parser.Fallthrough:
+		// 절의 끝이 아닌 fallthrough는 리졸버, 실행기가 에러로 다룸
+	case *parser.CallStmt:
+		b.addSimple(node)
+		if name, ok := b.terminatingCall(&node.Call); ok {
+			b.jump(nil, name)
+		}
+	default:
+		b.addSimple(node)
+	}
+}
+
+// addSimple은 분기 없는 문장, 식을 현재 블록에 넣음
+// try가 있다면 에러로 리턴하는 갈래가 생기므로, exit으로 가는 간선을 두고 새 블록에서 이어 감
+func (b *builder) addSimple(node parser.Node) {
+	b.add(node)
+	if containsTry(node) {
+		b.addEdge(b.current, nil)
+		b.startBlock(b.newBlock("try.ok"))
+	}
+}
+
+// branch는 현재 블록을 cond로 분기시킴
+func (b *builder) branch(cond parser.Expr, then, otherwise *Block) {
+	b.addSimpleCond(cond)
+	b.current.CondOrNil = cond
+	b.addEdge(b.current, then)
+	b.addEdge(b.current, otherwise)
+}
+
+// addSimpleCond는 try가 든 조건을 위해, 조건 평가 전에 exit으로 가는 갈래를 둠
+func (b *builder) addSimpleCond(cond parser.Expr) {
+	if containsTry(cond) {
+		b.addEdge(b.current, nil)
+		b.startBlock(b.newBlock("try.ok"))
+	}
+}
+
+func (b *builder) buildIf(node *parser.If) {
+	if node.ShortDeclOrNil != nil {
+		b.addSimple(node.ShortDeclOrNil)
+	}
+	then := b.newBlock("if.then")
+	var elseOrNil *Block
+	if node.ElseOrNil != nil {
+		elseOrNil = b.newBlock("if.else")
+	}
+	done := b.newBlock("if.done")
+	if elseOrNil != nil {
+		b.branch(node.Bexp, then, elseOrNil)
+	} else {
+		b.branch(node.Bexp, then, done)
+	}
+	b.current = then
+	b.buildBlock(&node.ThenBlock)
+	b.addEdge(b.current, done)
+	if elseOrNil != nil {
+		b.current = elseOrNil
+		b.buildBlock(node.ElseOrNil)
+		b.addEdge(b.current, done)
+	}
+	b.current = done
+}
+
+// loop는 header에서 cond로 본문과 done을 고르는 루프를 만듦
+// cond가 리터럴 true라면 루프를 빠져나가는 간선이 없음
+func (b *builder) loop(header *Block, cond parser.Expr, body, done, continueTo *Block, build func()) {
+	b.startBlock(header)
+	if isTrueLiteral(cond) {
+		b.addEdge(header, body)
+	} else {
+		b.branch(cond, body, done)
+	}
+	b.targets = append(b.targets, jumpTarget{breakTo: done, continueOrNil: continueTo})
+	b.current = body
+	build()
+	b.targets = b.targets[:len(b.targets)-1]
+	b.addEdge(b.current, continueTo)
+	b.current = done
+}
+
+func (b *builder) buildForBexp(node *parser.ForBexp) {
+	header := b.newBlock("for.cond")
+	body := b.newBlock("for.body")
+	done := b.newBlock("for.done")
+	b.loop(header, node.Bexp, body, done, header, func() {
+		b.buildBlock(&node.Block)
+	})
+}
+
+func (b *builder) buildForWithAssign(node *parser.ForWithAssign) {
+	b.addSimple(&node.ShortDecl)
+	header := b.newBlock("for.cond")
+	body := b.newBlock("for.body")
+	post := b.newBlock("for.post")
+	done := b.newBlock("for.done")
+	b.loop(header, node.Bexp, body, done, post, func() {
+		b.buildBlock(&node.Block)
+	})
+	post.Nodes = append(post.Nodes, &node.Assign)
+	b.addEdge(post, header)
+}
+
+func (b *builder) buildForRange(node *parser.ForRange) {
+	b.addSimple(node.Expr)
+	header := b.newBlock("range.next")
+	body := b.newBlock("range.body")
+	done := b.newBlock("range.done")
+	b.startBlock(header)
+	b.addEdge(header, body)
+	b.addEdge(header, done)
+	b.targets = append(b.targets, jumpTarget{breakTo: done, continueOrNil: header})
+	b.current = body
+	b.add(node)
+	b.buildBlock(&node.Block)
+	b.targets = b.targets[:len(b.targets)-1]
+	b.addEdge(b.current, header)
+	b.current = done
+}
+
+// buildSwitch는 case 식마다 조건 블록을 두어 위에서 아래로 검사함. default는 맞는 case가 없을 때 선택됨
+func (b *builder) buildSwitch(node *parser.Switch) {
+	if node.ShortDeclOrNil != nil {
+		b.addSimple(node.ShortDeclOrNil)
+	}
+	if node.TagOrNil != nil {
+		b.addSimple(node.TagOrNil)
+	}
+	// case 식마다 조건 블록을 두어, 거짓이면 다음 조건 블록으로 잇게 함
+	tests, conds, clauseOf := []*Block{}, []parser.Expr{}, []int{}
+	for i, clause := range node.Clauses {
+		for _, expr := range clause.Exprs {
+			tests = append(tests, b.newBlock("switch.case"))
+			conds = append(conds, expr)
+			clauseOf = append(clauseOf, i)
+		}
+	}
+	bodies := make([]*Block, len(node.Clauses))
+	for i := range node.Clauses {
+		bodies[i] = b.newBlock("switch.body")
+	}
+	done := b.newBlock("switch.done")
+	otherwise := done
+	for i, clause := range node.Clauses {
+		if clause.IsDefault {
+			otherwise = bodies[i]
+		}
+	}
+	if len(tests) == 0 {
+		b.addEdge(b.current, otherwise)
+	}
+	for i, test := range tests {
+		b.startBlock(test)
+		next := otherwise
+		if i+1 < len(tests) {
+			next = tests[i+1]
+		}
+		b.branch(conds[i], bodies[clauseOf[i]], next)
+	}
+	b.targets = append(b.targets, jumpTarget{breakTo: done})
+	for i := range node.Clauses {
+		b.current = bodies[i]
+		b.buildBlock(&node.Clauses[i].Block)
+		if node.Clauses[i].Fallthrough && i+1 < len(bodies) {
+			b.addEdge(b.current, bodies[i+1])
+		} else {
+			b.addEdge(b.current, done)
+		}
+	}
+	b.targets = b.targets[:len(b.targets)-1]
+	b.current = done
+}
+
+func (b *builder) buildTypeSwitch(node *parser.TypeSwitch) {
+	b.addSimple(node.Subject)
+	head := b.current
+	bodies := make([]*Block, len(node.Clauses))
+	hasDefault := false
+	for i, clause := range node.Clauses {
+		bodies[i] = b.newBlock("typeswitch.body")
+		hasDefault = hasDefault || clause.IsDefault
+	}
+	done := b.newBlock("typeswitch.done")
+	for _, body := range bodies {
+		b.addEdge(head, body)
+	}
+	if !hasDefault {
+		b.addEdge(head, done)
+	}
+	b.targets = append(b.targets, jumpTarget{breakTo: done})
+	for i := range node.Clauses {
+		b.current = bodies[i]
+		b.buildBlock(&node.Clauses[i].Block)
+		b.addEdge(b.current, done)
+	}
+	b.targets = b.targets[:len(b.targets)-1]
+	b.current = done
+}
+
+func (b *builder) breakTarget() (*Block, bool) {
+	if len(b.targets) == 0 {
+		return nil, false
+	}
+	return b.targets[len(b.targets)-1].breakTo, true
+}
+
+// continueTarget은 가장 안쪽 루프의 continue 자리를 찾음. switch는 건너뜀
+func (b *builder) continueTarget() (*Block, bool) {
+	for i := len(b.targets) - 1; i >= 0; i-- {
+		if b.targets[i].continueOrNil != nil {
+			return b.targets[i].continueOrNil, true
+		}
+	}
+	return nil, false
+}
+
+// terminatingCall은 call이 함수로 돌아오지 않는 빌트인 panic, exit의 호출인지 검사하고 그 이름을 리턴함
+func (b *builder) terminatingCall(call *parser.Call) (string, bool) {
+	callee := call.PrimaryOrNil
+	if callee.PrimaryKind != parser.IdPrimary || len(call.ArgsList) != 1 {
+		return "", false
+	}
+	ref, ok := b.table[callee.IdOrNil.IdId]
+	if !ok || ref.Kind != resolver.RefBuiltin || (ref.Name != "panic" && ref.Name != "exit") {
+		return "", false
+	}
+	return ref.Name, true
+}
+
+// prune은 return 등의 뒤에 만들어졌지만 아무 노드도 받지 못한 빈 블록을 지우고 번호를 다시 매김
+// 지운 블록이 가진 after는 후속 블록으로 넘겨, 도달할 수 없는 코드의 진단에 쓰이게 함
+func (b *builder) prune() {
+	for changed := true; changed; {
+		changed = false
+		for _, block := range b.g.Blocks {
+			if block.Kind != "unreachable" || len(block.Preds) != 0 || len(block.Nodes) != 0 || block.CondOrNil != nil {
+				continue
+			}
+			for _, succ := range block.Succs {
+				succ.Preds = removeBlock(succ.Preds, block)
+				if succ.after == "" {
+					succ.after = block.after
+				}
+			}
+			b.g.Blocks = removeBlock(b.g.Blocks, block)
+			if b.g.FallOffOrNil == block {
+				b.g.FallOffOrNil = nil
+			}
+			changed = true
+			break
+		}
+	}
+	for i, block := range b.g.Blocks {
+		block.Index = i
+	}
+}
+
+func removeBlock(blocks []*Block, target *Block) []*Block {
+	kept := blocks[:0]
+	for _, block := range blocks {
+		if block != target {
+			kept = append(kept, block)
+		}
+	}
+	return kept
+}
+
+func isTrueLiteral(expr parser.Expr) bool {
+	primary, ok := expr.(*parser.Primary)
+	return ok && primary.PrimaryKind == parser.ValuePrimary && primary.ValueOrNil != nil &&
+		primary.ValueOrNil.ValueKind == parser.BoolValue && *primary.ValueOrNil.BoolOrNil
+}
+
+func containsTry(node parser.Node) bool {
+	found := false
+	parser.Inspect(node, func(n parser.Node) bool {
+		switch n.(type) {
+		case *parser.Try, *parser.TryStmt:
+			found = true
+		case *parser.Fexp, *parser.FuncDecl:
+			// 중첩된 함수의 try는 그 함수에서 리턴함
+			return false
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,206 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+func resolveForTest(t *testing.T, input string) (*parser.PackageAST, resolver.ResolveTable) {
+	t.Helper()
+	lx := lexer.NewLexer()
+	lx.Set(input)
+	pkg, err := parser.NewParser(lx).ParsePackage()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	table, _, _, _, err := resolver.Resolve(pkg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	return pkg, table
+}
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "missing_return_after_if",
+			input: "func f(n int) int { if n > 0 { return 1; } }",
+			want:  []string{"diagnostic at f(#0) in f: missing return at end of function"},
+		},
+		{
+			name: "terminating_bodies",
+			input: "func a(n int) int { if n > 0 { return 1; } else { return 2; } } " +
+				"func b(n int) int { for true { n = n + 1; } } " +
+				"func c(n int) int { switch n { case 1: return 1; default: panic(\"bad\"); } } " +
+				"func d(n int) int { for i := 0; i < n; i = i + 1; { if i > 3 { return i; } } return 0; }",
+			want: nil,
+		},
+		{
+			// break로 빠져나갈 수 있는 루프와 default 없는 switch는 본문 끝에 닿음
+			name: "loops_and_switches_that_fall_off",
+			input: "func a(n int) int { for true { if n > 3 { break; } n = n + 1; } } " +
+				"func b(n int) int { switch n { case 1: return 1; } }",
+			want: []string{
+				"diagnostic at a(#0) in a: missing return at end of function",
+				"diagnostic at b(#5) in b: missing return at end of function",
+			},
+		},
+		{
+			name: "unreachable_code",
+			input: "func f(n int) int { return n; print(1); print(2); } " +
+				"func g(n int) { for n > 0 { n = n - 1; continue; print(n); } panic(\"x\"); if n > 0 { print(3); } } " +
+				"func h(n int) int { if n > 0 { return n; } exit(1); print(4); }",
+			want: []string{
+				"diagnostic at f(#0) in f: unreachable code after return: print(1)",
+				"diagnostic at g(#5) in g: unreachable code after continue: print(n)",
+				"diagnostic at g(#5) in g: unreachable code after panic: if n > 0",
+				"diagnostic at h(#15) in h: unreachable code after exit: print(4)",
+			},
+		},
+		{
+			name: "unreachable_join_and_infinite_loop",
+			input: "func f(n int) int { if n > 0 { return 1; } else { return 2; } n = 3; return n; } " +
+				"func g() { for true { print(1); } print(2); }",
+			want: []string{
+				"diagnostic at f(#0) in f: unreachable code after return: n = 3",
+				"diagnostic at g(#5) in g: unreachable code: print(2)",
+			},
+		},
+		{
+			name: "use_before_assignment",
+			input: "func f(c bool) int { var x int; var y int; if c { x = 1; y = 1; } else { y = 2; } print(y); return x; } " +
+				"func g(n int) { for i := 0; i < n; i = i + 1; { var s string; if i > 0 { print(s); } s = \"a\"; } }",
+			want: []string{
+				"diagnostic at x(#10) in f: variable x used before assignment",
+				"diagnostic at s(#21) in g: variable s used before assignment",
+			},
+		},
+		{
+			// 대입이 모든 갈래에 있거나, zero value를 쓰는 관용적인 변수, 클로저가 잡은 변수는 보고하지 않음
+			name: "not_reported",
+			input: "func f(c bool) int { var x int; switch c { case true: x = 1; default: x = 2; } var y int; set := func() { y = 3; }; set(); return x + y; } " +
+				"func g(xs []int) int { var z int; var total int; for _, x := range xs { total = total + x; } return total + z; }",
+			want: nil,
+		},
+		{
+			name:  "closures_get_their_own_graphs",
+			input: "func f() { g := func(n int) int { if n > 0 { return n; } }; print(g(1)); }",
+			want:  []string{"diagnostic at f(#0) in f.func1: missing return at end of function"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg, table := resolveForTest(t, tc.input)
+			got := []string{}
+			for _, d := range Check(pkg, table) {
+				got = append(got, d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Fatalf("diagnostics mismatch:\n--- got ---\n%s\n--- want ---\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestGraph_DOT(t *testing.T) {
+	pkg, table := resolveForTest(t, "func f(n int) int { s := 0; for n > 0 { s = s + n; n = n - 1; } return s; }")
+	g := BuildFunc(pkg.DeclsOrNil[0].(*parser.FuncDecl), table)
+	want := `digraph "f" {
+	node [shape=box, fontname=monospace];
+	b0 [label="b0 entry\ls := 0\l"];
+	b1 [label="b1 for.cond\lif n > 0\l"];
+	b2 [label="b2 for.body\ls = s + n\ln = n - 1\l"];
+	b3 [label="b3 for.done\lreturn s\l"];
+	b4 [label="b4 exit\l"];
+	b0 -> b1;
+	b1 -> b2 [label="true"];
+	b1 -> b3 [label="false"];
+	b2 -> b1;
+	b3 -> b4;
+}
+`
+	if got := g.DOT(); got != want {
+		t.Fatalf("dot mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+// liveness는 Backward 방향을 검사하기 위한, 블록의 시작에서 살아 있는 변수 이름을 구하는 분석임
+type liveness struct{}
+
+func (liveness) Direction() Direction      { return Backward }
+func (liveness) Boundary() map[string]bool { return map[string]bool{} }
+func (liveness) Top() map[string]bool      { return map[string]bool{} }
+func (liveness) Meet(a, b map[string]bool) map[string]bool {
+	met := map[string]bool{}
+	for name := range a {
+		met[name] = true
+	}
+	for name := range b {
+		met[name] = true
+	}
+	return met
+}
+func (liveness) Transfer(block *Block, fact map[string]bool) map[string]bool {
+	live := map[string]bool{}
+	for name := range fact {
+		live[name] = true
+	}
+	if block.CondOrNil != nil {
+		for _, id := range usedIds(block.CondOrNil) {
+			live[id.Name] = true
+		}
+	}
+	for i := len(block.Nodes) - 1; i >= 0; i-- {
+		for _, id := range assignedIds(block.Nodes[i]) {
+			delete(live, id.Name)
+		}
+		for _, id := range usedIds(block.Nodes[i]) {
+			live[id.Name] = true
+		}
+	}
+	return live
+}
+func (liveness) Equal(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if !b[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSolve_Backward(t *testing.T) {
+	pkg, table := resolveForTest(t, "func f(n int) int { s := 0; t := 5; for n > 0 { s = s + n; n = n - 1; } t = 1; return s + t; }")
+	g := BuildFunc(pkg.DeclsOrNil[0].(*parser.FuncDecl), table)
+	result := Solve[map[string]bool](g, liveness{})
+	names := func(fact map[string]bool) string {
+		out := []string{}
+		for _, name := range []string{"n", "s", "t"} {
+			if fact[name] {
+				out = append(out, name)
+			}
+		}
+		return strings.Join(out, ",")
+	}
+	// 루프를 도는 동안 n, s가 살아 있고, t의 초기값 5는 쓰이지 않음
+	want := []string{"n", "n,s", "n,s", "s", ""}
+	for i, block := range g.Blocks {
+		if got := names(result.In[i]); got != want[i] {
+			t.Fatalf("live-in of b%d %s: got %q, want %q", i, block.Kind, got, want[i])
+		}
+	}
+	if got := names(result.Out[0]); got != "n,s" {
+		t.Fatalf("live-out of entry: got %q", got)
+	}
+}
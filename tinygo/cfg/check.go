@@ -0,0 +1,118 @@
+package cfg
+
+import (
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// Diagnostic은 그래프에서 발견한 제어 흐름의 문제임
+type Diagnostic struct {
+	// 문제가 있는 그래프의 이름
+	Func string
+	At   parser.Id
+	Msg  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("diagnostic at %s in %s: %s", d.At.String(), d.Func, d.Msg)
+}
+
+// Check는 패키지의 모든 함수에 대해 진단들을 리턴함
+// - 값을 리턴해야 하는 함수가 본문 끝에 닿을 수 있음
+// - return, break 등의 뒤에 있어 실행될 수 없는 코드
+// - 초기식 없이 선언된 지역 변수를, 대입하기 전에 읽을 수 있음
+func Check(pkg *parser.PackageAST, table resolver.ResolveTable) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	for _, g := range BuildPackage(pkg, table) {
+		diagnostics = append(diagnostics, CheckGraph(g, table)...)
+	}
+	return diagnostics
+}
+
+// CheckGraph는 그래프 하나의 진단들을 리턴함
+func CheckGraph(g *Graph, table resolver.ResolveTable) []Diagnostic {
+	reachable := Solve[bool](g, reachability{})
+	diagnostics := checkMissingReturn(g, reachable)
+	diagnostics = append(diagnostics, checkUnreachable(g, reachable)...)
+	diagnostics = append(diagnostics, checkUseBeforeAssign(g, table)...)
+	return diagnostics
+}
+
+// reachability는 entry에서 블록에 닿을 수 있는지를 구하는 전진 분석임
+type reachability struct{}
+
+func (reachability) Direction() Direction                  { return Forward }
+func (reachability) Boundary() bool                        { return true }
+func (reachability) Top() bool                             { return false }
+func (reachability) Meet(a, b bool) bool                   { return a || b }
+func (reachability) Transfer(block *Block, fact bool) bool { return fact }
+func (reachability) Equal(a, b bool) bool                  { return a == b }
+
+// checkMissingReturn은 값을 리턴하는 함수가 return 없이 본문 끝에 닿는지 검사함
+// 제너레이터는 본문 끝에서 순회가 끝나므로 검사하지 않음
+func checkMissingReturn(g *Graph, reachable Result[bool]) []Diagnostic {
+	if len(g.ReturnTypesOrNil) == 0 || g.IsGenerator || g.FallOffOrNil == nil {
+		return nil
+	}
+	if !reachable.Out[g.FallOffOrNil.Index] {
+		return nil
+	}
+	return []Diagnostic{{Func: g.Name, At: g.DeclId, Msg: "missing return at end of function"}}
+}
+
+// checkUnreachable은 닿을 수 없는 코드를, 간선으로 이어진 덩어리마다 그 첫 노드에서 한 번만 보고함
+func checkUnreachable(g *Graph, reachable Result[bool]) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	visited := make([]bool, len(g.Blocks))
+	for _, block := range g.Blocks {
+		if reachable.In[block.Index] || visited[block.Index] || block == g.Exit {
+			continue
+		}
+		// 덩어리에서 번호가 가장 작은, 즉 소스에서 가장 앞선 노드를 가진 블록을 보고함
+		var first *Block
+		for _, member := range deadRegion(block, g, reachable, visited) {
+			if (len(member.Nodes) > 0 || member.CondOrNil != nil) && (first == nil || member.Index < first.Index) {
+				first = member
+			}
+		}
+		if first == nil {
+			continue
+		}
+		msg := "unreachable code"
+		if first.after != "" {
+			msg += " after " + first.after
+		}
+		diagnostics = append(diagnostics, Diagnostic{Func: g.Name, At: g.DeclId, Msg: msg + ": " + blockHead(first)})
+	}
+	return diagnostics
+}
+
+// deadRegion은 block과, 닿을 수 없는 블록들만 거쳐 어느 방향으로든 이어진 블록들을 모음
+func deadRegion(block *Block, g *Graph, reachable Result[bool], visited []bool) []*Block {
+	region := []*Block{}
+	stack := []*Block{block}
+	visited[block.Index] = true
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		region = append(region, top)
+		for _, next := range append(append([]*Block{}, top.Succs...), top.Preds...) {
+			if reachable.In[next.Index] || visited[next.Index] || next == g.Exit {
+				continue
+			}
+			visited[next.Index] = true
+			stack = append(stack, next)
+		}
+	}
+	return region
+}
+
+// blockHead는 블록에서 처음 실행될 노드의 문장 모양임
+func blockHead(block *Block) string {
+	if len(block.Nodes) > 0 {
+		return nodeText(block.Nodes[0])
+	}
+	return condText(block)
+}
@@ -0,0 +1,96 @@
+package cfg
+
+// Direction은 데이터흐름 분석에서 사실이 흘러가는 방향임
+type Direction int
+
+const (
+	// Forward는 entry에서 시작해 실행 순서대로 사실을 전파함
+	Forward Direction = iota
+	// Backward는 exit에서 시작해 실행의 역순으로 사실을 전파함
+	Backward
+)
+
+// Analysis는 블록 단위의 데이터흐름 분석임. F는 프로그램 지점마다 성립하는 사실의 타입임
+// Meet, Transfer는 단조여야 하고, 사실의 격자는 높이가 유한해야 Solve가 끝남
+type Analysis[F any] interface {
+	Direction() Direction
+	// Boundary는 Forward라면 entry의 시작, Backward라면 exit의 끝에서 성립하는 사실임
+	Boundary() F
+	// Top은 아직 방문하지 않은 블록의 초기 사실이며, Meet의 항등원이어야 함
+	Top() F
+	// Meet은 여러 갈래에서 합류하는 사실들을 합침
+	Meet(a, b F) F
+	// Transfer는 블록을 지나며 바뀐 사실을 리턴함
+	// Forward라면 블록 시작의 사실을 받아 끝의 사실을, Backward라면 끝의 사실을 받아 시작의 사실을 리턴함
+	Transfer(block *Block, fact F) F
+	Equal(a, b F) bool
+}
+
+// Result는 블록 번호로 찾는, 각 블록의 시작(In)과 끝(Out)에서 성립하는 사실임
+// 방향과 상관없이 In은 블록을 실행하기 전, Out은 실행한 뒤의 지점임
+type Result[F any] struct {
+	In  []F
+	Out []F
+}
+
+// Solve는 워크리스트로 고정점에 이를 때까지 사실을 전파함
+func Solve[F any](g *Graph, analysis Analysis[F]) Result[F] {
+	n := len(g.Blocks)
+	result := Result[F]{In: make([]F, n), Out: make([]F, n)}
+	forward := analysis.Direction() == Forward
+	// Forward라면 In이 입력, Out이 출력이고 Backward라면 반대임
+	input, output := result.In, result.Out
+	boundary := g.Entry
+	if !forward {
+		input, output = result.Out, result.In
+		boundary = g.Exit
+	}
+	for i := range g.Blocks {
+		input[i] = analysis.Top()
+		output[i] = analysis.Top()
+	}
+
+	// 블록 순서(Forward) 또는 그 역순(Backward)으로 시작해야 빨리 수렴함
+	worklist := make([]*Block, 0, n)
+	queued := make([]bool, n)
+	for i := range g.Blocks {
+		block := g.Blocks[i]
+		if !forward {
+			block = g.Blocks[n-1-i]
+		}
+		worklist = append(worklist, block)
+		queued[block.Index] = true
+	}
+	for len(worklist) > 0 {
+		block := worklist[0]
+		worklist = worklist[1:]
+		queued[block.Index] = false
+
+		// 흐름상 앞선 블록들의 출력을 합침
+		fact := analysis.Top()
+		if block == boundary {
+			fact = analysis.Boundary()
+		}
+		sources, targets := block.Preds, block.Succs
+		if !forward {
+			sources, targets = block.Succs, block.Preds
+		}
+		for _, source := range sources {
+			fact = analysis.Meet(fact, output[source.Index])
+		}
+		input[block.Index] = fact
+
+		out := analysis.Transfer(block, fact)
+		if analysis.Equal(out, output[block.Index]) {
+			continue
+		}
+		output[block.Index] = out
+		for _, target := range targets {
+			if !queued[target.Index] {
+				queued[target.Index] = true
+				worklist = append(worklist, target)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package cfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT은 그래프를 Graphviz DOT 형식으로 출력함. 디버깅용임
+// dot -Tsvg 등으로 그림을 얻을 수 있음
+func (g *Graph) DOT() string {
+	lines := []string{fmt.Sprintf("digraph %s {", dotQuote(g.Name))}
+	lines = append(lines, "\tnode [shape=box, fontname=monospace];")
+	lines = append(lines, g.dotBody("\t", "b")...)
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// DOT은 여러 그래프를 함수마다 클러스터로 묶어 하나의 DOT 그래프로 출력함
+func DOT(graphs []*Graph) string {
+	lines := []string{"digraph cfg {", "\tnode [shape=box, fontname=monospace];"}
+	for i, g := range graphs {
+		lines = append(lines, fmt.Sprintf("\tsubgraph cluster_%d {", i))
+		lines = append(lines, fmt.Sprintf("\t\tlabel = %s;", dotQuote(g.Name)))
+		lines = append(lines, g.dotBody("\t\t", fmt.Sprintf("f%d_b", i))...)
+		lines = append(lines, "\t}")
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// dotBody는 블록과 간선을 출력함. 블록의 DOT 이름은 prefix에 블록 번호를 붙인 것임
+func (g *Graph) dotBody(indent, prefix string) []string {
+	lines := []string{}
+	for _, block := range g.Blocks {
+		lines = append(lines, fmt.Sprintf("%s%s%d [label=%s];", indent, prefix, block.Index, dotQuote(blockLabel(block))))
+	}
+	for _, block := range g.Blocks {
+		for i, succ := range block.Succs {
+			attr := ""
+			if block.CondOrNil != nil {
+				attr = " [label=\"true\"]"
+				if i == 1 {
+					attr = " [label=\"false\"]"
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s%s%d -> %s%d%s;", indent, prefix, block.Index, prefix, succ.Index, attr))
+		}
+	}
+	return lines
+}
+
+// blockLabel은 블록 번호와 종류, 노드들, 분기 조건을 한 줄씩 왼쪽 정렬한 레이블임
+func blockLabel(block *Block) string {
+	lines := []string{fmt.Sprintf("b%d %s", block.Index, block.Kind)}
+	for _, node := range block.Nodes {
+		lines = append(lines, nodeText(node))
+	}
+	if block.CondOrNil != nil {
+		lines = append(lines, condText(block))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// condText는 분기 조건을 switch 절이라면 case, 아니라면 if로 출력함
+func condText(block *Block) string {
+	if block.Kind == "switch.case" {
+		return "case " + exprText(block.CondOrNil)
+	}
+	return "if " + exprText(block.CondOrNil)
+}
+
+// dotQuote는 s를 DOT의 문자열로 만듦. 줄바꿈은 왼쪽 정렬 줄바꿈 \l이 됨
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\l`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,153 @@
+package cfg
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/token"
+)
+
+// 블록의 노드를 DOT 레이블과 진단에 쓰기 위해 한 줄의 소스 모양으로 출력함
+// AST의 String()은 트리 모양의 여러 줄이라 그래프에 담기 어려움
+
+// nodeText는 블록에 들어가는 문장, 식을 한 줄로 출력함
+func nodeText(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Assign:
+		targets := make([]string, len(n.Ids))
+		for i, id := range n.Ids {
+			targets[i] = id.Name
+			for _, field := range n.FieldPathAt(i) {
+				targets[i] += "." + field.Name
+			}
+		}
+		return strings.Join(targets, ", ") + " = " + exprsText(n.Exprs)
+	case *parser.ShortDecl:
+		if n.Immutable {
+			return "let " + idsText(n.Ids) + " = " + exprsText(n.Exprs)
+		}
+		return idsText(n.Ids) + " := " + exprsText(n.Exprs)
+	case *parser.VarDecl:
+		text := "var " + idsText(n.Ids) + " " + typeText(n.Type)
+		if len(n.ExprsOrNil) > 0 {
+			text += " = " + exprsText(n.ExprsOrNil)
+		}
+		return text
+	case *parser.CallStmt:
+		return exprText(&n.Call)
+	case *parser.TryStmt:
+		return exprText(n.Try)
+	case *parser.Return:
+		if len(n.ExprsOrNil) == 0 {
+			return "return"
+		}
+		return "return " + exprsText(n.ExprsOrNil)
+	case *parser.Yield:
+		return "yield " + exprsText(n.Exprs)
+	case *parser.FuncDecl:
+		return "func " + n.Id.Name
+	case *parser.ForRange:
+		return idsText(n.Vars) + " = range next"
+	case parser.Expr:
+		return exprText(n)
+	}
+	return "?"
+}
+
+func exprsText(exprs []parser.Expr) string {
+	texts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		texts[i] = exprText(expr)
+	}
+	return strings.Join(texts, ", ")
+}
+
+func idsText(ids []parser.Id) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func exprText(expr parser.Expr) string {
+	switch e := expr.(type) {
+	case *parser.Primary:
+		switch e.PrimaryKind {
+		case parser.ExprPrimary:
+			return "(" + exprText(e.ExprOrNil) + ")"
+		case parser.IdPrimary:
+			return e.IdOrNil.Name
+		default:
+			return valueText(e.ValueOrNil)
+		}
+	case *parser.Unary:
+		return token.StringSpec(token.TokenKind(e.Op)) + exprText(e.Object)
+	case *parser.Binary:
+		return exprText(e.LeftExpr) + " " + token.StringSpec(token.TokenKind(e.Op)) + " " + exprText(e.RightExpr)
+	case *parser.Call:
+		text := exprText(&e.PrimaryOrNil)
+		for _, args := range e.ArgsList {
+			text += "(" + exprsText(args) + ")"
+		}
+		return text
+	case *parser.Selector:
+		return exprText(e.Object) + "." + e.Field.Name
+	case *parser.TypeAssert:
+		if e.TypeOrNil == nil {
+			return exprText(e.Object) + ".(type)"
+		}
+		return exprText(e.Object) + ".(" + typeText(*e.TypeOrNil) + ")"
+	case *parser.Try:
+		return exprText(e.Object) + "?"
+	case *parser.Index:
+		if e.Index == nil {
+			types := make([]string, len(e.TypeArgsOrNil))
+			for i, t := range e.TypeArgsOrNil {
+				types[i] = typeText(t)
+			}
+			return exprText(e.Object) + "[" + strings.Join(types, ", ") + "]"
+		}
+		return exprText(e.Object) + "[" + exprText(e.Index) + "]"
+	case *parser.Match:
+		return "match " + exprText(e.Subject) + " {...}"
+	}
+	return "?"
+}
+
+func valueText(value *parser.ValueForm) string {
+	switch value.ValueKind {
+	case parser.NumberValue:
+		return strconv.Itoa(*value.NumberOrNil)
+	case parser.BoolValue:
+		return strconv.FormatBool(*value.BoolOrNil)
+	case parser.StrLitValue:
+		return strconv.Quote(*value.StrLitOrNil)
+	case parser.ErrValue:
+		if value.ErrOrNilIfOk == nil {
+			return "ok"
+		}
+		return "error(" + strconv.Quote(*value.ErrOrNilIfOk) + ")"
+	case parser.FexpValue:
+		return "func(...) {...}"
+	case parser.StructLitValue:
+		return value.StructLitOrNil.TypeName.Name + "{...}"
+	case parser.SliceLitValue:
+		return "[]" + typeText(value.SliceLitOrNil.ElemType) + "{" + exprsText(value.SliceLitOrNil.Elems) + "}"
+	case parser.TupleLitValue:
+		return "(" + exprsText(value.TupleLitOrNil.Elems) + ")"
+	}
+	return "?"
+}
+
+// typeText는 타입을 id 번호 없이 출력함
+func typeText(t parser.Type) string {
+	switch t.TypeKind {
+	case parser.NamedType:
+		return t.NameOrNil.Name
+	case parser.SliceType:
+		return "[]" + typeText(*t.ElemOrNil)
+	}
+	return t.String()
+}
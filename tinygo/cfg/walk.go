@@ -0,0 +1,47 @@
+package cfg
+
+import "github.com/rlaaudgjs5638/langTest/tinygo/parser"
+
+// usedIds는 node가 값으로 읽는 식별자들을 순서대로 리턴함. 중첩된 함수의 본문은 제외함
+// 대입, 선언의 좌변과 range 변수는 읽는 것이 아니므로 포함되지 않음
+func usedIds(node parser.Node) []parser.Id {
+	ids := []parser.Id{}
+	parser.Inspect(node, func(n parser.Node) bool {
+		switch n := n.(type) {
+		case *parser.Fexp, *parser.FuncDecl, *parser.ForRange:
+			// 블록의 ForRange 노드는 range 변수의 대입만을 뜻함
+			return false
+		case *parser.Primary:
+			if n.PrimaryKind == parser.IdPrimary {
+				ids = append(ids, *n.IdOrNil)
+			}
+		}
+		return true
+	})
+	return ids
+}
+
+// assignedIds는 node가 값을 대입하는 식별자들을 리턴함. 필드 대입은 변수 자체를 대입하지 않으므로 제외함
+func assignedIds(node parser.Node) []parser.Id {
+	switch n := node.(type) {
+	case *parser.Assign:
+		ids := []parser.Id{}
+		for i, id := range n.Ids {
+			if len(n.FieldPathAt(i)) == 0 {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	case *parser.ShortDecl:
+		return n.Ids
+	case *parser.VarDecl:
+		if len(n.ExprsOrNil) > 0 {
+			return n.Ids
+		}
+	case *parser.ForRange:
+		return n.Vars
+	case *parser.FuncDecl:
+		return []parser.Id{n.Id}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/callgraph"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// runCallgraph는 프로그램의 호출 그래프와 함수마다의 효과를 출력함
+// 기본은 함수마다 한 줄씩 읽기 위한 형식이며, -dot과 -json으로 다른 도구에 넘길 수 있음
+func runCallgraph(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("callgraph", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the graph as JSON")
+	asDOT := flags.Bool("dot", false, "print the graph in Graphviz DOT format")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || (*asJSON && *asDOT) {
+		fmt.Fprintln(stderr, "usage: tinygo callgraph [-json | -dot] <file.tgo | module dir>")
+		return 2
+	}
+	prog, err := loadProgram(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tinygo callgraph: %v\n", err)
+		return 2
+	}
+	g := callgraph.BuildProgram(prog.pkgs, prog.table)
+	asts := []*parser.PackageAST{}
+	for _, pkg := range prog.pkgs {
+		asts = append(asts, pkg.AST)
+	}
+
+	switch {
+	case *asJSON:
+		out, err := g.JSON(asts...)
+		if err != nil {
+			fmt.Fprintf(stderr, "tinygo callgraph: %v\n", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, string(out))
+	case *asDOT:
+		fmt.Fprint(stdout, g.DOT())
+	default:
+		for _, f := range g.Funcs {
+			line := f.Name + ": " + f.Effects.String()
+			if f.Recursive {
+				line += " (recursive)"
+			}
+			fmt.Fprintln(stdout, line)
+			for _, callee := range f.Callees() {
+				fmt.Fprintln(stdout, "\t-> "+callee.Name)
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// runDeps는 전역 초기화 의존 그래프와 그로부터 정해진 초기화 순서를 출력함
+// 순환 의존이 있으면 리졸빙이 실패하므로, 순환 경로를 담은 오류를 출력하고 2로 끝남
+func runDeps(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("deps", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asDOT := flags.Bool("dot", false, "print the graph in Graphviz DOT format")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: tinygo deps [-dot] <file.tgo | module dir>")
+		return 2
+	}
+	prog, err := loadProgram(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tinygo deps: %v\n", err)
+		return 2
+	}
+	graph, err := resolver.BuildInitGraph(prog.table, prog.hoist)
+	if err != nil {
+		fmt.Fprintf(stderr, "tinygo deps: %v\n", err)
+		return 2
+	}
+
+	if *asDOT {
+		fmt.Fprint(stdout, depsDOT(graph))
+		return 0
+	}
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(stdout, "%s (%s)\n", node, node.Kind)
+		for _, edge := range graph.Edges(node) {
+			line := "\t-> " + edge.To.String()
+			if pos := prog.posOf(edge.At); pos.IsValid() {
+				line += "\t" + pos.String()
+			}
+			fmt.Fprintln(stdout, line)
+		}
+	}
+	names := []string{}
+	for _, step := range prog.order {
+		node, _ := graph.Node(step.VarId)
+		names = append(names, node.String())
+	}
+	fmt.Fprintln(stdout, "init order: "+strings.Join(names, ", "))
+	return 0
+}
+
+// depsDOT은 초기화 의존 그래프를 DOT 형식으로 출력함. 함수는 타원, 변수는 상자임
+func depsDOT(graph *resolver.InitGraph) string {
+	lines := []string{"digraph deps {", "\tnode [fontname=monospace];"}
+	index := map[*resolver.InitNode]int{}
+	for i, node := range graph.Nodes {
+		index[node] = i
+		shape := "box"
+		if node.Kind == resolver.InitFunc {
+			shape = "ellipse"
+		}
+		lines = append(lines, fmt.Sprintf("\tn%d [label=%q, shape=%s];", i, node.String(), shape))
+	}
+	for _, node := range graph.Nodes {
+		for _, edge := range graph.Edges(node) {
+			lines = append(lines, fmt.Sprintf("\tn%d -> n%d;", index[node], index[edge.To]))
+		}
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// posOf는 프로그램의 어느 패키지에서든 id의 소스 위치를 찾음
+func (p *program) posOf(id parser.Id) parser.Pos {
+	for _, pkg := range p.pkgs {
+		if pos := pkg.AST.PosOf(id); pos.IsValid() {
+			return pos
+		}
+	}
+	return parser.Pos{}
+}
@@ -0,0 +1,124 @@
+// tinygo는 tiny go 프로그램을 다루는 명령줄 도구임
+//
+//	tinygo vet [-json] [-only a,b] [-list] <file.tgo | module dir>
+//	tinygo callgraph [-json | -dot] <file.tgo | module dir>
+//	tinygo deps [-dot] <file.tgo | module dir>
+//	tinygo run [-maxdepth n] <file.tgo | module dir> [args...]
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/loader"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// command는 tinygo의 하위 명령 하나임. run은 프로세스의 종료 코드를 리턴함
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "vet", usage: "vet [-json] [-only a,b] [-list] <file.tgo | module dir>", run: runVet},
+	{name: "callgraph", usage: "callgraph [-json | -dot] <file.tgo | module dir>", run: runCallgraph},
+	{name: "deps", usage: "deps [-dot] <file.tgo | module dir>", run: runDeps},
+	{name: "run", usage: "run [-maxdepth n] <file.tgo | module dir> [args...]", run: runRun},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		for _, cmd := range commands {
+			if cmd.name == args[0] {
+				return cmd.run(args[1:], stdout, stderr)
+			}
+		}
+		fmt.Fprintf(stderr, "tinygo: unknown command %q\n", args[0])
+	}
+	fmt.Fprintln(stderr, "usage:")
+	for _, cmd := range commands {
+		fmt.Fprintln(stderr, "\ttinygo "+cmd.usage)
+	}
+	return 2
+}
+
+// program은 리졸빙까지 마친 프로그램임. 패키지들은 임포트되는 것이 먼저 오고, main 패키지가 마지막임
+type program struct {
+	pkgs     []resolver.PackageSource
+	table    resolver.ResolveTable
+	hoist    *resolver.HoistInfo
+	order    resolver.InitOrder
+	builtins map[string]int
+}
+
+// loadProgram은 target이 디렉토리라면 모듈로, 아니라면 .tgo 파일 하나로 읽어 리졸빙함
+func loadProgram(target string) (*program, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		pkgs, err := loader.NewDirLoader(target).Load()
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range pkgs {
+			relocate(pkg.AST, target)
+		}
+		table, hoist, order, builtins, err := resolver.ResolveProgram(pkgs)
+		if err != nil {
+			return nil, err
+		}
+		return &program{pkgs: pkgs, table: table, hoist: hoist, order: order, builtins: builtins}, nil
+	}
+	src, err := os.ReadFile(target)
+	if err != nil {
+		return nil, err
+	}
+	lx := lexer.NewLexer()
+	lx.Set(string(src))
+	pkg, err := parser.NewParser(lx).ParsePackage()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+	pkg.SetFile(target)
+	table, hoist, order, builtins, err := resolver.Resolve(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+	pkgs := []resolver.PackageSource{{Path: loader.MainPath, AST: pkg}}
+	return &program{pkgs: pkgs, table: table, hoist: hoist, order: order, builtins: builtins}, nil
+}
+
+// relocate는 모듈 루트 기준인 파일 이름들을 명령을 실행한 위치 기준으로 바꿈
+func relocate(pkg *parser.PackageAST, root string) {
+	for idId, pos := range pkg.Positions {
+		pos.File = filepath.Join(root, pos.File)
+		pkg.Positions[idId] = pos
+	}
+	for i := range pkg.Comments {
+		pkg.Comments[i].Pos.File = filepath.Join(root, pkg.Comments[i].Pos.File)
+	}
+}
+
+// splitList는 쉼표로 나열된 이름들을 나눔
+func splitList(s string) []string {
+	names := []string{}
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/evaluator"
+)
+
+// runRun은 프로그램을 실행하고 그 종료 코드로 끝남. target 뒤의 인자들은 프로그램의 args()로 넘어감
+// go와 같이 exit(code)는 code로, 잡히지 않은 panic과 런타임 에러는 메시지를 출력하고 2로 끝남
+// 프로그램을 읽거나 리졸빙하지 못하면 go run의 빌드 실패처럼 1로 끝남
+// 깊은 재귀가 호스트를 죽이지 않도록 항상 힙 스택 모드로 실행하며, 호출 깊이가 -maxdepth를 넘으면 stack overflow 에러임
+// print는 표준 출력에 바로 쓰므로 stdout은 쓰지 않음
+func runRun(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	maxDepth := flags.Int("maxdepth", evaluator.DefaultMaxCallDepth, "maximum call depth before a stack overflow error")
+	// target 이후의 인자는 프로그램의 것이므로, 플래그는 target 앞에서만 읽음
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 || *maxDepth <= 0 {
+		fmt.Fprintln(stderr, "usage: tinygo run [-maxdepth n] <file.tgo | module dir> [args...]")
+		return 2
+	}
+	programArgs := flags.Args()
+	prog, err := loadProgram(programArgs[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "tinygo run: %v\n", err)
+		return 1
+	}
+	mainPkg := prog.pkgs[len(prog.pkgs)-1].AST
+	opts := evaluator.Options{Args: programArgs, HeapStack: true, MaxCallDepth: *maxDepth}
+	e, err := evaluator.NewEvaluatorWithOptions(opts, *mainPkg, prog.hoist, prog.order, prog.table, prog.builtins)
+	if err == nil {
+		err = e.EvalMainFunc()
+	}
+	code := evaluator.ExitCode(err)
+	if _, ok := err.(*evaluator.ExitError); !ok && err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+	return code
+}
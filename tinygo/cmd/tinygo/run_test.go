@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ExitCodes(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		// tinygo 다음에 오는 인자. "{file}"은 프로그램 파일 경로로 바뀜
+		args       []string
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			name:     "normal_return",
+			input:    "func main() {}",
+			args:     []string{"run", "{file}"},
+			wantCode: 0,
+		},
+		{
+			// target 뒤의 인자는 플래그처럼 보여도 프로그램의 것임
+			name:     "exit_with_args",
+			input:    "func main() { xs := args(); if xs[1] == \"-v\" { exit(len(xs)); } }",
+			args:     []string{"run", "{file}", "-v", "a"},
+			wantCode: 3,
+		},
+		{
+			name:       "uncaught_panic",
+			input:      "func main() { panic(\"boom\"); }",
+			args:       []string{"run", "{file}"},
+			wantCode:   2,
+			wantStderr: "panic: boom\n",
+		},
+		{
+			// 깊은 재귀도 호스트 스택을 넘치게 하지 않고 tinygo의 에러로 끝남
+			name:       "unbounded_recursion",
+			input:      "func f(n int) int { return 1 + f(n + 1); } func main() { n := f(0); }",
+			args:       []string{"run", "{file}"},
+			wantCode:   2,
+			wantStderr: "stack overflow: call depth exceeds 100000\n",
+		},
+		{
+			name:       "unbounded_recursion_in_var_init",
+			input:      "func f(n int) int { return 1 + f(n + 1); } var n int = f(0); func main() {}",
+			args:       []string{"run", "-maxdepth", "2000", "{file}"},
+			wantCode:   2,
+			wantStderr: "init expr evaluation failed: stack overflow: call depth exceeds 2000\n",
+		},
+		{
+			name:       "max_depth_flag",
+			input:      "func f(n int) int { return 1 + f(n + 1); } func main() { n := f(0); }",
+			args:       []string{"run", "-maxdepth", "2000", "{file}"},
+			wantCode:   2,
+			wantStderr: "stack overflow: call depth exceeds 2000\n",
+		},
+		{
+			name:       "resolve_error",
+			input:      "func main() { x := y; }",
+			args:       []string{"run", "{file}"},
+			wantCode:   1,
+			wantStderr: "resolve error at y(#2): undefined identifier\n",
+		},
+		{
+			name:       "fallthrough_in_final_clause",
+			input:      "func main() { x := 1; switch x { case 1: fallthrough; } }",
+			args:       []string{"run", "{file}"},
+			wantCode:   1,
+			wantStderr: "resolve error at main(#0): cannot fallthrough final case in switch\n",
+		},
+		{
+			name:       "missing_target",
+			args:       []string{"run"},
+			wantCode:   2,
+			wantStderr: "usage: tinygo run [-maxdepth n] <file.tgo | module dir> [args...]\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "main.tgo")
+			if err := os.WriteFile(file, []byte(tc.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			args := []string{}
+			for _, arg := range tc.args {
+				args = append(args, strings.ReplaceAll(arg, "{file}", file))
+			}
+			var stdout, stderr bytes.Buffer
+			code := run(args, &stdout, &stderr)
+			if code != tc.wantCode {
+				t.Fatalf("expected exit code %d, got %d (stderr: %s)", tc.wantCode, code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tc.wantStderr) || (tc.wantStderr == "" && stderr.Len() > 0) {
+				t.Fatalf("unexpected stderr:\n got %q\nwant %q", stderr.String(), tc.wantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lint"
+)
+
+// runVet은 프로그램의 모든 패키지에 분석기들을 실행함
+// 진단이 없으면 0, 진단이 있으면 1, 프로그램을 읽지 못하면 2로 끝남
+func runVet(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("vet", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print diagnostics as a JSON array")
+	only := flags.String("only", "", "comma-separated analyzers to run (default: all)")
+	list := flags.Bool("list", false, "list the analyzers and exit")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *list {
+		for _, a := range lint.All {
+			fmt.Fprintf(stdout, "%-12s %s\n", a.Name, a.Doc)
+		}
+		return 0
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: tinygo vet [-json] [-only a,b] [-list] <file.tgo | module dir>")
+		return 2
+	}
+	analyzers := lint.All
+	if *only != "" {
+		analyzers = []*lint.Analyzer{}
+		for _, name := range splitList(*only) {
+			a, ok := lint.ByName(name)
+			if !ok {
+				fmt.Fprintf(stderr, "tinygo vet: unknown analyzer %q\n", name)
+				return 2
+			}
+			analyzers = append(analyzers, a)
+		}
+	}
+
+	prog, err := loadProgram(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "tinygo vet: %v\n", err)
+		return 2
+	}
+	diagnostics := lint.RunProgram(prog.pkgs, prog.table, analyzers)
+
+	if *asJSON {
+		out, err := lint.JSON(diagnostics)
+		if err != nil {
+			fmt.Fprintf(stderr, "tinygo vet: %v\n", err)
+			return 2
+		}
+		fmt.Fprintln(stdout, string(out))
+	} else {
+		for _, d := range diagnostics {
+			fmt.Fprintln(stdout, d.String())
+		}
+	}
+	if len(diagnostics) > 0 {
+		return 1
+	}
+	return 0
+}
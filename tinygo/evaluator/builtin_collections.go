@@ -0,0 +1,322 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// 슬라이스를 다루는 고차 함수 빌트인들
+// 함수 인자로는 클로저, 빌트인, 모듈 함수, 메서드 값 모두 callValue를 통해 같은 방식으로 호출됨
+// 사용자 함수에서 일어난 panic은 루프를 멈추고 그대로 호출자에게 전파됨
+
+// anyType은 원소 타입을 알 수 없을 때 쓰이는 빈 interface 타입임
+var anyType = parser.Type{TypeKind: parser.InterfaceTypeKind, InterfaceOrNil: &parser.InterfaceType{}}
+
+func builtinMap(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	xs, f, err := sliceAndFuncArgs("seq.Map", args, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]Value, len(xs.Elems))
+	for i, elem := range xs.Elems {
+		result, ctrlSig, err := e.applySingle("seq.Map", f, elem)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		results[i] = result
+	}
+	return []Value{newSliceVal(e.resultElemType(f, results), results)}, nil, nil
+}
+
+func builtinFilter(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	xs, pred, err := sliceAndFuncArgs("seq.Filter", args, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	kept := []Value{}
+	for _, elem := range xs.Elems {
+		ok, ctrlSig, err := e.applyPredicate("seq.Filter", pred, elem)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		if ok {
+			kept = append(kept, elem)
+		}
+	}
+	return []Value{newSliceVal(xs.ElemType, kept)}, nil, nil
+}
+
+// seq.Reduce(xs, f, init)은 acc = f(acc, x)를 왼쪽부터 적용한 최종 acc를 리턴함
+func builtinReduce(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	xs, f, err := sliceAndFuncArgs("seq.Reduce", args, 3)
+	if err != nil {
+		return nil, nil, err
+	}
+	acc := args[2]
+	for _, elem := range xs.Elems {
+		values, ctrlSig, err := e.callValue(f, []Value{acc, elem})
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		acc, err = expectSingle(values, "seq.Reduce function result")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return []Value{acc}, nil, nil
+}
+
+// seq.FlatMap(xs, f)는 f가 리턴한 슬라이스들을 이어 붙인 슬라이스를 리턴함
+func builtinFlatMap(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	xs, f, err := sliceAndFuncArgs("seq.FlatMap", args, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := []Value{}
+	var elemTypeOrNil *parser.Type
+	if t, ok := e.declaredResultType(f); ok && t.TypeKind == parser.SliceType {
+		elemTypeOrNil = t.ElemOrNil
+	}
+	for _, elem := range xs.Elems {
+		result, ctrlSig, err := e.applySingle("seq.FlatMap", f, elem)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		inner, ok := result.(*SliceValue)
+		if !ok {
+			return nil, nil, fmt.Errorf("seq.FlatMap expects function to return slice, got %s", dynamicTypeName(result))
+		}
+		if elemTypeOrNil == nil {
+			elemTypeOrNil = &inner.ElemType
+		}
+		results = append(results, inner.Elems...)
+	}
+	if elemTypeOrNil == nil {
+		elemTypeOrNil = &anyType
+	}
+	return []Value{newSliceVal(*elemTypeOrNil, results)}, nil, nil
+}
+
+// seq.Zip(xs, ys)는 같은 위치의 원소를 묶은 (T, U) 튜플의 슬라이스를 리턴함. 길이는 짧은 쪽을 따름
+func builtinZip(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	if err := expectArgCount("seq.Zip", args, 2); err != nil {
+		return nil, nil, err
+	}
+	xs, err := sliceArg("seq.Zip", args, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	ys, err := sliceArg("seq.Zip", args, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := min(len(xs.Elems), len(ys.Elems))
+	pairs := make([]Value, n)
+	for i := 0; i < n; i++ {
+		pairs[i] = newTupleVal([]Value{xs.Elems[i], ys.Elems[i]})
+	}
+	pairType := parser.Type{TypeKind: parser.TupleType, TupleOrNil: []parser.Type{xs.ElemType, ys.ElemType}}
+	return []Value{newSliceVal(pairType, pairs)}, nil, nil
+}
+
+// seq.SortBy(xs, key)는 key(x)의 오름차순으로 안정 정렬한 새 슬라이스를 리턴함
+// key는 원소마다 한 번씩만 호출되며, 모두 int이거나 모두 string이어야 함
+func builtinSortBy(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	xs, key, err := sliceAndFuncArgs("seq.SortBy", args, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]Value, len(xs.Elems))
+	for i, elem := range xs.Elems {
+		k, ctrlSig, err := e.applySingle("seq.SortBy", key, elem)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		if k.Kind() != IntKind && k.Kind() != StrKind {
+			return nil, nil, fmt.Errorf("seq.SortBy expects int or string keys, got %s", dynamicTypeName(k))
+		}
+		if i > 0 && k.Kind() != keys[0].Kind() {
+			return nil, nil, fmt.Errorf("seq.SortBy keys must all have the same type")
+		}
+		keys[i] = k
+	}
+	order := make([]int, len(xs.Elems))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lessKey(keys[order[a]], keys[order[b]])
+	})
+	sorted := make([]Value, len(order))
+	for i, idx := range order {
+		sorted[i] = xs.Elems[idx]
+	}
+	return []Value{newSliceVal(xs.ElemType, sorted)}, nil, nil
+}
+
+// seq.GroupBy(xs, key)는 key(x)가 같은 원소끼리 묶은 (K, []T) 튜플의 슬라이스를 리턴함
+// 그룹은 키가 처음 나온 순서이며, 그룹 안의 원소는 원래 순서를 유지함
+func builtinGroupBy(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	xs, key, err := sliceAndFuncArgs("seq.GroupBy", args, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	groupKeys := []Value{}
+	groups := [][]Value{}
+	for _, elem := range xs.Elems {
+		k, ctrlSig, err := e.applySingle("seq.GroupBy", key, elem)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		found := -1
+		for i, groupKey := range groupKeys {
+			eq, ok := equalValues(k, groupKey)
+			if !ok {
+				return nil, nil, fmt.Errorf("seq.GroupBy keys %s and %s are not comparable", k.Inspect(), groupKey.Inspect())
+			}
+			if eq {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			groupKeys = append(groupKeys, k)
+			groups = append(groups, []Value{})
+			found = len(groups) - 1
+		}
+		groups[found] = append(groups[found], elem)
+	}
+	groupSliceType := parser.Type{TypeKind: parser.SliceType, ElemOrNil: &xs.ElemType}
+	pairs := make([]Value, len(groups))
+	for i, group := range groups {
+		pairs[i] = newTupleVal([]Value{groupKeys[i], newSliceVal(xs.ElemType, group)})
+	}
+	pairType := parser.Type{TypeKind: parser.TupleType, TupleOrNil: []parser.Type{e.resultElemType(key, groupKeys), groupSliceType}}
+	return []Value{newSliceVal(pairType, pairs)}, nil, nil
+}
+
+// builtinAnyAll은 seq.Any, seq.All을 만듦. 결과가 정해지는 순간 나머지 원소는 검사하지 않음
+func builtinAnyAll(name string, want bool) func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	return func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+		xs, pred, err := sliceAndFuncArgs(name, args, 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, elem := range xs.Elems {
+			ok, ctrlSig, err := e.applyPredicate(name, pred, elem)
+			if err != nil || ctrlSig != nil {
+				return nil, ctrlSig, err
+			}
+			if ok == want {
+				return []Value{newBoolVal(want)}, nil, nil
+			}
+		}
+		return []Value{newBoolVal(!want)}, nil, nil
+	}
+}
+
+// sliceAndFuncArgs는 (xs []T, f func, ...) 꼴의 인자를 검사함
+func sliceAndFuncArgs(name string, args []Value, count int) (*SliceValue, Value, error) {
+	if err := expectArgCount(name, args, count); err != nil {
+		return nil, nil, err
+	}
+	xs, err := sliceArg(name, args, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isCallable(args[1]) {
+		return nil, nil, fmt.Errorf("%s expects function as argument 2", name)
+	}
+	return xs, args[1], nil
+}
+
+func sliceArg(name string, args []Value, index int) (*SliceValue, error) {
+	xs, ok := args[index].(*SliceValue)
+	if !ok {
+		return nil, fmt.Errorf("%s expects slice as argument %d", name, index+1)
+	}
+	return xs, nil
+}
+
+// applySingle은 원소 하나로 f를 호출하고 값 하나를 돌려받음
+// 원소가 튜플이고 f의 매개변수 개수가 그 길이와 같다면 펼쳐서 넘김. seq.Zip, seq.GroupBy의 결과를 다루기 위함
+func (e *Evaluator) applySingle(name string, f Value, elem Value) (Value, *ControlSignal, error) {
+	args := []Value{elem}
+	if tuple, ok := elem.(*TupleValue); ok && len(tuple.Elems) > 1 && len(paramsOf(f)) == len(tuple.Elems) {
+		args = tuple.Elems
+	}
+	values, ctrlSig, err := e.callValue(f, args)
+	if err != nil || ctrlSig != nil {
+		return nil, ctrlSig, err
+	}
+	result, err := expectSingle(values, name+" function result")
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, nil, nil
+}
+
+func (e *Evaluator) applyPredicate(name string, pred Value, elem Value) (bool, *ControlSignal, error) {
+	result, ctrlSig, err := e.applySingle(name, pred, elem)
+	if err != nil || ctrlSig != nil {
+		return false, ctrlSig, err
+	}
+	boolVal, ok := result.(*BoolValue)
+	if !ok {
+		return false, nil, fmt.Errorf("%s expects predicate to return bool, got %s", name, dynamicTypeName(result))
+	}
+	return boolVal.Value, nil, nil
+}
+
+// declaredResultType은 f가 리턴 타입 하나로 선언된 함수라면 그 타입을 리턴함
+// 리턴 타입에 묶이지 않은 타입 매개변수가 남아 있다면 선언된 타입이 없는 것으로 봄
+func (e *Evaluator) declaredResultType(f Value) (parser.Type, bool) {
+	var returnTypes []parser.Type
+	var typeArgs map[parser.IdId]parser.Type
+	switch fn := f.(type) {
+	case *ClosureValue:
+		returnTypes, typeArgs = fn.ReturnTypes, fn.TypeArgs
+	case *BoundMethodValue:
+		returnTypes = fn.Method.ReturnTypes
+	}
+	if len(returnTypes) != 1 {
+		return parser.Type{}, false
+	}
+	t := e.substTypeParams(returnTypes[0], typeArgs)
+	if e.hasTypeParam(t) {
+		return parser.Type{}, false
+	}
+	return t, true
+}
+
+// resultElemType은 f의 결과들을 담을 슬라이스의 원소 타입을 정함
+// 선언된 리턴 타입이 있으면 그 타입이고, 빌트인처럼 없다면 결과 값이 기본 타입일 때 그 타입, 그 외엔 빈 interface임
+func (e *Evaluator) resultElemType(f Value, results []Value) parser.Type {
+	if t, ok := e.declaredResultType(f); ok {
+		return t
+	}
+	if len(results) == 0 {
+		return anyType
+	}
+	switch results[0].Kind() {
+	case IntKind:
+		return parser.Type{TypeKind: parser.IntType}
+	case BoolKind:
+		return parser.Type{TypeKind: parser.BoolType}
+	case StrKind:
+		return parser.Type{TypeKind: parser.StringType}
+	case ErrKind:
+		return parser.Type{TypeKind: parser.ErrorType}
+	default:
+		return anyType
+	}
+}
+
+func lessKey(a, b Value) bool {
+	if ai, ok := a.(*IntValue); ok {
+		return ai.Value < b.(*IntValue).Value
+	}
+	return a.(*StringValue).Value < b.(*StringValue).Value
+}
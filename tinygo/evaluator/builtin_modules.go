@@ -0,0 +1,326 @@
+package evaluator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// 모듈로 묶인 빌트인들
+// 리졸버의 BuiltinModules와 이름, 멤버가 일치해야 함
+
+func builtinModuleByName(name string) (map[string]BuiltinFunc, bool) {
+	members, ok := builtinModuleRegistry[name]
+	return members, ok
+}
+
+var builtinModuleRegistry = map[string]map[string]BuiltinFunc{
+	"strconv": {
+		"Itoa": {
+			Name: "strconv.Itoa",
+			Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+				if err := expectArgCount("strconv.Itoa", args, 1); err != nil {
+					return nil, nil, err
+				}
+				n, err := intArg("strconv.Itoa", args, 0)
+				if err != nil {
+					return nil, nil, err
+				}
+				return []Value{newStringVal(strconv.FormatInt(n, 10))}, nil, nil
+			},
+		},
+		"Atoi": {
+			Name: "strconv.Atoi",
+			Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+				if err := expectArgCount("strconv.Atoi", args, 1); err != nil {
+					return nil, nil, err
+				}
+				s, err := stringArg("strconv.Atoi", args, 0)
+				if err != nil {
+					return nil, nil, err
+				}
+				// 변환 실패는 호스트 에러가 아닌, tiny go의 error 값으로 리턴함
+				n, convErr := strconv.ParseInt(s, 10, 64)
+				if convErr != nil {
+					msg := fmt.Sprintf("strconv.Atoi: parsing %q: %s", s, convErr.(*strconv.NumError).Err)
+					return []Value{newIntVal(0), newErrorVal(&msg)}, nil, nil
+				}
+				return []Value{newIntVal(n), newErrorVal(nil)}, nil, nil
+			},
+		},
+	},
+	"strings": {
+		"Substring": {
+			Name: "strings.Substring",
+			Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+				if err := expectArgCount("strings.Substring", args, 3); err != nil {
+					return nil, nil, err
+				}
+				s, err := stringArg("strings.Substring", args, 0)
+				if err != nil {
+					return nil, nil, err
+				}
+				start, err := intArg("strings.Substring", args, 1)
+				if err != nil {
+					return nil, nil, err
+				}
+				end, err := intArg("strings.Substring", args, 2)
+				if err != nil {
+					return nil, nil, err
+				}
+				if start < 0 || end < start || end > int64(len(s)) {
+					return nil, nil, fmt.Errorf("slice bounds out of range [%d:%d] with length %d", start, end, len(s))
+				}
+				return []Value{newStringVal(s[start:end])}, nil, nil
+			},
+		},
+		"Index": {
+			Name: "strings.Index",
+			Impl: stringsBinaryFunc("strings.Index", func(s, substr string) Value {
+				return newIntVal(int64(strings.Index(s, substr)))
+			}),
+		},
+		"Contains": {
+			Name: "strings.Contains",
+			Impl: stringsBinaryFunc("strings.Contains", func(s, substr string) Value {
+				return newBoolVal(strings.Contains(s, substr))
+			}),
+		},
+		"Split": {
+			Name: "strings.Split",
+			Impl: stringsBinaryFunc("strings.Split", func(s, sep string) Value {
+				parts := strings.Split(s, sep)
+				elems := make([]Value, len(parts))
+				for i, part := range parts {
+					elems[i] = newStringVal(part)
+				}
+				return newSliceVal(parser.Type{TypeKind: parser.StringType}, elems)
+			}),
+		},
+		"Trim": {
+			Name: "strings.Trim",
+			Impl: stringsBinaryFunc("strings.Trim", func(s, cutset string) Value {
+				return newStringVal(strings.Trim(s, cutset))
+			}),
+		},
+		"TrimSpace": {
+			Name: "strings.TrimSpace",
+			Impl: stringsUnaryFunc("strings.TrimSpace", strings.TrimSpace),
+		},
+		"ToUpper": {
+			Name: "strings.ToUpper",
+			Impl: stringsUnaryFunc("strings.ToUpper", strings.ToUpper),
+		},
+		"ToLower": {
+			Name: "strings.ToLower",
+			Impl: stringsUnaryFunc("strings.ToLower", strings.ToLower),
+		},
+		"Join": {
+			Name: "strings.Join",
+			Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+				if err := expectArgCount("strings.Join", args, 2); err != nil {
+					return nil, nil, err
+				}
+				slice, ok := args[0].(*SliceValue)
+				if !ok {
+					return nil, nil, fmt.Errorf("strings.Join expects []string as argument 1")
+				}
+				sep, err := stringArg("strings.Join", args, 1)
+				if err != nil {
+					return nil, nil, err
+				}
+				parts := make([]string, len(slice.Elems))
+				for i, elem := range slice.Elems {
+					str, ok := elem.(*StringValue)
+					if !ok {
+						return nil, nil, fmt.Errorf("strings.Join expects []string as argument 1")
+					}
+					parts[i] = str.Value
+				}
+				return []Value{newStringVal(strings.Join(parts, sep))}, nil, nil
+			},
+		},
+		"Repeat": {
+			Name: "strings.Repeat",
+			Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+				if err := expectArgCount("strings.Repeat", args, 2); err != nil {
+					return nil, nil, err
+				}
+				s, err := stringArg("strings.Repeat", args, 0)
+				if err != nil {
+					return nil, nil, err
+				}
+				count, err := intArg("strings.Repeat", args, 1)
+				if err != nil {
+					return nil, nil, err
+				}
+				if count < 0 {
+					return nil, nil, fmt.Errorf("strings.Repeat: negative Repeat count")
+				}
+				return []Value{newStringVal(strings.Repeat(s, int(count)))}, nil, nil
+			},
+		},
+	},
+	// 컬렉션 고차 함수. 구현은 builtin_collections.go
+	"seq": {
+		"Map":     {Name: "seq.Map", Impl: builtinMap},
+		"Filter":  {Name: "seq.Filter", Impl: builtinFilter},
+		"Reduce":  {Name: "seq.Reduce", Impl: builtinReduce},
+		"FlatMap": {Name: "seq.FlatMap", Impl: builtinFlatMap},
+		"Zip":     {Name: "seq.Zip", Impl: builtinZip},
+		"SortBy":  {Name: "seq.SortBy", Impl: builtinSortBy},
+		"GroupBy": {Name: "seq.GroupBy", Impl: builtinGroupBy},
+		"Any":     {Name: "seq.Any", Impl: builtinAnyAll("seq.Any", true)},
+		"All":     {Name: "seq.All", Impl: builtinAnyAll("seq.All", false)},
+	},
+	// 영속 컬렉션. 구현은 builtin_persistent.go
+	"coll": {
+		"Vector":  {Name: "coll.Vector", Impl: builtinVector},
+		"HashMap": {Name: "coll.HashMap", Impl: builtinHashMap},
+		"Conj":    {Name: "coll.Conj", Impl: builtinConj},
+		"Assoc":   {Name: "coll.Assoc", Impl: builtinAssoc},
+		"Dissoc":  {Name: "coll.Dissoc", Impl: builtinDissoc},
+		"Get":     {Name: "coll.Get", Impl: builtinGet},
+	},
+	"fmt": {
+		"Sprintf": {
+			Name: "fmt.Sprintf",
+			Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+				if len(args) < 1 {
+					return nil, nil, fmt.Errorf("fmt.Sprintf expects at least 1 argument")
+				}
+				format, err := stringArg("fmt.Sprintf", args, 0)
+				if err != nil {
+					return nil, nil, err
+				}
+				out, err := sprintf(format, args[1:])
+				if err != nil {
+					return nil, nil, err
+				}
+				return []Value{newStringVal(out)}, nil, nil
+			},
+		},
+	},
+}
+
+// sprintf는 %d %s %v %t %q %% 만 지원함
+// go와 달리 인자 개수나 타입이 맞지 않으면 출력에 섞지 않고 런타임 에러로 처리함
+func sprintf(format string, args []Value) (string, error) {
+	var sb strings.Builder
+	argIndex := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sb.WriteByte(format[i])
+			continue
+		}
+		i++
+		if i >= len(format) {
+			return "", fmt.Errorf("fmt.Sprintf: format ends with %%")
+		}
+		verb := format[i]
+		if verb == '%' {
+			sb.WriteByte('%')
+			continue
+		}
+		if argIndex >= len(args) {
+			return "", fmt.Errorf("fmt.Sprintf: missing argument for %%%c", verb)
+		}
+		arg := args[argIndex]
+		argIndex++
+		switch verb {
+		case 'd':
+			n, ok := arg.(*IntValue)
+			if !ok {
+				return "", fmt.Errorf("fmt.Sprintf: %%d expects int, got %s", dynamicTypeName(arg))
+			}
+			sb.WriteString(strconv.FormatInt(n.Value, 10))
+		case 's':
+			switch val := arg.(type) {
+			case *StringValue:
+				sb.WriteString(val.Value)
+			case *ErrorValue:
+				sb.WriteString(val.Inspect())
+			default:
+				return "", fmt.Errorf("fmt.Sprintf: %%s expects string or error, got %s", dynamicTypeName(arg))
+			}
+		case 'q':
+			str, ok := arg.(*StringValue)
+			if !ok {
+				return "", fmt.Errorf("fmt.Sprintf: %%q expects string, got %s", dynamicTypeName(arg))
+			}
+			sb.WriteString(strconv.Quote(str.Value))
+		case 't':
+			b, ok := arg.(*BoolValue)
+			if !ok {
+				return "", fmt.Errorf("fmt.Sprintf: %%t expects bool, got %s", dynamicTypeName(arg))
+			}
+			sb.WriteString(b.Inspect())
+		case 'v':
+			sb.WriteString(arg.Inspect())
+		default:
+			return "", fmt.Errorf("fmt.Sprintf: unsupported verb %%%c", verb)
+		}
+	}
+	if argIndex < len(args) {
+		return "", fmt.Errorf("fmt.Sprintf: %d extra argument(s)", len(args)-argIndex)
+	}
+	return sb.String(), nil
+}
+
+func stringsUnaryFunc(name string, fn func(string) string) func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	return func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+		if err := expectArgCount(name, args, 1); err != nil {
+			return nil, nil, err
+		}
+		s, err := stringArg(name, args, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []Value{newStringVal(fn(s))}, nil, nil
+	}
+}
+
+func stringsBinaryFunc(name string, fn func(string, string) Value) func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	return func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+		if err := expectArgCount(name, args, 2); err != nil {
+			return nil, nil, err
+		}
+		left, err := stringArg(name, args, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		right, err := stringArg(name, args, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []Value{fn(left, right)}, nil, nil
+	}
+}
+
+func expectArgCount(name string, args []Value, count int) error {
+	if len(args) != count {
+		if count == 1 {
+			return fmt.Errorf("%s expects 1 argument", name)
+		}
+		return fmt.Errorf("%s expects %d arguments", name, count)
+	}
+	return nil
+}
+
+func stringArg(name string, args []Value, index int) (string, error) {
+	str, ok := args[index].(*StringValue)
+	if !ok {
+		return "", fmt.Errorf("%s expects string as argument %d", name, index+1)
+	}
+	return str.Value, nil
+}
+
+func intArg(name string, args []Value, index int) (int64, error) {
+	n, ok := args[index].(*IntValue)
+	if !ok {
+		return 0, fmt.Errorf("%s expects int as argument %d", name, index+1)
+	}
+	return n.Value, nil
+}
@@ -0,0 +1,250 @@
+package evaluator
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/persistent"
+)
+
+// 영속 벡터, 해시 맵을 만들고 갱신하는 빌트인들
+// 갱신 빌트인은 항상 새 값을 리턴하며 인자로 받은 값은 바뀌지 않음
+
+func builtinVector(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	return []Value{newVectorVal(persistent.NewVector(args...))}, nil, nil
+}
+
+// coll.HashMap(k1, v1, k2, v2, ...)은 주어진 키, 값 쌍들로 맵을 만듦. 같은 키가 다시 나오면 뒤의 값이 이김
+func builtinHashMap(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	if len(args)%2 != 0 {
+		return nil, nil, fmt.Errorf("coll.HashMap expects key, value pairs")
+	}
+	m := newEmptyHashMap()
+	for i := 0; i < len(args); i += 2 {
+		h, err := hashValue(args[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		m = m.Assoc(h, args[i], args[i+1])
+	}
+	return []Value{newHashMapVal(m)}, nil, nil
+}
+
+// coll.Conj(v, x, ...)는 v 뒤에 x들을 붙인 새 벡터를 리턴함
+func builtinConj(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	if len(args) < 2 {
+		return nil, nil, fmt.Errorf("coll.Conj expects at least 2 arguments")
+	}
+	vec, ok := args[0].(*VectorValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("coll.Conj expects vector as argument 1")
+	}
+	next := vec.Vec
+	for _, x := range args[1:] {
+		next = next.Conj(x)
+	}
+	return []Value{newVectorVal(next)}, nil, nil
+}
+
+// coll.Assoc(c, k, v)는 벡터라면 k번째 원소를, 맵이라면 키 k의 값을 v로 바꾼 새 값을 리턴함
+// 벡터의 k가 길이와 같으면 뒤에 붙임
+func builtinAssoc(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	if err := expectArgCount("coll.Assoc", args, 3); err != nil {
+		return nil, nil, err
+	}
+	switch coll := args[0].(type) {
+	case *VectorValue:
+		index, err := intArg("coll.Assoc", args, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		next, ok := coll.Vec.Assoc(int(index), args[2])
+		if !ok {
+			return nil, nil, fmt.Errorf("coll.Assoc index out of range [%d] with length %d", index, coll.Vec.Len())
+		}
+		return []Value{newVectorVal(next)}, nil, nil
+	case *HashMapValue:
+		h, err := hashValue(args[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		return []Value{newHashMapVal(coll.Map.Assoc(h, args[1], args[2]))}, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("coll.Assoc expects vector or hashMap as argument 1")
+	}
+}
+
+// coll.Dissoc(m, k, ...)는 키 k들을 뺀 새 맵을 리턴함. 없는 키는 무시함
+func builtinDissoc(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	if len(args) < 2 {
+		return nil, nil, fmt.Errorf("coll.Dissoc expects at least 2 arguments")
+	}
+	m, ok := args[0].(*HashMapValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("coll.Dissoc expects hashMap as argument 1")
+	}
+	next := m.Map
+	for _, key := range args[1:] {
+		h, err := hashValue(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		next = next.Dissoc(h, key)
+	}
+	return []Value{newHashMapVal(next)}, nil, nil
+}
+
+// coll.Get(c, k)는 (값, true)를 리턴하며, 없는 인덱스, 키라면 (nil, false)를 리턴함
+func builtinGet(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+	if err := expectArgCount("coll.Get", args, 2); err != nil {
+		return nil, nil, err
+	}
+	var value Value
+	var found bool
+	switch coll := args[0].(type) {
+	case *VectorValue:
+		index, err := intArg("coll.Get", args, 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, found = coll.Vec.Get(int(index))
+	case *HashMapValue:
+		h, err := hashValue(args[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		value, found = coll.Map.Get(h, args[1])
+	default:
+		return nil, nil, fmt.Errorf("coll.Get expects vector or hashMap as argument 1")
+	}
+	if !found {
+		return []Value{newNilVal(), newBoolVal(false)}, nil, nil
+	}
+	return []Value{value, newBoolVal(true)}, nil, nil
+}
+
+// hashValue는 해시 맵의 키로 쓰일 값의 해시를 계산함
+// equalValues로 같은 두 값은 항상 같은 해시를 가짐. 함수, 슬라이스처럼 비교할 수 없는 값은 키가 될 수 없음
+func hashValue(v Value) (uint32, error) {
+	h := fnv.New32a()
+	if err := writeHashKey(h, v); err != nil {
+		return 0, err
+	}
+	return h.Sum32(), nil
+}
+
+func writeHashKey(h hash.Hash32, v Value) error {
+	switch val := v.(type) {
+	case *IntValue:
+		fmt.Fprintf(h, "i%d;", val.Value)
+	case *BoolValue:
+		fmt.Fprintf(h, "b%t;", val.Value)
+	case *StringValue:
+		fmt.Fprintf(h, "s%d:%s;", len(val.Value), val.Value)
+	case *ErrorValue:
+		// 에러는 정체성으로 비교하므로, 같은 메시지의 다른 에러는 해시만 같고 서로 다른 키임
+		fmt.Fprintf(h, "e%t:%d:%s;", val.IsOk, len(val.ErrMsg), val.ErrMsg)
+	case *NilValue:
+		fmt.Fprint(h, "n;")
+	case *TupleValue:
+		return writeHashKeys(h, "t", val.Elems)
+	case *StructValue:
+		return writeHashKeys(h, "r"+val.TypeName, val.Fields)
+	case *AdtValue:
+		return writeHashKeys(h, "a"+val.TypeName+"."+val.Variant, val.Fields)
+	case *VectorValue:
+		elems := make([]Value, 0, val.Vec.Len())
+		val.Vec.Each(func(_ int, elem Value) bool {
+			elems = append(elems, elem)
+			return true
+		})
+		return writeHashKeys(h, "v", elems)
+	case *HashMapValue:
+		// 같은 맵이라도 구성 순서에 따라 순회 순서가 다를 수 있으므로 순서와 무관하게 합침
+		var sum uint32
+		var err error
+		val.Map.Each(func(key, value Value) bool {
+			var kh, vh uint32
+			if kh, err = hashValue(key); err != nil {
+				return false
+			}
+			if vh, err = hashValue(value); err != nil {
+				return false
+			}
+			sum += kh*31 ^ vh
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "m%d:%d;", val.Map.Len(), sum)
+	default:
+		return fmt.Errorf("unhashable type %s", dynamicTypeName(v))
+	}
+	return nil
+}
+
+func writeHashKeys(h hash.Hash32, tag string, elems []Value) error {
+	fmt.Fprintf(h, "%s%d(", tag, len(elems))
+	for _, elem := range elems {
+		if err := writeHashKey(h, elem); err != nil {
+			return err
+		}
+	}
+	fmt.Fprint(h, ");")
+	return nil
+}
+
+// equalVectors는 두 벡터의 원소를 차례로 비교함
+func equalVectors(left, right *VectorValue) (bool, bool) {
+	if left.Vec.Len() != right.Vec.Len() {
+		return false, true
+	}
+	eq, comparable := true, true
+	left.Vec.Each(func(i int, elem Value) bool {
+		other, _ := right.Vec.Get(i)
+		elemEq, ok := equalValues(elem, other)
+		if !ok {
+			comparable = false
+			return false
+		}
+		eq = eq && elemEq
+		return true
+	})
+	if !comparable {
+		return false, false
+	}
+	return eq, true
+}
+
+// equalHashMaps는 두 맵이 같은 키들을 같은 값에 묶고 있는지 비교함
+func equalHashMaps(left, right *HashMapValue) (bool, bool) {
+	if left.Map.Len() != right.Map.Len() {
+		return false, true
+	}
+	eq, comparable := true, true
+	left.Map.Each(func(key, value Value) bool {
+		h, err := hashValue(key)
+		if err != nil {
+			comparable = false
+			return false
+		}
+		other, found := right.Map.Get(h, key)
+		if !found {
+			eq = false
+			return true
+		}
+		valueEq, ok := equalValues(value, other)
+		if !ok {
+			comparable = false
+			return false
+		}
+		eq = eq && valueEq
+		return true
+	})
+	if !comparable {
+		return false, false
+	}
+	return eq, true
+}
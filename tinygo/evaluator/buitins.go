@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
 )
 
 type BuiltinFunc struct {
@@ -69,11 +71,18 @@ var builtinRegistry = map[string]BuiltinFunc{
 			if len(args) != 1 {
 				return nil, nil, fmt.Errorf("len expects 1 argument")
 			}
-			str, ok := args[0].(*StringValue)
-			if !ok {
-				return nil, nil, fmt.Errorf("len expects string")
+			switch arg := args[0].(type) {
+			case *StringValue:
+				return []Value{newIntVal(int64(len(arg.Value)))}, nil, nil
+			case *SliceValue:
+				return []Value{newIntVal(int64(len(arg.Elems)))}, nil, nil
+			case *VectorValue:
+				return []Value{newIntVal(int64(arg.Vec.Len()))}, nil, nil
+			case *HashMapValue:
+				return []Value{newIntVal(int64(arg.Map.Len()))}, nil, nil
+			default:
+				return nil, nil, fmt.Errorf("len expects string, slice, vector or hashMap")
 			}
-			return []Value{newIntVal(int64(len(str.Value)))}, nil, nil
 		},
 	},
 	//TODO scan은 현재 구현 실패. 포인터 전달 필요
@@ -123,4 +132,182 @@ var builtinRegistry = map[string]BuiltinFunc{
 			return nil, newPanicSignal(args), nil
 		},
 	},
+	"exit": {
+		Name: "exit",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("exit", args, 1); err != nil {
+				return nil, nil, err
+			}
+			code, err := intArg("exit", args, 0)
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, newExitSignal(code), nil
+		},
+	},
+	"args": {
+		Name: "args",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("args", args, 0); err != nil {
+				return nil, nil, err
+			}
+			elems := make([]Value, len(e.programArgs))
+			for i, arg := range e.programArgs {
+				elems[i] = newStringVal(arg)
+			}
+			return []Value{newSliceVal(parser.Type{TypeKind: parser.StringType}, elems)}, nil, nil
+		},
+	},
+	"getenv": {
+		Name: "getenv",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("getenv", args, 1); err != nil {
+				return nil, nil, err
+			}
+			name, err := stringArg("getenv", args, 0)
+			if err != nil {
+				return nil, nil, err
+			}
+			// go의 os.Getenv처럼 없는 변수는 빈 문자열임
+			return []Value{newStringVal(os.Getenv(name))}, nil, nil
+		},
+	},
+	"wrapError": {
+		Name: "wrapError",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("wrapError", args, 2); err != nil {
+				return nil, nil, err
+			}
+			cause, ok := args[0].(*ErrorValue)
+			if !ok {
+				return nil, nil, fmt.Errorf("wrapError expects error as argument 1")
+			}
+			ctx, err := stringArg("wrapError", args, 1)
+			if err != nil {
+				return nil, nil, err
+			}
+			// ok를 감싸면 그대로 ok임. 호출부에서 에러 여부를 따로 검사하지 않아도 되게 하기 위함
+			if cause.IsOk {
+				return []Value{cause}, nil, nil
+			}
+			return []Value{newWrappedErrorVal(ctx, cause)}, nil, nil
+		},
+	},
+	"unwrap": {
+		Name: "unwrap",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			errVal, err := errorArg("unwrap", args)
+			if err != nil {
+				return nil, nil, err
+			}
+			if errVal.CauseOrNil == nil {
+				return []Value{newErrorVal(nil)}, nil, nil
+			}
+			return []Value{errVal.CauseOrNil}, nil, nil
+		},
+	},
+	"errorIs": {
+		Name: "errorIs",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("errorIs", args, 2); err != nil {
+				return nil, nil, err
+			}
+			errVal, ok1 := args[0].(*ErrorValue)
+			target, ok2 := args[1].(*ErrorValue)
+			if !ok1 || !ok2 {
+				return nil, nil, fmt.Errorf("errorIs expects (error, error)")
+			}
+			if target.IsOk {
+				return []Value{newBoolVal(errVal.IsOk)}, nil, nil
+			}
+			return []Value{newBoolVal(errVal.chainHas(target))}, nil, nil
+		},
+	},
+	"errorWith": {
+		Name: "errorWith",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("errorWith", args, 2); err != nil {
+				return nil, nil, err
+			}
+			msg, err := stringArg("errorWith", args, 0)
+			if err != nil {
+				return nil, nil, err
+			}
+			errVal := newErrorVal(&msg)
+			errVal.DetailOrNil = args[1]
+			return []Value{errVal}, nil, nil
+		},
+	},
+	// errorAs는 원인 체인에서 example과 같은 동적 타입의 정보를 가진 첫 에러를 찾아 그 정보를 리턴함
+	// 타입은 표현식이 아니므로 errorAs(err, ParseErr{})처럼 예시 값으로 타입을 지정함
+	"errorAs": {
+		Name: "errorAs",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount("errorAs", args, 2); err != nil {
+				return nil, nil, err
+			}
+			errVal, ok := args[0].(*ErrorValue)
+			if !ok {
+				return nil, nil, fmt.Errorf("errorAs expects error as argument 1")
+			}
+			example := args[1]
+			for cur := errVal; cur != nil; cur = cur.CauseOrNil {
+				if cur.DetailOrNil != nil && sameDynamicType(cur.DetailOrNil, example) {
+					return []Value{cur.DetailOrNil, newBoolVal(true)}, nil, nil
+				}
+			}
+			return []Value{example, newBoolVal(false)}, nil, nil
+		},
+	},
+	// compose(f, g, h)는 h, g, f 순으로 적용하는 함수를 리턴함. compose(f, g)(x) == f(g(x))
+	// 다중 값을 리턴하는 함수의 결과는 다음 함수의 인자로 펼쳐짐
+	"compose": {
+		Name: "compose",
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if len(args) == 0 {
+				return nil, nil, fmt.Errorf("compose expects at least 1 argument")
+			}
+			for i, arg := range args {
+				if !isCallable(arg) {
+					return nil, nil, fmt.Errorf("compose expects function as argument %d", i+1)
+				}
+			}
+			fns := append([]Value{}, args...)
+			composed := BuiltinFunc{
+				Name: "compose",
+				Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+					values := args
+					for i := len(fns) - 1; i >= 0; i-- {
+						var ctrlSig *ControlSignal
+						var err error
+						if i < len(fns)-1 {
+							values = packArgsFor(fns[i], values)
+						}
+						values, ctrlSig, err = e.callValue(fns[i], values)
+						if err != nil || ctrlSig != nil {
+							return nil, ctrlSig, err
+						}
+					}
+					return values, nil, nil
+				},
+			}
+			return []Value{newBuiltinFuncVal(composed)}, nil, nil
+		},
+	},
+}
+
+func isCallable(v Value) bool {
+	kind := v.Kind()
+	return kind == ClosureKind || kind == BuiltinFuncKind || kind == BoundMethodKind
+}
+
+func errorArg(name string, args []Value) (*ErrorValue, error) {
+	if err := expectArgCount(name, args, 1); err != nil {
+		return nil, err
+	}
+	errVal, ok := args[0].(*ErrorValue)
+	if !ok {
+		return nil, fmt.Errorf("%s expects error", name)
+	}
+	return errVal, nil
 }
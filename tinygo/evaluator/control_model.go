@@ -3,6 +3,8 @@ package evaluator
 type ControlSignal struct {
 	Kind   ControlKind
 	Values []Value
+	// CtrlTailCall일 때 호출할 함수 값. Values는 그 인자들임
+	CalleeOrNil Value
 }
 type ControlKind int
 
@@ -13,6 +15,12 @@ const (
 	// 런타임에서 호스트가 발생시킨 에러는 ControlSignal이 아닌 error리턴으로 처리됨
 	//CtrlPanic은 사용자가 panic()문으로 발생시킨 패닉에 대한 것임
 	CtrlPanic
+	// return f(x) 처럼 꼬리 위치의 호출은 호출하지 않고 함수 바깥으로 전달함
+	// callClosure가 현재 콜프레임을 버린 뒤 이어서 호출하므로 꼬리 재귀가 호스트 스택을 쌓지 않음
+	CtrlTailCall
+	// exit(code)는 panic처럼 모든 함수를 빠져나가 프로그램을 끝냄. Values[0]이 종료 코드임
+	// 제너레이터 코루틴, 힙 스택 모드의 continuation 등 진행 중이던 실행은 신호가 지나가며 정리됨
+	CtrlExit
 )
 
 func newControlSignal(kind ControlKind, values []Value) *ControlSignal {
@@ -22,3 +30,11 @@ func newControlSignal(kind ControlKind, values []Value) *ControlSignal {
 func newPanicSignal(values []Value) *ControlSignal {
 	return newControlSignal(CtrlPanic, values)
 }
+
+func newTailCallSignal(callee Value, args []Value) *ControlSignal {
+	return &ControlSignal{Kind: CtrlTailCall, Values: args, CalleeOrNil: callee}
+}
+
+func newExitSignal(code int64) *ControlSignal {
+	return newControlSignal(CtrlExit, []Value{newIntVal(code)})
+}
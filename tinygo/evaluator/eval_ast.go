@@ -12,7 +12,8 @@ func (e *Evaluator) evalFuncDecl(funcDecl parser.FuncDecl) error {
 	// callFrame의 env에 자신의 value값을 채울 떄에, Value의 closedEnv에 자기자신을 포함시킬 것
 	// 전체적으로 리졸버와 동일하게 스코핑-동작하기
 	// (ShortDecl, VarDecl같은 익명함수 대입 시엔, 함수의 closedEnv에 자기자신이 들어가지 못함)
-	closure := newClosureVal(&funcDecl.Id, funcDecl.ParamsOrNil, funcDecl.ReturnTypesOrNil, funcDecl.Block, e.CurrentEnv())
+	closure := e.capturingClosure(newClosureVal(&funcDecl.Id, funcDecl.ParamsOrNil, funcDecl.ReturnTypesOrNil, funcDecl.Block, e.CurrentEnv(), funcDecl.IsGenerator))
+	closure.TypeParams = funcDecl.TypeParamsOrNil
 	return e.setValueForId(funcDecl.Id, closure)
 }
 func (e *Evaluator) evalCallStmt(callStmt parser.CallStmt) (*ControlSignal, error) {
@@ -35,42 +36,56 @@ func (e *Evaluator) evalBlock(block parser.Block, reuseCurrentEnv bool) (*Contro
 		defer e.popEnvFrame()
 	}
 	for _, stmt := range block.StmtsOrNil {
-		var ctrlSig *ControlSignal
-		var err error
-		switch node := stmt.(type) {
-		case *parser.Assign:
-			ctrlSig, err = e.evalAssign(node)
-		case *parser.CallStmt:
-			ctrlSig, err = e.evalCallStmt(*node)
-		case *parser.ShortDecl:
-			ctrlSig, err = e.evalShortDecl(node)
-		case *parser.VarDecl:
-			ctrlSig, err = e.evalVarDecl(node)
-		case *parser.FuncDecl:
-			err = e.evalFuncDecl(*node)
-		case *parser.Return:
-			ctrlSig, err = e.evalReturn(node)
-		case *parser.Break:
-			ctrlSig = newControlSignal(CtrlBreak, nil)
-		case *parser.Continue:
-			ctrlSig = newControlSignal(CtrlContinue, nil)
-		case *parser.If:
-			ctrlSig, err = e.EvalIf(*node)
-		case *parser.ForBexp:
-			ctrlSig, err = e.EvalForBexp(*node)
-		case *parser.ForWithAssign:
-			ctrlSig, err = e.EvalForWithAssign(*node)
-		case *parser.Block:
-			ctrlSig, err = e.evalBlock(*node, false)
-		default:
-			err = fmt.Errorf("unknown stmt node: %T", stmt)
-		}
+		ctrlSig, err := e.evalStmt(stmt)
 		if err != nil || ctrlSig != nil {
 			return ctrlSig, err
 		}
 	}
 	return nil, nil
 }
+
+func (e *Evaluator) evalStmt(stmt parser.Stmt) (*ControlSignal, error) {
+	switch node := stmt.(type) {
+	case *parser.Assign:
+		return e.evalAssign(node)
+	case *parser.CallStmt:
+		return e.evalCallStmt(*node)
+	case *parser.TryStmt:
+		// 전파되지 않은 경우 남는 값은 버림
+		_, ctrlSig, err := e.ValuateTry(node.Try)
+		return ctrlSig, err
+	case *parser.ShortDecl:
+		return e.evalShortDecl(node)
+	case *parser.VarDecl:
+		return e.evalVarDecl(node)
+	case *parser.FuncDecl:
+		return nil, e.evalFuncDecl(*node)
+	case *parser.Return:
+		return e.evalReturn(node)
+	case *parser.Break:
+		return newControlSignal(CtrlBreak, nil), nil
+	case *parser.Continue:
+		return newControlSignal(CtrlContinue, nil), nil
+	case *parser.If:
+		return e.EvalIf(*node)
+	case *parser.ForBexp:
+		return e.EvalForBexp(*node)
+	case *parser.ForWithAssign:
+		return e.EvalForWithAssign(*node)
+	case *parser.ForRange:
+		return e.EvalForRange(node)
+	case *parser.Yield:
+		return e.evalYield(node)
+	case *parser.TypeSwitch:
+		return e.EvalTypeSwitch(node)
+	case *parser.Switch:
+		return e.EvalSwitch(node)
+	case *parser.Block:
+		return e.evalBlock(*node, false)
+	default:
+		return nil, fmt.Errorf("unknown stmt node: %T", stmt)
+	}
+}
 func (e *Evaluator) EvalIf(ifNode parser.If) (*ControlSignal, error) {
 	e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
 	defer e.popEnvFrame()
@@ -149,15 +164,14 @@ func (e *Evaluator) EvalForWithAssign(node parser.ForWithAssign) (*ControlSignal
 			case CtrlBreak:
 				return nil, nil
 			case CtrlContinue:
-				ctrlSig, err = e.evalAssign(&node.Assign)
-				if err != nil || ctrlSig != nil {
-					return ctrlSig, err
-				}
-				continue
 			default:
 				return ctrlSig, nil
 			}
 		}
+		// go 1.22와 같이 다음 반복의 변수는 후처리문 직전에 이전 반복의 값으로 새로 만들어짐
+		if node.LoopVarsCaptured {
+			e.renewLoopEnv()
+		}
 		ctrlSig, err = e.evalAssign(&node.Assign)
 		if err != nil || ctrlSig != nil {
 			return ctrlSig, err
@@ -165,38 +179,257 @@ func (e *Evaluator) EvalForWithAssign(node parser.ForWithAssign) (*ControlSignal
 	}
 }
 
-func (e *Evaluator) evalAssign(assign *parser.Assign) (*ControlSignal, error) {
+// renewLoopEnv는 루프 헤더의 환경을 같은 값을 가진 새 환경으로 바꿈
+// 이전 반복에서 만든 클로저는 이전 환경을 잡고 있으므로, 이후 반복의 대입이 보이지 않음
+func (e *Evaluator) renewLoopEnv() {
+	env := e.CurrentEnv()
+	slots := make([]Value, len(env.Slots))
+	copy(slots, env.Slots)
+	e.callStack.setMostCurrentEnv(&EnvFrame{Slots: slots, ParentEnvFrame: env.ParentEnvFrame})
+}
 
-	values, ctrlSig, err := e.evalExprsAsSingles(assign.Exprs)
+// EvalTypeSwitch는 주어진 값의 동적 타입과 일치하는 첫 절을 실행함
+// 일치하는 절이 없으면 default 절을 실행함
+func (e *Evaluator) EvalTypeSwitch(node *parser.TypeSwitch) (*ControlSignal, error) {
+	values, ctrlSig, err := e.Valuate(node.Subject)
 	if err != nil || ctrlSig != nil {
 		return ctrlSig, err
 	}
-	for i, id := range assign.Ids {
-		if err := e.setValueForId(id, values[i]); err != nil {
+	subject, err := expectSingle(values, "type switch")
+	if err != nil {
+		return nil, err
+	}
+	matched := e.typeSwitchClause(node, subject)
+	if matched == nil {
+		return nil, nil
+	}
+	// 리졸버와 동일하게 절마다 새 스코프. 바인딩은 그 스코프의 첫 슬롯에 위치함
+	e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
+	defer e.popEnvFrame()
+	if node.BindingOrNil != nil {
+		if err := e.setValueForId(*node.BindingOrNil, subject); err != nil {
 			return nil, err
 		}
 	}
+	ctrlSig, err = e.evalBlock(matched.Block, true)
+	if err != nil {
+		return nil, err
+	}
+	// switch 안의 break는 switch만 빠져나감
+	if ctrlSig != nil && ctrlSig.Kind == CtrlBreak {
+		return nil, nil
+	}
+	return ctrlSig, nil
+}
+
+// typeSwitchClause는 subject의 동적 타입과 일치하는 첫 절을 찾음. 없으면 default 절이고, 그것도 없으면 nil임
+func (e *Evaluator) typeSwitchClause(node *parser.TypeSwitch, subject Value) *parser.TypeCaseClause {
+	var matched *parser.TypeCaseClause
+	for i, clause := range node.Clauses {
+		if clause.IsDefault {
+			if matched == nil {
+				matched = &node.Clauses[i]
+			}
+			continue
+		}
+		if e.anyTypeMatches(subject, clause.Types) {
+			return &node.Clauses[i]
+		}
+	}
+	return matched
+}
+
+func (e *Evaluator) EvalSwitch(node *parser.Switch) (*ControlSignal, error) {
+	// 리졸버와 동일하게 init 문장을 위한 switch 스코프
+	e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
+	defer e.popEnvFrame()
+
+	if node.ShortDeclOrNil != nil {
+		ctrlSig, err := e.evalShortDecl(node.ShortDeclOrNil)
+		if err != nil || ctrlSig != nil {
+			return ctrlSig, err
+		}
+	}
+	var tagOrNil Value
+	if node.TagOrNil != nil {
+		values, ctrlSig, err := e.Valuate(node.TagOrNil)
+		if err != nil || ctrlSig != nil {
+			return ctrlSig, err
+		}
+		tagOrNil, err = expectSingle(values, "switch tag")
+		if err != nil {
+			return nil, err
+		}
+	}
+	// go와 같이 case 식은 위에서 아래로, 왼쪽에서 오른쪽으로 평가하며, default는 맞는 case가 없을 때만 선택됨
+	matched := -1
+	defaultIndex := -1
+	for i, clause := range node.Clauses {
+		if clause.IsDefault {
+			defaultIndex = i
+			continue
+		}
+		ok, ctrlSig, err := e.caseMatches(tagOrNil, clause.Exprs)
+		if err != nil || ctrlSig != nil {
+			return ctrlSig, err
+		}
+		if ok {
+			matched = i
+			break
+		}
+	}
+	if matched < 0 {
+		matched = defaultIndex
+	}
+	if matched < 0 {
+		return nil, nil
+	}
+	for i := matched; i < len(node.Clauses); i++ {
+		// 절마다 새 스코프
+		ctrlSig, err := e.evalBlock(node.Clauses[i].Block, false)
+		if err != nil {
+			return nil, err
+		}
+		// switch 안의 break는 switch만 빠져나감
+		if ctrlSig != nil {
+			if ctrlSig.Kind == CtrlBreak {
+				return nil, nil
+			}
+			return ctrlSig, nil
+		}
+		// fallthrough는 다음 절의 case 식을 평가하지 않고 본문으로 넘어감
+		if !node.Clauses[i].Fallthrough {
+			break
+		}
+	}
 	return nil, nil
 }
 
+// caseMatches는 case 식 중 하나라도 tag와 같은지 검사함. tag가 없으면 case 식 자체가 참인지 검사함
+func (e *Evaluator) caseMatches(tagOrNil Value, exprs []parser.Expr) (bool, *ControlSignal, error) {
+	for _, expr := range exprs {
+		if tagOrNil == nil {
+			cond, ctrlSig, err := e.evalBoolExpr(expr)
+			if err != nil || ctrlSig != nil {
+				return false, ctrlSig, err
+			}
+			if cond {
+				return true, nil, nil
+			}
+			continue
+		}
+		values, ctrlSig, err := e.Valuate(expr)
+		if err != nil || ctrlSig != nil {
+			return false, ctrlSig, err
+		}
+		eq, err := caseEqual(tagOrNil, values)
+		if err != nil || eq {
+			return eq, nil, err
+		}
+	}
+	return false, nil, nil
+}
+
+// caseEqual은 평가된 case 식의 값이 tag와 같은지 검사함
+func caseEqual(tag Value, values []Value) (bool, error) {
+	caseVal, err := expectSingle(values, "switch case")
+	if err != nil {
+		return false, err
+	}
+	eq, ok := equalValues(tag, caseVal)
+	if !ok {
+		return false, fmt.Errorf("invalid case %s in switch on %s (mismatched or incomparable types)", caseVal.Inspect(), tag.Inspect())
+	}
+	return eq, nil
+}
+
+func (e *Evaluator) anyTypeMatches(v Value, types []parser.Type) bool {
+	for _, t := range types {
+		if e.valueHasType(v, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Evaluator) evalAssign(assign *parser.Assign) (*ControlSignal, error) {
+
+	values, ctrlSig, err := e.evalExprsForTargets(assign.Exprs, len(assign.Ids))
+	if err != nil || ctrlSig != nil {
+		return ctrlSig, err
+	}
+	return nil, e.assignValues(assign, values)
+}
+
+// assignValues는 평가된 우변의 값들을 대입문의 대상들에 대입함
+func (e *Evaluator) assignValues(assign *parser.Assign, values []Value) error {
+	for i, id := range assign.Ids {
+		path := assign.FieldPathAt(i)
+		if len(path) == 0 {
+			if err := e.setValueForId(id, values[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		// p.x.y = v는 p에 x.y만 바뀐 새 struct를 대입하는 것과 같음
+		root, err := e.valueForId(&id)
+		if err != nil {
+			return err
+		}
+		updated, err := assignFieldPath(root, path, values[i])
+		if err != nil {
+			return err
+		}
+		if err := e.setValueForId(id, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignFieldPath는 path를 따라 내려가며 마지막 필드만 바뀐 struct 사본들을 만들어 리턴함
+func assignFieldPath(object Value, path []parser.Id, value Value) (Value, error) {
+	structVal, ok := object.(*StructValue)
+	if !ok {
+		return nil, fmt.Errorf("field assign expects struct, got %s", object.Inspect())
+	}
+	index := structVal.fieldIndex(path[0].Name)
+	if index < 0 {
+		return nil, fmt.Errorf("%s has no field or method %s", structVal.TypeName, path[0].Name)
+	}
+	if len(path) == 1 {
+		return structVal.withField(index, value), nil
+	}
+	inner, err := assignFieldPath(structVal.Fields[index], path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	return structVal.withField(index, inner), nil
+}
+
 func (e *Evaluator) evalShortDecl(shortDecl *parser.ShortDecl) (*ControlSignal, error) {
 
-	values, ctrlSig, err := e.evalExprsAsSingles(shortDecl.Exprs)
+	values, ctrlSig, err := e.evalExprsForTargets(shortDecl.Exprs, len(shortDecl.Ids))
 	if err != nil || ctrlSig != nil {
 		return ctrlSig, err
 	}
-	for i, id := range shortDecl.Ids {
+	return nil, e.setValuesForIds(shortDecl.Ids, values)
+}
+
+// setValuesForIds는 선언된 이름들에 값을 차례로 대입함
+func (e *Evaluator) setValuesForIds(ids []parser.Id, values []Value) error {
+	for i, id := range ids {
 		if err := e.setValueForId(id, values[i]); err != nil {
-			return nil, err
+			return err
 		}
 	}
-	return nil, nil
+	return nil
 }
 
 func (e *Evaluator) evalVarDecl(node *parser.VarDecl) (*ControlSignal, error) {
 
 	if len(node.ExprsOrNil) == 0 {
-		zero := ZeroValueForType(node.Type)
+		zero := e.ZeroValueForType(node.Type)
 		for _, id := range node.Ids {
 			if err := e.setValueForId(id, zero); err != nil {
 				return nil, err
@@ -204,22 +437,20 @@ func (e *Evaluator) evalVarDecl(node *parser.VarDecl) (*ControlSignal, error) {
 		}
 		return nil, nil
 	}
-	values, ctrlSig, err := e.evalExprsAsSingles(node.ExprsOrNil)
+	values, ctrlSig, err := e.evalExprsForTypedTargets(node.ExprsOrNil, len(node.Ids), &node.Type)
 	if err != nil || ctrlSig != nil {
 		return ctrlSig, err
 	}
-	for i, id := range node.Ids {
-		if err := e.setValueForId(id, values[i]); err != nil {
-			return nil, err
-		}
-	}
-	return nil, nil
+	return nil, e.setValuesForIds(node.Ids, values)
 }
 
 func (e *Evaluator) evalReturn(node *parser.Return) (*ControlSignal, error) {
 	if len(node.ExprsOrNil) == 0 {
 		return newControlSignal(CtrlReturn, []Value{}), nil
 	}
+	if call, ok := node.ExprsOrNil[0].(*parser.Call); ok && len(node.ExprsOrNil) == 1 {
+		return e.evalTailCall(call)
+	}
 	values, ctrlSig, err := e.evalExprsAsSingles(node.ExprsOrNil)
 	if err != nil || ctrlSig != nil {
 		return ctrlSig, err
@@ -227,6 +458,32 @@ func (e *Evaluator) evalReturn(node *parser.Return) (*ControlSignal, error) {
 	return newControlSignal(CtrlReturn, values), nil
 }
 
+// evalTailCall은 return f(x)의 f와 인자들만 평가하고, 호출은 꼬리 호출 신호로 callClosure에 맡김
+// 빌트인처럼 콜프레임을 만들지 않는 호출은 바로 호출함
+func (e *Evaluator) evalTailCall(call *parser.Call) (*ControlSignal, error) {
+	callee, args, ctrlSig, err := e.valuateCallee(call, nil)
+	if err != nil || ctrlSig != nil {
+		return ctrlSig, err
+	}
+	if tailCall := tailCallOf(callee, args); tailCall != nil {
+		return tailCall, nil
+	}
+	values, ctrlSig, err := e.callValue(callee, args)
+	if err != nil || ctrlSig != nil {
+		return ctrlSig, err
+	}
+	return newControlSignal(CtrlReturn, values), nil
+}
+
+// tailCallOf는 callee가 콜프레임을 만드는 함수라면 꼬리 호출 신호를 리턴하고, 아니면 nil을 리턴함
+func tailCallOf(callee Value, args []Value) *ControlSignal {
+	switch callee.(type) {
+	case *ClosureValue, *BoundMethodValue:
+		return newTailCallSignal(callee, args)
+	}
+	return nil
+}
+
 func (e *Evaluator) evalExprsAsSingles(exprs []parser.Expr) ([]Value, *ControlSignal, error) {
 	values := make([]Value, 0, len(exprs))
 	for _, expr := range exprs {
@@ -239,20 +496,61 @@ func (e *Evaluator) evalExprsAsSingles(exprs []parser.Expr) ([]Value, *ControlSi
 	return values, nil, nil
 }
 
+// evalExprsForTargets는 대입 대상이 targetCount개일 때의 우변을 평가함
+// v, ok := x.(T) 처럼 두 대상에 타입 단언 하나가 대입되면 comma-ok 형태로 평가함
+// 대상 개수와 같은 길이의 튜플 하나가 대입되면 이를 풀어 각 대상에 대입함
+func (e *Evaluator) evalExprsForTargets(exprs []parser.Expr, targetCount int) ([]Value, *ControlSignal, error) {
+	return e.evalExprsForTypedTargets(exprs, targetCount, nil)
+}
+
+// evalExprsForTypedTargets는 var 선언처럼 대상의 타입이 명시된 경우의 우변을 평가함
+// var r (int, error) = f() 처럼 튜플 타입 대상 하나에 여러 값이 대입되면 튜플로 묶음
+func (e *Evaluator) evalExprsForTypedTargets(exprs []parser.Expr, targetCount int, typeOrNil *parser.Type) ([]Value, *ControlSignal, error) {
+	if targetCount == 2 && len(exprs) == 1 {
+		if assert, ok := exprs[0].(*parser.TypeAssert); ok {
+			return e.ValuateTypeAssert(assert, true)
+		}
+	}
+	values, ctrlSig, err := e.evalExprsAsSingles(exprs)
+	if err != nil || ctrlSig != nil {
+		return nil, ctrlSig, err
+	}
+	values, err = targetValues(values, targetCount, typeOrNil)
+	return values, nil, err
+}
+
+// targetValues는 평가된 우변의 값들을 대상 개수에 맞춰 튜플로 묶거나 풂
+func targetValues(values []Value, targetCount int, typeOrNil *parser.Type) ([]Value, error) {
+	if typeOrNil != nil && targetCount == 1 {
+		values = packTuple(values, *typeOrNil)
+	}
+	values = spreadTuple(values, targetCount)
+	if len(values) != targetCount {
+		return nil, fmt.Errorf("assignment mismatch: %d variables but %d values", targetCount, len(values))
+	}
+	return values, nil
+}
+
 func (e *Evaluator) evalBoolExpr(expr parser.Expr) (bool, *ControlSignal, error) {
 	values, ctrlSig, err := e.Valuate(expr)
 	if err != nil || ctrlSig != nil {
 		return false, ctrlSig, err
 	}
+	cond, err := condValue(values)
+	return cond, nil, err
+}
+
+// condValue는 평가된 조건식의 값을 bool로 꺼냄
+func condValue(values []Value) (bool, error) {
 	val, err := expectSingle(values, "condition")
 	if err != nil {
-		return false, nil, err
+		return false, err
 	}
 	boolVal, ok := val.(*BoolValue)
 	if !ok {
-		return false, nil, fmt.Errorf("condition expects bool")
+		return false, fmt.Errorf("condition expects bool")
 	}
-	return boolVal.Value, nil, nil
+	return boolVal.Value, nil
 }
 
 func (e *Evaluator) setValueForId(id parser.Id, value Value) error {
@@ -1,9 +1,13 @@
 package evaluator
 
 import (
+	"errors"
+	"runtime/debug"
 	"testing"
+	"testing/fstest"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/loader"
 	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
 	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
 )
@@ -171,6 +175,953 @@ func TestEvalMain_BuiltinInterop(t *testing.T) {
 	}
 }
 
+func TestEvalMain_StructLiteralAndFieldAccess(t *testing.T) {
+	input := "type Point struct { x int; y int; } var sum int = 0; func main(){ p := Point{x: 1, y: 2}; sum = p.x + p.y; var z Point; z.x = 5; sum = sum + z.x + z.y; }"
+	e, pkg := evalMainFromInput(t, input)
+	sumVal := getGlobalValue(t, e, pkg, "sum").(*IntValue)
+	if sumVal.Value != 8 {
+		t.Fatalf("expected sum=8, got %v", sumVal.Inspect())
+	}
+}
+
+func TestEvalMain_StructValueSemantics(t *testing.T) {
+	input := "type Inner struct { v int; } type Outer struct { in Inner; n int; } var a int = 0; var b int = 0; var g Outer; func main(){ o := Outer{in: Inner{v: 1}, n: 2}; c := o; c.in.v = 10; a = o.in.v; b = c.in.v; g = c; }"
+	e, pkg := evalMainFromInput(t, input)
+	aVal := getGlobalValue(t, e, pkg, "a").(*IntValue)
+	bVal := getGlobalValue(t, e, pkg, "b").(*IntValue)
+	if aVal.Value != 1 || bVal.Value != 10 {
+		t.Fatalf("expected a=1 b=10, got %d %d", aVal.Value, bVal.Value)
+	}
+	gVal := getGlobalValue(t, e, pkg, "g")
+	if gVal.Inspect() != "Outer{in: Inner{v: 10}, n: 2}" {
+		t.Fatalf("unexpected struct inspect: %s", gVal.Inspect())
+	}
+}
+
+func TestEvalMain_StructEqualityAndZeroValue(t *testing.T) {
+	input := "type Point struct { x int; y int; } var p Point; var same bool = false; var diff bool = false; func main(){ same = p == Point{}; diff = Point{x: 1} != Point{x: 1, y: 0}; }"
+	e, pkg := evalMainFromInput(t, input)
+	if p := getGlobalValue(t, e, pkg, "p"); p.Inspect() != "Point{x: 0, y: 0}" {
+		t.Fatalf("unexpected zero value: %s", p.Inspect())
+	}
+	sameVal := getGlobalValue(t, e, pkg, "same").(*BoolValue)
+	diffVal := getGlobalValue(t, e, pkg, "diff").(*BoolValue)
+	if !sameVal.Value || diffVal.Value {
+		t.Fatalf("expected same=true diff=false, got %v %v", sameVal.Value, diffVal.Value)
+	}
+}
+
+func TestEvalMain_StructUnknownField_Error(t *testing.T) {
+	input := "type Point struct { x int; } func main(){ p := Point{x: 1}; a := p.z; }"
+	_, err := evalMainExpectError(t, input)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if err.Error() != "Point has no field or method z" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEvalMain_MethodCall(t *testing.T) {
+	input := "type Point struct { x int; y int; } func (p Point) Sum() int { return p.x + p.y; } func (p Point) Add(d int) Point { p.x = p.x + d; return p; } var r int = 0; var orig int = 0; func main(){ p := Point{x: 1, y: 2}; q := p.Add(10); r = q.Sum(); orig = p.x; }"
+	e, pkg := evalMainFromInput(t, input)
+	rVal := getGlobalValue(t, e, pkg, "r").(*IntValue)
+	origVal := getGlobalValue(t, e, pkg, "orig").(*IntValue)
+	if rVal.Value != 13 || origVal.Value != 1 {
+		t.Fatalf("expected r=13 orig=1, got %d %d", rVal.Value, origVal.Value)
+	}
+}
+
+func TestEvalMain_InterfaceDynamicDispatch(t *testing.T) {
+	input := "type Shape interface { Area() int; } type Rect struct { w int; h int; } type Square struct { s int; } func (r Rect) Area() int { return r.w * r.h; } func (s Square) Area() int { return s.s * s.s; } func total(a Shape, b Shape) int { return a.Area() + b.Area(); } var sum int = 0; func main(){ var s Shape; s = Rect{w: 2, h: 3}; sum = total(s, Square{s: 4}); }"
+	e, pkg := evalMainFromInput(t, input)
+	sumVal := getGlobalValue(t, e, pkg, "sum").(*IntValue)
+	if sumVal.Value != 22 {
+		t.Fatalf("expected sum=22, got %v", sumVal.Inspect())
+	}
+}
+
+func TestEvalMain_TypeAssertion(t *testing.T) {
+	input := "type Shape interface { Area() int; } type Named interface { Name() string; } type Rect struct { w int; h int; } func (r Rect) Area() int { return r.w * r.h; } var w int = 0; var isRect bool = false; var isNamed bool = true; func main(){ var s Shape; s = Rect{w: 2, h: 3}; w = s.(Rect).w; rr, matched := s.(Rect); isRect = matched; nn, named := s.(Named); isNamed = named; }"
+	e, pkg := evalMainFromInput(t, input)
+	wVal := getGlobalValue(t, e, pkg, "w").(*IntValue)
+	isRect := getGlobalValue(t, e, pkg, "isRect").(*BoolValue)
+	isNamed := getGlobalValue(t, e, pkg, "isNamed").(*BoolValue)
+	if wVal.Value != 2 || !isRect.Value || isNamed.Value {
+		t.Fatalf("expected w=2 isRect=true isNamed=false, got %d %v %v", wVal.Value, isRect.Value, isNamed.Value)
+	}
+}
+
+func TestEvalMain_TypeAssertion_FailurePanics(t *testing.T) {
+	input := "type Rect struct { w int; } func main(){ a := 1; r := a.(Rect); }"
+	_, err := evalMainExpectError(t, input)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if err.Error() != "interface conversion: value is int, not Rect" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEvalMain_TypeSwitch(t *testing.T) {
+	input := "type Shape interface { Area() int; } type Rect struct { w int; h int; } func (r Rect) Area() int { return r.w * r.h; } var out string = \"\"; func describe(v Shape) string { switch x := v.(type) { case int, bool: return \"basic\"; case Rect: if x.w == 0 { break; } return \"rect\"; default: return \"other\"; } return \"empty rect\"; } func main(){ out = describe(1) + \",\" + describe(Rect{w: 1}) + \",\" + describe(Rect{}) + \",\" + describe(\"s\"); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	if outVal.Value != "basic,rect,empty rect,other" {
+		t.Fatalf("unexpected type switch result: %s", outVal.Value)
+	}
+}
+
+func TestEvalMain_StringsModule(t *testing.T) {
+	input := "var out string = \"\"; var idx int = 0; var has bool = false; var n int = 0; func main(){ parts := strings.Split(strings.TrimSpace(\"  a,b,c \"), \",\"); n = len(parts); out = strings.Join([]string{strings.ToUpper(parts[0]), strings.Repeat(parts[1], 2), strings.Substring(\"xcy\", 1, 2)}, \"-\") + strings.ToLower(\"!Q\") + strings.Trim(\"__z__\", \"_\"); idx = strings.Index(out, \"c\"); has = strings.Contains(out, \"bb\"); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	if outVal.Value != "A-bb-c!qz" {
+		t.Fatalf("unexpected strings result: %s", outVal.Value)
+	}
+	idxVal := getGlobalValue(t, e, pkg, "idx").(*IntValue)
+	hasVal := getGlobalValue(t, e, pkg, "has").(*BoolValue)
+	nVal := getGlobalValue(t, e, pkg, "n").(*IntValue)
+	if idxVal.Value != 5 || !hasVal.Value || nVal.Value != 3 {
+		t.Fatalf("expected idx=5 has=true n=3, got %d %v %d", idxVal.Value, hasVal.Value, nVal.Value)
+	}
+}
+
+func TestEvalMain_StrconvModule(t *testing.T) {
+	input := "var sum int = 0; var msg string = \"\"; var s string = \"\"; func main(){ a, err := strconv.Atoi(\"41\"); if err == ok { sum = a + 1; } b, err2 := strconv.Atoi(\"x1\"); msg = errString(err2); sum = sum + b; s = strconv.Itoa(sum * 10); }"
+	e, pkg := evalMainFromInput(t, input)
+	sumVal := getGlobalValue(t, e, pkg, "sum").(*IntValue)
+	msgVal := getGlobalValue(t, e, pkg, "msg").(*StringValue)
+	sVal := getGlobalValue(t, e, pkg, "s").(*StringValue)
+	if sumVal.Value != 42 || sVal.Value != "420" {
+		t.Fatalf("expected sum=42 s=420, got %d %s", sumVal.Value, sVal.Value)
+	}
+	if msgVal.Value != "strconv.Atoi: parsing \"x1\": invalid syntax" {
+		t.Fatalf("unexpected Atoi error: %s", msgVal.Value)
+	}
+}
+
+func TestEvalMain_FmtSprintf(t *testing.T) {
+	input := "type Point struct { x int; y int; } var out string = \"\"; func main(){ out = fmt.Sprintf(\"%d %s %t %q %v %v 100%%\", 7, \"go\", true, \"q\", Point{x: 1, y: 2}, []int{1, 2}); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	if outVal.Value != "7 go true \"q\" Point{x: 1, y: 2} [1 2] 100%" {
+		t.Fatalf("unexpected Sprintf result: %s", outVal.Value)
+	}
+}
+
+func TestEvalMain_BuiltinModule_Errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "sprintf_missing_argument",
+			input: "func main(){ s := fmt.Sprintf(\"%d %d\", 1); }",
+			want:  "fmt.Sprintf: missing argument for %d",
+		},
+		{
+			name:  "sprintf_wrong_type",
+			input: "func main(){ s := fmt.Sprintf(\"%d\", \"a\"); }",
+			want:  "fmt.Sprintf: %d expects int, got string",
+		},
+		{
+			name:  "substring_out_of_range",
+			input: "func main(){ s := strings.Substring(\"abc\", 2, 5); }",
+			want:  "slice bounds out of range [2:5] with length 3",
+		},
+		{
+			name:  "index_out_of_range",
+			input: "func main(){ xs := strings.Split(\"a\", \",\"); s := xs[1]; }",
+			want:  "index out of range [1] with length 1",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := evalMainExpectError(t, tc.input)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if err.Error() != tc.want {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEvalMain_Tuples(t *testing.T) {
+	input := "var sum int = 0; var first int = 0; var msg string = \"\"; var same bool = false; var shown string = \"\"; " +
+		"func pair() (int, int) { return 3, 4; } " +
+		"func add(a int, b int) int { return a + b; } " +
+		"func swap(t (int, int)) (int, int) { return t[1], t[0]; } " +
+		"func pass(r (int, error)) ((int, error)) { return r; } " +
+		"func parse(s string) ((int, error)) { return strconv.Atoi(s); } " +
+		"func main(){ sum = add(pair()); t := (1, \"a\"); first = t[0]; x, y := swap(pair()); sum = sum * 100 + x * 10 + y; " +
+		"var r (int, error) = strconv.Atoi(\"x\"); n, err := pass(r); msg = errString(err); first = first + n; " +
+		"v, err2 := parse(\"7\"); same = (v, err2) == (7, ok); shown = fmt.Sprintf(\"%v\", t); }"
+	e, pkg := evalMainFromInput(t, input)
+	sumVal := getGlobalValue(t, e, pkg, "sum").(*IntValue)
+	if sumVal.Value != 743 {
+		t.Fatalf("expected sum=743, got %d", sumVal.Value)
+	}
+	firstVal := getGlobalValue(t, e, pkg, "first").(*IntValue)
+	msgVal := getGlobalValue(t, e, pkg, "msg").(*StringValue)
+	if firstVal.Value != 1 || msgVal.Value != "strconv.Atoi: parsing \"x\": invalid syntax" {
+		t.Fatalf("unexpected tuple pass-through: %d %s", firstVal.Value, msgVal.Value)
+	}
+	sameVal := getGlobalValue(t, e, pkg, "same").(*BoolValue)
+	shownVal := getGlobalValue(t, e, pkg, "shown").(*StringValue)
+	if !sameVal.Value || shownVal.Value != "(1, a)" {
+		t.Fatalf("expected same=true shown=(1, a), got %v %s", sameVal.Value, shownVal.Value)
+	}
+}
+
+func TestEvalMain_TryPropagation(t *testing.T) {
+	input := "type Point struct { x int; y int; } var sum int = 0; var msg string = \"\"; var zero bool = false; var checked string = \"\"; " +
+		"func parsePoint(a string, b string) (Point, error) { x := strconv.Atoi(a)?; y := strconv.Atoi(b)?; return Point{x: x, y: y}, ok; } " +
+		"func check(n int) error { if n < 0 { return newError(\"negative\"); } return ok; } " +
+		"func checkAll(a int, b int) (string, error) { check(a)?; check(b)?; return \"fine\", ok; } " +
+		"func viaTuple(r (int, error)) (int, error) { n := r?; return n * 2, ok; } " +
+		"func main(){ p, err := parsePoint(\"3\", \"4\"); sum = p.x + p.y; q, err2 := parsePoint(\"1\", \"y\"); msg = errString(err2); zero = q == Point{x: 0, y: 0}; " +
+		"s, err3 := checkAll(1, -1); checked = s + errString(err3); d, err4 := viaTuple(strconv.Atoi(\"21\")); sum = sum * 100 + d; }"
+	e, pkg := evalMainFromInput(t, input)
+	sumVal := getGlobalValue(t, e, pkg, "sum").(*IntValue)
+	if sumVal.Value != 742 {
+		t.Fatalf("expected sum=742, got %d", sumVal.Value)
+	}
+	msgVal := getGlobalValue(t, e, pkg, "msg").(*StringValue)
+	zeroVal := getGlobalValue(t, e, pkg, "zero").(*BoolValue)
+	if msgVal.Value != "strconv.Atoi: parsing \"y\": invalid syntax" || !zeroVal.Value {
+		t.Fatalf("expected propagated Atoi error with zero Point, got %q %v", msgVal.Value, zeroVal.Value)
+	}
+	checkedVal := getGlobalValue(t, e, pkg, "checked").(*StringValue)
+	if checkedVal.Value != "negative" {
+		t.Fatalf("expected checked=negative, got %q", checkedVal.Value)
+	}
+}
+
+func TestEvalMain_RichErrors(t *testing.T) {
+	input := "type ParseErr struct { line int; } var ErrNotFound error = newError(\"not found\"); " +
+		"var msg string = \"\"; var isNotFound bool = false; var sameText bool = true; var unwrapped bool = false; var line int = 0; var found bool = true; var okWrap bool = false; " +
+		"func find(key string) (int, error) { if key == \"a\" { return 1, ok; } return 0, ErrNotFound; } " +
+		"func load(key string) (int, error) { n, err := find(key); if err != ok { return 0, wrapError(err, \"load \" + key); } return n, ok; } " +
+		"func parse() error { return wrapError(errorWith(\"bad line\", ParseErr{line: 7}), \"parse\"); } " +
+		"func main(){ n, err := load(\"b\"); msg = errString(wrapError(err, \"main\")); isNotFound = errorIs(err, ErrNotFound); " +
+		"sameText = newError(\"not found\") == ErrNotFound; unwrapped = unwrap(err) == ErrNotFound && unwrap(ErrNotFound) == ok; " +
+		"detail, has := errorAs(parse(), ParseErr{}); line = detail.line; if has { line = line * 10; } " +
+		"p, found2 := errorAs(err, ParseErr{}); found = found2; okWrap = wrapError(ok, \"ctx\") == ok && errorIs(ok, ok); }"
+	e, pkg := evalMainFromInput(t, input)
+	msgVal := getGlobalValue(t, e, pkg, "msg").(*StringValue)
+	if msgVal.Value != "main: load b: not found" {
+		t.Fatalf("unexpected wrapped message: %q", msgVal.Value)
+	}
+	isNotFound := getGlobalValue(t, e, pkg, "isNotFound").(*BoolValue)
+	sameText := getGlobalValue(t, e, pkg, "sameText").(*BoolValue)
+	unwrapped := getGlobalValue(t, e, pkg, "unwrapped").(*BoolValue)
+	if !isNotFound.Value || sameText.Value || !unwrapped.Value {
+		t.Fatalf("expected isNotFound=true sameText=false unwrapped=true, got %v %v %v", isNotFound.Value, sameText.Value, unwrapped.Value)
+	}
+	lineVal := getGlobalValue(t, e, pkg, "line").(*IntValue)
+	foundVal := getGlobalValue(t, e, pkg, "found").(*BoolValue)
+	okWrap := getGlobalValue(t, e, pkg, "okWrap").(*BoolValue)
+	if lineVal.Value != 70 || foundVal.Value || !okWrap.Value {
+		t.Fatalf("expected line=70 found=false okWrap=true, got %d %v %v", lineVal.Value, foundVal.Value, okWrap.Value)
+	}
+}
+
+func TestEvalMain_ExprSwitch(t *testing.T) {
+	input := "var out string = \"\"; var trace string = \"\"; var loops int = 0; " +
+		"func digit(i int) string { switch i { case 0: return \"zero\"; case 1, 2, 3: return \"small\"; default: return \"big\"; } } " +
+		"func sign(n int) string { switch { case n < 0: return \"-\"; case n > 0: return \"+\"; } return \"0\"; } " +
+		"func mark(s string) bool { trace = trace + s; return true; } " +
+		"func main(){ out = digit(0) + \",\" + digit(2) + \",\" + digit(9) + \",\" + sign(-3) + sign(0) + sign(5); " +
+		"switch n := 2; n { case 1: trace = trace + \"one\"; case 2: trace = trace + \"two\"; fallthrough; case 3: trace = trace + \"three\"; break; trace = trace + \"never\"; case 4: trace = trace + \"four\"; } " +
+		"switch { default: trace = trace + \"|d\"; case mark(\"|a\"): trace = trace + \"|A\"; } " +
+		"for i := 0; i < 5; i = i + 1; { switch i { case 1: continue; case 3: break; } loops = loops + 1; } }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	if outVal.Value != "zero,small,big,-0+" {
+		t.Fatalf("unexpected switch result: %s", outVal.Value)
+	}
+	traceVal := getGlobalValue(t, e, pkg, "trace").(*StringValue)
+	if traceVal.Value != "twothree|a|A" {
+		t.Fatalf("unexpected switch trace: %s", traceVal.Value)
+	}
+	// case 3의 break는 switch만 빠져나가고, case 1의 continue는 루프로 전달됨
+	loopsVal := getGlobalValue(t, e, pkg, "loops").(*IntValue)
+	if loopsVal.Value != 4 {
+		t.Fatalf("expected loops=4, got %d", loopsVal.Value)
+	}
+}
+
+func TestEvalMain_SumTypesAndMatch(t *testing.T) {
+	input := "type Shape = Circle(r int) | Rect(w int, h int) | Empty; " +
+		"type Opt = Some(s Shape) | None; " +
+		"var out string = \"\"; var zero Shape; var same bool = false; " +
+		"func area(s Shape) int { return match s { Circle(r) => 3 * r * r, Rect(w, h) if w == h => w * w, Rect(w, h) => w * h, Empty => 0, }; } " +
+		"func label(n int) string { return match n { 0 => \"zero\", -1 => \"minus\", k if k > 100 => \"big\", _ => \"other\" }; } " +
+		"func inner(o Opt) string { return match o { Some(Circle(_)) => \"circle\", Some(_) => \"shape\", None => \"none\" }; } " +
+		"func main(){ out = fmt.Sprintf(\"%d %d %d %d\", area(Circle(2)), area(Rect(3, 3)), area(Rect(2, 5)), area(Empty)); " +
+		"out = out + \" \" + label(0) + \",\" + label(-1) + \",\" + label(500) + \",\" + label(7); " +
+		"out = out + \" \" + inner(Some(Circle(1))) + \",\" + inner(Some(Rect(1, 2))) + \",\" + inner(None); " +
+		"out = out + \" \" + fmt.Sprintf(\"%v\", Rect(1, 2)) + \",\" + fmt.Sprintf(\"%v\", zero); " +
+		"same = Circle(1) == Circle(1) && Circle(1) != Circle(2) && Empty == Empty; }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "12 9 10 0 zero,minus,big,other circle,shape,none Rect(1, 2),Empty"
+	if outVal.Value != want {
+		t.Fatalf("unexpected match result:\n got %s\nwant %s", outVal.Value, want)
+	}
+	sameVal := getGlobalValue(t, e, pkg, "same").(*BoolValue)
+	if !sameVal.Value {
+		t.Fatalf("expected structural equality of variants")
+	}
+}
+
+func TestEvalMain_PipeAndCompose(t *testing.T) {
+	input := "type Box struct { n int; } func (b Box) Add(k int) int { return b.n + k; } " +
+		"var out string = \"\"; var cmp bool = false; " +
+		"func inc(n int) int { return n + 1; } func double(n int) int { return n * 2; } " +
+		"func add(a int, b int) int { return a + b; } func pair() (int, int) { return 3, 4; } " +
+		"func main(){ a := 1 + 2 |> inc |> double; b := 5 |> add(10); c := pair() |> add; " +
+		"d := \"ab\" |> strings.ToUpper |> strings.Repeat(2); e := 3 |> Box{n: 1}.Add; " +
+		"f := compose(double, inc); g := compose(strconv.Itoa, add); h := 7 |> (compose(inc, inc, double)); " +
+		"k := 4 |> func(n int) int { return n * n; }; " +
+		"out = fmt.Sprintf(\"%d %d %d %s %d %d %s %d %d\", a, b, c, d, e, f(4), g(pair()), h, k); " +
+		"cmp = 2 |> inc == 3 && \"x\" |> len < 2; }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "8 15 7 ABAB 4 10 7 16 16"
+	if outVal.Value != want {
+		t.Fatalf("unexpected pipe result:\n got %s\nwant %s", outVal.Value, want)
+	}
+	cmpVal := getGlobalValue(t, e, pkg, "cmp").(*BoolValue)
+	if !cmpVal.Value {
+		t.Fatalf("expected |> to bind tighter than comparison")
+	}
+}
+
+func TestEvalMain_GeneratorsAndRange(t *testing.T) {
+	input := "var out string = \"\"; var produced int = 0; var finished bool = false; " +
+		"func count(n int) { for i := 0; i < n; i = i + 1; { produced = produced + 1; yield i; } finished = true; } " +
+		"func evens(n int) { for v := range count(n) { if v / 2 * 2 != v { continue; } yield v; } } " +
+		"func pairs(s string) { for i, c := range s { yield i, c; } } " +
+		"func firstOver(limit int) int { for v := range count(100) { if v > limit { return v; } } return -1; } " +
+		"func main(){ " +
+		"for v := range count(5) { if v == 2 { break; } out = out + strconv.Itoa(v); } " +
+		"out = out + fmt.Sprintf(\" %d %t\", produced, finished); " +
+		"g := evens(7); for v := range g { out = out + \" \" + strconv.Itoa(v); } for v := range g { out = out + \" \" + strconv.Itoa(v); } " +
+		"for i, c := range pairs(\"ab\") { out = out + fmt.Sprintf(\" %d:%d\", i, c); } " +
+		"sq := func(n int) { for i := range n { yield i * i; } }; for _, x := range []int{1, 3} { for v := range sq(x) { out = out + \" \" + strconv.Itoa(v); } } " +
+		"n := 0; for range 4 { n = n + 1; } out = out + fmt.Sprintf(\" %d %d\", n, firstOver(6)); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	// break 이후엔 제너레이터 본문이 더 진행되지 않으며, 같은 제너레이터 값은 처음부터 다시 순회됨
+	want := "01 3 false 0 2 4 6 0 2 4 6 0:97 1:98 0 0 1 4 4 7"
+	if outVal.Value != want {
+		t.Fatalf("unexpected generator result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_CollectionBuiltins(t *testing.T) {
+	input := "type P struct { name string; age int; } var out string = \"\"; var calls int = 0; " +
+		"func double(n int) int { return n * 2; } " +
+		"func main(){ xs := []int{3, 1, 4, 1, 5}; " +
+		"a := seq.Map(xs, double); b := xs |> seq.Filter(func(n int) bool { return n > 1; }); " +
+		"c := seq.Reduce(xs, func(acc int, n int) int { return acc + n; }, 0); " +
+		"d := seq.FlatMap([]int{1, 2}, func(n int) []int { return []int{n, n * 10}; }); " +
+		"e := seq.Zip(xs, []string{\"a\", \"b\"}); f := seq.Map(e, func(n int, s string) string { return strings.Repeat(s, n); }); " +
+		"ps := []P{P{name: \"kim\", age: 30}, P{name: \"lee\", age: 20}, P{name: \"park\", age: 30}}; " +
+		"g := seq.Map(seq.SortBy(ps, func(p P) int { return p.age; }), func(p P) string { return p.name; }); " +
+		"h := seq.GroupBy(xs, func(n int) bool { return n > 2; }); " +
+		"var i []string = seq.Map(xs, strconv.Itoa); " +
+		"j := seq.Any(xs, func(n int) bool { calls = calls + 1; return n == 4; }); k := seq.All(xs, func(n int) bool { return n > 1; }); " +
+		"out = fmt.Sprintf(\"%v %v %d %v %v %v %v %v %v %t %t %d\", a, b, c, d, e, f, g, h, i, j, k, calls); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	// seq.Any는 결과가 정해지면 나머지 원소를 검사하지 않음
+	want := "[6 2 8 2 10] [3 4 5] 14 [1 10 2 20] [(3, a) (1, b)] [aaa b] [lee kim park] [(true, [3 4 5]) (false, [1 1])] [3 1 4 1 5] true false 3"
+	if outVal.Value != want {
+		t.Fatalf("unexpected collection result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_PersistentCollections(t *testing.T) {
+	input := "var out string = \"\"; " +
+		"func main(){ v := coll.Vector(1, 2, 3); w := coll.Conj(v, 4); u := coll.Assoc(w, 0, 10); " +
+		"m := coll.HashMap(\"a\", 1, \"b\", 2); m2 := coll.Assoc(m, \"c\", 3); m3 := coll.Dissoc(m2, \"a\", \"zz\"); " +
+		"x, has := coll.Get(m3, \"a\"); y, found := coll.Get(u, 1); " +
+		"sum := 0; for i, n := range u { sum = sum + i * n; } " +
+		"keys := 0; for k, n := range m2 { keys = keys + n; } " +
+		"big := seq.Reduce([]int{1, 2, 3, 4}, coll.Conj, coll.Vector()); " +
+		"key := coll.HashMap(coll.Vector(1, 2), \"pair\", (1, \"x\"), \"tuple\"); " +
+		"out = fmt.Sprintf(\"%v %v %v %d %d %v %v %v %v %t %v %t %d %d %t %t %v %v\", " +
+		"v, w, u, len(u), len(m2), m, m2, m3, x, has, y, found, sum, keys, " +
+		"big == coll.Vector(1, 2, 3, 4), coll.Dissoc(m2, \"c\") == m, key[coll.Vector(1, 2)], key[(1, \"x\")]); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	// 갱신은 새 값을 만들 뿐 원래 v, m은 그대로임
+	want := "vector[1 2 3] vector[1 2 3 4] vector[10 2 3 4] 4 3 hashMap[a:1 b:2] hashMap[a:1 b:2 c:3] hashMap[b:2 c:3] nil false 2 true 20 6 true true pair tuple"
+	if outVal.Value != want {
+		t.Fatalf("unexpected persistent collection result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_ConstAndLet(t *testing.T) {
+	input := "const ( Red = iota; Green; Blue; ) const Name = \"rgb\" + \"!\"; const Limit int = Blue * 10; " +
+		"var out string = \"\"; var scaled int = Limit + 1; " +
+		"func main(){ let a, b = Green, Name; let f = func(n int) int { return n + Limit; }; " +
+		"out = fmt.Sprintf(\"%d %d %s %d %d %t\", a, Blue, b, scaled, f(1), Limit == 20); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "1 2 rgb! 21 21 true"
+	if outVal.Value != want {
+		t.Fatalf("unexpected const result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_Generics(t *testing.T) {
+	input := "type Number interface { int; } type Ordered interface { int | string; } " +
+		"type Named interface { Name() string; } type P struct { n string; } func (p P) Name() string { return p.n; } " +
+		"func Map[T, U any](xs []T, f func(T) U) []U { return seq.Map(xs, f); } " +
+		"func Sum[T Number](xs []T) T { var total T; for _, x := range xs { total = total + x; } return total; } " +
+		"func Twice[T Ordered](v T) T { return v + v; } " +
+		"func Index[K comparable](xs []K, k K) int { for i, x := range xs { if x == k { return i; } } return -1; } " +
+		"func Zero[T any]() T { var z T; return z; } " +
+		"func Names[T Named](xs []T) string { s := \"\"; for _, x := range xs { s = s + x.Name(); } return s; } " +
+		"func Const[T any](v T) func() T { return func() T { return v; }; } " +
+		"var out string = \"\"; " +
+		"func main(){ strs := Map([]int{1, 2, 3}, func(n int) string { return strconv.Itoa(n * 2); }); " +
+		"toStr := Map[int, string]; again := toStr([]int{7}, strconv.Itoa); " +
+		"out = fmt.Sprintf(\"%s %s %d %d %s %d %d %s %d %s %s %d\", strings.Join(strs, \",\"), again[0], Sum([]int{1, 2, 3}), Twice(3), Twice(\"ab\"), " +
+		"Index([]string{\"x\", \"y\"}, \"y\"), Zero[int](), Zero[string]() + \"!\", len(Zero[[]int]()), Names([]P{P{n: \"a\"}, P{n: \"b\"}}), Const(\"c\")(), len(Map([]string{\"a\"}, Const))); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "2,4,6 7 6 6 abab 1 0 ! 0 ab c 1"
+	if outVal.Value != want {
+		t.Fatalf("unexpected generics result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_TailCalls(t *testing.T) {
+	// 꼬리 호출은 콜프레임을 쌓지 않으므로, 호스트 스택을 작게 묶어도 백만 번의 재귀도 끝까지 실행됨
+	defer debug.SetMaxStack(debug.SetMaxStack(16 << 20))
+	input := "type Counter struct { step int; } " +
+		"func (c Counter) Count(n int, acc int) int { if n == 0 { return acc; } return c.Count(n - 1, acc + c.step); } " +
+		"func loop(n int, acc int) int { if n == 0 { return acc; } return loop(n - 1, acc + 1); } " +
+		"func isEven(n int) bool { if n == 0 { return true; } return isOdd(n - 1); } " +
+		"func isOdd(n int) bool { if n == 0 { return false; } return isEven(n - 1); } " +
+		"func find(n int) (int, error) { if n == 0 { return 7, ok; } switch { case n > 0: return find(n - 1); } return 0, ok; } " +
+		"func sum(n int) int { if n == 0 { return 0; } return n + sum(n - 1); } " +
+		"var out string = \"\"; " +
+		"func main(){ c := Counter{step: 2}; var down func(int) int; down = func(n int) int { if n == 0 { return 42; } return down(n - 1); }; " +
+		"v, err := find(100000); " +
+		"out = fmt.Sprintf(\"%d %t %t %d %d %d %t %d\", loop(1000000, 0), isEven(100000), isOdd(99999), down(100000), c.Count(100000, 0), v, err == ok, sum(100)); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "1000000 true true 42 200000 7 true 5050"
+	if outVal.Value != want {
+		t.Fatalf("unexpected tail call result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_HeapStack(t *testing.T) {
+	// 힙 스택 모드에선 꼬리 호출이 아닌 깊은 재귀도 작은 호스트 스택 위에서 끝까지 실행됨
+	defer debug.SetMaxStack(debug.SetMaxStack(16 << 20))
+	input := "func sum(n int) int { if n == 0 { return 0; } return n + sum(n - 1); } " +
+		"func depth(n int) int { if n == 0 { return 0; } for true { break; } r := 0; " +
+		"for i := 0; i < 2; i = i + 1; { if i == 0 { continue; } r = depth(n - 1) + 1; } return r; } " +
+		"var out string = \"\"; " +
+		"func main(){ out = fmt.Sprintf(\"%d %d\", sum(50000), depth(30000)); }"
+	e, pkg := buildEvaluatorFromInput(t, input)
+	e.UseHeapStack(0)
+	if err := e.EvalMainFunc(); err != nil {
+		t.Fatalf("EvalMainFunc error: %v", err)
+	}
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "1250025000 30000"
+	if outVal.Value != want {
+		t.Fatalf("unexpected heap stack result:\n got %s\nwant %s", outVal.Value, want)
+	}
+
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "unbounded_recursion",
+			input: "func f(n int) int { return 1 + f(n + 1); } func main(){ n := f(0); }",
+			want:  "stack overflow: call depth exceeds 5000",
+		},
+		{
+			name:  "panic_from_deep_frame",
+			input: "func dive(n int) int { if n == 0 { panic(\"bottom\"); } return 1 + dive(n - 1); } func main(){ n := dive(4000); }",
+			want:  "panic: bottom",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, _ := buildEvaluatorFromInput(t, tc.input)
+			e.UseHeapStack(5000)
+			err := e.EvalMainFunc()
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("expected error %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestEvalMain_HeapStackMatchesDefault(t *testing.T) {
+	// 힙 스택 모드는 호출이 있는 문장과 식을 machine으로 실행하므로, 구문마다 호출을 넣어 기본 모드와 같은 결과인지 확인함
+	input := "type Shape = Circle(r int) | Rect(w int, h int) | Empty; " +
+		"type Box struct { n int; inner Inner; } type Inner struct { k int; } " +
+		"func (b Box) Add(k int) int { return b.n + k; } " +
+		"func id(n int) int { return n; } func yes() bool { return true; } func anyOf(v interface{}) interface{} { return v; } " +
+		"func twice(n int) (int, error) { m := strconv.Atoi(strconv.Itoa(id(n)))?; return m * 2, ok; } " +
+		"func fails() (int, error) { n := strconv.Atoi(\"x\")?; return n, ok; } " +
+		"func area(s Shape) int { return match id(1) { 1 => match s { Circle(r) => id(3) * r * r, Rect(w, h) if id(w) == h => w * w, Rect(w, h) => w * h, Empty => 0 }, _ => -1 }; } " +
+		"func gen(n int) { for i := 0; i < id(n); i = i + id(1); { yield id(i) * 10; } } " +
+		"func kind(v interface{}) string { switch x := anyOf(v).(type) { case int: if x == id(0) { break; } return \"int\" + strconv.Itoa(id(x)); case Box: return \"box\"; } return \"zero\"; } " +
+		"func grade(n int) string { s := \"\"; switch id(n) { case id(1): s = s + \"one\"; fallthrough; case id(2): s = s + \"two\"; case 3: s = s + \"three\"; break; s = s + \"never\"; default: s = s + \"many\"; } return s; } " +
+		"func loop(n int, acc int) int { if n == 0 { return acc; } return loop(n - 1, acc + id(1)); } " +
+		"func depth(n int) int { if id(n) == 0 { return 0; } return depth(n - 1) + 1; } " +
+		"var out string = \"\"; " +
+		"func main() { b := Box{n: id(1), inner: Inner{k: id(7)}}; b.inner.k = id(8); total := 0; " +
+		"for i := range id(4) { if i == id(1) { continue; } if i == id(3) { break; } total = total + id(i); } " +
+		"for _, v := range coll.Vector(id(1), 2, 3) { total = total + v; } " +
+		"for k, v := range coll.HashMap(\"a\", id(10)) { total = total + v + len(k); } " +
+		"for v := range gen(3) { if v == id(20) { break; } total = total + v; } " +
+		"for i, _ := range \"ab\" { total = total + id(i); } " +
+		"n := 0; for n < id(5) { n = n + id(1); } " +
+		"xs := []int{id(1), id(2)}; tp := (id(3), \"t\"); m, err := twice(id(4)); fv, failed := fails(); total = total + fv; " +
+		"bx, found := anyOf(b).(Box); s := b.Add(id(2)) |> id; q := id(5) |> b.Add; " +
+		"r := yes() && id(1) == 1 || id(0) == 1; var w (int, error) = twice(id(1)); " +
+		"if c := id(2); c > id(1) { total = total + 100; } else { total = total - 100; } " +
+		"out = fmt.Sprintf(\"%d %d %d %d %d %t %t %d %d %t %d %d %d %d %s %s %s %s %s %s %d %d %t\", " +
+		"total, n, xs[id(1)], tp[0], m, err == ok, failed == ok, bx.inner.k, b.inner.k, found, s, q, area(Circle(id(2))), area(Rect(id(3), 3)) + area(Rect(2, id(5))), " +
+		"kind(id(4)), kind(0), kind(b), grade(1), grade(3), grade(id(9)), loop(100000, 0), depth(3000), r && w[1] == ok); }"
+	want := "130 5 2 3 8 true false 8 8 true 3 6 12 19 int4 zero box onetwo three many 100000 3000 true"
+	for _, heapStack := range []bool{false, true} {
+		e, pkg := buildEvaluatorFromInput(t, input)
+		if heapStack {
+			e.UseHeapStack(0)
+		}
+		if err := e.EvalMainFunc(); err != nil {
+			t.Fatalf("EvalMainFunc error (heap stack %t): %v", heapStack, err)
+		}
+		outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+		if outVal.Value != want {
+			t.Fatalf("unexpected result (heap stack %t):\n got %s\nwant %s", heapStack, outVal.Value, want)
+		}
+	}
+}
+
+func TestEvalMain_PerIterationLoopVars(t *testing.T) {
+	// 클로저와 나중에 실행되는 제너레이터는 각자 자기 반복의 변수를 잡음
+	input := "var out string = \"\"; " +
+		"func main(){ fs := coll.Vector(); gens := coll.Vector(); " +
+		"for i := 0; i < 3; i = i + 1; { fs = coll.Conj(fs, func() int { return i; }); gens = coll.Conj(gens, func() { yield i * 10; }); } " +
+		"names := coll.Vector(); for _, s := range []string{\"a\", \"b\"} { names = coll.Conj(names, func() string { return s; }); } " +
+		"steps := 0; for i := 0; i < 5; i = i + 1; { bump := func() { i = i + 1; }; bump(); steps = steps + 1; } " +
+		"lazy := 0; for v := range gens[2]() { lazy = v; } " +
+		"out = fmt.Sprintf(\"%d%d%d %s%s %d %d\", fs[0](), fs[1](), fs[2](), names[0](), names[1](), steps, lazy); }"
+	e, pkg := evalMainFromInput(t, input)
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	want := "012 ab 3 20"
+	if outVal.Value != want {
+		t.Fatalf("unexpected loop var result:\n got %s\nwant %s", outVal.Value, want)
+	}
+}
+
+func TestEvalMain_Tuples_Errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "assignment_mismatch",
+			input: "func pair() (int, int) { return 1, 2; } func main(){ a, b, c := pair(); }",
+			want:  "assignment mismatch: 3 variables but 2 values",
+		},
+		{
+			name:  "multi_value_in_arg_list",
+			input: "func pair() (int, int) { return 1, 2; } func add(a int, b int, c int) int { return a + b + c; } func main(){ n := add(pair(), 3); }",
+			want:  "call arg expects single value",
+		},
+		{
+			name:  "try_on_non_error",
+			input: "func f() (int, error) { n := len(\"ab\")?; return n, ok; } func main(){ a, b := f(); }",
+			want:  "? expects error as last value, got int",
+		},
+		{
+			name:  "wrap_non_error",
+			input: "func main(){ e := wrapError(\"x\", \"ctx\"); }",
+			want:  "wrapError expects error as argument 1",
+		},
+		{
+			name:  "switch_case_type_mismatch",
+			input: "func main(){ switch 1 { case \"a\": } }",
+			want:  "invalid case a in switch on 1 (mismatched or incomparable types)",
+		},
+		{
+			name:  "variant_field_type_mismatch",
+			input: "type Shape = Circle(r int) | Empty; func main(){ s := Circle(\"x\"); }",
+			want:  "cannot use string as int in field r of Circle",
+		},
+		{
+			name:  "match_value_of_other_type",
+			input: "type Shape = Circle(r int) | Empty; func main(){ var x interface{} = 3; n := match x { Circle(r) => r, Empty => 0 }; }",
+			want:  "no match arm matched value 3",
+		},
+		{
+			name:  "pipe_into_non_function",
+			input: "func main(){ n := 1 |> 2; }",
+			want:  "call target is not callable",
+		},
+		{
+			name:  "compose_non_function",
+			input: "func inc(n int) int { return n + 1; } func main(){ f := compose(inc, 1); }",
+			want:  "compose expects function as argument 2",
+		},
+		{
+			name:  "map_over_non_slice",
+			input: "func double(n int) int { return n * 2; } func main(){ xs := seq.Map(1, double); }",
+			want:  "seq.Map expects slice as argument 1",
+		},
+		{
+			name:  "filter_with_non_function",
+			input: "func main(){ xs := seq.Filter([]int{1}, 2); }",
+			want:  "seq.Filter expects function as argument 2",
+		},
+		{
+			name:  "filter_predicate_not_bool",
+			input: "func main(){ xs := seq.Filter([]int{1}, func(n int) int { return n; }); }",
+			want:  "seq.Filter expects predicate to return bool, got int",
+		},
+		{
+			name:  "sortBy_unordered_key",
+			input: "func main(){ xs := seq.SortBy([]int{1, 2}, func(n int) bool { return n > 1; }); }",
+			want:  "seq.SortBy expects int or string keys, got bool",
+		},
+		{
+			name:  "panic_inside_map_function",
+			input: "func main(){ xs := seq.Map([]int{1, 2}, func(n int) int { if n == 2 { panic(\"boom\"); } return n; }); }",
+			want:  "panic: boom",
+		},
+		{
+			name:  "range_over_bool",
+			input: "func main(){ for v := range true { } }",
+			want:  "cannot range over bool",
+		},
+		{
+			name:  "range_mismatch",
+			input: "func count() { yield 1; } func main(){ for a, b := range count() { } }",
+			want:  "range mismatch: 2 variables but 1 values",
+		},
+		{
+			name:  "panic_inside_generator",
+			input: "func boom() { yield 1; panic(\"boom\"); } func main(){ for v := range boom() { } }",
+			want:  "panic: boom",
+		},
+		{
+			name:  "tuple_index_out_of_range",
+			input: "func main(){ t := (1, 2); n := t[2]; }",
+			want:  "index out of range [2] with length 2",
+		},
+		{
+			name:  "hashMap_odd_args",
+			input: "func main(){ m := coll.HashMap(\"a\", 1, \"b\"); }",
+			want:  "coll.HashMap expects key, value pairs",
+		},
+		{
+			name:  "unhashable_key",
+			input: "func main(){ m := coll.HashMap([]int{1}, 1); }",
+			want:  "unhashable type []int",
+		},
+		{
+			name:  "assoc_vector_out_of_range",
+			input: "func main(){ v := coll.Assoc(coll.Vector(1), 3, 0); }",
+			want:  "coll.Assoc index out of range [3] with length 1",
+		},
+		{
+			name:  "conj_on_slice",
+			input: "func main(){ v := coll.Conj([]int{1}, 2); }",
+			want:  "coll.Conj expects vector as argument 1",
+		},
+		{
+			name:  "missing_hashMap_key",
+			input: "func main(){ m := coll.HashMap(\"a\", 1); n := m[\"b\"]; }",
+			want:  "key b not found in hashMap",
+		},
+		{
+			name:  "instantiated_generic_arg_mismatch",
+			input: "func Pair[T any](a T, b T) T { return a; } func main(){ f := Pair[int]; var x interface{} = \"s\"; n := f(x, 1); }",
+			want:  "type string of argument 1 does not match inferred type int for T",
+		},
+		{
+			name:  "generic_func_value_constraint",
+			input: "type Number interface { int; } func Dbl[T Number](v T) T { return v + v; } func apply(f func(string) string) string { return f(\"a\"); } func main(){ s := apply(Dbl); }",
+			want:  "string does not satisfy Number",
+		},
+		{
+			name:  "comparable_dynamic_slice",
+			input: "func Eq[K comparable](a K, b K) bool { return a == b; } func main(){ var x interface{} = []int{1}; n := Eq(x, x); }",
+			want:  "[]int does not satisfy comparable",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := evalMainExpectError(t, tc.input)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if err.Error() != tc.want {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuiltinModules_MatchResolver(t *testing.T) {
+	for _, module := range resolver.BuiltinModules {
+		members, ok := builtinModuleByName(module.Name)
+		if !ok {
+			t.Fatalf("missing builtin module implementation: %s", module.Name)
+		}
+		if len(members) != len(module.Members) {
+			t.Fatalf("member count mismatch for %s: resolver %d, evaluator %d", module.Name, len(module.Members), len(members))
+		}
+		for _, member := range module.Members {
+			if _, ok := members[member.Name]; !ok {
+				t.Fatalf("missing builtin implementation: %s.%s", module.Name, member.Name)
+			}
+		}
+	}
+}
+
+func TestEvalProgram_MultiPackage(t *testing.T) {
+	files := map[string]string{
+		"main.tgo":       "package main; import \"geo\"; var area int = 0; var trace string = \"\"; var m string = geo.Record(\"main\"); func main() { p := geo.NewRect(2, 3); area = p.Area() + geo.Unit; trace = geo.Trace; }",
+		"geo/rect.tgo":   "package geo; type Rect struct { w int; h int; } func NewRect(w int, h int) Rect { return Rect{w: w, h: h}; } func (r Rect) Area() int { return scale(r.w * r.h); }",
+		"geo/record.tgo": "package geo; var Trace string; var Unit int = scale(1); var Log string = Record(\"geo\"); func Record(s string) string { Trace = Trace + s + \",\"; return s; } func scale(n int) int { return n * 10; }",
+	}
+	e, pkg := evalProgramFromFiles(t, files)
+	areaVal := getGlobalValue(t, e, pkg, "area").(*IntValue)
+	if areaVal.Value != 70 {
+		t.Fatalf("expected area=70, got %d", areaVal.Value)
+	}
+	// 임포트된 패키지의 전역이 먼저 초기화되어야 함
+	traceVal := getGlobalValue(t, e, pkg, "trace").(*StringValue)
+	if traceVal.Value != "geo,main," {
+		t.Fatalf("unexpected init trace: %s", traceVal.Value)
+	}
+}
+
+func TestEvalMain_InitFuncs(t *testing.T) {
+	// 전역 변수 초기화가 모두 끝난 뒤, main 전에 init이 선언 순서대로 실행됨
+	input := "var trace string = \"var\"; func init() { trace = trace + \",init1\"; } " +
+		"func main() { trace = trace + \",main\"; } " +
+		"func init() { trace = trace + \",init2:\" + strconv.Itoa(late); } var late int = 7;"
+	e, pkg := evalMainFromInput(t, input)
+	traceVal := getGlobalValue(t, e, pkg, "trace").(*StringValue)
+	if traceVal.Value != "var,init1,init2:7,main" {
+		t.Fatalf("unexpected init trace: %s", traceVal.Value)
+	}
+}
+
+func TestEvalMain_InitPanic_IsInitFailure(t *testing.T) {
+	input := "var ran bool = false; func init() {} func init() { panic(\"bad config\"); } func main() { ran = true; }"
+	e, pkg := buildEvaluatorFromInput(t, input)
+	err := e.EvalMainFunc()
+	var initErr *InitError
+	if !errors.As(err, &initErr) {
+		t.Fatalf("expected init error, got %v", err)
+	}
+	if err.Error() != "init failed: init #2: panic: bad config" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if getGlobalValue(t, e, pkg, "ran").(*BoolValue).Value {
+		t.Fatalf("main must not run after init failure")
+	}
+}
+
+func TestEvalProgram_InitFuncsRunImportedFirst(t *testing.T) {
+	files := map[string]string{
+		"main.tgo":   "package main; import \"conf\"; var trace string = \"\"; func init() { trace = conf.Trace + \"main,\"; } func main() {}",
+		"conf/a.tgo": "package conf; var Trace string = \"\"; func init() { Trace = Trace + \"a,\"; }",
+		"conf/b.tgo": "package conf; func init() { Trace = Trace + \"b,\"; }",
+	}
+	e, pkg := evalProgramFromFiles(t, files)
+	traceVal := getGlobalValue(t, e, pkg, "trace").(*StringValue)
+	if traceVal.Value != "a,b,main," {
+		t.Fatalf("unexpected init trace: %s", traceVal.Value)
+	}
+}
+
+func TestEvalMain_ArgsAndGetenv(t *testing.T) {
+	t.Setenv("TINYGO_TEST_MODE", "fast")
+	// args()는 전역 변수 초기식에서도 쓸 수 있음
+	input := "var argc int = len(args()); var out string = \"\"; " +
+		"func main() { xs := args(); out = fmt.Sprintf(\"%d %s %s [%s] [%s]\", argc, xs[0], xs[2], getenv(\"TINYGO_TEST_MODE\"), getenv(\"TINYGO_TEST_UNSET\")); }"
+	pkg := parsePackageForEval(t, input)
+	table, hoist, order, builtins, err := resolver.Resolve(pkg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	e, err := NewEvaluatorWithOptions(Options{Args: []string{"tool.tgo", "-v", "in.txt"}}, *pkg, hoist, order, table, builtins)
+	if err != nil {
+		t.Fatalf("new evaluator error: %v", err)
+	}
+	if err := e.EvalMainFunc(); err != nil {
+		t.Fatalf("EvalMainFunc error: %v", err)
+	}
+	outVal := getGlobalValue(t, e, pkg, "out").(*StringValue)
+	if outVal.Value != "3 tool.tgo in.txt [fast] []" {
+		t.Fatalf("unexpected args result: %s", outVal.Value)
+	}
+
+	// 인자 없이 만든 Evaluator의 args()는 빈 슬라이스임
+	e, pkg = evalMainFromInput(t, "var n int = -1; func main() { n = len(args()); }")
+	if n := getGlobalValue(t, e, pkg, "n").(*IntValue).Value; n != 0 {
+		t.Fatalf("expected no args, got %d", n)
+	}
+}
+
+func TestEvalMain_ExitCodes(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		heapStack bool
+		wantCode  int
+		// 종료 후의 전역 trace 값. exit 뒤의 코드는 실행되지 않아야 함
+		wantTrace string
+	}{
+		{
+			name:      "normal_return",
+			input:     "var trace string = \"\"; func main() { trace = \"main\"; }",
+			wantCode:  0,
+			wantTrace: "main",
+		},
+		{
+			name: "exit_from_nested_call",
+			input: "var trace string = \"\"; func stop(code int) { trace = trace + \"stop,\"; exit(code); trace = trace + \"after,\"; } " +
+				"func main() { trace = \"main,\"; for i := 0; i < 3; i = i + 1; { stop(3); } trace = trace + \"end\"; }",
+			wantCode:  3,
+			wantTrace: "main,stop,",
+		},
+		{
+			name:      "exit_zero",
+			input:     "var trace string = \"\"; func main() { exit(0); trace = \"after\"; }",
+			wantCode:  0,
+			wantTrace: "",
+		},
+		{
+			// 루프를 벗어나며 제너레이터 본문도 멈추므로 finished가 붙지 않음
+			name: "exit_inside_generator_range",
+			input: "var trace string = \"\"; func count() { for i := 0; i < 5; i = i + 1; { yield i; } trace = trace + \"finished,\"; } " +
+				"func main() { for v := range count() { trace = trace + strconv.Itoa(v) + \",\"; if v == 1 { exit(4); } } }",
+			wantCode:  4,
+			wantTrace: "0,1,",
+		},
+		{
+			name: "exit_from_generator_body",
+			input: "var trace string = \"\"; func gen() { yield 1; exit(5); yield 2; } " +
+				"func main() { for v := range gen() { trace = trace + strconv.Itoa(v) + \",\"; } trace = trace + \"end\"; }",
+			wantCode:  5,
+			wantTrace: "1,",
+		},
+		{
+			name: "exit_from_callback",
+			input: "var trace string = \"\"; " +
+				"func main() { ys := seq.Map([]int{1, 2, 3}, func(n int) int { if n == 2 { exit(6); } trace = trace + strconv.Itoa(n); return n; }); trace = trace + \"end\"; }",
+			wantCode:  6,
+			wantTrace: "1",
+		},
+		{
+			name:      "exit_from_deep_frame_on_heap_stack",
+			input:     "var trace string = \"\"; func dive(n int) int { if n == 0 { exit(7); } return 1 + dive(n - 1); } func main() { n := dive(3000); trace = \"after\"; }",
+			heapStack: true,
+			wantCode:  7,
+			wantTrace: "",
+		},
+		{
+			// init 안의 exit은 init 실패가 아니며 main은 실행되지 않음
+			name:      "exit_from_init",
+			input:     "var trace string = \"\"; func init() { trace = \"init,\"; exit(0); } func main() { trace = trace + \"main\"; }",
+			wantCode:  0,
+			wantTrace: "init,",
+		},
+		{
+			name:      "uncaught_panic",
+			input:     "var trace string = \"\"; func main() { trace = \"main\"; panic(\"boom\"); }",
+			wantCode:  2,
+			wantTrace: "main",
+		},
+		{
+			name:      "runtime_error",
+			input:     "var trace string = \"\"; func main() { xs := []int{1}; trace = \"main\"; n := xs[3]; }",
+			wantCode:  2,
+			wantTrace: "main",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, pkg := buildEvaluatorFromInput(t, tc.input)
+			if tc.heapStack {
+				e.UseHeapStack(5000)
+			}
+			err := e.EvalMainFunc()
+			if code := ExitCode(err); code != tc.wantCode {
+				t.Fatalf("expected exit code %d, got %d (err: %v)", tc.wantCode, code, err)
+			}
+			if traceVal := getGlobalValue(t, e, pkg, "trace").(*StringValue); traceVal.Value != tc.wantTrace {
+				t.Fatalf("unexpected trace: %q", traceVal.Value)
+			}
+		})
+	}
+}
+
+func TestNewEvaluator_ExitDuringVarInit(t *testing.T) {
+	pkg := parsePackageForEval(t, "func check() int { exit(9); return 1; } var ready int = check(); func main() {}")
+	table, hoist, order, builtins, err := resolver.Resolve(pkg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	_, err = NewEvaluator(*pkg, hoist, order, table, builtins)
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 9 {
+		t.Fatalf("expected exit status 9, got %v", err)
+	}
+}
+
+func evalProgramFromFiles(t *testing.T, files map[string]string) (*Evaluator, *parser.PackageAST) {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, src := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(src)}
+	}
+	pkgs, err := loader.NewLoader(fsys).Load()
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	table, hoist, order, builtins, err := resolver.ResolveProgram(pkgs)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	mainPkg := pkgs[len(pkgs)-1].AST
+	e, err := NewEvaluator(*mainPkg, hoist, order, table, builtins)
+	if err != nil {
+		t.Fatalf("NewEvaluator error: %v", err)
+	}
+	if err := e.EvalMainFunc(); err != nil {
+		t.Fatalf("EvalMainFunc error: %v", err)
+	}
+	return e, mainPkg
+}
+
 func evalMainFromInput(t *testing.T, input string) (*Evaluator, *parser.PackageAST) {
 	t.Helper()
 	e, pkg := buildEvaluatorFromInput(t, input)
@@ -1,6 +1,11 @@
 package evaluator
 
-import "github.com/rlaaudgjs5638/langTest/tinygo/parser"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
 
 type EvalPanic struct {
 	calledFuncId parser.Id
@@ -24,3 +29,40 @@ func NewEvalError(calledFuncId parser.Id, occuredNode parser.Node, errMsg string
 		tailError:    tailError,
 	}
 }
+
+// InitError는 init 함수가 panic이나 런타임 에러로 끝나 프로그램 초기화에 실패한 것임
+type InitError struct {
+	// 실패한 init 함수가 프로그램 전체에서 몇 번째로 실행되었는지. 1부터 셈
+	Index int
+	Err   error
+}
+
+func (e *InitError) Error() string {
+	return fmt.Sprintf("init failed: init #%d: %v", e.Index, e.Err)
+}
+
+func (e *InitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitError는 프로그램이 exit(code)로 끝난 것임. 코드가 0이어도 리턴됨
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode는 EvalMainFunc나 NewEvaluator가 리턴한 err를 프로세스 종료 코드로 바꿈
+// 에러가 없으면 0, exit(code)로 끝났다면 code, 잡히지 않은 panic이나 런타임 에러라면 go와 같이 2임
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit.Code
+	}
+	return 2
+}
@@ -0,0 +1,258 @@
+package evaluator
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// coroutine은 제너레이터 본문을 실행하는 고루틴과 range 루프 사이의 통로임
+// 두 쪽은 번갈아 가며 실행되므로, 전역 환경을 공유해도 동시에 접근하는 일은 없음
+type coroutine struct {
+	// 소비자 -> 제너레이터. true면 다음 값을 요청, false면 순회 중단
+	resume chan bool
+	// 제너레이터 -> 소비자. yield된 값 또는 본문의 종료
+	events chan genEvent
+}
+
+// genEvent는 제너레이터가 소비자에게 보내는 신호임
+// done이 false면 values가 yield된 값이고, true면 본문이 끝났다는 뜻이며 ctrlSig, err에 그 결과가 담김
+type genEvent struct {
+	values  []Value
+	done    bool
+	ctrlSig *ControlSignal
+	err     error
+}
+
+// forkForCoroutine은 제너레이터 본문을 별도 고루틴에서 실행할 Evaluator를 만듦
+// 전역 환경과 선언 정보는 공유하고, 콜스택과 코루틴만 따로 가짐
+func (e *Evaluator) forkForCoroutine(co *coroutine) *Evaluator {
+	child := *e
+	child.callStack = CallStack{callFrames: []CallFrame{}}
+	child.coroutineOrNil = co
+	child.heapStackOrNil = e.heapStackOrNil.fork()
+	return &child
+}
+
+// startGenerator는 제너레이터 본문을 실행할 고루틴을 띄움
+// 본문은 첫 값이 요청될 때 비로소 시작됨
+func (e *Evaluator) startGenerator(g *GeneratorValue) *coroutine {
+	co := &coroutine{resume: make(chan bool), events: make(chan genEvent)}
+	child := e.forkForCoroutine(co)
+	go func() {
+		if !<-co.resume {
+			co.events <- genEvent{done: true}
+			return
+		}
+		_, ctrlSig, err := child.enterClosure(g.Closure, g.Args)
+		co.events <- genEvent{done: true, ctrlSig: ctrlSig, err: err}
+	}()
+	return co
+}
+
+// evalYield는 값을 소비자에게 넘기고, 다음 값이 요청될 때까지 본문 실행을 멈춤
+// 소비자가 순회를 멈췄다면 return처럼 본문을 빠져나감
+func (e *Evaluator) evalYield(node *parser.Yield) (*ControlSignal, error) {
+	values, ctrlSig, err := e.evalExprsAsSingles(node.Exprs)
+	if err != nil || ctrlSig != nil {
+		return ctrlSig, err
+	}
+	return e.yieldValues(values)
+}
+
+// yieldValues는 평가된 값들을 소비자에게 넘기고 다음 요청을 기다림
+func (e *Evaluator) yieldValues(values []Value) (*ControlSignal, error) {
+	if e.coroutineOrNil == nil {
+		return nil, fmt.Errorf("yield outside generator")
+	}
+	e.coroutineOrNil.events <- genEvent{values: values}
+	if !<-e.coroutineOrNil.resume {
+		return newControlSignal(CtrlReturn, []Value{}), nil
+	}
+	return nil, nil
+}
+
+// EvalForRange는 range 대상을 한 번 평가한 뒤 그 종류에 따라 순회함
+func (e *Evaluator) EvalForRange(node *parser.ForRange) (*ControlSignal, error) {
+	values, ctrlSig, err := e.Valuate(node.Expr)
+	if err != nil || ctrlSig != nil {
+		return ctrlSig, err
+	}
+	it, err := e.rangeOver(values)
+	if err != nil {
+		return nil, err
+	}
+	defer it.stop()
+	// 리졸버와 동일하게 range 변수를 위한 루프 스코프
+	e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
+	defer e.popEnvFrame()
+
+	for {
+		values, ok, ctrlSig, err := it.next()
+		if err != nil || ctrlSig != nil || !ok {
+			return ctrlSig, err
+		}
+		if err := e.bindRangeVars(node, values); err != nil {
+			return nil, err
+		}
+		ctrlSig, err = e.evalBlock(node.Block, false)
+		if err != nil {
+			return nil, err
+		}
+		if ctrlSig != nil {
+			switch ctrlSig.Kind {
+			case CtrlBreak:
+				return nil, nil
+			case CtrlContinue:
+			default:
+				return ctrlSig, nil
+			}
+		}
+	}
+}
+
+// rangeIterator는 range 대상의 값들을 반복마다 하나씩 꺼냄
+type rangeIterator struct {
+	// next는 다음 반복에서 range 변수에 대입할 값들을 리턴함
+	// ok가 false면 순회가 끝난 것이며, 제너레이터 본문이 panic, exit으로 끝났다면 그 신호를 함께 리턴함
+	next func() (values []Value, ok bool, ctrlSig *ControlSignal, err error)
+	// stop은 루프를 벗어날 때 부름. 끝나지 않은 제너레이터 본문을 멈춤
+	stop func()
+}
+
+// rangeOver는 평가된 range 대상의 순회자를 만듦
+// int n은 0..n-1, string은 바이트 위치와 룬, slice, vector는 인덱스와 원소, hashMap은 키와 값,
+// 제너레이터는 yield된 값들을 차례로 꺼냄
+func (e *Evaluator) rangeOver(values []Value) (*rangeIterator, error) {
+	subject, err := expectSingle(values, "range")
+	if err != nil {
+		return nil, err
+	}
+	switch v := subject.(type) {
+	case *IntValue:
+		i := int64(0)
+		return indexIterator(func() ([]Value, bool) {
+			if i >= v.Value {
+				return nil, false
+			}
+			i++
+			return []Value{newIntVal(i - 1)}, true
+		}), nil
+	case *StringValue:
+		pos := 0
+		return indexIterator(func() ([]Value, bool) {
+			if pos >= len(v.Value) {
+				return nil, false
+			}
+			// for range와 같이 잘못된 utf-8 바이트는 U+FFFD 하나로 읽음
+			r, size := utf8.DecodeRuneInString(v.Value[pos:])
+			values := []Value{newIntVal(int64(pos)), newIntVal(int64(r))}
+			pos += size
+			return values, true
+		}), nil
+	case *SliceValue:
+		// go와 같이 순회할 원소들은 루프 시작 시점에 정해짐
+		elems := v.Elems
+		i := 0
+		return indexIterator(func() ([]Value, bool) {
+			if i >= len(elems) {
+				return nil, false
+			}
+			i++
+			return []Value{newIntVal(int64(i - 1)), elems[i-1]}, true
+		}), nil
+	case *VectorValue:
+		i := 0
+		return indexIterator(func() ([]Value, bool) {
+			elem, ok := v.Vec.Get(i)
+			if !ok {
+				return nil, false
+			}
+			i++
+			return []Value{newIntVal(int64(i - 1)), elem}, true
+		}), nil
+	case *HashMapValue:
+		// 순회 순서는 키의 해시에 따라 정해짐
+		entries := [][]Value{}
+		v.Map.Each(func(key, value Value) bool {
+			entries = append(entries, []Value{key, value})
+			return true
+		})
+		i := 0
+		return indexIterator(func() ([]Value, bool) {
+			if i >= len(entries) {
+				return nil, false
+			}
+			i++
+			return entries[i-1], true
+		}), nil
+	case *GeneratorValue:
+		return e.generatorIterator(v), nil
+	default:
+		return nil, fmt.Errorf("cannot range over %s", dynamicTypeName(subject))
+	}
+}
+
+// indexIterator는 멈출 것이 없는 순회자를 만듦
+func indexIterator(next func() ([]Value, bool)) *rangeIterator {
+	return &rangeIterator{
+		next: func() ([]Value, bool, *ControlSignal, error) {
+			values, ok := next()
+			return values, ok, nil, nil
+		},
+		stop: func() {},
+	}
+}
+
+// generatorIterator는 제너레이터를 코루틴으로 실행하며 yield된 값들을 꺼내는 순회자를 만듦
+// break, return, 에러 등으로 루프를 벗어나면 stop이 제너레이터 본문도 함께 멈춤
+func (e *Evaluator) generatorIterator(g *GeneratorValue) *rangeIterator {
+	co := e.startGenerator(g)
+	finished := false
+	return &rangeIterator{
+		next: func() ([]Value, bool, *ControlSignal, error) {
+			co.resume <- true
+			event := <-co.events
+			if !event.done {
+				return event.values, true, nil, nil
+			}
+			finished = true
+			if event.err != nil {
+				return nil, false, nil, event.err
+			}
+			// 제너레이터 안의 panic, exit은 루프 바깥으로 전파됨
+			if event.ctrlSig != nil && (event.ctrlSig.Kind == CtrlPanic || event.ctrlSig.Kind == CtrlExit) {
+				return nil, false, event.ctrlSig, nil
+			}
+			return nil, false, nil, nil
+		},
+		stop: func() {
+			if !finished {
+				finished = true
+				co.resume <- false
+				<-co.events
+			}
+		},
+	}
+}
+
+// bindRangeVars는 한 번의 반복에서 range 변수에 값을 대입함
+func (e *Evaluator) bindRangeVars(node *parser.ForRange, values []Value) error {
+	if len(node.Vars) > len(values) {
+		return fmt.Errorf("range mismatch: %d variables but %d values", len(node.Vars), len(values))
+	}
+	// 클로저가 range 변수를 잡는다면 반복마다 새 변수를 만듦
+	if node.LoopVarsCaptured {
+		e.renewLoopEnv()
+	}
+	for i, v := range node.Vars {
+		if v.Name == "_" {
+			continue
+		}
+		if err := e.setValueForId(v, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -17,6 +17,20 @@ type Evaluator struct {
 
 	// 빌트인 함수값들
 	builtInSlots []Value
+	// 타입 선언들. 제로값과 struct 리터럴 평가에 사용
+	typeDecls map[parser.IdId]*parser.TypeDecl
+	// 리시버 타입의 TypeDecl id -> 메서드 이름 -> 메서드 클로저
+	methods map[parser.IdId]map[string]*ClosureValue
+	// 합 타입 변형의 선언 id -> 변형이 속한 TypeDecl과 순번. 매치 시 변형 패턴 판별에 사용
+	variants map[parser.IdId]variantRef
+	// 제너레이터 본문을 실행 중인 Evaluator라면 소비자와 주고받는 코루틴. 아니면 nil
+	coroutineOrNil *coroutine
+	// 힙 스택 모드라면 최대 호출 깊이와 호출 여부 캐시. 기본 모드라면 nil
+	heapStackOrNil *heapStack
+	// init 함수들의 클로저. EvalMainFunc가 main 직전에 차례로 실행함
+	initFuncs []*ClosureValue
+	// args()가 돌려줄 프로그램 인자. go의 os.Args처럼 첫 원소는 프로그램 이름임
+	programArgs []string
 	//디버그 여부
 	debug bool
 }
@@ -31,6 +45,10 @@ type CallStack struct {
 type CallFrame struct {
 	funcIdOrNil *parser.Id
 	currentEnv  *EnvFrame
+	// 호출된 함수의 리턴 타입. ? 로 에러를 전파할 때 나머지 리턴 값의 제로값을 만드는 데 쓰임
+	returnTypes []parser.Type
+	// 본문 안의 타입 매개변수에 묶인 타입 인자들. 제네릭 함수가 아니면 nil
+	typeArgs map[parser.IdId]parser.Type
 }
 
 func (cf *CallFrame) String() string {
@@ -52,11 +70,32 @@ func (cs *CallStack) peekMostCurrentEnv() *EnvFrame {
 	return cs.callFrames[len(cs.callFrames)-1].currentEnv
 }
 
+// peekReturnTypes는 가장 최신 callFrame의 함수 리턴 타입을 리턴한다
+func (cs *CallStack) peekReturnTypes() []parser.Type {
+	return cs.callFrames[len(cs.callFrames)-1].returnTypes
+}
+
 // setMostCurrentEnv는 가장 최신 callFrame의 currentEnv의 값을 바꾼다.
 func (cs *CallStack) setMostCurrentEnv(ef *EnvFrame) {
 	cs.callFrames[len(cs.callFrames)-1].currentEnv = ef
 }
 func NewEvaluator(packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, initOrder resolver.InitOrder, resolveTable resolver.ResolveTable, builtins map[string]int) (*Evaluator, error) {
+	return NewEvaluatorWithOptions(Options{}, packageAst, hoistInfo, initOrder, resolveTable, builtins)
+}
+
+// Options는 Evaluator를 만들 때 전역 변수 초기화 전에 정해져야 하는 실행 설정임
+type Options struct {
+	// args()가 돌려줄 프로그램 인자
+	Args []string
+	// 힙 스택 모드로 실행할지 여부. 전역 변수 초기식의 깊은 재귀도 보호됨
+	HeapStack bool
+	// 힙 스택 모드의 최대 호출 깊이. 0 이하라면 DefaultMaxCallDepth임
+	MaxCallDepth int
+}
+
+// NewEvaluatorWithOptions는 opts대로 설정한 Evaluator를 만듦
+// 전역 변수 초기식도 args()를 부르거나 깊이 재귀할 수 있으므로, 설정은 초기화 전에 정해져야 함
+func NewEvaluatorWithOptions(opts Options, packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, initOrder resolver.InitOrder, resolveTable resolver.ResolveTable, builtins map[string]int) (*Evaluator, error) {
 
 	hoistedFuncDecls := []*parser.FuncDecl{}
 	hoistedVarTypeByIdId := map[parser.IdId]parser.Type{}
@@ -103,6 +142,24 @@ func NewEvaluator(packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, i
 				}
 			}
 		}
+		for _, idId := range hoistInfo.VariantIds() {
+			ref, ok := resolveTable[idId]
+			if !ok {
+				return nil, fmt.Errorf("missing resolve entry for variant")
+			}
+			if ref.Slot > maxGlobalSlot {
+				maxGlobalSlot = ref.Slot
+			}
+		}
+		for _, idId := range hoistInfo.ConstIds() {
+			ref, ok := resolveTable[idId]
+			if !ok {
+				return nil, fmt.Errorf("missing resolve entry for const")
+			}
+			if ref.Slot > maxGlobalSlot {
+				maxGlobalSlot = ref.Slot
+			}
+		}
 	} else {
 		return nil, fmt.Errorf("hoist info doesn't exist")
 	}
@@ -129,19 +186,52 @@ func NewEvaluator(packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, i
 		},
 		globalEnvFrame: globalEnv,
 		builtInSlots:   []Value{},
+		typeDecls:      map[parser.IdId]*parser.TypeDecl{},
+		methods:        map[parser.IdId]map[string]*ClosureValue{},
+		variants:       map[parser.IdId]variantRef{},
+		programArgs:    opts.Args,
 		debug:          false,
 	}
+	if opts.HeapStack {
+		e.UseHeapStack(opts.MaxCallDepth)
+	}
+	for _, id := range hoistInfo.TypeIds() {
+		decl := hoistInfo.GetTypeDeclById(id)
+		if decl == nil {
+			return nil, fmt.Errorf("missing hoisted type decl")
+		}
+		e.typeDecls[id] = decl
+	}
+	// 메서드는 리시버를 첫 번째 매개변수로 받는 클로저로 바인딩
+	// 메서드는 패키지 레벨에서만 선언되므로 전역 환경을 캡처함
+	for _, typeId := range hoistInfo.TypeIds() {
+		for name, methodId := range hoistInfo.MethodsOfType(typeId) {
+			decl := hoistInfo.GetMethodDeclById(methodId)
+			if decl == nil {
+				return nil, fmt.Errorf("missing hoisted method decl")
+			}
+			params := append([]parser.Param{*decl.ReceiverOrNil}, decl.ParamsOrNil...)
+			if e.methods[typeId] == nil {
+				e.methods[typeId] = map[string]*ClosureValue{}
+			}
+			e.methods[typeId][name] = newClosureVal(&decl.Id, params, decl.ReturnTypesOrNil, decl.Block, globalEnv, decl.IsGenerator)
+		}
+	}
 	//4. 빌트인 레지스트리 생성. resolver가 제공한 builtins를 사용
 	e.builtInSlots = make([]Value, maxBuiltinSlot(builtins)+1)
 	for name, slot := range builtins {
-		fn, ok := builtinByName(name)
-		if !ok {
-			return nil, fmt.Errorf("missing builtin implementation: %s", name)
-		}
 		if slot < 0 || slot >= len(e.builtInSlots) {
 			return nil, fmt.Errorf("builtin slot out of range: %s", name)
 		}
-		e.builtInSlots[slot] = newBuiltinFuncVal(fn)
+		if fn, ok := builtinByName(name); ok {
+			e.builtInSlots[slot] = newBuiltinFuncVal(fn)
+			continue
+		}
+		members, ok := builtinModuleByName(name)
+		if !ok {
+			return nil, fmt.Errorf("missing builtin implementation: %s", name)
+		}
+		e.builtInSlots[slot] = newModuleVal(name, members)
 	}
 	// 5. hoistInfo서 funcDecl 꺼낸 후 클로저로 바인딩
 	// resolveTable에 정의된 slot에 맞게 해당 값 채우기
@@ -154,10 +244,47 @@ func NewEvaluator(packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, i
 		if ref.Slot < 0 || ref.Slot >= len(globalEnv.Slots) {
 			return nil, fmt.Errorf("global slot out of range for func")
 		}
-		closure := newClosureVal(&fn.Id, fn.ParamsOrNil, fn.ReturnTypesOrNil, fn.Block, globalEnv)
+		closure := newClosureVal(&fn.Id, fn.ParamsOrNil, fn.ReturnTypesOrNil, fn.Block, globalEnv, fn.IsGenerator)
+		closure.TypeParams = fn.TypeParamsOrNil
 
 		globalEnv.Slots[ref.Slot] = closure
 	}
+	// 5-1. 합 타입의 변형 생성자를 전역 슬롯에 바인딩
+	// 필드 없는 변형은 값 자체를, 필드 있는 변형은 생성자 함수를 넣음
+	for _, idId := range hoistInfo.VariantIds() {
+		decl := hoistInfo.GetVariantTypeDeclById(idId)
+		if decl == nil {
+			return nil, fmt.Errorf("missing type decl for variant")
+		}
+		index := variantIndex(decl, idId)
+		e.variants[idId] = variantRef{decl: decl, index: index}
+		ref := resolveTable[idId]
+		if ref.Slot < 0 || ref.Slot >= len(globalEnv.Slots) {
+			return nil, fmt.Errorf("global slot out of range for variant")
+		}
+		globalEnv.Slots[ref.Slot] = e.variantConstructor(decl, index)
+	}
+	// 5-2. 상수는 리졸버가 접어 둔 값을 그대로 전역 슬롯에 넣음
+	// 어떤 전역 변수보다 먼저 채워지므로 초기화 순서와 무관함
+	for _, idId := range hoistInfo.ConstIds() {
+		value, ok := hoistInfo.GetConstValueById(idId)
+		if !ok {
+			return nil, fmt.Errorf("missing folded value for const")
+		}
+		ref := resolveTable[idId]
+		if ref.Slot < 0 || ref.Slot >= len(globalEnv.Slots) {
+			return nil, fmt.Errorf("global slot out of range for const")
+		}
+		globalEnv.Slots[ref.Slot] = constToValue(value)
+	}
+	// 5-3. init 함수는 전역 슬롯 없이 실행 순서대로 보관함
+	for _, id := range hoistInfo.InitFuncIds() {
+		fn := hoistInfo.GetInitFuncDeclById(id)
+		if fn == nil {
+			return nil, fmt.Errorf("missing hoisted init func decl")
+		}
+		e.initFuncs = append(e.initFuncs, newClosureVal(&fn.Id, nil, nil, fn.Block, globalEnv, false))
+	}
 	// 6. initOrder의 순서대로 varDecl꺼낸 후 평가
 	// 6-1 zero init시 go의 zero값으로 채우기. ExprInit시엔 Valuate후 채우기
 	// 6-2 resolveTable에 정의된 slot에 맞게 해당 값 채우기
@@ -179,7 +306,7 @@ func NewEvaluator(packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, i
 			if !ok {
 				return nil, fmt.Errorf("missing var type for zero init")
 			}
-			globalEnv.Slots[ref.Slot] = ZeroValueForType(typ)
+			globalEnv.Slots[ref.Slot] = e.ZeroValueForType(typ)
 			continue
 		}
 
@@ -190,12 +317,20 @@ func NewEvaluator(packageAst parser.PackageAST, hoistInfo *resolver.HoistInfo, i
 		}
 		values, ctrlSigOrNil, err := e.Valuate(step.ExprOrNil)
 		if err != nil {
-			return nil, fmt.Errorf("init expr evaluation failed")
+			return nil, fmt.Errorf("init expr evaluation failed: %w", err)
 		}
 		if ctrlSigOrNil != nil {
 			if ctrlSigOrNil.Kind == CtrlPanic {
 				return nil, fmt.Errorf("panic during hoisting: %s", ctrlSigOrNil.Values[0].Inspect())
 			}
+			// 초기식 안의 exit은 main을 실행하지 않고 프로그램을 끝냄
+			if ctrlSigOrNil.Kind == CtrlExit {
+				return nil, exitErrorOf(ctrlSigOrNil)
+			}
+		}
+		// var r (int, error) = f() 처럼 튜플 타입 전역에 여러 값이 대입되면 튜플로 묶음
+		if typ, ok := hoistedVarTypeByIdId[step.VarId]; ok {
+			values = packTuple(values, typ)
 		}
 		if len(values) != 1 {
 			// 이 부분은 리졸버에 근거함.
@@ -216,6 +351,8 @@ type EnvFrame struct {
 	ParentEnvFrame *EnvFrame
 }
 
+// EvalMainFunc는 init 함수들과 main을 실행함
+// exit(code)로 끝났다면 *ExitError를 리턴하며, ExitCode로 프로세스 종료 코드를 얻을 수 있음
 func (e *Evaluator) EvalMainFunc() error {
 	// 1. main함수의 시그니처 검사하기
 	// 2. env에서 main함수 꺼내서 호출
@@ -224,7 +361,7 @@ func (e *Evaluator) EvalMainFunc() error {
 	var mainDecl *parser.FuncDecl
 	for _, decl := range e.packageAST.DeclsOrNil {
 		fn, ok := decl.(*parser.FuncDecl)
-		if !ok {
+		if !ok || fn.ReceiverOrNil != nil {
 			continue
 		}
 		if fn.Id.Name == "main" {
@@ -250,7 +387,32 @@ func (e *Evaluator) EvalMainFunc() error {
 	if len(mainClosure.Params) != 0 || len(mainClosure.ReturnTypes) != 0 {
 		return fmt.Errorf("main must have signature func()")
 	}
-	_, ctrlSig, err := e.callClosure(mainClosure, []Value{})
+	// 전역 변수 초기화는 NewEvaluator에서 끝났으므로, main 전에 init 함수들을 선언 순서대로 실행함
+	for i, initClosure := range e.initFuncs {
+		if err := e.runTopLevel(initClosure); err != nil {
+			// init 안의 exit은 실패가 아니라 프로그램의 정상적인 종료임
+			if _, ok := err.(*ExitError); ok {
+				return err
+			}
+			return &InitError{Index: i + 1, Err: err}
+		}
+	}
+	if err := e.runTopLevel(mainClosure); err != nil {
+		return err
+	}
+	if e.debug == true {
+		for i, e := range e.callStack.callFrames {
+			fmt.Printf("%dth CallFame:", i)
+			fmt.Println(e.String())
+		}
+	}
+	return nil
+}
+
+// runTopLevel은 init이나 main처럼 인자도 결과도 없는 함수를 실행하고, 잡히지 않은 panic을 에러로 바꿈
+// exit(code)로 끝났다면 *ExitError를 리턴함
+func (e *Evaluator) runTopLevel(closure *ClosureValue) error {
+	_, ctrlSig, err := e.callClosure(closure, []Value{})
 	if err != nil {
 		return err
 	}
@@ -261,17 +423,19 @@ func (e *Evaluator) EvalMainFunc() error {
 			}
 			return fmt.Errorf("panic")
 		}
-		return fmt.Errorf("unexpected control signal: %v", ctrlSig.Kind)
-	}
-	if e.debug == true {
-		for i, e := range e.callStack.callFrames {
-			fmt.Printf("%dth CallFame:", i)
-			fmt.Println(e.String())
+		if ctrlSig.Kind == CtrlExit {
+			return exitErrorOf(ctrlSig)
 		}
+		return fmt.Errorf("unexpected control signal: %v", ctrlSig.Kind)
 	}
 	return nil
 }
 
+func exitErrorOf(ctrlSig *ControlSignal) *ExitError {
+	code, _ := ctrlSig.Values[0].(*IntValue)
+	return &ExitError{Code: int(code.Value)}
+}
+
 func (e *Evaluator) pushEnvFrame(ef *EnvFrame) {
 	ef.ParentEnvFrame = e.CurrentEnv()
 	e.callStack.setMostCurrentEnv(ef)
@@ -284,6 +448,17 @@ func (e *Evaluator) CurrentEnv() *EnvFrame {
 	return e.callStack.peekMostCurrentEnv()
 }
 
+func constToValue(value resolver.ConstValue) Value {
+	switch value.Kind {
+	case parser.IntType:
+		return newIntVal(value.Int)
+	case parser.BoolType:
+		return newBoolVal(value.Bool)
+	default:
+		return newStringVal(value.Str)
+	}
+}
+
 func maxBuiltinSlot(slots map[string]int) int {
 	max := -1
 	for _, slot := range slots {
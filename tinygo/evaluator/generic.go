@@ -0,0 +1,370 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// 제네릭 함수를 단형화 없이 실행하기 위한 함수들
+// 제네릭 함수 값은 타입 인자를 묶은 맵을 들고 다니며, 호출 시 콜프레임이 이를 가짐
+// 본문 안에서 타입 매개변수를 만나면 현재 콜프레임의 타입 인자로 바꿔 읽음
+
+// currentTypeArgs는 실행 중인 함수 본문의 타입 인자들을 리턴함
+func (e *Evaluator) currentTypeArgs() map[parser.IdId]parser.Type {
+	frames := e.callStack.callFrames
+	if len(frames) == 0 {
+		return nil
+	}
+	return frames[len(frames)-1].typeArgs
+}
+
+// capturingClosure는 제네릭 함수 본문에서 만든 클로저가 바깥의 타입 인자를 잡아 두게 함
+func (e *Evaluator) capturingClosure(c *ClosureValue) *ClosureValue {
+	c.TypeArgs = e.currentTypeArgs()
+	return c
+}
+
+// typeArgFor는 name이 타입 매개변수라면 현재 묶인 타입 인자를 리턴함
+// 묶이지 않은 타입 매개변수는 빈 interface로 취급함
+func (e *Evaluator) typeArgFor(name parser.Id) (parser.Type, bool) {
+	ref, ok := e.resolveTable[name.IdId]
+	if !ok || ref.Kind != resolver.RefTypeParam {
+		return parser.Type{}, false
+	}
+	if typeArg, ok := e.currentTypeArgs()[ref.RefIdNodeId]; ok {
+		return typeArg, true
+	}
+	return anyType, true
+}
+
+// substTypeParams는 t 안의 타입 매개변수 중 args에 묶인 것들을 바꾼 새 타입을 리턴함
+func (e *Evaluator) substTypeParams(t parser.Type, args map[parser.IdId]parser.Type) parser.Type {
+	if len(args) == 0 {
+		return t
+	}
+	switch t.TypeKind {
+	case parser.NamedType:
+		ref := e.resolveTable[t.NameOrNil.IdId]
+		if typeArg, ok := args[ref.RefIdNodeId]; ok && ref.Kind == resolver.RefTypeParam {
+			return typeArg
+		}
+	case parser.SliceType:
+		elem := e.substTypeParams(*t.ElemOrNil, args)
+		return parser.Type{TypeKind: parser.SliceType, ElemOrNil: &elem}
+	case parser.TupleType:
+		elems := make([]parser.Type, len(t.TupleOrNil))
+		for i, elem := range t.TupleOrNil {
+			elems[i] = e.substTypeParams(elem, args)
+		}
+		return parser.Type{TypeKind: parser.TupleType, TupleOrNil: elems}
+	case parser.FuncionType:
+		return parser.Type{TypeKind: parser.FuncionType, FuncTypeOrNil: e.substFuncType(*t.FuncTypeOrNil, args)}
+	}
+	return t
+}
+
+func (e *Evaluator) substFuncType(ft parser.FuncType, args map[parser.IdId]parser.Type) *parser.FuncType {
+	argTypes := make([]parser.Type, len(ft.ArgTypesOrNil))
+	for i, arg := range ft.ArgTypesOrNil {
+		argTypes[i] = e.substTypeParams(arg, args)
+	}
+	returnTypes := make([]parser.Type, len(ft.ReturnTypesOrNil))
+	for i, ret := range ft.ReturnTypesOrNil {
+		returnTypes[i] = e.substTypeParams(ret, args)
+	}
+	return &parser.FuncType{ArgTypesOrNil: argTypes, ReturnTypesOrNil: returnTypes}
+}
+
+// hasTypeParam은 t 안에 아직 바뀌지 않은 타입 매개변수가 남아 있는지 여부임
+func (e *Evaluator) hasTypeParam(t parser.Type) bool {
+	switch t.TypeKind {
+	case parser.NamedType:
+		return e.resolveTable[t.NameOrNil.IdId].Kind == resolver.RefTypeParam
+	case parser.SliceType:
+		return e.hasTypeParam(*t.ElemOrNil)
+	case parser.TupleType:
+		for _, elem := range t.TupleOrNil {
+			if e.hasTypeParam(elem) {
+				return true
+			}
+		}
+	case parser.FuncionType:
+		for _, arg := range t.FuncTypeOrNil.ArgTypesOrNil {
+			if e.hasTypeParam(arg) {
+				return true
+			}
+		}
+		for _, ret := range t.FuncTypeOrNil.ReturnTypesOrNil {
+			if e.hasTypeParam(ret) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// instantiate는 c의 타입 매개변수들에 앞에서부터 typeArgs를 묶은 새 함수 값을 리턴함
+// 이미 묶인 타입 매개변수는 그대로 둠
+func (e *Evaluator) instantiate(c *ClosureValue, typeArgs []parser.Type) (*ClosureValue, error) {
+	if len(typeArgs) > len(c.TypeParams) {
+		return nil, fmt.Errorf("got %d type arguments but %s has %d type parameters", len(typeArgs), c.Inspect(), len(c.TypeParams))
+	}
+	bound := copyTypeArgs(c.TypeArgs)
+	for i, typeArg := range typeArgs {
+		id := c.TypeParams[i].Id.IdId
+		if _, ok := bound[id]; !ok {
+			// 타입 인자 안의 타입 매개변수는 인스턴스화하는 쪽의 것이므로 지금 바꿔 둠
+			bound[id] = e.substTypeParams(typeArg, e.currentTypeArgs())
+		}
+	}
+	return withTypeArgs(c, bound), nil
+}
+
+// bindTypeArgs는 아직 묶이지 않은 c의 타입 매개변수를 인자 값들의 동적 타입으로부터 추론하고
+// 타입 매개변수로 선언된 매개변수의 값이 제약을 충족하는지 검사함
+func (e *Evaluator) bindTypeArgs(c *ClosureValue, args []Value) (*ClosureValue, error) {
+	bound := copyTypeArgs(c.TypeArgs)
+	for i, param := range c.Params {
+		if err := e.inferFromValue(c, param.Type, args[i], i, bound); err != nil {
+			return nil, err
+		}
+	}
+	for _, tp := range c.TypeParams {
+		if _, ok := bound[tp.Id.IdId]; !ok {
+			bound[tp.Id.IdId] = anyType
+		}
+	}
+	for i, param := range c.Params {
+		tp, ok := e.ownTypeParam(c, param.Type)
+		if !ok {
+			continue
+		}
+		if !e.satisfiesConstraint(args[i], e.substTypeParams(tp.Constraint, bound)) {
+			return nil, fmt.Errorf("%s does not satisfy %s", dynamicTypeName(args[i]), constraintName(tp.Constraint))
+		}
+	}
+	return withTypeArgs(c, bound), nil
+}
+
+func withTypeArgs(c *ClosureValue, bound map[parser.IdId]parser.Type) *ClosureValue {
+	instance := *c
+	instance.TypeArgs = bound
+	return &instance
+}
+
+func copyTypeArgs(args map[parser.IdId]parser.Type) map[parser.IdId]parser.Type {
+	copied := make(map[parser.IdId]parser.Type, len(args))
+	for id, t := range args {
+		copied[id] = t
+	}
+	return copied
+}
+
+// ownTypeParam은 t가 c의 타입 매개변수라면 이를 리턴함
+func (e *Evaluator) ownTypeParam(c *ClosureValue, t parser.Type) (parser.TypeParam, bool) {
+	if t.TypeKind != parser.NamedType {
+		return parser.TypeParam{}, false
+	}
+	ref := e.resolveTable[t.NameOrNil.IdId]
+	if ref.Kind != resolver.RefTypeParam {
+		return parser.TypeParam{}, false
+	}
+	for _, tp := range c.TypeParams {
+		if tp.Id.IdId == ref.RefIdNodeId {
+			return tp, true
+		}
+	}
+	return parser.TypeParam{}, false
+}
+
+// inferFromValue는 매개변수 타입 pattern과 인자 값 v를 맞춰 보며 타입 매개변수를 묶음
+func (e *Evaluator) inferFromValue(c *ClosureValue, pattern parser.Type, v Value, argIndex int, bound map[parser.IdId]parser.Type) error {
+	if tp, ok := e.ownTypeParam(c, pattern); ok {
+		prev, isBound := bound[tp.Id.IdId]
+		if !isBound {
+			// nil은 타입을 알려 주지 않으므로 다른 인자에 맡김
+			if v.Kind() != NilKind {
+				bound[tp.Id.IdId] = e.valueType(v)
+			}
+			return nil
+		}
+		if !isEmptyInterface(prev) && !e.valueHasType(v, prev) {
+			return fmt.Errorf("type %s of argument %d does not match inferred type %s for %s", dynamicTypeName(v), argIndex+1, typeName(prev), tp.Id.Name)
+		}
+		return nil
+	}
+	switch pattern.TypeKind {
+	case parser.SliceType:
+		if sliceVal, ok := v.(*SliceValue); ok {
+			return e.unifyTypes(c, *pattern.ElemOrNil, sliceVal.ElemType, argIndex, bound)
+		}
+	case parser.TupleType:
+		tupleVal, ok := v.(*TupleValue)
+		if !ok || len(tupleVal.Elems) != len(pattern.TupleOrNil) {
+			return nil
+		}
+		for i, elem := range tupleVal.Elems {
+			if err := e.inferFromValue(c, pattern.TupleOrNil[i], elem, argIndex, bound); err != nil {
+				return err
+			}
+		}
+	case parser.FuncionType:
+		actual := e.valueType(v)
+		if actual.TypeKind == parser.FuncionType {
+			return e.unifyTypes(c, pattern, actual, argIndex, bound)
+		}
+	}
+	return nil
+}
+
+// unifyTypes는 매개변수 타입 pattern과 인자 값의 타입 actual을 맞춰 보며 타입 매개변수를 묶음
+// 인스턴스화하지 않은 제네릭 함수 값처럼 actual에 타입 매개변수가 남아 있다면 그 부분에선 추론하지 않음
+func (e *Evaluator) unifyTypes(c *ClosureValue, pattern, actual parser.Type, argIndex int, bound map[parser.IdId]parser.Type) error {
+	if e.hasTypeParam(actual) && actual.TypeKind == parser.NamedType {
+		return nil
+	}
+	if tp, ok := e.ownTypeParam(c, pattern); ok {
+		if e.hasTypeParam(actual) {
+			return nil
+		}
+		prev, isBound := bound[tp.Id.IdId]
+		if !isBound {
+			bound[tp.Id.IdId] = actual
+			return nil
+		}
+		if !isEmptyInterface(prev) && !e.sameType(prev, actual) {
+			return fmt.Errorf("type %s of argument %d does not match inferred type %s for %s", typeName(actual), argIndex+1, typeName(prev), tp.Id.Name)
+		}
+		return nil
+	}
+	if pattern.TypeKind != actual.TypeKind {
+		return nil
+	}
+	switch pattern.TypeKind {
+	case parser.SliceType:
+		return e.unifyTypes(c, *pattern.ElemOrNil, *actual.ElemOrNil, argIndex, bound)
+	case parser.TupleType:
+		if len(pattern.TupleOrNil) != len(actual.TupleOrNil) {
+			return nil
+		}
+		for i := range pattern.TupleOrNil {
+			if err := e.unifyTypes(c, pattern.TupleOrNil[i], actual.TupleOrNil[i], argIndex, bound); err != nil {
+				return err
+			}
+		}
+	case parser.FuncionType:
+		pf, af := pattern.FuncTypeOrNil, actual.FuncTypeOrNil
+		if len(pf.ArgTypesOrNil) != len(af.ArgTypesOrNil) || len(pf.ReturnTypesOrNil) != len(af.ReturnTypesOrNil) {
+			return nil
+		}
+		for i := range pf.ArgTypesOrNil {
+			if err := e.unifyTypes(c, pf.ArgTypesOrNil[i], af.ArgTypesOrNil[i], argIndex, bound); err != nil {
+				return err
+			}
+		}
+		for i := range pf.ReturnTypesOrNil {
+			if err := e.unifyTypes(c, pf.ReturnTypesOrNil[i], af.ReturnTypesOrNil[i], argIndex, bound); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// valueType은 v의 동적 타입을 타입 표현으로 만듦. 타입 표현이 없는 값은 빈 interface로 봄
+func (e *Evaluator) valueType(v Value) parser.Type {
+	switch val := v.(type) {
+	case *IntValue:
+		return parser.Type{TypeKind: parser.IntType}
+	case *BoolValue:
+		return parser.Type{TypeKind: parser.BoolType}
+	case *StringValue:
+		return parser.Type{TypeKind: parser.StringType}
+	case *ErrorValue:
+		return parser.Type{TypeKind: parser.ErrorType}
+	case *StructValue:
+		if val.TypeIdOrNil != nil {
+			if decl, ok := e.typeDecls[*val.TypeIdOrNil]; ok {
+				return parser.Type{TypeKind: parser.NamedType, NameOrNil: &decl.Id}
+			}
+		}
+		fields := make([]parser.Field, len(val.Fields))
+		for i, field := range val.Fields {
+			fields[i] = parser.Field{Id: parser.Id{Name: val.FieldNames[i]}, Type: e.valueType(field)}
+		}
+		return parser.Type{TypeKind: parser.StructureType, StructOrNil: &parser.StructType{Fields: fields}}
+	case *AdtValue:
+		if decl, ok := e.typeDecls[val.TypeId]; ok {
+			return parser.Type{TypeKind: parser.NamedType, NameOrNil: &decl.Id}
+		}
+	case *SliceValue:
+		return parser.Type{TypeKind: parser.SliceType, ElemOrNil: &val.ElemType}
+	case *TupleValue:
+		elems := make([]parser.Type, len(val.Elems))
+		for i, elem := range val.Elems {
+			elems[i] = e.valueType(elem)
+		}
+		return parser.Type{TypeKind: parser.TupleType, TupleOrNil: elems}
+	case *ClosureValue:
+		return *e.closureType(val, val.Params)
+	case *BoundMethodValue:
+		return *e.closureType(val.Method, val.Method.Params[1:])
+	}
+	return anyType
+}
+
+// closureType은 클로저의 시그니처를, 클로저가 잡아 둔 타입 인자로 바꿔 만듦
+func (e *Evaluator) closureType(c *ClosureValue, params []parser.Param) *parser.Type {
+	argTypes := make([]parser.Type, len(params))
+	for i, param := range params {
+		argTypes[i] = param.Type
+	}
+	ft := e.substFuncType(parser.FuncType{ArgTypesOrNil: argTypes, ReturnTypesOrNil: c.ReturnTypes}, c.TypeArgs)
+	return &parser.Type{TypeKind: parser.FuncionType, FuncTypeOrNil: ft}
+}
+
+// satisfiesConstraint는 v가 타입 매개변수의 제약을 충족하는지 검사함
+func (e *Evaluator) satisfiesConstraint(v Value, constraint parser.Type) bool {
+	if constraint.TypeKind == parser.NamedType {
+		if decl, ok := e.typeDeclForName(*constraint.NameOrNil); ok && decl.Type.TypeKind == parser.InterfaceTypeKind {
+			constraint = decl.Type
+		}
+	}
+	if isEmptyInterface(constraint) {
+		return true
+	}
+	return e.valueHasType(v, constraint)
+}
+
+func isEmptyInterface(t parser.Type) bool {
+	if t.TypeKind != parser.InterfaceTypeKind {
+		return false
+	}
+	iface := t.InterfaceOrNil
+	return len(iface.Methods) == 0 && len(iface.UnionOrNil) == 0 && !iface.Comparable
+}
+
+// constraintName은 에러 메시지에 쓰일 제약의 이름임
+func constraintName(t parser.Type) string {
+	if t.TypeKind != parser.InterfaceTypeKind {
+		return typeName(t)
+	}
+	iface := t.InterfaceOrNil
+	switch {
+	case isEmptyInterface(t):
+		return "any"
+	case len(iface.Methods) == 0 && len(iface.UnionOrNil) == 0:
+		return "comparable"
+	case len(iface.Methods) == 0 && !iface.Comparable:
+		members := make([]string, len(iface.UnionOrNil))
+		for i, member := range iface.UnionOrNil {
+			members[i] = typeName(member)
+		}
+		return strings.Join(members, " | ")
+	default:
+		return "interface"
+	}
+}
@@ -0,0 +1,60 @@
+package evaluator
+
+import "github.com/rlaaudgjs5638/langTest/tinygo/parser"
+
+// 힙 스택 모드
+// 기본 모드에선 tinygo 함수 호출마다 호스트(go) 스택이 쌓이므로, 꼬리 호출이 아닌 깊은 재귀는 호스트를 죽임
+// 힙 스택 모드에선 함수 호출을 machine이 실행함 (machine.go)
+// 콜프레임은 callStack에, 호출이 끝난 뒤 이어서 할 일은 machine의 continuation 스택에 쌓이며 둘 다 힙에 있음
+// 제어신호와 에러도 continuation 스택을 따라 내려가며 루프, switch, 함수 호출 지점에서 처리됨
+// 콜스택 깊이가 maxDepth에 닿으면 tinygo의 stack overflow 에러로 실행을 끝냄
+// 깊이별 비용과 한계는 tiny_go_v1.md의 힙 스택 모드 항목에 정리함
+
+// DefaultMaxCallDepth는 힙 스택 모드의 기본 최대 호출 깊이임
+const DefaultMaxCallDepth = 100000
+
+type heapStack struct {
+	maxDepth int
+	// 식과 문장마다 안에 호출이 있는지 여부
+	// 호출이 없는 노드는 호스트 스택을 깊게 쓰지 않으므로 machine도 기본 모드의 재귀 평가로 실행함
+	callsByNode map[parser.Node]bool
+}
+
+// UseHeapStack은 evaluator를 힙 스택 모드로 바꿈. maxDepth가 0 이하라면 DefaultMaxCallDepth를 씀
+func (e *Evaluator) UseHeapStack(maxDepth int) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxCallDepth
+	}
+	e.heapStackOrNil = &heapStack{maxDepth: maxDepth, callsByNode: map[parser.Node]bool{}}
+}
+
+// fork는 제너레이터 본문처럼 새 콜스택 위에서 실행할 evaluator를 위한 힙 스택 상태를 리턴함
+// 코루틴과 소비자는 번갈아 실행되므로 호출 여부 캐시는 공유함
+func (hs *heapStack) fork() *heapStack {
+	if hs == nil {
+		return nil
+	}
+	return &heapStack{maxDepth: hs.maxDepth, callsByNode: hs.callsByNode}
+}
+
+// hasCall은 node 안에 tinygo 함수를 부를 수 있는 호출식이나 |> 가 있는지 리턴함
+// 함수 리터럴과 함수 선언의 본문은 그 자리에서 실행되지 않으므로 보지 않음
+func (hs *heapStack) hasCall(node parser.Node) bool {
+	if found, ok := hs.callsByNode[node]; ok {
+		return found
+	}
+	found := false
+	parser.Inspect(node, func(n parser.Node) bool {
+		switch n := n.(type) {
+		case *parser.Call:
+			found = true
+		case *parser.Binary:
+			found = found || n.Op == parser.Pipe
+		case *parser.Fexp, *parser.FuncDecl:
+			return false
+		}
+		return !found
+	})
+	hs.callsByNode[node] = found
+	return found
+}
@@ -0,0 +1,866 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// machine은 힙 스택 모드에서 함수 호출을 호스트 스택 대신 힙의 명시적인 스택 위에서 실행함
+// 콜프레임은 기본 모드와 같이 callStack에 쌓이고, 앞 단계가 끝난 뒤 이어서 할 일(남은 문장, 식의 나머지, 루프의 다음 반복 등)은 konts에 쌓임
+// 각 단계는 결과를 values, ctrlSig, err에 남기거나 konts에 다음 단계를 쌓은 뒤 곧바로 리턴하므로,
+// tinygo의 호출 깊이와 무관하게 호스트 스택은 run 루프와 한 단계만큼만 쓰임
+// 호출이 없는 문장과 식은 깊이 들어갈 일이 없으므로 기본 모드의 재귀 평가(evalStmt, Valuate)로 한 번에 실행함
+// 빌트인은 호스트 함수이므로, seq.Map처럼 클로저를 부르는 빌트인 안의 호출은 호스트 스택 위에서 새 machine으로 실행됨
+type machine struct {
+	e     *Evaluator
+	konts []kont
+	// 마지막으로 끝난 단계의 결과
+	values  []Value
+	ctrlSig *ControlSignal
+	err     error
+}
+
+// kont는 앞 단계가 끝난 뒤 이어서 할 일임
+type kont struct {
+	// next는 앞 단계가 신호 없이 끝났을 때 그 값을 받아 부름
+	next func(values []Value)
+	// unwindOrNil은 제어신호나 에러가 이 지점을 지나갈 때 부름. nil이면 그냥 지나감
+	// 쌓아 둔 환경과 콜프레임을 내리고, 루프, switch, 함수 호출처럼 신호를 받는 곳이라면 신호를 소비함
+	unwindOrNil func()
+}
+
+func (e *Evaluator) newMachine() *machine {
+	return &machine{e: e}
+}
+
+// run은 쌓인 할 일이 없어질 때까지 실행하고 마지막 결과를 리턴함
+func (m *machine) run() ([]Value, *ControlSignal, error) {
+	for len(m.konts) > 0 {
+		top := len(m.konts) - 1
+		k := m.konts[top]
+		m.konts[top] = kont{}
+		m.konts = m.konts[:top]
+		if m.err != nil || m.ctrlSig != nil {
+			if k.unwindOrNil != nil {
+				k.unwindOrNil()
+			}
+			continue
+		}
+		values := m.values
+		m.values = nil
+		k.next(values)
+	}
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	return m.values, m.ctrlSig, nil
+}
+
+func (m *machine) push(k kont) {
+	m.konts = append(m.konts, k)
+}
+
+// then은 앞 단계의 값을 받아 이어서 할 일을 쌓음
+func (m *machine) then(next func(values []Value)) {
+	m.push(kont{next: next})
+}
+
+func (m *machine) result(values []Value, ctrlSig *ControlSignal, err error) {
+	m.values, m.ctrlSig, m.err = values, ctrlSig, err
+}
+
+func (m *machine) fail(err error) {
+	m.err = err
+}
+
+// scope는 새 환경을 쌓고, 이어지는 단계들이 끝나거나 신호가 지나갈 때 그 환경을 내림
+func (m *machine) scope() {
+	m.e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
+	m.push(kont{
+		next: func(values []Value) {
+			m.e.popEnvFrame()
+			m.values = values
+		},
+		unwindOrNil: m.e.popEnvFrame,
+	})
+}
+
+// enter는 callClosure와 같이 c를 호출함. 결과는 처음 호출된 함수의 리턴 타입 outerReturnTypes에 맞춰 묶음
+func (m *machine) enter(c *ClosureValue, args []Value, outerReturnTypes []parser.Type) {
+	instance, err := m.e.bindCall(c, args)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	// 제너레이터는 호출 시 인자만 묶어 두고, 본문은 range가 코루틴으로 실행함
+	if instance.IsGenerator {
+		m.result(packReturnValues([]Value{newGeneratorVal(instance, args)}, outerReturnTypes), nil, nil)
+		return
+	}
+	m.frame(instance, args, outerReturnTypes)
+}
+
+// frame은 c의 콜프레임을 쌓고 본문을 실행함
+// 본문을 빠져나오면 콜프레임을 내리며, return은 리턴 값으로 바꾸고 꼬리 호출은 그 자리에서 이어서 호출함
+func (m *machine) frame(c *ClosureValue, args []Value, outerReturnTypes []parser.Type) {
+	e := m.e
+	if len(e.callStack.callFrames) > e.heapStackOrNil.maxDepth {
+		m.fail(fmt.Errorf("stack overflow: call depth exceeds %d", e.heapStackOrNil.maxDepth))
+		return
+	}
+	callFrame, err := e.newCallFrame(c, args)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	e.callStack.pushCallFrame(callFrame)
+	m.push(kont{
+		next: func([]Value) {
+			e.callStack.popCallFrame()
+			// 리턴이 없다면 아무 값도 전파하지 않음
+			m.values = packReturnValues([]Value{}, outerReturnTypes)
+		},
+		unwindOrNil: func() {
+			e.callStack.popCallFrame()
+			if m.err != nil {
+				return
+			}
+			values, ctrlSig, err := frameResult(c, m.ctrlSig)
+			switch {
+			case err != nil || ctrlSig != nil && ctrlSig.Kind != CtrlTailCall:
+				m.result(nil, ctrlSig, err)
+			case ctrlSig == nil:
+				// 꼬리 호출된 함수의 결과는 처음 호출된 함수의 리턴 타입에 맞춰 다시 묶음
+				m.result(packReturnValues(values, outerReturnTypes), nil, nil)
+			default:
+				callee, args, err := tailCallee(ctrlSig)
+				m.result(nil, nil, err)
+				if err == nil {
+					m.enter(callee, args, outerReturnTypes)
+				}
+			}
+		},
+	})
+	//param은 block과 같은 환경에서 존재
+	m.block(c.Block, true)
+}
+
+// callValue는 이미 평가된 함수 값 callee를 args로 호출함
+func (m *machine) callValue(callee Value, args []Value) {
+	switch fn := callee.(type) {
+	case *BuiltinFuncValue:
+		m.result(fn.Func.Impl(m.e, args))
+	case *ClosureValue:
+		m.enter(fn, args, fn.ReturnTypes)
+	case *BoundMethodValue:
+		// 리시버를 첫 번째 인자로 붙여 메서드 클로저를 호출
+		m.enter(fn.Method, append([]Value{fn.Receiver}, args...), fn.Method.ReturnTypes)
+	default:
+		m.fail(fmt.Errorf("call target is not callable"))
+	}
+}
+
+// callee는 valuateCallee와 같이 호출식을 마지막 호출 직전까지 평가하고, 마지막으로 호출할 함수 값과 인자들을 k에 넘김
+func (m *machine) callee(c *parser.Call, pipedOrNil []Value, k func(callee Value, args []Value)) {
+	m.then(func(applied []Value) {
+		m.apply(c, 0, applied, pipedOrNil, k)
+	})
+	m.expr(&c.PrimaryOrNil)
+}
+
+// apply는 c의 i번째 인자 목록을 평가해 applied를 호출함
+func (m *machine) apply(c *parser.Call, i int, applied []Value, pipedOrNil []Value, k func(callee Value, args []Value)) {
+	if i == len(c.ArgsList) {
+		m.fail(fmt.Errorf("invalid call: missing arguments"))
+		return
+	}
+	callee, err := m.e.calleeAt(c, i, applied)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.callArgs(c.ArgsList[i], callee, func(args []Value) {
+		if i == 0 && pipedOrNil != nil {
+			args = pipedArgs(callee, pipedOrNil, args)
+		}
+		if i == len(c.ArgsList)-1 {
+			k(callee, args)
+			return
+		}
+		m.then(func(values []Value) {
+			m.apply(c, i+1, values, nil, k)
+		})
+		m.callValue(callee, args)
+	})
+}
+
+// callArgs는 evalCallArgs와 같이 한 번의 호출에 넘길 인자들을 평가함
+func (m *machine) callArgs(argExprs []parser.Expr, callee Value, k func(args []Value)) {
+	if len(argExprs) != 1 {
+		m.singles(argExprs, "call arg", k)
+		return
+	}
+	m.then(func(values []Value) {
+		args, err := spreadArg(callee, values)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		k(args)
+	})
+	m.expr(argExprs[0])
+}
+
+// exprs는 식들을 차례로 평가해 나온 값들을 모두 이어 붙여 k에 넘김
+func (m *machine) exprs(exprs []parser.Expr, k func(values []Value)) {
+	values := make([]Value, 0, len(exprs))
+	var next func(i int)
+	next = func(i int) {
+		if i == len(exprs) {
+			k(values)
+			return
+		}
+		m.then(func(vals []Value) {
+			values = append(values, vals...)
+			next(i + 1)
+		})
+		m.expr(exprs[i])
+	}
+	next(0)
+}
+
+// singles는 값을 하나씩 내는 식들을 차례로 평가해 k에 넘김
+func (m *machine) singles(exprs []parser.Expr, context string, k func(values []Value)) {
+	elems := make([]Value, 0, len(exprs))
+	var next func(i int)
+	next = func(i int) {
+		if i == len(exprs) {
+			k(elems)
+			return
+		}
+		m.then(func(values []Value) {
+			elem, err := expectSingle(values, context)
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			elems = append(elems, elem)
+			next(i + 1)
+		})
+		m.expr(exprs[i])
+	}
+	next(0)
+}
+
+// cond는 조건식을 평가해 그 bool 값을 k에 넘김
+func (m *machine) cond(expr parser.Expr, k func(cond bool)) {
+	m.then(func(values []Value) {
+		cond, err := condValue(values)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		k(cond)
+	})
+	m.expr(expr)
+}
+
+// targets는 evalExprsForTypedTargets와 같이 대입 대상이 targetCount개일 때의 우변을 평가함
+func (m *machine) targets(exprs []parser.Expr, targetCount int, typeOrNil *parser.Type, k func(values []Value)) {
+	if targetCount == 2 && len(exprs) == 1 {
+		if assert, ok := exprs[0].(*parser.TypeAssert); ok {
+			m.then(func(values []Value) {
+				values, err := m.e.assertValue(assert, values, true)
+				if err != nil {
+					m.fail(err)
+					return
+				}
+				k(values)
+			})
+			m.expr(assert.Object)
+			return
+		}
+	}
+	m.exprs(exprs, func(values []Value) {
+		values, err := targetValues(values, targetCount, typeOrNil)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		k(values)
+	})
+}
+
+// expr는 식 하나를 평가함. 호출이 없는 식은 Valuate로 바로 평가함
+func (m *machine) expr(expr parser.Expr) {
+	e := m.e
+	if !e.heapStackOrNil.hasCall(expr) {
+		m.result(e.Valuate(expr))
+		return
+	}
+	switch node := expr.(type) {
+	case *parser.Binary:
+		m.binary(node)
+	case *parser.Unary:
+		m.then(func(values []Value) {
+			m.result(unaryOp(node, values))
+		})
+		m.expr(node.Object)
+	case *parser.Primary:
+		m.primary(node)
+	case *parser.Call:
+		m.callee(node, nil, m.callValue)
+	case *parser.Selector:
+		m.then(func(values []Value) {
+			values, err := e.selectValue(node, values)
+			m.result(values, nil, err)
+		})
+		m.expr(node.Object)
+	case *parser.TypeAssert:
+		m.then(func(values []Value) {
+			values, err := e.assertValue(node, values, false)
+			m.result(values, nil, err)
+		})
+		m.expr(node.Object)
+	case *parser.Index:
+		m.index(node)
+	case *parser.Try:
+		m.then(func(values []Value) {
+			m.result(e.tryValues(values))
+		})
+		m.expr(node.Object)
+	case *parser.Match:
+		m.match(node)
+	default:
+		m.fail(fmt.Errorf("unknown expr node: %T", expr))
+	}
+}
+
+func (m *machine) binary(b *parser.Binary) {
+	switch b.Op {
+	case parser.Pipe:
+		m.pipe(b)
+	case parser.And, parser.Or:
+		m.then(func(values []Value) {
+			left, err := logicalOperand(values)
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			if shortCircuits(b.Op, left) {
+				m.result([]Value{newBoolVal(left)}, nil, nil)
+				return
+			}
+			m.then(func(values []Value) {
+				right, err := logicalOperand(values)
+				if err != nil {
+					m.fail(err)
+					return
+				}
+				m.result([]Value{newBoolVal(right)}, nil, nil)
+			})
+			m.expr(b.RightExpr)
+		})
+		m.expr(b.LeftExpr)
+	default:
+		m.then(func(values []Value) {
+			leftVal, err := expectSingle(values, "binary")
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			m.then(func(values []Value) {
+				m.result(binaryOp(b.Op, leftVal, values))
+			})
+			m.expr(b.RightExpr)
+		})
+		m.expr(b.LeftExpr)
+	}
+}
+
+// pipe는 ValuatePipe와 같이 x |> rhs 를 평가함
+func (m *machine) pipe(b *parser.Binary) {
+	m.then(func(piped []Value) {
+		if len(piped) == 0 {
+			m.fail(fmt.Errorf("|> expects a value on the left"))
+			return
+		}
+		if call, ok := b.RightExpr.(*parser.Call); ok {
+			m.callee(call, piped, m.callValue)
+			return
+		}
+		m.then(func(values []Value) {
+			callee, err := expectSingle(values, "|>")
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			m.callValue(callee, packArgsFor(callee, piped))
+		})
+		m.expr(b.RightExpr)
+	})
+	m.expr(b.LeftExpr)
+}
+
+// primary는 괄호 식과, 원소 식에 호출이 있는 struct, 슬라이스, 튜플 리터럴을 평가함
+func (m *machine) primary(p *parser.Primary) {
+	if p.PrimaryKind == parser.ExprPrimary {
+		m.expr(p.ExprOrNil)
+		return
+	}
+	switch value := p.ValueOrNil; value.ValueKind {
+	case parser.StructLitValue:
+		m.structLit(value.StructLitOrNil)
+	case parser.SliceLitValue:
+		lit := value.SliceLitOrNil
+		m.singles(lit.Elems, "slice element", func(elems []Value) {
+			m.result([]Value{m.e.sliceLitOf(lit, elems)}, nil, nil)
+		})
+	case parser.TupleLitValue:
+		m.singles(value.TupleLitOrNil.Elems, "tuple element", func(elems []Value) {
+			m.result([]Value{newTupleVal(elems)}, nil, nil)
+		})
+	default:
+		m.result(m.e.ValuatePrimary(p))
+	}
+}
+
+// structLit은 ValuateStructLit과 같이 필드 식을 차례로 평가해 struct 값을 만듦
+func (m *machine) structLit(lit *parser.StructLit) {
+	structVal, err := m.e.zeroStructForLit(lit)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	var field func(i int)
+	field = func(i int) {
+		if i == len(lit.Fields) {
+			m.result([]Value{structVal}, nil, nil)
+			return
+		}
+		index, err := structFieldIndex(structVal, lit.Fields[i])
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		m.then(func(values []Value) {
+			fieldVal, err := expectSingle(values, "struct field")
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			structVal.Fields[index] = fieldVal
+			field(i + 1)
+		})
+		m.expr(lit.Fields[i].Expr)
+	}
+	field(0)
+}
+
+func (m *machine) index(i *parser.Index) {
+	m.then(func(values []Value) {
+		object, err := expectSingle(values, "index")
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		if i.TypeArgsOrNil != nil {
+			values, err := m.e.instantiateValue(object, i.TypeArgsOrNil)
+			m.result(values, nil, err)
+			return
+		}
+		m.then(func(values []Value) {
+			values, err := indexValue(object, values)
+			m.result(values, nil, err)
+		})
+		m.expr(i.Index)
+	})
+	m.expr(i.Object)
+}
+
+// match는 ValuateMatch와 같이 위의 갈래부터 패턴과 가드가 맞는 첫 갈래의 본문을 평가함
+func (m *machine) match(node *parser.Match) {
+	m.then(func(values []Value) {
+		subject, err := expectSingle(values, "match")
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		m.arm(node, subject, 0)
+	})
+	m.expr(node.Subject)
+}
+
+// arm은 i번째 갈래를 새 스코프에서 검사하고, 맞지 않으면 스코프를 내린 뒤 다음 갈래로 넘어감
+func (m *machine) arm(node *parser.Match, subject Value, i int) {
+	e := m.e
+	if i == len(node.Arms) {
+		m.fail(fmt.Errorf("no match arm matched value %s", subject.Inspect()))
+		return
+	}
+	arm := node.Arms[i]
+	matched := true
+	e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
+	m.push(kont{
+		next: func(values []Value) {
+			e.popEnvFrame()
+			if !matched {
+				m.arm(node, subject, i+1)
+				return
+			}
+			m.values = values
+		},
+		unwindOrNil: e.popEnvFrame,
+	})
+	// 패턴 안의 리터럴엔 호출이 없으므로 바로 검사함
+	ok, ctrlSig, err := e.matchPattern(arm.Pattern, subject)
+	if err != nil || ctrlSig != nil || !ok {
+		matched = false
+		m.result(nil, ctrlSig, err)
+		return
+	}
+	if arm.GuardOrNil == nil {
+		m.expr(arm.Body)
+		return
+	}
+	m.then(func(values []Value) {
+		guard, err := guardValue(values)
+		if err != nil || !guard {
+			matched = false
+			m.result(nil, nil, err)
+			return
+		}
+		m.expr(arm.Body)
+	})
+	m.expr(arm.GuardOrNil)
+}
+
+// block은 본문의 문장들을 차례로 실행함. reuseCurrentEnv라면 새 환경을 쌓지 않음
+func (m *machine) block(block parser.Block, reuseCurrentEnv bool) {
+	if !reuseCurrentEnv {
+		m.scope()
+	}
+	m.stmts(block.StmtsOrNil)
+}
+
+func (m *machine) stmts(stmts []parser.Stmt) {
+	if len(stmts) == 0 {
+		return
+	}
+	if len(stmts) > 1 {
+		m.then(func([]Value) {
+			m.stmts(stmts[1:])
+		})
+	}
+	m.stmt(stmts[0])
+}
+
+// stmt는 문장 하나를 실행함. 호출이 없는 문장은 evalStmt로 바로 실행함
+func (m *machine) stmt(stmt parser.Stmt) {
+	e := m.e
+	if !e.heapStackOrNil.hasCall(stmt) {
+		ctrlSig, err := e.evalStmt(stmt)
+		m.result(nil, ctrlSig, err)
+		return
+	}
+	switch node := stmt.(type) {
+	case *parser.Assign:
+		m.targets(node.Exprs, len(node.Ids), nil, func(values []Value) {
+			m.result(nil, nil, e.assignValues(node, values))
+		})
+	case *parser.CallStmt:
+		m.expr(&node.Call)
+	case *parser.TryStmt:
+		// 전파되지 않은 경우 남는 값은 버림
+		m.expr(node.Try)
+	case *parser.ShortDecl:
+		m.targets(node.Exprs, len(node.Ids), nil, func(values []Value) {
+			m.result(nil, nil, e.setValuesForIds(node.Ids, values))
+		})
+	case *parser.VarDecl:
+		m.targets(node.ExprsOrNil, len(node.Ids), &node.Type, func(values []Value) {
+			m.result(nil, nil, e.setValuesForIds(node.Ids, values))
+		})
+	case *parser.Return:
+		m.ret(node)
+	case *parser.If:
+		m.ifStmt(node)
+	case *parser.ForBexp:
+		m.forBexp(node)
+	case *parser.ForWithAssign:
+		m.forWithAssign(node)
+	case *parser.ForRange:
+		m.forRange(node)
+	case *parser.Yield:
+		m.exprs(node.Exprs, func(values []Value) {
+			ctrlSig, err := e.yieldValues(values)
+			m.result(nil, ctrlSig, err)
+		})
+	case *parser.TypeSwitch:
+		m.typeSwitch(node)
+	case *parser.Switch:
+		m.switchStmt(node)
+	case *parser.Block:
+		m.block(*node, false)
+	default:
+		m.fail(fmt.Errorf("unknown stmt node: %T", stmt))
+	}
+}
+
+// ret은 evalReturn과 같이 return의 값들을 평가함. 유일한 식이 호출이라면 꼬리 호출 신호를 남김
+func (m *machine) ret(node *parser.Return) {
+	returnValues := func(values []Value) {
+		m.result(nil, newControlSignal(CtrlReturn, values), nil)
+	}
+	call, ok := node.ExprsOrNil[0].(*parser.Call)
+	if !ok || len(node.ExprsOrNil) != 1 {
+		m.exprs(node.ExprsOrNil, returnValues)
+		return
+	}
+	m.callee(call, nil, func(callee Value, args []Value) {
+		if tailCall := tailCallOf(callee, args); tailCall != nil {
+			m.result(nil, tailCall, nil)
+			return
+		}
+		m.then(returnValues)
+		m.callValue(callee, args)
+	})
+}
+
+func (m *machine) ifStmt(node *parser.If) {
+	m.scope()
+	m.then(func([]Value) {
+		m.cond(node.Bexp, func(cond bool) {
+			if cond {
+				m.block(node.ThenBlock, false)
+				return
+			}
+			if node.ElseOrNil != nil {
+				m.block(*node.ElseOrNil, false)
+			}
+		})
+	})
+	if node.ShortDeclOrNil != nil {
+		m.stmt(node.ShortDeclOrNil)
+	}
+}
+
+// loopBody는 루프 본문을 한 번 실행한 뒤 again으로 다음 반복을 이어 감
+// break는 루프를 끝내고, continue는 본문의 나머지를 건너뛰고 again으로 넘어감
+func (m *machine) loopBody(block parser.Block, again func()) {
+	m.push(kont{
+		next: func([]Value) {
+			again()
+		},
+		unwindOrNil: func() {
+			if m.err != nil {
+				return
+			}
+			switch m.ctrlSig.Kind {
+			case CtrlBreak:
+				m.ctrlSig = nil
+			case CtrlContinue:
+				m.ctrlSig = nil
+				again()
+			}
+		},
+	})
+	m.block(block, false)
+}
+
+func (m *machine) forBexp(node *parser.ForBexp) {
+	m.scope()
+	var iterate func()
+	iterate = func() {
+		m.cond(node.Bexp, func(cond bool) {
+			if cond {
+				m.loopBody(node.Block, iterate)
+			}
+		})
+	}
+	iterate()
+}
+
+func (m *machine) forWithAssign(node *parser.ForWithAssign) {
+	m.scope()
+	var iterate func()
+	post := func() {
+		// go 1.22와 같이 다음 반복의 변수는 후처리문 직전에 이전 반복의 값으로 새로 만들어짐
+		if node.LoopVarsCaptured {
+			m.e.renewLoopEnv()
+		}
+		m.then(func([]Value) {
+			iterate()
+		})
+		m.stmt(&node.Assign)
+	}
+	iterate = func() {
+		m.cond(node.Bexp, func(cond bool) {
+			if cond {
+				m.loopBody(node.Block, post)
+			}
+		})
+	}
+	m.then(func([]Value) {
+		iterate()
+	})
+	m.stmt(&node.ShortDecl)
+}
+
+func (m *machine) forRange(node *parser.ForRange) {
+	e := m.e
+	m.then(func(values []Value) {
+		it, err := e.rangeOver(values)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		// 리졸버와 동일하게 range 변수를 위한 루프 스코프
+		m.scope()
+		m.push(kont{
+			next: func([]Value) {
+				it.stop()
+			},
+			unwindOrNil: it.stop,
+		})
+		var iterate func()
+		iterate = func() {
+			values, ok, ctrlSig, err := it.next()
+			if err != nil || ctrlSig != nil || !ok {
+				m.result(nil, ctrlSig, err)
+				return
+			}
+			if err := e.bindRangeVars(node, values); err != nil {
+				m.fail(err)
+				return
+			}
+			m.loopBody(node.Block, iterate)
+		}
+		iterate()
+	})
+	m.expr(node.Expr)
+}
+
+// switchBreak은 switch 안의 break가 switch만 빠져나가게 함
+func (m *machine) switchBreak() {
+	m.push(kont{
+		next: func([]Value) {},
+		unwindOrNil: func() {
+			if m.err == nil && m.ctrlSig.Kind == CtrlBreak {
+				m.ctrlSig = nil
+			}
+		},
+	})
+}
+
+func (m *machine) typeSwitch(node *parser.TypeSwitch) {
+	e := m.e
+	m.then(func(values []Value) {
+		subject, err := expectSingle(values, "type switch")
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		matched := e.typeSwitchClause(node, subject)
+		if matched == nil {
+			return
+		}
+		m.switchBreak()
+		// 리졸버와 동일하게 절마다 새 스코프. 바인딩은 그 스코프의 첫 슬롯에 위치함
+		m.scope()
+		if node.BindingOrNil != nil {
+			if err := e.setValueForId(*node.BindingOrNil, subject); err != nil {
+				m.fail(err)
+				return
+			}
+		}
+		m.block(matched.Block, true)
+	})
+	m.expr(node.Subject)
+}
+
+func (m *machine) switchStmt(node *parser.Switch) {
+	m.switchBreak()
+	// 리졸버와 동일하게 init 문장을 위한 switch 스코프
+	m.scope()
+	m.then(func([]Value) {
+		if node.TagOrNil == nil {
+			m.switchCase(node, nil, 0, -1)
+			return
+		}
+		m.then(func(values []Value) {
+			tag, err := expectSingle(values, "switch tag")
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			m.switchCase(node, tag, 0, -1)
+		})
+		m.expr(node.TagOrNil)
+	})
+	if node.ShortDeclOrNil != nil {
+		m.stmt(node.ShortDeclOrNil)
+	}
+}
+
+// switchCase는 EvalSwitch와 같이 i번째 절부터 case 식을 평가해 맞는 절을 찾음
+// 맞는 절이 없으면 defaultIndex의 절을 실행함
+func (m *machine) switchCase(node *parser.Switch, tagOrNil Value, i int, defaultIndex int) {
+	if i == len(node.Clauses) {
+		if defaultIndex >= 0 {
+			m.clausesFrom(node, defaultIndex)
+		}
+		return
+	}
+	clause := node.Clauses[i]
+	if clause.IsDefault {
+		m.switchCase(node, tagOrNil, i+1, i)
+		return
+	}
+	m.caseExprs(tagOrNil, clause.Exprs, func(matched bool) {
+		if matched {
+			m.clausesFrom(node, i)
+			return
+		}
+		m.switchCase(node, tagOrNil, i+1, defaultIndex)
+	})
+}
+
+// caseExprs는 caseMatches와 같이 case 식 중 하나라도 맞는지를 k에 넘김
+func (m *machine) caseExprs(tagOrNil Value, exprs []parser.Expr, k func(matched bool)) {
+	if len(exprs) == 0 {
+		k(false)
+		return
+	}
+	next := func(matched bool) {
+		if matched {
+			k(true)
+			return
+		}
+		m.caseExprs(tagOrNil, exprs[1:], k)
+	}
+	if tagOrNil == nil {
+		m.cond(exprs[0], next)
+		return
+	}
+	m.then(func(values []Value) {
+		eq, err := caseEqual(tagOrNil, values)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		next(eq)
+	})
+	m.expr(exprs[0])
+}
+
+// clausesFrom은 i번째 절을 새 스코프에서 실행하고, fallthrough라면 다음 절로 넘어감
+func (m *machine) clausesFrom(node *parser.Switch, i int) {
+	if node.Clauses[i].Fallthrough && i+1 < len(node.Clauses) {
+		m.then(func([]Value) {
+			m.clausesFrom(node, i+1)
+		})
+	}
+	m.block(node.Clauses[i].Block, false)
+}
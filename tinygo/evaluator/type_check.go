@@ -0,0 +1,281 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// 타입 단언, type switch, interface 충족 여부를 런타임에 판단하는 함수들
+// tiny go는 정적 타입 검사가 없으므로, 값의 동적 타입과 선언된 타입을 여기서 비교함
+
+// valueHasType은 v의 동적 타입이 t와 일치하거나, t가 interface일 때 v가 이를 충족하는지 검사함
+func (e *Evaluator) valueHasType(v Value, t parser.Type) bool {
+	switch t.TypeKind {
+	case parser.IntType:
+		return v.Kind() == IntKind
+	case parser.BoolType:
+		return v.Kind() == BoolKind
+	case parser.StringType:
+		return v.Kind() == StrKind
+	case parser.ErrorType:
+		return v.Kind() == ErrKind
+	case parser.FuncionType:
+		return isCallable(v)
+	case parser.NamedType:
+		if typeArg, ok := e.typeArgFor(*t.NameOrNil); ok {
+			return e.valueHasType(v, typeArg)
+		}
+		decl, ok := e.typeDeclForName(*t.NameOrNil)
+		if !ok {
+			return false
+		}
+		if decl.Type.TypeKind == parser.InterfaceTypeKind {
+			return e.implements(v, decl.Type.InterfaceOrNil)
+		}
+		if decl.Type.TypeKind == parser.SumTypeKind {
+			adtVal, ok := v.(*AdtValue)
+			return ok && adtVal.TypeId == decl.Id.IdId
+		}
+		structVal, ok := v.(*StructValue)
+		return ok && structVal.TypeIdOrNil != nil && *structVal.TypeIdOrNil == decl.Id.IdId
+	case parser.StructureType:
+		structVal, ok := v.(*StructValue)
+		if !ok || structVal.TypeIdOrNil != nil {
+			return false
+		}
+		return sameStructType(structVal, e.zeroStruct(nil, t.StructOrNil))
+	case parser.InterfaceTypeKind:
+		return e.implements(v, t.InterfaceOrNil)
+	case parser.SliceType:
+		sliceVal, ok := v.(*SliceValue)
+		return ok && e.sameType(sliceVal.ElemType, *t.ElemOrNil)
+	case parser.TupleType:
+		tupleVal, ok := v.(*TupleValue)
+		if !ok || len(tupleVal.Elems) != len(t.TupleOrNil) {
+			return false
+		}
+		for i, elem := range tupleVal.Elems {
+			if !e.valueHasType(elem, t.TupleOrNil[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// implements는 v의 메서드 집합이 iface의 모든 메서드를 같은 시그니처로 포함하는지 검사함
+// go와 같이 명시적인 implements 선언 없이 암묵적으로 충족됨
+// 제약으로 쓰이는 interface라면 comparable 여부와 타입 원소에 속하는지도 검사함
+func (e *Evaluator) implements(v Value, iface *parser.InterfaceType) bool {
+	if v.Kind() == NilKind {
+		return false
+	}
+	if iface.Comparable {
+		if _, comparable := equalValues(v, v); !comparable {
+			return false
+		}
+	}
+	if len(iface.UnionOrNil) > 0 {
+		inUnion := false
+		for _, member := range iface.UnionOrNil {
+			if e.valueHasType(v, member) {
+				inUnion = true
+				break
+			}
+		}
+		if !inUnion {
+			return false
+		}
+	}
+	for _, spec := range iface.Methods {
+		method, ok := e.methodOf(v, spec.Id.Name)
+		if !ok {
+			return false
+		}
+		// 메서드 클로저의 첫 번째 매개변수는 리시버임
+		params := method.Params[1:]
+		if len(params) != len(spec.FuncType.ArgTypesOrNil) || len(method.ReturnTypes) != len(spec.FuncType.ReturnTypesOrNil) {
+			return false
+		}
+		for i, param := range params {
+			if !e.sameType(param.Type, spec.FuncType.ArgTypesOrNil[i]) {
+				return false
+			}
+		}
+		for i, ret := range method.ReturnTypes {
+			if !e.sameType(ret, spec.FuncType.ReturnTypesOrNil[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// methodOf는 v의 동적 타입에 선언된 이름이 name인 메서드를 찾음
+func (e *Evaluator) methodOf(v Value, name string) (*ClosureValue, bool) {
+	structVal, ok := v.(*StructValue)
+	if !ok || structVal.TypeIdOrNil == nil {
+		return nil, false
+	}
+	method, ok := e.methods[*structVal.TypeIdOrNil][name]
+	return method, ok
+}
+
+// sameType은 두 타입 표현이 같은 타입을 가리키는지 비교함
+// 이름 있는 타입은 이름이 아닌, 리졸브된 TypeDecl로 비교함
+func (e *Evaluator) sameType(left, right parser.Type) bool {
+	// 타입 매개변수는 현재 묶인 타입 인자로 바꿔 비교함
+	if left.TypeKind == parser.NamedType {
+		if typeArg, ok := e.typeArgFor(*left.NameOrNil); ok {
+			left = typeArg
+		}
+	}
+	if right.TypeKind == parser.NamedType {
+		if typeArg, ok := e.typeArgFor(*right.NameOrNil); ok {
+			right = typeArg
+		}
+	}
+	if left.TypeKind != right.TypeKind {
+		return false
+	}
+	switch left.TypeKind {
+	case parser.NamedType:
+		leftDecl, lok := e.typeDeclForName(*left.NameOrNil)
+		rightDecl, rok := e.typeDeclForName(*right.NameOrNil)
+		return lok && rok && leftDecl == rightDecl
+	case parser.FuncionType:
+		return e.sameFuncType(*left.FuncTypeOrNil, *right.FuncTypeOrNil)
+	case parser.StructureType:
+		if len(left.StructOrNil.Fields) != len(right.StructOrNil.Fields) {
+			return false
+		}
+		for i, field := range left.StructOrNil.Fields {
+			other := right.StructOrNil.Fields[i]
+			if field.Id.Name != other.Id.Name || !e.sameType(field.Type, other.Type) {
+				return false
+			}
+		}
+		return true
+	case parser.InterfaceTypeKind:
+		if len(left.InterfaceOrNil.Methods) != len(right.InterfaceOrNil.Methods) {
+			return false
+		}
+		for i, method := range left.InterfaceOrNil.Methods {
+			other := right.InterfaceOrNil.Methods[i]
+			if method.Id.Name != other.Id.Name || !e.sameFuncType(method.FuncType, other.FuncType) {
+				return false
+			}
+		}
+		return true
+	case parser.SliceType:
+		return e.sameType(*left.ElemOrNil, *right.ElemOrNil)
+	case parser.TupleType:
+		if len(left.TupleOrNil) != len(right.TupleOrNil) {
+			return false
+		}
+		for i := range left.TupleOrNil {
+			if !e.sameType(left.TupleOrNil[i], right.TupleOrNil[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+func (e *Evaluator) sameFuncType(left, right parser.FuncType) bool {
+	if len(left.ArgTypesOrNil) != len(right.ArgTypesOrNil) || len(left.ReturnTypesOrNil) != len(right.ReturnTypesOrNil) {
+		return false
+	}
+	for i := range left.ArgTypesOrNil {
+		if !e.sameType(left.ArgTypesOrNil[i], right.ArgTypesOrNil[i]) {
+			return false
+		}
+	}
+	for i := range left.ReturnTypesOrNil {
+		if !e.sameType(left.ReturnTypesOrNil[i], right.ReturnTypesOrNil[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDynamicType은 두 값의 동적 타입이 같은지 비교함
+// 이름 있는 struct는 이름이 아닌 선언으로 비교하므로, 다른 패키지의 같은 이름 타입과 구분됨
+func sameDynamicType(left, right Value) bool {
+	leftStruct, lok := left.(*StructValue)
+	rightStruct, rok := right.(*StructValue)
+	if lok || rok {
+		return lok && rok && sameStructType(leftStruct, rightStruct)
+	}
+	leftAdt, lok := left.(*AdtValue)
+	rightAdt, rok := right.(*AdtValue)
+	if lok || rok {
+		return lok && rok && leftAdt.TypeId == rightAdt.TypeId
+	}
+	return dynamicTypeName(left) == dynamicTypeName(right)
+}
+
+// dynamicTypeName은 에러 메시지에 쓰일 v의 동적 타입 이름을 리턴함
+func dynamicTypeName(v Value) string {
+	switch val := v.(type) {
+	case *IntValue:
+		return "int"
+	case *BoolValue:
+		return "bool"
+	case *StringValue:
+		return "string"
+	case *ErrorValue:
+		return "error"
+	case *StructValue:
+		return val.TypeName
+	case *AdtValue:
+		return val.TypeName
+	case *GeneratorValue:
+		return "generator"
+	case *VectorValue:
+		return "vector"
+	case *HashMapValue:
+		return "hashMap"
+	case *NilValue:
+		return "nil"
+	case *SliceValue:
+		return "[]" + val.ElemType.String()
+	case *TupleValue:
+		elems := make([]string, len(val.Elems))
+		for i, elem := range val.Elems {
+			elems[i] = dynamicTypeName(elem)
+		}
+		return "(" + strings.Join(elems, ", ") + ")"
+	default:
+		return "func"
+	}
+}
+
+// typeName은 에러 메시지에 쓰일 타입 표현의 이름을 리턴함
+func typeName(t parser.Type) string {
+	switch t.TypeKind {
+	case parser.NamedType:
+		return t.NameOrNil.Name
+	case parser.StructureType:
+		return "struct"
+	case parser.InterfaceTypeKind:
+		return "interface"
+	case parser.FuncionType:
+		return "func"
+	case parser.SliceType:
+		return "[]" + typeName(*t.ElemOrNil)
+	case parser.TupleType:
+		elems := make([]string, len(t.TupleOrNil))
+		for i, elem := range t.TupleOrNil {
+			elems[i] = typeName(elem)
+		}
+		return "(" + strings.Join(elems, ", ") + ")"
+	default:
+		return t.String()
+	}
+}
@@ -20,6 +20,16 @@ func (e *Evaluator) Valuate(expr parser.Expr) ([]Value, *ControlSignal, error) {
 		return e.ValuatePrimary(node)
 	case *parser.Call:
 		return e.ValuateCall(node)
+	case *parser.Selector:
+		return e.ValuateSelector(node)
+	case *parser.TypeAssert:
+		return e.ValuateTypeAssert(node, false)
+	case *parser.Index:
+		return e.ValuateIndex(node)
+	case *parser.Try:
+		return e.ValuateTry(node)
+	case *parser.Match:
+		return e.ValuateMatch(node)
 	default:
 		return nil, nil, fmt.Errorf("unknown expr node: %T", expr)
 	}
@@ -30,6 +40,11 @@ func (e *Evaluator) ValuateUnary(u *parser.Unary) ([]Value, *ControlSignal, erro
 	if err != nil || ctrlSigOrNil != nil {
 		return nil, ctrlSigOrNil, err
 	}
+	return unaryOp(u, values)
+}
+
+// unaryOp는 평가된 피연산자에 단항 연산을 적용함
+func unaryOp(u *parser.Unary, values []Value) ([]Value, *ControlSignal, error) {
 	v, err := expectSingle(values, "unary")
 	if err != nil {
 		return nil, nil, err
@@ -56,6 +71,8 @@ func (e *Evaluator) ValuateUnary(u *parser.Unary) ([]Value, *ControlSignal, erro
 
 func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, error) {
 	switch b.Op {
+	case parser.Pipe:
+		return e.ValuatePipe(b)
 	case parser.And, parser.Or:
 		// And, Or최적화에 기반한 로직임
 		// &&이면서 left가 거짓-> 무조건 거짓. || 이면서 left가 참->무조건 참
@@ -64,33 +81,22 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 		if err != nil || ctrlSigOrNil != nil {
 			return nil, ctrlSigOrNil, err
 		}
-		leftVal, err := expectSingle(leftValues, "binary")
+		left, err := logicalOperand(leftValues)
 		if err != nil {
 			return nil, nil, err
 		}
-		leftBool, ok := leftVal.(*BoolValue)
-		if !ok {
-			return nil, nil, fmt.Errorf("logical op expects bool")
-		}
-		if b.Op == parser.And && !leftBool.Value {
-			return []Value{newBoolVal(false)}, nil, nil
-		}
-		if b.Op == parser.Or && leftBool.Value {
-			return []Value{newBoolVal(true)}, nil, nil
+		if shortCircuits(b.Op, left) {
+			return []Value{newBoolVal(left)}, nil, nil
 		}
 		rightValues, ctrlSigOrNil, err := e.Valuate(b.RightExpr)
 		if err != nil || ctrlSigOrNil != nil {
 			return nil, ctrlSigOrNil, err
 		}
-		rightVal, err := expectSingle(rightValues, "binary")
+		right, err := logicalOperand(rightValues)
 		if err != nil {
 			return nil, nil, err
 		}
-		rightBool, ok := rightVal.(*BoolValue)
-		if !ok {
-			return nil, nil, fmt.Errorf("logical op expects bool")
-		}
-		return []Value{newBoolVal(rightBool.Value)}, nil, nil
+		return []Value{newBoolVal(right)}, nil, nil
 	}
 
 	leftValues, ctrlSigOrNil, err := e.Valuate(b.LeftExpr)
@@ -105,12 +111,34 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 	if err != nil || ctrlSigOrNil != nil {
 		return nil, ctrlSigOrNil, err
 	}
+	return binaryOp(b.Op, leftVal, rightValues)
+}
+
+// logicalOperand는 &&, ||의 피연산자 값을 bool로 꺼냄
+func logicalOperand(values []Value) (bool, error) {
+	v, err := expectSingle(values, "binary")
+	if err != nil {
+		return false, err
+	}
+	boolVal, ok := v.(*BoolValue)
+	if !ok {
+		return false, fmt.Errorf("logical op expects bool")
+	}
+	return boolVal.Value, nil
+}
+
+// shortCircuits는 왼쪽 값만으로 &&, ||의 결과가 정해지는지 리턴함. 정해진다면 결과는 왼쪽 값과 같음
+func shortCircuits(op parser.BinaryKind, left bool) bool {
+	return (op == parser.And && !left) || (op == parser.Or && left)
+}
+
+// binaryOp는 평가된 두 피연산자에 &&, ||, |>가 아닌 이항 연산을 적용함
+func binaryOp(op parser.BinaryKind, leftVal Value, rightValues []Value) ([]Value, *ControlSignal, error) {
 	rightVal, err := expectSingle(rightValues, "binary")
 	if err != nil {
 		return nil, nil, err
 	}
-
-	switch b.Op {
+	switch op {
 	case parser.Plus, parser.MinusBinary, parser.Mul, parser.Div:
 		leftInt, lok := leftVal.(*IntValue)
 		rightInt, rok := rightVal.(*IntValue)
@@ -119,7 +147,7 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 		// 둘 다  int인 경우가 아닐 경우, string집합 위의 +연산인지 검증함
 		if !lok || !rok {
 
-			if b.Op == parser.Plus {
+			if op == parser.Plus {
 				leftStr, lsok := leftVal.(*StringValue)
 				rightStr, rsok := rightVal.(*StringValue)
 				if lsok && rsok {
@@ -128,7 +156,7 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 			}
 			return nil, nil, fmt.Errorf("arithmetic op expects int")
 		}
-		switch b.Op {
+		switch op {
 		case parser.Plus:
 			return []Value{newIntVal(leftInt.Value + rightInt.Value)}, nil, nil
 		case parser.MinusBinary:
@@ -146,7 +174,7 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 		if !ok {
 			return nil, nil, fmt.Errorf("equality op expects same comparable types")
 		}
-		if b.Op == parser.NotEqual {
+		if op == parser.NotEqual {
 			eq = !eq
 		}
 		return []Value{newBoolVal(eq)}, nil, nil
@@ -156,7 +184,7 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 		if !lok || !rok {
 			return nil, nil, fmt.Errorf("comparison op expects int")
 		}
-		switch b.Op {
+		switch op {
 		case parser.GreaterThan:
 			return []Value{newBoolVal(leftInt.Value > rightInt.Value)}, nil, nil
 		case parser.GreaterOrEqual:
@@ -167,7 +195,7 @@ func (e *Evaluator) ValuateBinary(b *parser.Binary) ([]Value, *ControlSignal, er
 			return []Value{newBoolVal(leftInt.Value <= rightInt.Value)}, nil, nil
 		}
 	}
-	return nil, nil, fmt.Errorf("unknown binary op: %v", b.Op)
+	return nil, nil, fmt.Errorf("unknown binary op: %v", op)
 }
 
 func (e *Evaluator) ValuatePrimary(p *parser.Primary) ([]Value, *ControlSignal, error) {
@@ -181,6 +209,15 @@ func (e *Evaluator) ValuatePrimary(p *parser.Primary) ([]Value, *ControlSignal,
 		}
 		return []Value{val}, nil, nil
 	case parser.ValuePrimary:
+		if p.ValueOrNil != nil && p.ValueOrNil.ValueKind == parser.StructLitValue {
+			return e.ValuateStructLit(p.ValueOrNil.StructLitOrNil)
+		}
+		if p.ValueOrNil != nil && p.ValueOrNil.ValueKind == parser.SliceLitValue {
+			return e.ValuateSliceLit(p.ValueOrNil.SliceLitOrNil)
+		}
+		if p.ValueOrNil != nil && p.ValueOrNil.ValueKind == parser.TupleLitValue {
+			return e.ValuateTupleLit(p.ValueOrNil.TupleLitOrNil)
+		}
 		val, err := e.ValuateValueForm(p.ValueOrNil)
 		if err != nil {
 			return nil, nil, err
@@ -215,116 +252,628 @@ func (e *Evaluator) ValuateValueForm(v *parser.ValueForm) (Value, error) {
 			return nil, fmt.Errorf("func literal missing body")
 		}
 		fexp := v.FexpOrNil
-		return newClosureVal(nil, fexp.ParamsOrNil, fexp.ReturnTypesOrNil, fexp.Block, e.CurrentEnv()), nil
+		return e.capturingClosure(newClosureVal(nil, fexp.ParamsOrNil, fexp.ReturnTypesOrNil, fexp.Block, e.CurrentEnv(), fexp.IsGenerator)), nil
+	case parser.StructLitValue:
+		// struct 리터럴은 필드 식에서 제어 신호가 발생할 수 있으므로 ValuatePrimary에서 처리함
+		return nil, fmt.Errorf("struct literal must be valuated as primary")
+	case parser.SliceLitValue:
+		// 슬라이스 리터럴도 원소 식에서 제어 신호가 발생할 수 있으므로 ValuatePrimary에서 처리함
+		return nil, fmt.Errorf("slice literal must be valuated as primary")
+	case parser.TupleLitValue:
+		return nil, fmt.Errorf("tuple literal must be valuated as primary")
 	default:
 		return nil, fmt.Errorf("unknown value kind: %v", v.ValueKind)
 	}
 }
 
+// ValuateStructLit은 선언된 필드 순서대로 값을 채우고, 생략된 필드는 제로값으로 채움
+func (e *Evaluator) ValuateStructLit(lit *parser.StructLit) ([]Value, *ControlSignal, error) {
+	structVal, err := e.zeroStructForLit(lit)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, init := range lit.Fields {
+		index, err := structFieldIndex(structVal, init)
+		if err != nil {
+			return nil, nil, err
+		}
+		values, ctrlSigOrNil, err := e.Valuate(init.Expr)
+		if err != nil || ctrlSigOrNil != nil {
+			return nil, ctrlSigOrNil, err
+		}
+		fieldVal, err := expectSingle(values, "struct field")
+		if err != nil {
+			return nil, nil, err
+		}
+		structVal.Fields[index] = fieldVal
+	}
+	return []Value{structVal}, nil, nil
+}
+
+// zeroStructForLit은 struct 리터럴의 타입으로 모든 필드가 제로값인 값을 만듦
+func (e *Evaluator) zeroStructForLit(lit *parser.StructLit) (*StructValue, error) {
+	decl, ok := e.typeDeclForName(lit.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("unknown type: %s", lit.TypeName.Name)
+	}
+	if decl.Type.TypeKind != parser.StructureType {
+		return nil, fmt.Errorf("%s is not a struct type", lit.TypeName.Name)
+	}
+	return e.zeroStruct(decl, decl.Type.StructOrNil), nil
+}
+
+// structFieldIndex는 필드 식을 평가하기 전에 리터럴이 채우는 필드의 위치를 찾음
+func structFieldIndex(structVal *StructValue, init parser.FieldInit) (int, error) {
+	index := structVal.fieldIndex(init.Field.Name)
+	if index < 0 {
+		return -1, fmt.Errorf("unknown field %s in struct literal of type %s", init.Field.Name, structVal.TypeName)
+	}
+	return index, nil
+}
+
+func (e *Evaluator) ValuateSliceLit(lit *parser.SliceLit) ([]Value, *ControlSignal, error) {
+	elems := make([]Value, 0, len(lit.Elems))
+	for _, elemExpr := range lit.Elems {
+		values, ctrlSigOrNil, err := e.Valuate(elemExpr)
+		if err != nil || ctrlSigOrNil != nil {
+			return nil, ctrlSigOrNil, err
+		}
+		elem, err := expectSingle(values, "slice element")
+		if err != nil {
+			return nil, nil, err
+		}
+		elems = append(elems, elem)
+	}
+	return []Value{e.sliceLitOf(lit, elems)}, nil, nil
+}
+
+// sliceLitOf는 평가된 원소들로 슬라이스 리터럴의 값을 만듦
+func (e *Evaluator) sliceLitOf(lit *parser.SliceLit, elems []Value) *SliceValue {
+	// 슬라이스는 함수 밖으로 나갈 수 있으므로 원소 타입의 타입 매개변수를 지금 바꿔 둠
+	return newSliceVal(e.substTypeParams(lit.ElemType, e.currentTypeArgs()), elems)
+}
+
+func (e *Evaluator) ValuateTupleLit(lit *parser.TupleLit) ([]Value, *ControlSignal, error) {
+	elems := make([]Value, 0, len(lit.Elems))
+	for _, elemExpr := range lit.Elems {
+		values, ctrlSigOrNil, err := e.Valuate(elemExpr)
+		if err != nil || ctrlSigOrNil != nil {
+			return nil, ctrlSigOrNil, err
+		}
+		elem, err := expectSingle(values, "tuple element")
+		if err != nil {
+			return nil, nil, err
+		}
+		elems = append(elems, elem)
+	}
+	return []Value{newTupleVal(elems)}, nil, nil
+}
+
+// ValuateTry는 ? 의 대상을 평가해 마지막 값이 ok라면 나머지 값들을 리턴함
+// 마지막 값이 error라면, 감싼 함수가 나머지 리턴 값은 제로값으로 채워 그 error를 리턴하게 함
+func (e *Evaluator) ValuateTry(t *parser.Try) ([]Value, *ControlSignal, error) {
+	values, ctrlSigOrNil, err := e.Valuate(t.Object)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	return e.tryValues(values)
+}
+
+// tryValues는 ? 의 대상을 평가한 값들에서 마지막 error를 검사함
+func (e *Evaluator) tryValues(values []Value) ([]Value, *ControlSignal, error) {
+	// (T, error) 튜플 하나도 풀어서 다룸
+	if len(values) == 1 {
+		if tuple, ok := values[0].(*TupleValue); ok {
+			values = tuple.Elems
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("? expects error as last value")
+	}
+	errVal, ok := values[len(values)-1].(*ErrorValue)
+	if !ok {
+		return nil, nil, fmt.Errorf("? expects error as last value, got %s", dynamicTypeName(values[len(values)-1]))
+	}
+	if errVal.IsOk {
+		return values[:len(values)-1], nil, nil
+	}
+	returnTypes := e.callStack.peekReturnTypes()
+	results := make([]Value, len(returnTypes))
+	for i, ret := range returnTypes[:len(returnTypes)-1] {
+		results[i] = e.ZeroValueForType(ret)
+	}
+	results[len(results)-1] = errVal
+	return nil, newControlSignal(CtrlReturn, results), nil
+}
+
+func (e *Evaluator) ValuateIndex(i *parser.Index) ([]Value, *ControlSignal, error) {
+	values, ctrlSigOrNil, err := e.Valuate(i.Object)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	object, err := expectSingle(values, "index")
+	if err != nil {
+		return nil, nil, err
+	}
+	if i.TypeArgsOrNil != nil {
+		values, err := e.instantiateValue(object, i.TypeArgsOrNil)
+		return values, nil, err
+	}
+	values, ctrlSigOrNil, err = e.Valuate(i.Index)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	values, err = indexValue(object, values)
+	return values, nil, err
+}
+
+// instantiateValue는 f[int, string] 처럼 타입 인자를 묶은 새 함수 값을 만듦
+func (e *Evaluator) instantiateValue(object Value, typeArgs []parser.Type) ([]Value, error) {
+	closure, ok := object.(*ClosureValue)
+	if !ok || len(closure.TypeParams) == 0 {
+		return nil, fmt.Errorf("cannot instantiate non-generic %s", dynamicTypeName(object))
+	}
+	instance, err := e.instantiate(closure, typeArgs)
+	if err != nil {
+		return nil, err
+	}
+	return []Value{instance}, nil
+}
+
+// indexValue는 평가된 대상 object에서 평가된 인덱스 값이 가리키는 원소를 꺼냄
+func indexValue(object Value, indexValues []Value) ([]Value, error) {
+	indexVal, err := expectSingle(indexValues, "index")
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := object.(*HashMapValue); ok {
+		h, err := hashValue(indexVal)
+		if err != nil {
+			return nil, err
+		}
+		value, found := m.Map.Get(h, indexVal)
+		if !found {
+			return nil, fmt.Errorf("key %s not found in hashMap", indexVal.Inspect())
+		}
+		return []Value{value}, nil
+	}
+	index, ok := indexVal.(*IntValue)
+	if !ok {
+		return nil, fmt.Errorf("index must be int, got %s", indexVal.Inspect())
+	}
+	switch obj := object.(type) {
+	case *SliceValue:
+		if index.Value < 0 || index.Value >= int64(len(obj.Elems)) {
+			return nil, fmt.Errorf("index out of range [%d] with length %d", index.Value, len(obj.Elems))
+		}
+		return []Value{obj.Elems[index.Value]}, nil
+	case *StringValue:
+		// go와 같이 문자열 인덱스는 바이트 값을 리턴함
+		if index.Value < 0 || index.Value >= int64(len(obj.Value)) {
+			return nil, fmt.Errorf("index out of range [%d] with length %d", index.Value, len(obj.Value))
+		}
+		return []Value{newIntVal(int64(obj.Value[index.Value]))}, nil
+	case *TupleValue:
+		if index.Value < 0 || index.Value >= int64(len(obj.Elems)) {
+			return nil, fmt.Errorf("index out of range [%d] with length %d", index.Value, len(obj.Elems))
+		}
+		return []Value{obj.Elems[index.Value]}, nil
+	case *VectorValue:
+		elem, ok := obj.Vec.Get(int(index.Value))
+		if !ok {
+			return nil, fmt.Errorf("index out of range [%d] with length %d", index.Value, obj.Vec.Len())
+		}
+		return []Value{elem}, nil
+	default:
+		return nil, fmt.Errorf("index expects slice, tuple, string, vector or hashMap, got %s", object.Inspect())
+	}
+}
+
+func (e *Evaluator) ValuateSelector(s *parser.Selector) ([]Value, *ControlSignal, error) {
+	// 다른 패키지의 전역(pkg.Name)은 리졸버가 Name을 해당 전역에 연결해 둠
+	if _, ok := e.resolveTable[s.Field.IdId]; ok {
+		val, err := e.valueForId(&s.Field)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []Value{val}, nil, nil
+	}
+	values, ctrlSigOrNil, err := e.Valuate(s.Object)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	values, err = e.selectValue(s, values)
+	return values, nil, err
+}
+
+// selectValue는 평가된 대상에서 모듈 멤버, 필드, 메서드 중 s가 가리키는 것을 꺼냄
+func (e *Evaluator) selectValue(s *parser.Selector, values []Value) ([]Value, error) {
+	object, err := expectSingle(values, "selector")
+	if err != nil {
+		return nil, err
+	}
+	if module, ok := object.(*ModuleValue); ok {
+		fn, ok := module.Members[s.Field.Name]
+		if !ok {
+			return nil, fmt.Errorf("undefined: %s.%s", module.Name, s.Field.Name)
+		}
+		return []Value{newBuiltinFuncVal(fn)}, nil
+	}
+	if object.Kind() == NilKind {
+		return nil, fmt.Errorf("nil interface has no field or method %s", s.Field.Name)
+	}
+	structVal, ok := object.(*StructValue)
+	if !ok {
+		return nil, fmt.Errorf("selector expects struct, got %s", object.Inspect())
+	}
+	if index := structVal.fieldIndex(s.Field.Name); index >= 0 {
+		return []Value{structVal.Fields[index]}, nil
+	}
+	// 필드가 아니라면 값의 동적 타입에서 메서드를 찾음 (동적 디스패치)
+	if method, ok := e.methodOf(structVal, s.Field.Name); ok {
+		return []Value{newBoundMethodVal(structVal, method)}, nil
+	}
+	return nil, fmt.Errorf("%s has no field or method %s", structVal.TypeName, s.Field.Name)
+}
+
+// ValuateTypeAssert는 v.(T)를 평가함
+// commaOk면 실패 시에도 에러 대신 (제로값, false)를 리턴함
+func (e *Evaluator) ValuateTypeAssert(t *parser.TypeAssert, commaOk bool) ([]Value, *ControlSignal, error) {
+	values, ctrlSigOrNil, err := e.Valuate(t.Object)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	values, err = e.assertValue(t, values, commaOk)
+	return values, nil, err
+}
+
+// assertValue는 평가된 대상이 t의 타입을 지니는지 검사함
+func (e *Evaluator) assertValue(t *parser.TypeAssert, values []Value, commaOk bool) ([]Value, error) {
+	if t.TypeOrNil == nil {
+		return nil, fmt.Errorf("use of .(type) outside type switch")
+	}
+	object, err := expectSingle(values, "type assertion")
+	if err != nil {
+		return nil, err
+	}
+	ok := e.valueHasType(object, *t.TypeOrNil)
+	if commaOk {
+		if !ok {
+			return []Value{e.ZeroValueForType(*t.TypeOrNil), newBoolVal(false)}, nil
+		}
+		return []Value{object, newBoolVal(true)}, nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("interface conversion: value is %s, not %s", dynamicTypeName(object), typeName(*t.TypeOrNil))
+	}
+	return []Value{object}, nil
+}
+
 func (e *Evaluator) ValuateCall(c *parser.Call) ([]Value, *ControlSignal, error) {
+	return e.valuateCallWithPiped(c, nil)
+}
+
+// valuateCallWithPiped는 호출식을 평가하되, pipedOrNil이 있으면 이를 첫 번째 호출의 인자 앞에 붙임
+// x |> f(a) 가 f(x, a) 와 같은 경로로 평가되도록 하기 위함임
+func (e *Evaluator) valuateCallWithPiped(c *parser.Call, pipedOrNil []Value) ([]Value, *ControlSignal, error) {
+	callee, args, ctrlSigOrNil, err := e.valuateCallee(c, pipedOrNil)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	return e.callValue(callee, args)
+}
+
+// valuateCallee는 호출식을 마지막 호출 직전까지 평가해, 마지막으로 호출할 함수 값과 그 인자들을 리턴함
+// f(a)(b) 라면 f(a)까지 호출하고, 그 결과와 b를 리턴함
+func (e *Evaluator) valuateCallee(c *parser.Call, pipedOrNil []Value) (Value, []Value, *ControlSignal, error) {
 
 	//가장 처음 평가된 "표현"은 primary임.
 	// 계속해서 평가를 리듀스 해 갈 예정
 	// f()()-> g()->h
 	appliedExpr, ctrlSigOrNil, err := e.Valuate(&c.PrimaryOrNil)
 	if err != nil || ctrlSigOrNil != nil {
-		return nil, ctrlSigOrNil, err
+		return nil, nil, ctrlSigOrNil, err
 	}
 
-	for _, argTuple := range c.ArgsList {
-		if len(appliedExpr) != 1 {
-			return nil, nil, fmt.Errorf("invalid call: the callee must evaluate to a single function")
-
+	for i, argTuple := range c.ArgsList {
+		callee, err := e.calleeAt(c, i, appliedExpr)
+		if err != nil {
+			return nil, nil, nil, err
 		}
-		callee := appliedExpr[0]
 		// args: 한 번 호출에 필요한 arg 튜플
-		args := make([]Value, 0, len(argTuple))
-		for _, expr := range argTuple {
-			// 인자는 현재 환경에서 평가
-			values, ctrlSigOrNil, err := e.Valuate(expr)
-			if err != nil || ctrlSigOrNil != nil {
-				return nil, ctrlSigOrNil, err
-			}
-			argVal, err := expectSingle(values, "call arg")
-			if err != nil {
-				return nil, nil, err
-			}
-			args = append(args, argVal)
+		args, ctrlSigOrNil, err := e.evalCallArgs(argTuple, callee)
+		if err != nil || ctrlSigOrNil != nil {
+			return nil, nil, ctrlSigOrNil, err
+		}
+		if i == 0 && pipedOrNil != nil {
+			args = pipedArgs(callee, pipedOrNil, args)
+		}
+		if i == len(c.ArgsList)-1 {
+			return callee, args, nil, nil
+		}
+		values, ctrlSig, err := e.callValue(callee, args)
+		if err != nil || ctrlSig != nil {
+			return nil, nil, ctrlSig, err
 		}
+		appliedExpr = values
+	}
+	return nil, nil, nil, fmt.Errorf("invalid call: missing arguments")
+}
 
-		switch fn := callee.(type) {
-		case *BuiltinFuncValue:
-			values, ctrlSig, err := fn.Func.Impl(e, args)
-			if err != nil || ctrlSig != nil {
-				return nil, ctrlSig, err
-			}
-			appliedExpr = values
-		case *ClosureValue:
-			values, ctrlSig, err := e.callClosure(fn, args)
-			if err != nil || ctrlSig != nil {
-				return nil, ctrlSig, err
-			}
-			appliedExpr = values
-		default:
-			return nil, nil, fmt.Errorf("call target is not callable")
+// calleeAt은 c의 i번째 호출에서 호출할 함수 값을 리턴함. applied는 그 직전까지 평가된 값들임
+func (e *Evaluator) calleeAt(c *parser.Call, i int, applied []Value) (Value, error) {
+	if len(applied) != 1 {
+		return nil, fmt.Errorf("invalid call: the callee must evaluate to a single function")
+	}
+	callee := applied[0]
+	// 리졸버가 추론해 둔 타입 인자가 있다면 먼저 인스턴스화함
+	if closure, ok := callee.(*ClosureValue); ok && i == 0 && c.TypeArgsOrNil != nil && len(closure.TypeParams) > 0 {
+		instance, err := e.instantiate(closure, c.TypeArgsOrNil)
+		if err != nil {
+			return nil, err
 		}
+		return instance, nil
+	}
+	return callee, nil
+}
+
+// pipedArgs는 |> 로 넘어온 값들을 첫 번째 호출의 인자 앞에 붙임
+func pipedArgs(callee Value, piped []Value, args []Value) []Value {
+	if len(args) == 0 {
+		return packArgsFor(callee, piped)
 	}
+	return append(append([]Value{}, piped...), args...)
+}
+
+// callValue는 이미 평가된 함수 값 callee를 args로 호출함
+func (e *Evaluator) callValue(callee Value, args []Value) ([]Value, *ControlSignal, error) {
+	switch fn := callee.(type) {
+	case *BuiltinFuncValue:
+		return fn.Func.Impl(e, args)
+	case *ClosureValue:
+		return e.callClosure(fn, args)
+	case *BoundMethodValue:
+		// 리시버를 첫 번째 인자로 붙여 메서드 클로저를 호출
+		receiverAndArgs := append([]Value{fn.Receiver}, args...)
+		return e.callClosure(fn.Method, receiverAndArgs)
+	default:
+		return nil, nil, fmt.Errorf("call target is not callable")
+	}
+}
+
+// ValuatePipe는 x |> rhs 를 평가함
+// rhs가 호출식이면 x를 그 첫 번째 인자로 붙이고, 아니면 rhs를 함수 값으로 평가해 x로 호출함
+// x가 다중 값이면 모두 앞쪽 인자로 펼쳐짐
+func (e *Evaluator) ValuatePipe(b *parser.Binary) ([]Value, *ControlSignal, error) {
+	piped, ctrlSigOrNil, err := e.Valuate(b.LeftExpr)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	if len(piped) == 0 {
+		return nil, nil, fmt.Errorf("|> expects a value on the left")
+	}
+	if call, ok := b.RightExpr.(*parser.Call); ok {
+		return e.valuateCallWithPiped(call, piped)
+	}
+	values, ctrlSigOrNil, err := e.Valuate(b.RightExpr)
+	if err != nil || ctrlSigOrNil != nil {
+		return nil, ctrlSigOrNil, err
+	}
+	callee, err := expectSingle(values, "|>")
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.callValue(callee, packArgsFor(callee, piped))
+}
 
-	return appliedExpr, nil, nil
+// packArgsFor는 다중 값이 통째로 인자가 될 때, callee가 튜플 매개변수 하나만 받는다면 이를 튜플로 묶음
+func packArgsFor(callee Value, values []Value) []Value {
+	if params := paramsOf(callee); len(values) > 1 && len(params) == 1 {
+		return packTuple(values, params[0].Type)
+	}
+	return values
 }
 
+// evalCallArgs는 한 번의 호출에 넘길 인자들을 평가함
+// go와 같이 다중 값 호출이 유일한 인자라면 그 값들을 인자로 펼침
+// 단, 호출 대상이 튜플 매개변수 하나만 받는다면 펼치지 않고 튜플로 묶어 넘김
+func (e *Evaluator) evalCallArgs(argExprs []parser.Expr, callee Value) ([]Value, *ControlSignal, error) {
+	if len(argExprs) == 1 {
+		values, ctrlSigOrNil, err := e.Valuate(argExprs[0])
+		if err != nil || ctrlSigOrNil != nil {
+			return nil, ctrlSigOrNil, err
+		}
+		args, err := spreadArg(callee, values)
+		return args, nil, err
+	}
+	args := make([]Value, 0, len(argExprs))
+	for _, expr := range argExprs {
+		// 인자는 현재 환경에서 평가
+		values, ctrlSigOrNil, err := e.Valuate(expr)
+		if err != nil || ctrlSigOrNil != nil {
+			return nil, ctrlSigOrNil, err
+		}
+		argVal, err := expectSingle(values, "call arg")
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, argVal)
+	}
+	return args, nil, nil
+}
+
+// spreadArg는 유일한 인자 식의 값들을 인자로 펼침
+func spreadArg(callee Value, values []Value) ([]Value, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("call arg expects single value")
+	}
+	return packArgsFor(callee, values), nil
+}
+
+// paramsOf는 호출 대상이 선언한 매개변수들을 리턴함. 메서드라면 리시버는 제외함
+func paramsOf(callee Value) []parser.Param {
+	switch fn := callee.(type) {
+	case *ClosureValue:
+		return fn.Params
+	case *BoundMethodValue:
+		return fn.Method.Params[1:]
+	default:
+		return nil
+	}
+}
+
+// callClosure는 클로저를 호출함
+// 본문이 꼬리 호출로 끝나면 콜프레임을 정리한 뒤 같은 루프에서 이어서 호출함 (트램펄린)
+// 따라서 꼬리 재귀는 깊이와 무관하게 일정한 호스트 스택과 콜스택만 씀
+// 힙 스택 모드라면 호출 전체를 machine에서 실행함
 func (e *Evaluator) callClosure(c *ClosureValue, args []Value) ([]Value, *ControlSignal, error) {
+	if e.heapStackOrNil != nil {
+		m := e.newMachine()
+		m.enter(c, args, c.ReturnTypes)
+		return m.run()
+	}
+	outerReturnTypes := c.ReturnTypes
+	for {
+		instance, err := e.bindCall(c, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		// 제너레이터는 호출 시 인자만 묶어 두고, 본문은 range가 코루틴으로 실행함
+		if instance.IsGenerator {
+			return packReturnValues([]Value{newGeneratorVal(instance, args)}, outerReturnTypes), nil, nil
+		}
+		values, ctrlSig, err := e.runClosure(instance, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ctrlSig == nil {
+			// 꼬리 호출된 함수의 결과는 처음 호출된 함수의 리턴 타입에 맞춰 다시 묶음
+			return packReturnValues(values, outerReturnTypes), nil, nil
+		}
+		if ctrlSig.Kind != CtrlTailCall {
+			return nil, ctrlSig, nil
+		}
+		c, args, err = tailCallee(ctrlSig)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// bindCall은 인자 개수를 검사하고, 제네릭 함수라면 인자 값으로부터 남은 타입 인자를 추론해 묶은 인스턴스를 리턴함
+func (e *Evaluator) bindCall(c *ClosureValue, args []Value) (*ClosureValue, error) {
 	if len(args) != len(c.Params) {
-		return nil, nil, fmt.Errorf("arg count mismatch")
+		return nil, fmt.Errorf("arg count mismatch")
 	}
+	if len(c.TypeParams) > 0 {
+		return e.bindTypeArgs(c, args)
+	}
+	return c, nil
+}
+
+// tailCallee는 꼬리 호출 신호가 부를 클로저와 그 인자들을 리턴함. 메서드라면 리시버를 첫 번째 인자로 붙임
+func tailCallee(ctrlSig *ControlSignal) (*ClosureValue, []Value, error) {
+	switch fn := ctrlSig.CalleeOrNil.(type) {
+	case *ClosureValue:
+		return fn, ctrlSig.Values, nil
+	case *BoundMethodValue:
+		return fn.Method, append([]Value{fn.Receiver}, ctrlSig.Values...), nil
+	default:
+		return nil, nil, fmt.Errorf("call target is not callable")
+	}
+}
+
+// enterClosure는 새 콜프레임 위에서 클로저 본문을 끝까지 실행함
+// 본문이 꼬리 호출로 끝났다면 그 호출까지 마침
+func (e *Evaluator) enterClosure(c *ClosureValue, args []Value) ([]Value, *ControlSignal, error) {
+	if e.heapStackOrNil != nil {
+		m := e.newMachine()
+		m.frame(c, args, c.ReturnTypes)
+		return m.run()
+	}
+	values, ctrlSig, err := e.runClosure(c, args)
+	if err != nil || ctrlSig == nil || ctrlSig.Kind != CtrlTailCall {
+		return values, ctrlSig, err
+	}
+	return e.callValue(ctrlSig.CalleeOrNil, ctrlSig.Values)
+}
+
+// runClosure는 새 콜프레임 위에서 클로저 본문을 실행함
+// 본문이 꼬리 호출로 끝났다면 호출하지 않고 CtrlTailCall 신호를 리턴함
+func (e *Evaluator) runClosure(c *ClosureValue, args []Value) ([]Value, *ControlSignal, error) {
 	// 함수 호출 시엔, 기존의 EnvList에서 pop, push하지 않고,
 	// 대신 새 콜스텍의 원소를 추가 후 그 위에서 pop,push를 함
-	newStartingEnv := &EnvFrame{Slots: make([]Value, e.maxSlotFromParams(c.Params)+1), ParentEnvFrame: c.ParentEnv}
-	newCallFrame := CallFrame{
-		currentEnv:  newStartingEnv,
-		funcIdOrNil: c.IdOrNil,
+	newCallFrame, err := e.newCallFrame(c, args)
+	if err != nil {
+		return nil, nil, err
 	}
 	e.callStack.pushCallFrame(newCallFrame)
 	defer e.callStack.popCallFrame()
 
+	//리졸버와 스코핑 로직 일치:
+	//param은 block과 같은 환경에서 존재
+	ctrlSig, err := e.evalBlock(c.Block, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 리턴이 없다면 아무 값도 전파하지 않음
+	if ctrlSig == nil {
+		return []Value{}, nil, nil
+	}
+	return frameResult(c, ctrlSig)
+}
+
+// newCallFrame은 매개변수에 인자를 묶은 환경 위에서 c의 본문을 실행할 콜프레임을 만듦
+func (e *Evaluator) newCallFrame(c *ClosureValue, args []Value) (CallFrame, error) {
+	newStartingEnv := &EnvFrame{Slots: make([]Value, e.maxSlotFromParams(c.Params)+1), ParentEnvFrame: c.ParentEnv}
 	for i, param := range c.Params {
 		ref, ok := e.resolveTable[param.Id.IdId]
 		if !ok {
-			return nil, nil, fmt.Errorf("missing resolve entry for param")
+			return CallFrame{}, fmt.Errorf("missing resolve entry for param")
 		}
 		if ref.Kind != resolver.RefLocal {
-			return nil, nil, fmt.Errorf("param resolved as non-local")
+			return CallFrame{}, fmt.Errorf("param resolved as non-local")
 		}
 		if ref.Slot < 0 {
-			return nil, nil, fmt.Errorf("negative slot for param")
+			return CallFrame{}, fmt.Errorf("negative slot for param")
 		}
 		if ref.Slot >= len(newStartingEnv.Slots) {
 			newStartingEnv.Slots = growSlots(newStartingEnv.Slots, ref.Slot+1)
 		}
 		newStartingEnv.Slots[ref.Slot] = args[i]
 	}
-	//리졸버와 스코핑 로직 일치:
-	//param은 block과 같은 환경에서 존재
-	ctrlSig, err := e.evalBlock(c.Block, true)
-	if err != nil {
-		return nil, nil, err
-	}
-	// 리턴이 없다면 아무 값도 전파하지 않음
-	if ctrlSig == nil {
-		return []Value{}, nil, nil
-	}
+	return CallFrame{
+		currentEnv:  newStartingEnv,
+		funcIdOrNil: c.IdOrNil,
+		returnTypes: c.ReturnTypes,
+		typeArgs:    c.TypeArgs,
+	}, nil
+}
+
+// frameResult는 c의 본문을 빠져나온 제어신호를 함수의 결과로 바꿈
+// return은 리턴 값이 되고, panic, exit, 꼬리 호출 신호는 그대로 전파됨
+func frameResult(c *ClosureValue, ctrlSig *ControlSignal) ([]Value, *ControlSignal, error) {
 	switch ctrlSig.Kind {
 	case CtrlReturn:
-		return ctrlSig.Values, nil, nil
-	case CtrlPanic:
+		return packReturnValues(ctrlSig.Values, c.ReturnTypes), nil, nil
+	case CtrlPanic, CtrlTailCall, CtrlExit:
 		return nil, ctrlSig, nil
 	default:
-		//return, panic외의 제어신호는 함수 바깥으로 전파되지 못함
+		//return, panic, exit외의 제어신호는 함수 바깥으로 전파되지 못함
 		return nil, nil, fmt.Errorf("only \"return\" or \"panic\" control signals may propagate out of a function")
 
 	}
 }
 
+// packReturnValues는 리턴 타입에 맞춰 튜플을 묶거나 풂
+// (T, error)를 돌려주는 함수의 결과를 그대로 전달할 수 있게 하기 위함
+func packReturnValues(values []Value, returnTypes []parser.Type) []Value {
+	if len(returnTypes) == 1 {
+		return packTuple(values, returnTypes[0])
+	}
+	return spreadTuple(values, len(returnTypes))
+}
+
 // valueForId는 리졸브 테이블 기반으로 id를 평가함
 // Env와 resolve scope간의 동치를 상정하고 작동함.
 func (e *Evaluator) valueForId(id *parser.Id) (Value, error) {
@@ -417,13 +966,120 @@ func equalValues(left, right Value) (bool, bool) {
 		if lv.IsOk != rv.IsOk {
 			return false, true
 		}
-		return lv.ErrMsg == rv.ErrMsg, true
+		// 메시지가 같더라도 서로 다른 newError로 만든 에러는 다름
+		return lv == rv, true
+	case *StructValue:
+		rv, ok := right.(*StructValue)
+		if !ok {
+			return false, false
+		}
+		if !sameStructType(lv, rv) {
+			return false, false
+		}
+		// 모든 필드가 비교 가능해야 struct도 비교 가능함
+		eq := true
+		for i := range lv.Fields {
+			fieldEq, ok := equalValues(lv.Fields[i], rv.Fields[i])
+			if !ok {
+				return false, false
+			}
+			eq = eq && fieldEq
+		}
+		return eq, true
+	case *TupleValue:
+		rv, ok := right.(*TupleValue)
+		if !ok || len(lv.Elems) != len(rv.Elems) {
+			return false, false
+		}
+		// 모든 요소가 비교 가능해야 튜플도 비교 가능함
+		eq := true
+		for i := range lv.Elems {
+			elemEq, ok := equalValues(lv.Elems[i], rv.Elems[i])
+			if !ok {
+				return false, false
+			}
+			eq = eq && elemEq
+		}
+		return eq, true
+	case *AdtValue:
+		rv, ok := right.(*AdtValue)
+		if !ok || lv.TypeId != rv.TypeId {
+			return false, false
+		}
+		if lv.Variant != rv.Variant {
+			return false, true
+		}
+		eq := true
+		for i := range lv.Fields {
+			fieldEq, ok := equalValues(lv.Fields[i], rv.Fields[i])
+			if !ok {
+				return false, false
+			}
+			eq = eq && fieldEq
+		}
+		return eq, true
+	case *VectorValue:
+		rv, ok := right.(*VectorValue)
+		if !ok {
+			return false, false
+		}
+		return equalVectors(lv, rv)
+	case *HashMapValue:
+		rv, ok := right.(*HashMapValue)
+		if !ok {
+			return false, false
+		}
+		return equalHashMaps(lv, rv)
+	case *NilValue:
+		_, ok := right.(*NilValue)
+		return ok, true
 	default:
 		// 함수 값 간의 동등성 비교는 허용하지 않음
 		return false, false
 	}
 }
 
+// typeDeclForName은 타입 이름 id를 리졸브 테이블로 따라가 TypeDecl을 찾음
+func (e *Evaluator) typeDeclForName(name parser.Id) (*parser.TypeDecl, bool) {
+	ref, ok := e.resolveTable[name.IdId]
+	if !ok || ref.Kind != resolver.RefType {
+		return nil, false
+	}
+	decl, ok := e.typeDecls[ref.RefIdNodeId]
+	return decl, ok
+}
+
+// zeroStruct는 모든 필드가 제로값인 StructValue를 만듦
+func (e *Evaluator) zeroStruct(declOrNil *parser.TypeDecl, st *parser.StructType) *StructValue {
+	fieldNames := make([]string, len(st.Fields))
+	fields := make([]Value, len(st.Fields))
+	for i, field := range st.Fields {
+		fieldNames[i] = field.Id.Name
+		fields[i] = e.ZeroValueForType(field.Type)
+	}
+	if declOrNil == nil {
+		return newStructVal(nil, "struct", fieldNames, fields)
+	}
+	typeId := declOrNil.Id.IdId
+	return newStructVal(&typeId, declOrNil.Id.Name, fieldNames, fields)
+}
+
+func sameStructType(left, right *StructValue) bool {
+	if left.TypeIdOrNil != nil || right.TypeIdOrNil != nil {
+		return left.TypeIdOrNil != nil && right.TypeIdOrNil != nil && *left.TypeIdOrNil == *right.TypeIdOrNil
+	}
+	// 익명 struct끼리는 필드 이름의 나열이 같으면 같은 타입
+	if len(left.FieldNames) != len(right.FieldNames) {
+		return false
+	}
+	for i := range left.FieldNames {
+		if left.FieldNames[i] != right.FieldNames[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (e *Evaluator) maxSlotFromParams(params []parser.Param) int {
 	max := -1
 	for _, param := range params {
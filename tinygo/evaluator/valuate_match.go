@@ -0,0 +1,161 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// variantRef는 합 타입 변형이 어느 TypeDecl의 몇 번째 변형인지를 가리킴
+type variantRef struct {
+	decl  *parser.TypeDecl
+	index int
+}
+
+func variantIndex(decl *parser.TypeDecl, idId parser.IdId) int {
+	for i, variant := range decl.Type.SumOrNil.Variants {
+		if variant.Id.IdId == idId {
+			return i
+		}
+	}
+	return -1
+}
+
+// variantConstructor는 변형 이름이 가리킬 값을 만듦
+// 필드 없는 변형은 그 값 자체이고, 필드 있는 변형은 필드 값들을 받아 값을 만드는 함수임
+func (e *Evaluator) variantConstructor(decl *parser.TypeDecl, index int) Value {
+	variant := decl.Type.SumOrNil.Variants[index]
+	if len(variant.Fields) == 0 {
+		return newAdtVal(decl, index, []Value{})
+	}
+	name := variant.Id.Name
+	return newBuiltinFuncVal(BuiltinFunc{
+		Name: name,
+		Impl: func(e *Evaluator, args []Value) ([]Value, *ControlSignal, error) {
+			if err := expectArgCount(name, args, len(variant.Fields)); err != nil {
+				return nil, nil, err
+			}
+			for i, field := range variant.Fields {
+				if !e.valueHasType(args[i], field.Type) {
+					return nil, nil, fmt.Errorf("cannot use %s as %s in field %s of %s",
+						dynamicTypeName(args[i]), typeName(field.Type), field.Id.Name, name)
+				}
+			}
+			fields := append([]Value{}, args...)
+			return []Value{newAdtVal(decl, index, fields)}, nil, nil
+		},
+	})
+}
+
+// zeroAdt는 합 타입의 제로값을 만듦
+// 필드 없는 변형이 있으면 그 중 첫 번째이고, 없으면 첫 변형의 필드를 제로값으로 채운 것임
+func (e *Evaluator) zeroAdt(decl *parser.TypeDecl) *AdtValue {
+	for i, variant := range decl.Type.SumOrNil.Variants {
+		if len(variant.Fields) == 0 {
+			return newAdtVal(decl, i, []Value{})
+		}
+	}
+	variant := decl.Type.SumOrNil.Variants[0]
+	fields := make([]Value, len(variant.Fields))
+	for i, field := range variant.Fields {
+		fields[i] = e.ZeroValueForType(field.Type)
+	}
+	return newAdtVal(decl, 0, fields)
+}
+
+// ValuateMatch는 위의 갈래부터 패턴과 가드가 맞는 첫 갈래의 본문을 평가함
+// 리졸버가 완전성을 검사하지만 정적 타입 검사는 없으므로, 다른 타입의 값이 오면 맞는 갈래가 없을 수 있음
+func (e *Evaluator) ValuateMatch(node *parser.Match) ([]Value, *ControlSignal, error) {
+	values, ctrlSig, err := e.Valuate(node.Subject)
+	if err != nil || ctrlSig != nil {
+		return nil, ctrlSig, err
+	}
+	subject, err := expectSingle(values, "match")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, arm := range node.Arms {
+		values, ctrlSig, matched, err := e.evalMatchArm(arm, subject)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, err
+		}
+		if matched {
+			return values, nil, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no match arm matched value %s", subject.Inspect())
+}
+
+// evalMatchArm은 리졸버와 동일하게 갈래마다 새 스코프에서 패턴을 묶고 가드와 본문을 평가함
+func (e *Evaluator) evalMatchArm(arm parser.MatchArm, subject Value) ([]Value, *ControlSignal, bool, error) {
+	e.pushEnvFrame(&EnvFrame{Slots: []Value{}})
+	defer e.popEnvFrame()
+	matched, ctrlSig, err := e.matchPattern(arm.Pattern, subject)
+	if err != nil || ctrlSig != nil || !matched {
+		return nil, ctrlSig, false, err
+	}
+	if arm.GuardOrNil != nil {
+		values, ctrlSig, err := e.Valuate(arm.GuardOrNil)
+		if err != nil || ctrlSig != nil {
+			return nil, ctrlSig, false, err
+		}
+		guard, err := guardValue(values)
+		if err != nil || !guard {
+			return nil, nil, false, err
+		}
+	}
+	values, ctrlSig, err := e.Valuate(arm.Body)
+	if err != nil || ctrlSig != nil {
+		return nil, ctrlSig, false, err
+	}
+	return values, nil, true, nil
+}
+
+// guardValue는 평가된 가드의 값을 bool로 꺼냄
+func guardValue(values []Value) (bool, error) {
+	guard, err := expectSingle(values, "match guard")
+	if err != nil {
+		return false, err
+	}
+	boolVal, ok := guard.(*BoolValue)
+	if !ok {
+		return false, fmt.Errorf("match guard must be bool")
+	}
+	return boolVal.Value, nil
+}
+
+// matchPattern은 v가 pat에 맞는지 검사하고, 맞으면 패턴의 이름들을 현재 환경에 묶음
+func (e *Evaluator) matchPattern(pat parser.Pattern, v Value) (bool, *ControlSignal, error) {
+	switch pat.PatternKind {
+	case parser.WildcardPattern:
+		return true, nil, nil
+	case parser.BindPattern:
+		return true, nil, e.setValueForId(*pat.IdOrNil, v)
+	case parser.LiteralPattern:
+		values, ctrlSig, err := e.Valuate(pat.LiteralOrNil)
+		if err != nil || ctrlSig != nil {
+			return false, ctrlSig, err
+		}
+		// 비교할 수 없는 값은 리터럴에 맞지 않는 것으로 봄
+		eq, ok := equalValues(v, values[0])
+		return ok && eq, nil, nil
+	case parser.VariantPattern:
+		ref, ok := e.variants[e.resolveTable[pat.IdOrNil.IdId].RefIdNodeId]
+		if !ok {
+			return false, nil, fmt.Errorf("missing variant for pattern %s", pat.IdOrNil.Name)
+		}
+		adt, ok := v.(*AdtValue)
+		if !ok || adt.TypeId != ref.decl.Id.IdId || adt.Variant != pat.IdOrNil.Name {
+			return false, nil, nil
+		}
+		for i, arg := range pat.Args {
+			matched, ctrlSig, err := e.matchPattern(arg, adt.Fields[i])
+			if err != nil || ctrlSig != nil || !matched {
+				return false, ctrlSig, err
+			}
+		}
+		return true, nil, nil
+	default:
+		return false, nil, fmt.Errorf("unknown pattern kind")
+	}
+}
@@ -1,13 +1,17 @@
 package evaluator
 
 import (
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/persistent"
 )
 
-// Value_model에서의 제로값
-var ZeroValueForType = func(t parser.Type) Value {
+// ZeroValueForType은 Value_model에서의 제로값을 리턴함
+// 이름 있는 타입은 리졸브 테이블을 통해 TypeDecl을 찾아 그 본문의 제로값을 만듦
+func (e *Evaluator) ZeroValueForType(t parser.Type) Value {
 	switch t.TypeKind {
 	case parser.IntType:
 		return newIntVal(0)
@@ -18,7 +22,37 @@ var ZeroValueForType = func(t parser.Type) Value {
 	case parser.ErrorType:
 		return newErrorVal(nil)
 	case parser.FuncionType:
-		return newClosureVal(nil, nil, nil, parser.Block{}, nil)
+		return newClosureVal(nil, nil, nil, parser.Block{}, nil, false)
+	case parser.NamedType:
+		if typeArg, ok := e.typeArgFor(*t.NameOrNil); ok {
+			return e.ZeroValueForType(typeArg)
+		}
+		decl, ok := e.typeDeclForName(*t.NameOrNil)
+		if !ok {
+			return nil
+		}
+		if decl.Type.TypeKind == parser.StructureType {
+			return e.zeroStruct(decl, decl.Type.StructOrNil)
+		}
+		if decl.Type.TypeKind == parser.InterfaceTypeKind {
+			return newNilVal()
+		}
+		if decl.Type.TypeKind == parser.SumTypeKind {
+			return e.zeroAdt(decl)
+		}
+		return e.ZeroValueForType(decl.Type)
+	case parser.StructureType:
+		return e.zeroStruct(nil, t.StructOrNil)
+	case parser.InterfaceTypeKind:
+		return newNilVal()
+	case parser.SliceType:
+		return newSliceVal(e.substTypeParams(*t.ElemOrNil, e.currentTypeArgs()), []Value{})
+	case parser.TupleType:
+		elems := make([]Value, len(t.TupleOrNil))
+		for i, elem := range t.TupleOrNil {
+			elems[i] = e.ZeroValueForType(elem)
+		}
+		return newTupleVal(elems)
 	default:
 		return nil
 	}
@@ -43,6 +77,16 @@ const (
 	ErrKind
 	ClosureKind
 	BuiltinFuncKind
+	StructKind
+	BoundMethodKind
+	NilKind
+	SliceKind
+	ModuleKind
+	TupleKind
+	AdtKind
+	GeneratorKind
+	VectorKind
+	HashMapKind
 )
 
 type IntValue struct {
@@ -90,9 +134,16 @@ func (s *StringValue) Inspect() string {
 	return s.Value
 }
 
+// ErrorValue는 ok 또는 에러 값임
+// 에러 값은 생성될 때마다 고유한 정체성을 가지며, == 는 메시지가 아닌 정체성으로 비교함
+// 따라서 전역에 둔 newError 값은 go의 sentinel 에러처럼 쓸 수 있음
 type ErrorValue struct {
 	IsOk   bool
 	ErrMsg string
+	// wrapError로 감싸진 원인 에러
+	CauseOrNil *ErrorValue
+	// errorWith로 붙인 구조화된 정보
+	DetailOrNil Value
 }
 
 // newErrorVal은 msg의 nil체크로 해당 에러가 ok 값인지 아닌지 검사함
@@ -102,6 +153,21 @@ func newErrorVal(msg *string) *ErrorValue {
 	}
 	return &ErrorValue{IsOk: false, ErrMsg: *msg}
 }
+
+// newWrappedErrorVal은 cause를 원인으로 갖는 "ctx: cause" 에러를 만듦
+func newWrappedErrorVal(ctx string, cause *ErrorValue) *ErrorValue {
+	return &ErrorValue{IsOk: false, ErrMsg: ctx + ": " + cause.ErrMsg, CauseOrNil: cause}
+}
+
+// chainHas는 e부터 원인을 따라가며 target과 같은 정체성의 에러가 있는지 검사함
+func (e *ErrorValue) chainHas(target *ErrorValue) bool {
+	for cur := e; cur != nil; cur = cur.CauseOrNil {
+		if cur == target {
+			return true
+		}
+	}
+	return false
+}
 func (e *ErrorValue) Kind() ValueKind {
 	return ErrKind
 }
@@ -118,15 +184,23 @@ type ClosureValue struct {
 	ReturnTypes []parser.Type
 	Block       parser.Block
 	ParentEnv   *EnvFrame // captured env
+	// 제너레이터 함수라면 호출 시 본문을 실행하지 않고 GeneratorValue를 돌려줌
+	IsGenerator bool
+	// 제네릭 함수의 타입 매개변수들
+	TypeParams []parser.TypeParam
+	// 타입 매개변수 선언 id -> 묶인 타입 인자. 본문을 실행하는 콜프레임이 이를 가짐
+	// 제네릭 함수 안에서 만든 클로저는 바깥 함수의 타입 인자를 그대로 잡아 둠
+	TypeArgs map[parser.IdId]parser.Type
 }
 
-func newClosureVal(idOrNil *parser.Id, params []parser.Param, returnTypes []parser.Type, block parser.Block, parentEnv *EnvFrame) *ClosureValue {
+func newClosureVal(idOrNil *parser.Id, params []parser.Param, returnTypes []parser.Type, block parser.Block, parentEnv *EnvFrame, isGenerator bool) *ClosureValue {
 	return &ClosureValue{
 		IdOrNil:     idOrNil,
 		Params:      params,
 		ReturnTypes: returnTypes,
 		Block:       block,
 		ParentEnv:   parentEnv,
+		IsGenerator: isGenerator,
 	}
 }
 func (c *ClosureValue) Kind() ValueKind {
@@ -138,3 +212,280 @@ func (c *ClosureValue) Inspect() string {
 	}
 	return "closure<" + c.IdOrNil.Name + ">"
 }
+
+// StructValue는 불변 값으로 취급됨
+// 필드 대입 시엔 새 StructValue를 만들어 변수에 다시 대입하므로, 대입 시의 값 복사 의미론이 지켜짐
+type StructValue struct {
+	// TypeIdOrNil은 이름 있는 struct 타입의 TypeDecl id. 익명 struct면 nil
+	TypeIdOrNil *parser.IdId
+	TypeName    string
+	FieldNames  []string
+	Fields      []Value
+}
+
+func newStructVal(typeIdOrNil *parser.IdId, typeName string, fieldNames []string, fields []Value) *StructValue {
+	return &StructValue{
+		TypeIdOrNil: typeIdOrNil,
+		TypeName:    typeName,
+		FieldNames:  fieldNames,
+		Fields:      fields,
+	}
+}
+func (s *StructValue) Kind() ValueKind {
+	return StructKind
+}
+func (s *StructValue) Inspect() string {
+	parts := make([]string, 0, len(s.Fields))
+	for i, name := range s.FieldNames {
+		parts = append(parts, name+": "+s.Fields[i].Inspect())
+	}
+	return s.TypeName + "{" + strings.Join(parts, ", ") + "}"
+}
+
+// fieldIndex는 이름에 해당하는 필드의 인덱스를 리턴함. 없으면 -1
+func (s *StructValue) fieldIndex(name string) int {
+	for i, fieldName := range s.FieldNames {
+		if fieldName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// withField는 한 필드만 바뀐 새 StructValue를 리턴함
+func (s *StructValue) withField(index int, value Value) *StructValue {
+	fields := make([]Value, len(s.Fields))
+	copy(fields, s.Fields)
+	fields[index] = value
+	return newStructVal(s.TypeIdOrNil, s.TypeName, s.FieldNames, fields)
+}
+
+// BoundMethodValue는 x.M 처럼 리시버가 묶인 메서드 값임
+// 호출 시 리시버가 첫 번째 인자로 전달됨
+type BoundMethodValue struct {
+	Receiver Value
+	Method   *ClosureValue
+}
+
+func newBoundMethodVal(receiver Value, method *ClosureValue) *BoundMethodValue {
+	return &BoundMethodValue{
+		Receiver: receiver,
+		Method:   method,
+	}
+}
+func (b *BoundMethodValue) Kind() ValueKind {
+	return BoundMethodKind
+}
+func (b *BoundMethodValue) Inspect() string {
+	return "method<" + b.Method.IdOrNil.Name + ">"
+}
+
+// NilValue는 아무 값도 담지 않은 interface의 제로값임
+type NilValue struct{}
+
+func newNilVal() *NilValue {
+	return &NilValue{}
+}
+func (n *NilValue) Kind() ValueKind {
+	return NilKind
+}
+func (n *NilValue) Inspect() string {
+	return "nil"
+}
+
+// SliceValue는 []T 값임
+// 원소를 바꾸는 연산이 없으므로 여러 곳에서 원소 배열을 공유해도 안전함
+type SliceValue struct {
+	ElemType parser.Type
+	Elems    []Value
+}
+
+func newSliceVal(elemType parser.Type, elems []Value) *SliceValue {
+	return &SliceValue{
+		ElemType: elemType,
+		Elems:    elems,
+	}
+}
+func (s *SliceValue) Kind() ValueKind {
+	return SliceKind
+}
+func (s *SliceValue) Inspect() string {
+	elems := make([]string, len(s.Elems))
+	for i, elem := range s.Elems {
+		elems[i] = elem.Inspect()
+	}
+	return "[" + strings.Join(elems, " ") + "]"
+}
+
+// TupleValue는 (a, b) 형태의 튜플 값임
+// 다중 리턴 값을 하나의 값으로 저장하거나 전달할 때 쓰임
+type TupleValue struct {
+	Elems []Value
+}
+
+func newTupleVal(elems []Value) *TupleValue {
+	return &TupleValue{Elems: elems}
+}
+func (t *TupleValue) Kind() ValueKind {
+	return TupleKind
+}
+func (t *TupleValue) Inspect() string {
+	elems := make([]string, len(t.Elems))
+	for i, elem := range t.Elems {
+		elems[i] = elem.Inspect()
+	}
+	return "(" + strings.Join(elems, ", ") + ")"
+}
+
+// spreadTuple은 count개의 값이 필요한 곳에 같은 길이의 튜플 하나가 왔다면 이를 풀어냄
+func spreadTuple(values []Value, count int) []Value {
+	if count < 2 || len(values) != 1 {
+		return values
+	}
+	tuple, ok := values[0].(*TupleValue)
+	if !ok || len(tuple.Elems) != count {
+		return values
+	}
+	return tuple.Elems
+}
+
+// packTuple은 튜플 타입 t의 값 하나가 필요한 곳에 같은 개수의 값들이 왔다면 이를 튜플로 묶음
+func packTuple(values []Value, t parser.Type) []Value {
+	if t.TypeKind != parser.TupleType || len(values) < 2 || len(values) != len(t.TupleOrNil) {
+		return values
+	}
+	return []Value{newTupleVal(values)}
+}
+
+// AdtValue는 합 타입의 값으로, 어떤 변형인지와 그 필드 값들을 가짐
+// StructValue처럼 불변 값으로 취급됨
+type AdtValue struct {
+	TypeId     parser.IdId
+	TypeName   string
+	Variant    string
+	FieldNames []string
+	Fields     []Value
+}
+
+func newAdtVal(decl *parser.TypeDecl, index int, fields []Value) *AdtValue {
+	variant := decl.Type.SumOrNil.Variants[index]
+	fieldNames := make([]string, len(variant.Fields))
+	for i, field := range variant.Fields {
+		fieldNames[i] = field.Id.Name
+	}
+	return &AdtValue{
+		TypeId:     decl.Id.IdId,
+		TypeName:   decl.Id.Name,
+		Variant:    variant.Id.Name,
+		FieldNames: fieldNames,
+		Fields:     fields,
+	}
+}
+func (a *AdtValue) Kind() ValueKind {
+	return AdtKind
+}
+func (a *AdtValue) Inspect() string {
+	if len(a.Fields) == 0 {
+		return a.Variant
+	}
+	fields := make([]string, len(a.Fields))
+	for i, field := range a.Fields {
+		fields[i] = field.Inspect()
+	}
+	return a.Variant + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// GeneratorValue는 제너레이터 함수를 호출한 결과임
+// 본문은 range로 순회할 때 비로소 실행되며, 순회할 때마다 처음부터 다시 실행됨
+type GeneratorValue struct {
+	Closure *ClosureValue
+	Args    []Value
+}
+
+func newGeneratorVal(closure *ClosureValue, args []Value) *GeneratorValue {
+	return &GeneratorValue{
+		Closure: closure,
+		Args:    args,
+	}
+}
+func (g *GeneratorValue) Kind() ValueKind {
+	return GeneratorKind
+}
+func (g *GeneratorValue) Inspect() string {
+	if g.Closure.IdOrNil == nil {
+		return "generator<anonymous>"
+	}
+	return "generator<" + g.Closure.IdOrNil.Name + ">"
+}
+
+// VectorValue는 영속 벡터 값임
+// conj, assoc은 원본을 바꾸지 않고 바뀐 경로 외의 노드를 원본과 공유하는 새 벡터를 만듦
+type VectorValue struct {
+	Vec *persistent.Vector[Value]
+}
+
+func newVectorVal(vec *persistent.Vector[Value]) *VectorValue {
+	return &VectorValue{Vec: vec}
+}
+func (v *VectorValue) Kind() ValueKind {
+	return VectorKind
+}
+func (v *VectorValue) Inspect() string {
+	elems := make([]string, 0, v.Vec.Len())
+	v.Vec.Each(func(_ int, elem Value) bool {
+		elems = append(elems, elem.Inspect())
+		return true
+	})
+	return "vector[" + strings.Join(elems, " ") + "]"
+}
+
+// HashMapValue는 HAMT로 이루어진 영속 해시 맵 값임. 키는 hashValue로 해시할 수 있는 값이어야 함
+type HashMapValue struct {
+	Map *persistent.Map[Value, Value]
+}
+
+func newHashMapVal(m *persistent.Map[Value, Value]) *HashMapValue {
+	return &HashMapValue{Map: m}
+}
+
+// newEmptyHashMap은 equalValues로 키를 비교하는 빈 맵을 만듦
+func newEmptyHashMap() *persistent.Map[Value, Value] {
+	return persistent.NewMap[Value, Value](func(a, b Value) bool {
+		eq, ok := equalValues(a, b)
+		return ok && eq
+	})
+}
+func (m *HashMapValue) Kind() ValueKind {
+	return HashMapKind
+}
+
+// Inspect는 go의 fmt와 같이 키의 문자열 순으로 정렬해 출력함
+func (m *HashMapValue) Inspect() string {
+	entries := make([]string, 0, m.Map.Len())
+	m.Map.Each(func(key, value Value) bool {
+		entries = append(entries, key.Inspect()+":"+value.Inspect())
+		return true
+	})
+	sort.Strings(entries)
+	return "hashMap[" + strings.Join(entries, " ") + "]"
+}
+
+// ModuleValue는 strings, strconv처럼 빌트인 함수들을 묶은 모듈 값임
+// 리졸버가 모듈 이름을 셀렉터 없이 쓰는 것을 막으므로 멤버 접근에서만 나타남
+type ModuleValue struct {
+	Name    string
+	Members map[string]BuiltinFunc
+}
+
+func newModuleVal(name string, members map[string]BuiltinFunc) *ModuleValue {
+	return &ModuleValue{
+		Name:    name,
+		Members: members,
+	}
+}
+func (m *ModuleValue) Kind() ValueKind {
+	return ModuleKind
+}
+func (m *ModuleValue) Inspect() string {
+	return "module<" + m.Name + ">"
+}
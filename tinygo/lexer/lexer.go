@@ -10,6 +10,16 @@ import (
 type Lexer struct {
 	input           string
 	currentPosition int
+	// comments는 건너뛴 // 주석들로, 시작 위치 순서임
+	comments []Comment
+}
+
+// Comment는 // 부터 줄 끝까지의 주석임. 토큰이 되지 않고 렉서에 기록만 됨
+type Comment struct {
+	// 주석의 // 가 시작하는 바이트 위치
+	Offset int
+	// // 를 포함한, 줄바꿈 앞까지의 주석 내용
+	Text string
 }
 
 func NewLexer() *Lexer {
@@ -20,6 +30,25 @@ func (lx *Lexer) Set(s string) {
 	lx.input = s
 }
 
+// Comments는 지금까지 건너뛴 주석들을 리턴함
+func (lx *Lexer) Comments() []Comment {
+	return lx.comments
+}
+
+// Position은 인풋의 바이트 위치 offset의 줄과 칸을 1부터 세어 리턴함
+func (lx *Lexer) Position(offset int) (line, col int) {
+	line, col = 1, 1
+	for i := 0; i < offset && i < len(lx.input); i++ {
+		if lx.input[i] == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return line, col
+}
+
 // Next는 현재 위치에서의 토큰을 리턴한 후, 다음 위치로 렉서의 포지션을 옮긴다.
 func (lx *Lexer) Next() token.Token {
 	// 공백을 제거하면 "문자"와 맞닿게 된다.
@@ -206,7 +235,7 @@ func (lx *Lexer) rollback() func() {
 
 }
 
-// skipWhitespace는 lx의 인풋에서 공백을 스킵한다.
+// skipWhitespace는 lx의 인풋에서 공백과 주석을 스킵한다.
 func (lx *Lexer) skipWhitespace() {
 	for lx.currentPosition < len(lx.input) {
 		r := rune(lx.input[lx.currentPosition])
@@ -214,9 +243,26 @@ func (lx *Lexer) skipWhitespace() {
 			lx.currentPosition++
 			continue
 		}
+		if r == '/' && lx.isNextExist() && lx.input[lx.currentPosition+1] == '/' {
+			lx.skipComment()
+			continue
+		}
 		break
 	}
 }
+
+// skipComment는 // 부터 줄 끝까지를 건너뛰고 주석으로 기록한다.
+// 롤백으로 같은 주석을 다시 건너뛸 수 있으므로, 이미 기록된 위치면 다시 기록하지 않는다.
+func (lx *Lexer) skipComment() {
+	start := lx.currentPosition
+	for lx.currentPosition < len(lx.input) && lx.input[lx.currentPosition] != '\n' {
+		lx.currentPosition++
+	}
+	if n := len(lx.comments); n > 0 && lx.comments[n-1].Offset >= start {
+		return
+	}
+	lx.comments = append(lx.comments, Comment{Offset: start, Text: lx.input[start:lx.currentPosition]})
+}
 func isDigitOrAlpha(b byte) bool {
 	return isDigit(b) || isAlpha(b) || b == '_'
 }
@@ -251,3 +251,255 @@ func TestLexer_Whitespace_Is_Ignored(t *testing.T) {
 		assertTok(t, toks[i], want[i])
 	}
 }
+
+func TestLexer_StructTokens(t *testing.T) {
+	toks := lexAll(t, "type Point struct { x int; } p.x Point{x: 1}")
+
+	want := []expTok{
+		{token.TYPE, "type"},
+		{token.ID, "Point"},
+		{token.STRUCT, "struct"},
+		{token.LBRACE, "{"},
+		{token.ID, "x"},
+		{token.INT, "int"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.ID, "p"},
+		{token.DOT, "."},
+		{token.ID, "x"},
+		{token.ID, "Point"},
+		{token.LBRACE, "{"},
+		{token.ID, "x"},
+		{token.COLON, ":"},
+		{token.NUMBER, "1"},
+		{token.RBRACE, "}"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_InterfaceAndSwitchTokens(t *testing.T) {
+	toks := lexAll(t, "interface switch v.(type) case default: fallthrough")
+
+	want := []expTok{
+		{token.INTERFACE, "interface"},
+		{token.SWITCH, "switch"},
+		{token.ID, "v"},
+		{token.DOT, "."},
+		{token.LPAREN, "("},
+		{token.TYPE, "type"},
+		{token.RPAREN, ")"},
+		{token.CASE, "case"},
+		{token.DEFAULT, "default"},
+		{token.COLON, ":"},
+		{token.FALLTHROUGH, "fallthrough"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_MatchTokens(t *testing.T) {
+	toks := lexAll(t, "type S = A(x int) | B; match s { A(_) => 1, }")
+
+	want := []expTok{
+		{token.TYPE, "type"},
+		{token.ID, "S"},
+		{token.ASSIGN, "="},
+		{token.ID, "A"},
+		{token.LPAREN, "("},
+		{token.ID, "x"},
+		{token.INT, "int"},
+		{token.RPAREN, ")"},
+		{token.BAR, "|"},
+		{token.ID, "B"},
+		{token.SEMICOLON, ";"},
+		{token.MATCH, "match"},
+		{token.ID, "s"},
+		{token.LBRACE, "{"},
+		{token.ID, "A"},
+		{token.LPAREN, "("},
+		{token.UNDERSCORE, "_"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "=>"},
+		{token.NUMBER, "1"},
+		{token.COMMA, ","},
+		{token.RBRACE, "}"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_PipeToken(t *testing.T) {
+	toks := lexAll(t, "x |> f || y | z")
+
+	want := []expTok{
+		{token.ID, "x"},
+		{token.PIPE, "|>"},
+		{token.ID, "f"},
+		{token.OR, "||"},
+		{token.ID, "y"},
+		{token.BAR, "|"},
+		{token.ID, "z"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_YieldAndRangeTokens(t *testing.T) {
+	toks := lexAll(t, "yield i; for i, v := range g {}")
+
+	want := []expTok{
+		{token.YIELD, "yield"},
+		{token.ID, "i"},
+		{token.SEMICOLON, ";"},
+		{token.FOR, "for"},
+		{token.ID, "i"},
+		{token.COMMA, ","},
+		{token.ID, "v"},
+		{token.DECLSIGN, ":="},
+		{token.RANGE, "range"},
+		{token.ID, "g"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_ConstAndLetTokens(t *testing.T) {
+	toks := lexAll(t, "const ( A = iota; ) let x = A;")
+
+	want := []expTok{
+		{token.CONST, "const"},
+		{token.LPAREN, "("},
+		{token.ID, "A"},
+		{token.ASSIGN, "="},
+		{token.ID, "iota"},
+		{token.SEMICOLON, ";"},
+		{token.RPAREN, ")"},
+		{token.LET, "let"},
+		{token.ID, "x"},
+		{token.ASSIGN, "="},
+		{token.ID, "A"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_PackageAndImportTokens(t *testing.T) {
+	toks := lexAll(t, "package main; import \"geo/shapes\";")
+
+	want := []expTok{
+		{token.PACKAGE, "package"},
+		{token.ID, "main"},
+		{token.SEMICOLON, ";"},
+		{token.IMPORT, "import"},
+		{token.STRLIT, "geo/shapes"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_QuestionToken(t *testing.T) {
+	toks := lexAll(t, "n := f(x)?;")
+
+	want := []expTok{
+		{token.ID, "n"},
+		{token.DECLSIGN, ":="},
+		{token.ID, "f"},
+		{token.LPAREN, "("},
+		{token.ID, "x"},
+		{token.RPAREN, ")"},
+		{token.QUESTION, "?"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "<<EOF>>"},
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("token count mismatch: got=%d want=%d; got=%v", len(toks), len(want), toks)
+	}
+	for i := range want {
+		assertTok(t, toks[i], want[i])
+	}
+}
+
+func TestLexer_Comments_Are_Skipped_And_Recorded(t *testing.T) {
+	lx := NewLexer()
+	lx.Set("x := 10 / 2; // 나누기\n// 한 줄 주석\nprint(\"a//b\");//끝")
+
+	want := []expTok{
+		{token.ID, "x"},
+		{token.DECLSIGN, ":="},
+		{token.NUMBER, "10"},
+		{token.DIV, "/"},
+		{token.NUMBER, "2"},
+		{token.SEMICOLON, ";"},
+		{token.ID, "print"},
+		{token.LPAREN, "("},
+		{token.STRLIT, "a//b"},
+		{token.RPAREN, ")"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, "<<EOF>>"},
+	}
+	for i := range want {
+		assertTok(t, lx.Next(), want[i])
+	}
+
+	comments := lx.Comments()
+	wantComments := []string{"// 나누기", "// 한 줄 주석", "//끝"}
+	if len(comments) != len(wantComments) {
+		t.Fatalf("comment count mismatch: got=%v", comments)
+	}
+	for i, text := range wantComments {
+		if comments[i].Text != text {
+			t.Fatalf("comment %d: got=%q want=%q", i, comments[i].Text, text)
+		}
+	}
+	if line, col := lx.Position(comments[1].Offset); line != 2 || col != 1 {
+		t.Fatalf("position of second comment: got=%d:%d", line, col)
+	}
+}
@@ -0,0 +1,180 @@
+package lint
+
+import (
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// ErrCheck는 error를 리턴하는 함수를 호출하고 그 결과를 버리는 호출문을 보고함
+// 리턴 타입을 알 수 있는 함수 선언, 함수 리터럴로 초기화된 변수, 빌트인 모듈의 멤버와
+// 정적 타입을 알 수 있는 값의 메서드 호출만 검사함
+// 다른 패키지의 함수는 RunProgram으로 실행할 때만 검사함
+// 결과를 변수로 받거나 ?로 전파하는 것은 호출문이 아니므로 보고하지 않음
+var ErrCheck = &Analyzer{
+	Name: "errcheck",
+	Doc:  "report call statements that discard an error result",
+	Run: func(pass *Pass) {
+		c := &errChecker{pass: pass, facts: pass.facts()}
+		parser.Inspect(pass.Pkg, func(node parser.Node) bool {
+			stmt, ok := node.(*parser.CallStmt)
+			if !ok {
+				return true
+			}
+			callee, ok := c.callee(&stmt.Call)
+			if !ok {
+				return true
+			}
+			for _, t := range callee.results {
+				if t.TypeKind == parser.ErrorType {
+					pass.Reportf(callee.at, "error result of %s is not checked", callee.name)
+					return true
+				}
+			}
+			return true
+		})
+	},
+}
+
+// errChecker는 호출되는 함수와 값의 정적 타입을 찾음
+type errChecker struct {
+	pass  *Pass
+	facts *facts
+}
+
+// calleeInfo는 호출되는 함수의 리턴 타입과 보고에 쓸 이름, 위치임
+type calleeInfo struct {
+	results []parser.Type
+	name    string
+	at      parser.Id
+}
+
+// callee는 call이 부르는 함수를 찾음. 제너레이터처럼 결과가 호출문의 값이 아닌 것은 찾지 않음
+func (c *errChecker) callee(call *parser.Call) (calleeInfo, bool) {
+	if sel, ok := selectorOf(&call.PrimaryOrNil); ok {
+		if _, ok := c.pass.Table[sel.Field.IdId]; !ok {
+			return c.memberCallee(sel)
+		}
+	}
+	id, ok := calleeId(&call.PrimaryOrNil)
+	if !ok {
+		return calleeInfo{}, false
+	}
+	ref, ok := c.pass.Table[id.IdId]
+	if !ok {
+		return calleeInfo{}, false
+	}
+	if fn, ok := c.pass.funcDecl(ref.RefIdNodeId); ok && !fn.IsGenerator {
+		return calleeInfo{results: fn.ReturnTypesOrNil, name: id.Name, at: id}, true
+	}
+	if fexp, ok := c.facts.fexps[ref.RefIdNodeId]; ok && !fexp.IsGenerator {
+		return calleeInfo{results: fexp.ReturnTypesOrNil, name: id.Name, at: id}, true
+	}
+	return calleeInfo{}, false
+}
+
+// memberCallee는 빌트인 모듈의 멤버나 메서드인 sel을 찾음
+func (c *errChecker) memberCallee(sel *parser.Selector) (calleeInfo, bool) {
+	if object, ok := sel.Object.(*parser.Primary); ok && object.PrimaryKind == parser.IdPrimary {
+		if ref, ok := c.pass.Table[object.IdOrNil.IdId]; ok && ref.Kind == resolver.RefBuiltin {
+			module, ok := resolver.LookupBuiltinModule(object.IdOrNil.Name)
+			if !ok {
+				return calleeInfo{}, false
+			}
+			member, ok := module.Member(sel.Field.Name)
+			if !ok {
+				return calleeInfo{}, false
+			}
+			return calleeInfo{results: member.Returns, name: module.Name + "." + member.Name, at: sel.Field}, true
+		}
+	}
+	t, ok := c.staticType(sel.Object, map[parser.IdId]bool{})
+	if !ok || t.TypeKind != parser.NamedType {
+		return calleeInfo{}, false
+	}
+	ref, ok := c.pass.Table[t.NameOrNil.IdId]
+	if !ok {
+		return calleeInfo{}, false
+	}
+	fn, ok := c.pass.method(ref.RefIdNodeId, sel.Field.Name)
+	if !ok || fn.IsGenerator {
+		return calleeInfo{}, false
+	}
+	return calleeInfo{results: fn.ReturnTypesOrNil, name: t.NameOrNil.Name + "." + fn.Id.Name, at: sel.Field}, true
+}
+
+// staticType은 적힌 타입, 초기식, 구조체 리터럴, 단일 리턴 호출과 필드 접근으로 알 수 있는 expr의 타입을 리턴함
+// seen은 서로를 초기식으로 가리키는 선언들을 끊기 위한 것임
+func (c *errChecker) staticType(expr parser.Expr, seen map[parser.IdId]bool) (parser.Type, bool) {
+	switch e := expr.(type) {
+	case *parser.Primary:
+		switch e.PrimaryKind {
+		case parser.ExprPrimary:
+			return c.staticType(e.ExprOrNil, seen)
+		case parser.IdPrimary:
+			ref, ok := c.pass.Table[e.IdOrNil.IdId]
+			if !ok || seen[ref.RefIdNodeId] {
+				return parser.Type{}, false
+			}
+			seen[ref.RefIdNodeId] = true
+			if t, ok := c.facts.types[ref.RefIdNodeId]; ok {
+				return t, true
+			}
+			if init, ok := c.facts.inits[ref.RefIdNodeId]; ok {
+				return c.staticType(init, seen)
+			}
+		case parser.ValuePrimary:
+			if e.ValueOrNil.ValueKind == parser.StructLitValue {
+				return parser.Type{TypeKind: parser.NamedType, NameOrNil: &e.ValueOrNil.StructLitOrNil.TypeName}, true
+			}
+		}
+	case *parser.Call:
+		callee, ok := c.callee(e)
+		if ok && len(callee.results) == 1 {
+			return callee.results[0], true
+		}
+	case *parser.Selector:
+		object, ok := c.staticType(e.Object, seen)
+		if !ok || object.TypeKind != parser.NamedType {
+			return parser.Type{}, false
+		}
+		decl, ok := c.pass.typeDecl(c.pass.Table[object.NameOrNil.IdId].RefIdNodeId)
+		if !ok || decl.Type.TypeKind != parser.StructureType {
+			return parser.Type{}, false
+		}
+		for _, field := range decl.Type.StructOrNil.Fields {
+			if field.Id.Name == e.Field.Name {
+				return field.Type, true
+			}
+		}
+	}
+	return parser.Type{}, false
+}
+
+// calleeId는 호출되는 식이 이름이나 패키지 멤버라면 그 식별자를 리턴함
+func calleeId(p *parser.Primary) (parser.Id, bool) {
+	root, fields, ok := exprPath(p)
+	if !ok {
+		return parser.Id{}, false
+	}
+	if len(fields) == 0 {
+		return root, true
+	}
+	// pkg.F 의 F만 리졸브 테이블에 있으며, 메서드 호출은 테이블에 없어 걸러짐
+	sel, ok := selectorOf(p)
+	if !ok {
+		return parser.Id{}, false
+	}
+	return sel.Field, true
+}
+
+func selectorOf(expr parser.Expr) (*parser.Selector, bool) {
+	switch e := expr.(type) {
+	case *parser.Selector:
+		return e, true
+	case *parser.Primary:
+		if e.PrimaryKind == parser.ExprPrimary {
+			return selectorOf(e.ExprOrNil)
+		}
+	}
+	return nil, false
+}
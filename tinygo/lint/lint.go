@@ -0,0 +1,253 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// Analyzer는 리졸빙된 패키지 하나를 검사하는 분석기임
+// go/analysis와 같이 Run은 Pass로 패키지를 받아 진단을 보고함
+type Analyzer struct {
+	// 진단과 억제 주석에서 분석기를 가리키는 이름
+	Name string
+	// 무엇을 검사하는지에 대한 한 줄 설명
+	Doc string
+	Run func(pass *Pass)
+}
+
+// Pass는 분석기 하나를 패키지 하나에 실행하는 동안의 입력과 보고 창구임
+type Pass struct {
+	Analyzer *Analyzer
+	Pkg      *parser.PackageAST
+	Table    resolver.ResolveTable
+	// 같은 Run의 분석기들이 공유하는 정보
+	cache       *factCache
+	diagnostics []Diagnostic
+}
+
+// Reportf는 at 위치의 진단을 보고함
+func (p *Pass) Reportf(at parser.Id, format string, args ...any) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{
+		Analyzer: p.Analyzer.Name,
+		At:       at,
+		Pos:      p.Pkg.PosOf(at),
+		Msg:      fmt.Sprintf(format, args...),
+	})
+}
+
+// factCache는 패키지의 정보를 처음 쓸 때 한 번만 모으기 위한 자리임
+type factCache struct {
+	factsOrNil *facts
+	// RunProgram으로 실행할 때, 프로그램의 다른 패키지들의 전역 함수 선언
+	importedFuncs map[parser.IdId]*parser.FuncDecl
+	// RunProgram으로 실행할 때, 프로그램의 다른 패키지들의 메서드와 타입 선언
+	importedMethods map[parser.IdId]map[string]*parser.FuncDecl
+	importedTypes   map[parser.IdId]*parser.TypeDecl
+}
+
+// facts는 패키지의 선언과 참조 정보를 리턴함
+func (p *Pass) facts() *facts {
+	if p.cache.factsOrNil == nil {
+		p.cache.factsOrNil = collectFacts(p.Pkg, p.Table)
+	}
+	return p.cache.factsOrNil
+}
+
+// funcDecl은 declId가 가리키는 함수 선언을 이 패키지, 그리고 알고 있다면 다른 패키지에서 찾음
+func (p *Pass) funcDecl(declId parser.IdId) (*parser.FuncDecl, bool) {
+	if fn, ok := p.facts().funcs[declId]; ok {
+		return fn, true
+	}
+	fn, ok := p.cache.importedFuncs[declId]
+	return fn, ok
+}
+
+// method는 typeDeclId가 가리키는 타입의 메서드 name을 찾음
+func (p *Pass) method(typeDeclId parser.IdId, name string) (*parser.FuncDecl, bool) {
+	if fn, ok := p.facts().methods[typeDeclId][name]; ok {
+		return fn, true
+	}
+	fn, ok := p.cache.importedMethods[typeDeclId][name]
+	return fn, ok
+}
+
+// typeDecl은 declId가 가리키는 타입 선언을 찾음
+func (p *Pass) typeDecl(declId parser.IdId) (*parser.TypeDecl, bool) {
+	if decl, ok := p.facts().typeDecls[declId]; ok {
+		return decl, true
+	}
+	decl, ok := p.cache.importedTypes[declId]
+	return decl, ok
+}
+
+// Diagnostic은 분석기가 보고한 문제 하나임
+type Diagnostic struct {
+	Analyzer string
+	At       parser.Id
+	// 소스 위치. 위치를 기록하지 않은 패키지라면 유효하지 않음
+	Pos parser.Pos
+	Msg string
+}
+
+func (d Diagnostic) String() string {
+	if !d.Pos.IsValid() {
+		return fmt.Sprintf("diagnostic at %s: %s: %s", d.At.String(), d.Analyzer, d.Msg)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Pos.String(), d.Analyzer, d.Msg)
+}
+
+// All은 기본으로 실행되는 분석기들임
+var All = []*Analyzer{UnusedVar, UnusedParam, Shadow, UnusedFunc, ErrCheck, SelfAssign}
+
+// ByName은 이름이 name인 기본 분석기를 리턴함
+func ByName(name string) (*Analyzer, bool) {
+	for _, a := range All {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Run은 analyzers를 pkg에 차례로 실행하고, 억제 주석으로 가려지지 않은 진단들을 소스 순서로 리턴함
+// table은 pkg를 리졸빙한 테이블이며, 여러 패키지를 함께 리졸빙한 테이블이어도 됨
+func Run(pkg *parser.PackageAST, table resolver.ResolveTable, analyzers []*Analyzer) []Diagnostic {
+	return run(pkg, table, analyzers, &factCache{})
+}
+
+// RunProgram은 ResolveProgram으로 함께 리졸빙한 패키지들 각각에 analyzers를 실행함
+// 패키지마다 Run과 같지만, 다른 패키지의 함수를 부르는 것도 검사할 수 있음
+func RunProgram(pkgs []resolver.PackageSource, table resolver.ResolveTable, analyzers []*Analyzer) []Diagnostic {
+	imported := &factCache{
+		importedFuncs:   map[parser.IdId]*parser.FuncDecl{},
+		importedMethods: map[parser.IdId]map[string]*parser.FuncDecl{},
+		importedTypes:   map[parser.IdId]*parser.TypeDecl{},
+	}
+	for _, pkg := range pkgs {
+		for _, decl := range pkg.AST.DeclsOrNil {
+			switch d := decl.(type) {
+			case *parser.FuncDecl:
+				if d.ReceiverOrNil == nil {
+					imported.importedFuncs[d.Id.IdId] = d
+				} else {
+					recordMethod(imported.importedMethods, table, d)
+				}
+			case *parser.TypeDecl:
+				imported.importedTypes[d.Id.IdId] = d
+			}
+		}
+	}
+	diagnostics := []Diagnostic{}
+	for _, pkg := range pkgs {
+		cache := *imported
+		diagnostics = append(diagnostics, run(pkg.AST, table, analyzers, &cache)...)
+	}
+	return diagnostics
+}
+
+func run(pkg *parser.PackageAST, table resolver.ResolveTable, analyzers []*Analyzer, cache *factCache) []Diagnostic {
+	ignores := collectIgnores(pkg.Comments)
+	diagnostics := []Diagnostic{}
+	for _, a := range analyzers {
+		pass := &Pass{Analyzer: a, Pkg: pkg, Table: table, cache: cache}
+		a.Run(pass)
+		for _, d := range pass.diagnostics {
+			if !ignores.covers(d) {
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.Pos.File != b.Pos.File {
+			return a.Pos.File < b.Pos.File
+		}
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line < b.Pos.Line
+		}
+		if a.Pos.Col != b.Pos.Col {
+			return a.Pos.Col < b.Pos.Col
+		}
+		return a.At.IdId < b.At.IdId
+	})
+	return diagnostics
+}
+
+// IgnoreDirective는 진단을 억제하는 주석의 머리말임
+// "//vet:ignore"는 모든 분석기를, "//vet:ignore shadow,unusedvar"는 나열한 분석기만 억제하며
+// 주석이 있는 줄과 그 다음 줄의 진단에 적용됨
+const IgnoreDirective = "//vet:ignore"
+
+// ignore는 억제 주석 하나가 덮는 범위임
+type ignore struct {
+	file string
+	line int
+	// 비어 있으면 모든 분석기
+	analyzers map[string]bool
+}
+
+type ignores []ignore
+
+func collectIgnores(comments []parser.Comment) ignores {
+	out := ignores{}
+	for _, c := range comments {
+		rest, ok := strings.CutPrefix(c.Text, IgnoreDirective)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		ig := ignore{file: c.Pos.File, line: c.Pos.Line, analyzers: map[string]bool{}}
+		for _, name := range strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			ig.analyzers[name] = true
+		}
+		out = append(out, ig)
+	}
+	return out
+}
+
+func (igs ignores) covers(d Diagnostic) bool {
+	if !d.Pos.IsValid() {
+		return false
+	}
+	for _, ig := range igs {
+		if ig.file != d.Pos.File || (d.Pos.Line != ig.line && d.Pos.Line != ig.line+1) {
+			continue
+		}
+		if len(ig.analyzers) == 0 || ig.analyzers[d.Analyzer] {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonDiagnostic은 JSON 출력에서의 진단 모양임
+type jsonDiagnostic struct {
+	Analyzer string `json:"analyzer"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Col      int    `json:"col,omitempty"`
+	Name     string `json:"name"`
+	IdId     int64  `json:"id"`
+	Message  string `json:"message"`
+}
+
+// JSON은 진단들을 도구가 읽을 수 있는 JSON 배열로 출력함
+func JSON(diagnostics []Diagnostic) ([]byte, error) {
+	out := make([]jsonDiagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		out = append(out, jsonDiagnostic{
+			Analyzer: d.Analyzer,
+			File:     d.Pos.File,
+			Line:     d.Pos.Line,
+			Col:      d.Pos.Col,
+			Name:     d.At.Name,
+			IdId:     int64(d.At.IdId),
+			Message:  d.Msg,
+		})
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
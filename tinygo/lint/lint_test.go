@@ -0,0 +1,258 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/loader"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+func resolveForTest(t *testing.T, input string) (*parser.PackageAST, resolver.ResolveTable) {
+	t.Helper()
+	lx := lexer.NewLexer()
+	lx.Set(input)
+	pkg, err := parser.NewParser(lx).ParsePackage()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	table, _, _, _, err := resolver.Resolve(pkg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	return pkg, table
+}
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "unused_locals",
+			input: "func main() {\n" +
+				"\ta := 1;\n" +
+				"\tb := 2;\n" +
+				"\tb = 3;\n" +
+				"\tfor i, v := range []int{1} { print(v); }\n" +
+				"\tvar p Point;\n" +
+				"\tp.x = 1;\n" +
+				"\tprint(a);\n" +
+				"}\n" +
+				"type Point struct { x int; }",
+			want: []string{
+				"3:2: unusedvar: declared and not used: b",
+				"5:6: unusedvar: declared and not used: i",
+			},
+		},
+		{
+			// 메서드, 함수 리터럴, 값으로 넘겨지는 함수의 매개변수는 보고하지 않음
+			name: "unused_params",
+			input: "type T struct { n int; }\n" +
+				"func (t T) Get(unused int) int { return 1; }\n" +
+				"func f(a int, b int) int { return a; }\n" +
+				"func g(n int) int { return 0; }\n" +
+				"func main() {\n" +
+				"\th := func(x int) int { return 0; };\n" +
+				"\tprint(f(1, 2) + h(1) + apply(g));\n" +
+				"}\n" +
+				"func apply(k func(int) int) int { return k(1); }",
+			want: []string{"3:15: unusedparam: unused parameter: b"},
+		},
+		{
+			name: "shadowed_variables",
+			input: "func main() {\n" +
+				"\terr := ok;\n" +
+				"\tn := 1;\n" +
+				"\tif n > 0 {\n" +
+				"\t\terr := newError(\"x\");\n" +
+				"\t\tprint(errString(err));\n" +
+				"\t}\n" +
+				"\tf := func(n int) int { return n; };\n" +
+				"\tfor i := 0; i < n; i = i + 1; { n := n; print(n); }\n" +
+				"\tprint(f(n), errString(err));\n" +
+				"}",
+			want: []string{
+				"5:3: shadow: declaration of err shadows declaration at 2:2",
+				"8:12: shadow: declaration of n shadows declaration at 3:2",
+			},
+		},
+		{
+			// main과 전역 변수 초기식에서 닿는 함수만 쓰이는 것으로 봄
+			name: "unused_funcs",
+			input: "var total int = seed();\n" +
+				"func seed() int { return helper(); }\n" +
+				"func helper() int { return 1; }\n" +
+				"func orphan() int { return orphanHelper(); }\n" +
+				"func orphanHelper() int { return 2; }\n" +
+				"func main() { print(total); }\n" +
+				"func init() { warm(); }\n" +
+				"func warm() { print(0); }",
+			want: []string{
+				"4:6: unusedfunc: function orphan is unused",
+				"5:6: unusedfunc: function orphanHelper is unused",
+			},
+		},
+		{
+			name: "ignored_errors",
+			input: "func save(n int) error { if n < 0 { return newError(\"neg\"); } return ok; }\n" +
+				"func load() (int, error) { return 1, ok; }\n" +
+				"func main() {\n" +
+				"\tsave(1);\n" +
+				"\tload();\n" +
+				"\tcheck := func() error { return ok; };\n" +
+				"\tcheck();\n" +
+				"\tif err := save(3); err != ok { print(errString(err)); }\n" +
+				"}",
+			want: []string{
+				"4:2: errcheck: error result of save is not checked",
+				"5:2: errcheck: error result of load is not checked",
+				"7:2: errcheck: error result of check is not checked",
+			},
+		},
+		{
+			name: "ignored_method_and_module_errors",
+			input: "type Db struct { name string; }\n" +
+				"type App struct { db Db; }\n" +
+				"func (d Db) Save() error { return ok; }\n" +
+				"func (d Db) Name() string { return d.name; }\n" +
+				"func open() Db { return Db{name: \"a\"}; }\n" +
+				"func main() {\n" +
+				"\td := Db{name: \"a\"};\n" +
+				"\td.Save();\n" +
+				"\td.Name();\n" +
+				"\tapp := App{db: d};\n" +
+				"\tapp.db.Save();\n" +
+				"\topen().Save();\n" +
+				"\tstrconv.Atoi(\"1\");\n" +
+				"\tstrconv.Itoa(1);\n" +
+				"\tif err := d.Save(); err != ok { print(errString(err)); }\n" +
+				"}",
+			want: []string{
+				"8:4: errcheck: error result of Db.Save is not checked",
+				"11:9: errcheck: error result of Db.Save is not checked",
+				"12:9: errcheck: error result of Db.Save is not checked",
+				"13:10: errcheck: error result of strconv.Atoi is not checked",
+			},
+		},
+		{
+			name: "self_assignment",
+			input: "type Point struct { x int; y int; }\n" +
+				"func main() {\n" +
+				"\tp := Point{x: 1, y: 2};\n" +
+				"\tn := 1;\n" +
+				"\tn = n;\n" +
+				"\tp.x = p.x;\n" +
+				"\tp.x = p.y;\n" +
+				"\tn, p.y = p.y, n;\n" +
+				"\tprint(n, p.x);\n" +
+				"}",
+			want: []string{
+				"5:2: selfassign: self-assignment of n to n",
+				"6:2: selfassign: self-assignment of p.x to p.x",
+			},
+		},
+		{
+			name: "suppression_comments",
+			input: "func main() {\n" +
+				"\ta := 1; //vet:ignore\n" +
+				"\t//vet:ignore shadow,unusedvar\n" +
+				"\tb := 2;\n" +
+				"\t//vet:ignore errcheck\n" +
+				"\tc := 3;\n" +
+				"\t//vet:ignored\n" +
+				"\td := 4;\n" +
+				"}",
+			want: []string{
+				"6:2: unusedvar: declared and not used: c",
+				"8:2: unusedvar: declared and not used: d",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pkg, table := resolveForTest(t, tc.input)
+			got := []string{}
+			for _, d := range Run(pkg, table, All) {
+				got = append(got, d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Fatalf("diagnostics mismatch:\n--- got ---\n%s\n--- want ---\n%s", strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRun_CustomAnalyzer(t *testing.T) {
+	// 분석기는 Pass로 패키지와 리졸브 테이블을 받아 진단을 보고함
+	noPrint := &Analyzer{
+		Name: "noprint",
+		Doc:  "report calls to print",
+		Run: func(pass *Pass) {
+			for id, ref := range pass.Table {
+				if ref.Kind == resolver.RefBuiltin && ref.Name == "print" {
+					pass.Reportf(parser.Id{Name: ref.Name, IdId: id}, "call to print")
+				}
+			}
+		},
+	}
+	pkg, table := resolveForTest(t, "func main() {\n\tprint(1);\n}")
+	got := Run(pkg, table, []*Analyzer{noPrint})
+	if len(got) != 1 || got[0].String() != "2:2: noprint: call to print" {
+		t.Fatalf("unexpected diagnostics: %v", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	pkg, table := resolveForTest(t, "func main() {\n\tx := 1;\n}")
+	pkg.SetFile("main.tgo")
+	out, err := JSON(Run(pkg, table, All))
+	if err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+	want := `[
+  {
+    "analyzer": "unusedvar",
+    "file": "main.tgo",
+    "line": 2,
+    "col": 2,
+    "name": "x",
+    "id": 1,
+    "message": "declared and not used: x"
+  }
+]`
+	if string(out) != want {
+		t.Fatalf("json mismatch:\n--- got ---\n%s\n--- want ---\n%s", out, want)
+	}
+}
+
+func TestRunProgram(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.tgo":    {Data: []byte("package main;\nimport \"store\";\nfunc main() {\n\tstore.Save(1); //vet:ignore unusedvar\n}\n")},
+		"store/a.tgo": {Data: []byte("package store;\nfunc Save(n int) error { return check(n); }\nfunc check(n int) error { return ok; }\nfunc stale() {}\n")},
+	}
+	pkgs, err := loader.NewLoader(fsys).Load()
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	table, _, _, _, err := resolver.ResolveProgram(pkgs)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	got := []string{}
+	for _, d := range RunProgram(pkgs, table, All) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"store/a.tgo:3:12: unusedparam: unused parameter: n",
+		"store/a.tgo:4:6: unusedfunc: function stale is unused",
+		"main.tgo:4:8: errcheck: error result of Save is not checked",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("diagnostics mismatch:\n--- got ---\n%s\n--- want ---\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,37 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// SelfAssign은 x = x, p.f = p.f 처럼 자기 자신을 대입하는 것을 보고함
+var SelfAssign = &Analyzer{
+	Name: "selfassign",
+	Doc:  "report assignments of a variable or field to itself",
+	Run: func(pass *Pass) {
+		parser.Inspect(pass.Pkg, func(node parser.Node) bool {
+			assign, ok := node.(*parser.Assign)
+			if !ok || len(assign.Ids) != len(assign.Exprs) {
+				return true
+			}
+			for i, id := range assign.Ids {
+				root, fields, ok := exprPath(assign.Exprs[i])
+				if !ok || root.Name != id.Name || pass.Table[root.IdId].RefIdNodeId != pass.Table[id.IdId].RefIdNodeId {
+					continue
+				}
+				target := []string{id.Name}
+				for _, field := range assign.FieldPathAt(i) {
+					target = append(target, field.Name)
+				}
+				if strings.Join(target, ".") != strings.Join(append([]string{root.Name}, fields...), ".") {
+					continue
+				}
+				path := strings.Join(target, ".")
+				pass.Reportf(id, "self-assignment of %s to %s", path, path)
+			}
+			return true
+		})
+	},
+}
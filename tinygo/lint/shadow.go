@@ -0,0 +1,48 @@
+package lint
+
+import "github.com/rlaaudgjs5638/langTest/tinygo/parser"
+
+// Shadow는 바깥 함수 스코프의 지역 변수나 매개변수를 같은 이름으로 가리는 선언을 보고함
+// 전역 이름을 가리는 것과, 클로저나 루프에서 값을 복사하는 x := x 꼴은 보고하지 않음
+var Shadow = &Analyzer{
+	Name: "shadow",
+	Doc:  "report declarations that shadow an enclosing local variable or parameter",
+	Run: func(pass *Pass) {
+		for _, d := range pass.facts().decls {
+			outer := d.ShadowedOrNil
+			if outer == nil || outer.Kind == declGlobal || isSameName(d.InitOrNil, d.Id.Name) {
+				continue
+			}
+			at := outer.Id.String()
+			if pos := pass.Pkg.PosOf(outer.Id); pos.IsValid() {
+				at = pos.String()
+			}
+			pass.Reportf(d.Id, "declaration of %s shadows declaration at %s", d.Id.Name, at)
+		}
+	},
+}
+
+// isSameName은 exprOrNil이 name이라는 맨 식별자인지 리턴함
+func isSameName(exprOrNil parser.Expr, name string) bool {
+	root, fields, ok := exprPath(exprOrNil)
+	return ok && len(fields) == 0 && root.Name == name
+}
+
+// exprPath는 x 또는 x.a.b 꼴의 식을 루트 식별자와 필드 이름들로 나눔
+func exprPath(exprOrNil parser.Expr) (parser.Id, []string, bool) {
+	switch e := exprOrNil.(type) {
+	case *parser.Primary:
+		switch e.PrimaryKind {
+		case parser.IdPrimary:
+			return *e.IdOrNil, nil, true
+		case parser.ExprPrimary:
+			return exprPath(e.ExprOrNil)
+		}
+	case *parser.Selector:
+		root, fields, ok := exprPath(e.Object)
+		if ok {
+			return root, append(fields, e.Field.Name), true
+		}
+	}
+	return parser.Id{}, nil, false
+}
@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// UnusedVar는 선언한 뒤 한 번도 읽지 않은 지역 변수, 지역 함수, 바인딩을 보고함
+// 대입만 하는 것은 읽는 것이 아니지만, 필드에 대입하는 것은 변수를 쓰는 것으로 봄
+var UnusedVar = &Analyzer{
+	Name: "unusedvar",
+	Doc:  "report local variables that are declared but never read",
+	Run: func(pass *Pass) {
+		f := pass.facts()
+		for _, d := range f.decls {
+			switch d.Kind {
+			case declVar, declFunc, declBinding:
+				if !f.reads[d.Id.IdId] {
+					pass.Reportf(d.Id, "declared and not used: %s", d.Id.Name)
+				}
+			}
+		}
+	},
+}
+
+// UnusedParam은 함수 선언에서 한 번도 읽지 않은 매개변수를 보고함
+// 메서드와 함수 리터럴, 값으로 넘겨지는 함수는 시그니처가 밖에서 정해지므로 검사하지 않음
+var UnusedParam = &Analyzer{
+	Name: "unusedparam",
+	Doc:  "report parameters of plain functions that are never read",
+	Run: func(pass *Pass) {
+		f := pass.facts()
+		for _, d := range f.decls {
+			if d.Kind != declParam || f.reads[d.Id.IdId] || f.values[d.FuncOrNil.Id.IdId] {
+				continue
+			}
+			pass.Reportf(d.Id, "unused parameter: %s", d.Id.Name)
+		}
+	},
+}
+
+// UnusedFunc는 전역 초기화 그래프에서 닿을 수 없는 전역 함수를 보고함
+// 뿌리는 main, init, 전역 변수와 상수의 초기식, 메서드 본문이며, main이 아닌 패키지에서는 공개된 함수도 뿌리임
+// main 패키지에 main 함수가 없다면 (실행할 프로그램이 아니므로) 검사하지 않음
+var UnusedFunc = &Analyzer{
+	Name: "unusedfunc",
+	Doc:  "report package-level functions unreachable from main and global initializers",
+	Run:  runUnusedFunc,
+}
+
+func runUnusedFunc(pass *Pass) {
+	isMain := pass.Pkg.NameOrNil == nil || pass.Pkg.NameOrNil.Name == "main"
+	funcs := []*parser.FuncDecl{}
+	hasMain := false
+	for _, decl := range pass.Pkg.DeclsOrNil {
+		if fn, ok := decl.(*parser.FuncDecl); ok && fn.ReceiverOrNil == nil {
+			funcs = append(funcs, fn)
+			hasMain = hasMain || fn.Id.Name == "main"
+		}
+	}
+	if isMain && !hasMain {
+		return
+	}
+
+	// 전역 선언마다 본문과 초기식이 참조하는 선언들
+	deps := map[parser.IdId][]parser.IdId{}
+	reached := map[parser.IdId]bool{}
+	queue := []parser.IdId{}
+	root := func(id parser.IdId) {
+		if !reached[id] {
+			reached[id] = true
+			queue = append(queue, id)
+		}
+	}
+	for _, decl := range pass.Pkg.DeclsOrNil {
+		refs := globalRefs(decl, pass)
+		switch d := decl.(type) {
+		case *parser.FuncDecl:
+			if d.ReceiverOrNil != nil {
+				// 메서드는 인터페이스를 통해 불릴 수 있으므로 언제나 닿는 것으로 봄
+				for _, ref := range refs {
+					root(ref)
+				}
+				continue
+			}
+			deps[d.Id.IdId] = refs
+			if d.Id.Name == "main" || d.Id.Name == "init" || (!isMain && isExported(d.Id.Name)) {
+				root(d.Id.IdId)
+			}
+		case *parser.VarDecl, *parser.ConstDecl:
+			for _, ref := range refs {
+				root(ref)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dep := range deps[id] {
+			root(dep)
+		}
+	}
+	for _, fn := range funcs {
+		if !reached[fn.Id.IdId] {
+			pass.Reportf(fn.Id, "function %s is unused", fn.Id.Name)
+		}
+	}
+}
+
+// globalRefs는 decl이 읽는 전역 선언들의 IdId를 리턴함
+func globalRefs(decl parser.Decl, pass *Pass) []parser.IdId {
+	refs := []parser.IdId{}
+	w := &walker{table: pass.Table}
+	w.onRef = func(id parser.Id, _ bool) {
+		if ref, ok := pass.Table[id.IdId]; ok && ref.RefIdNodeId != id.IdId {
+			refs = append(refs, ref.RefIdNodeId)
+		}
+	}
+	w.push()
+	w.walk(decl)
+	w.pop()
+	return refs
+}
+
+// isExported는 리졸버와 같이 대문자로 시작하는 이름을 공개된 것으로 봄
+func isExported(name string) bool {
+	first, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(first)
+}
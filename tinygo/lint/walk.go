@@ -0,0 +1,445 @@
+package lint
+
+import (
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// declKind는 지역 선언의 종류임
+type declKind uint8
+
+const (
+	// :=, var, range 변수
+	declVar declKind = iota
+	// 리시버가 없는 함수 선언의 매개변수
+	declParam
+	// 메서드의 리시버, 매개변수와 함수 리터럴의 매개변수. 시그니처가 밖에서 정해지는 경우가 많음
+	declFixedParam
+	// 지역 함수 선언
+	declFunc
+	// 타입 switch와 match 패턴이 묶은 이름
+	declBinding
+	// 패키지 레벨 선언
+	declGlobal
+)
+
+// binding은 스코프에 등록된 이름임
+type binding struct {
+	Id   parser.Id
+	Kind declKind
+}
+
+// localDecl은 함수 안에서 새 이름을 만드는 선언 하나임
+type localDecl struct {
+	binding
+	// declParam일 때 매개변수가 속한 함수 선언
+	FuncOrNil *parser.FuncDecl
+	// 이름과 개수가 맞는 초기식. 없으면 nil
+	InitOrNil parser.Expr
+	// 바깥 스코프에서 같은 이름으로 보이던 선언. 없으면 nil
+	ShadowedOrNil *binding
+}
+
+// facts는 패키지를 한 번 훑어 모은, 분석기들이 공유하는 정보임
+type facts struct {
+	// 소스 순서의 지역 선언들. 타입 switch의 바인딩처럼 절마다 다시 선언되는 것은 한 번만 담김
+	decls []localDecl
+	// 값으로 읽힌 선언의 IdId. 필드에 대입하는 것도 변수를 쓰는 것으로 봄
+	reads map[parser.IdId]bool
+	// 호출되는 자리가 아닌 곳에서 값으로 쓰인 선언의 IdId
+	values map[parser.IdId]bool
+	// 패키지의 모든 함수 선언. 지역 함수를 포함하며 메서드는 제외함
+	funcs map[parser.IdId]*parser.FuncDecl
+	// 함수 리터럴로 초기화된 변수의 IdId -> 그 함수 리터럴
+	fexps map[parser.IdId]*parser.Fexp
+	// 타입이 적힌 선언(매개변수, 리시버, var)의 IdId -> 그 타입
+	types map[parser.IdId]parser.Type
+	// 초기식이 있는 지역 선언의 IdId -> 그 초기식
+	inits map[parser.IdId]parser.Expr
+	// 리시버 타입의 TypeDecl IdId -> 메서드 이름 -> 메서드 선언
+	methods map[parser.IdId]map[string]*parser.FuncDecl
+	// TypeDecl의 IdId -> 그 선언
+	typeDecls map[parser.IdId]*parser.TypeDecl
+}
+
+// collectFacts는 스코프를 따라가며 pkg의 선언과 참조를 모음
+func collectFacts(pkg *parser.PackageAST, table resolver.ResolveTable) *facts {
+	f := &facts{
+		reads:     map[parser.IdId]bool{},
+		values:    map[parser.IdId]bool{},
+		funcs:     map[parser.IdId]*parser.FuncDecl{},
+		fexps:     map[parser.IdId]*parser.Fexp{},
+		types:     map[parser.IdId]parser.Type{},
+		inits:     map[parser.IdId]parser.Expr{},
+		methods:   map[parser.IdId]map[string]*parser.FuncDecl{},
+		typeDecls: map[parser.IdId]*parser.TypeDecl{},
+	}
+	seen := map[parser.IdId]bool{}
+	w := &walker{table: table}
+	w.onDecl = func(d localDecl) {
+		if !seen[d.Id.IdId] {
+			seen[d.Id.IdId] = true
+			f.decls = append(f.decls, d)
+		}
+		if d.InitOrNil != nil {
+			f.inits[d.Id.IdId] = d.InitOrNil
+		}
+	}
+	w.onNode = func(node parser.Node) {
+		switch n := node.(type) {
+		case *parser.FuncDecl:
+			if n.ReceiverOrNil == nil {
+				f.funcs[n.Id.IdId] = n
+			} else {
+				f.types[n.ReceiverOrNil.Id.IdId] = n.ReceiverOrNil.Type
+				recordMethod(f.methods, table, n)
+			}
+			for _, param := range n.ParamsOrNil {
+				f.types[param.Id.IdId] = param.Type
+			}
+		case *parser.TypeDecl:
+			f.typeDecls[n.Id.IdId] = n
+		case *parser.ShortDecl:
+			f.recordFexps(n.Ids, n.Exprs)
+		case *parser.VarDecl:
+			f.recordFexps(n.Ids, n.ExprsOrNil)
+			for _, id := range n.Ids {
+				f.types[id.IdId] = n.Type
+			}
+		case *parser.Assign:
+			for i, id := range n.Ids {
+				if len(n.FieldPathAt(i)) > 0 {
+					f.reads[table[id.IdId].RefIdNodeId] = true
+				}
+			}
+		}
+	}
+	w.onRef = func(id parser.Id, callee bool) {
+		ref, ok := table[id.IdId]
+		if !ok || ref.Kind == resolver.RefBuiltin {
+			return
+		}
+		f.reads[ref.RefIdNodeId] = true
+		if !callee {
+			f.values[ref.RefIdNodeId] = true
+		}
+	}
+	w.walkPackage(pkg)
+	return f
+}
+
+// recordMethod는 메서드 fn을 리시버 타입의 TypeDecl 아래에 등록함
+func recordMethod(methods map[parser.IdId]map[string]*parser.FuncDecl, table resolver.ResolveTable, fn *parser.FuncDecl) {
+	receiver := fn.ReceiverOrNil.Type
+	if receiver.TypeKind != parser.NamedType {
+		return
+	}
+	ref, ok := table[receiver.NameOrNil.IdId]
+	if !ok {
+		return
+	}
+	if methods[ref.RefIdNodeId] == nil {
+		methods[ref.RefIdNodeId] = map[string]*parser.FuncDecl{}
+	}
+	methods[ref.RefIdNodeId][fn.Id.Name] = fn
+}
+
+func (f *facts) recordFexps(ids []parser.Id, exprs []parser.Expr) {
+	if len(ids) != len(exprs) {
+		return
+	}
+	for i, expr := range exprs {
+		if fexp := fexpOf(expr); fexp != nil {
+			f.fexps[ids[i].IdId] = fexp
+		}
+	}
+}
+
+// fexpOf는 expr이 함수 리터럴이면 그것을, 아니면 nil을 리턴함
+func fexpOf(expr parser.Expr) *parser.Fexp {
+	primary, ok := expr.(*parser.Primary)
+	if !ok {
+		return nil
+	}
+	switch primary.PrimaryKind {
+	case parser.ExprPrimary:
+		return fexpOf(primary.ExprOrNil)
+	case parser.ValuePrimary:
+		if primary.ValueOrNil != nil && primary.ValueOrNil.ValueKind == parser.FexpValue {
+			return primary.ValueOrNil.FexpOrNil
+		}
+	}
+	return nil
+}
+
+// walker는 리졸버와 같은 규칙으로 스코프를 열고 닫으며 parser.Inspect로 AST를 깊이 우선으로 방문함
+// 리졸버는 스코프를 리졸빙 중에만 들고 있으므로, 선언이 무엇을 가리는지는 다시 따라가며 구함
+type walker struct {
+	table  resolver.ResolveTable
+	scopes []map[string]binding
+	// 방문 중인 노드들. 안쪽 노드가 끝에 있음
+	frames []frame
+	// 호출되는 함수 자리의 primary. 괄호를 벗기면 안쪽 primary로 옮겨 감
+	calleeOrNil *parser.Primary
+	// 모든 선언, 문장, 식 노드마다 불림
+	onNode func(parser.Node)
+	// 새 이름을 만드는 지역 선언마다 불림
+	onDecl func(localDecl)
+	// 값으로 읽는 식별자마다 불림. callee는 호출되는 자리인지 여부임
+	onRef func(id parser.Id, callee bool)
+}
+
+// frame은 방문 중인 노드와, 그 노드에 들어가며 연 스코프의 수임
+type frame struct {
+	node   parser.Node
+	scopes int
+}
+
+func (w *walker) push() {
+	w.scopes = append(w.scopes, map[string]binding{})
+}
+
+func (w *walker) pop() {
+	w.scopes = w.scopes[:len(w.scopes)-1]
+}
+
+// lookup은 스코프들을 안쪽부터 찾아 name의 선언을 리턴함
+func (w *walker) lookup(name string) (binding, bool) {
+	for i := len(w.scopes) - 1; i >= 0; i-- {
+		if b, ok := w.scopes[i][name]; ok {
+			return b, true
+		}
+	}
+	return binding{}, false
+}
+
+// declare는 현재 스코프에 id를 등록함. "_"는 등록하지 않음
+func (w *walker) declare(id parser.Id, kind declKind, funcOrNil *parser.FuncDecl) {
+	w.declareInit(id, kind, funcOrNil, nil)
+}
+
+// declareInit은 초기식 initOrNil과 함께 id를 등록함
+func (w *walker) declareInit(id parser.Id, kind declKind, funcOrNil *parser.FuncDecl, initOrNil parser.Expr) {
+	if id.Name == "_" {
+		return
+	}
+	d := localDecl{binding: binding{Id: id, Kind: kind}, FuncOrNil: funcOrNil, InitOrNil: initOrNil}
+	if outer, ok := w.lookup(id.Name); ok {
+		d.ShadowedOrNil = &outer
+	}
+	w.scopes[len(w.scopes)-1][id.Name] = d.binding
+	if kind != declGlobal && w.onDecl != nil {
+		w.onDecl(d)
+	}
+}
+
+func (w *walker) ref(id parser.Id, callee bool) {
+	if w.onRef != nil {
+		w.onRef(id, callee)
+	}
+}
+
+// walkPackage는 전역 이름을 모두 등록한 뒤(호이스팅) 선언들을 방문함
+func (w *walker) walkPackage(pkg *parser.PackageAST) {
+	w.push()
+	for _, decl := range pkg.DeclsOrNil {
+		switch d := decl.(type) {
+		case *parser.VarDecl:
+			for _, id := range d.Ids {
+				w.declare(id, declGlobal, nil)
+			}
+		case *parser.FuncDecl:
+			if d.ReceiverOrNil == nil {
+				w.declare(d.Id, declGlobal, nil)
+			}
+		case *parser.ConstDecl:
+			for _, spec := range d.Specs {
+				for _, id := range spec.Ids {
+					w.declare(id, declGlobal, nil)
+				}
+			}
+		}
+	}
+	for _, decl := range pkg.DeclsOrNil {
+		w.walk(decl)
+	}
+	w.pop()
+}
+
+// walk는 node와 그 아래를 방문함. 패키지 레벨 선언이라면 현재 스코프가 전역 스코프여야 함
+func (w *walker) walk(node parser.Node) {
+	parser.Inspect(node, w.visit)
+}
+
+func (w *walker) visit(node parser.Node) bool {
+	if node == nil {
+		w.leave()
+		return true
+	}
+	if w.onNode != nil {
+		w.onNode(node)
+	}
+	switch n := node.(type) {
+	case *parser.Match:
+		// 갈래마다 패턴이 묶는 이름의 스코프를 열어야 하므로 자식을 직접 방문함
+		w.walkMatch(n)
+		return false
+	case *parser.Call:
+		w.calleeOrNil = &n.PrimaryOrNil
+	case *parser.Primary:
+		w.visitPrimary(n)
+	case *parser.Selector:
+		// 다른 패키지의 멤버라면 리졸버가 필드 이름을 그 선언으로 연결해 둠
+		if _, ok := w.table[n.Field.IdId]; ok {
+			w.ref(n.Field, false)
+		}
+	}
+	w.frames = append(w.frames, frame{node: node, scopes: w.enter(node)})
+	return true
+}
+
+// visitPrimary는 식별자를 참조로 알림. 호출되는 자리는 괄호로 감싸도 유지됨
+func (w *walker) visitPrimary(p *parser.Primary) {
+	callee := p == w.calleeOrNil
+	w.calleeOrNil = nil
+	switch p.PrimaryKind {
+	case parser.IdPrimary:
+		w.ref(*p.IdOrNil, callee)
+	case parser.ExprPrimary:
+		if inner, ok := p.ExprOrNil.(*parser.Primary); ok && callee {
+			w.calleeOrNil = inner
+		}
+	}
+}
+
+// enter는 node에 들어가며 필요한 스코프를 열고 매개변수 같은 이름을 등록함. 연 스코프의 수를 리턴함
+func (w *walker) enter(node parser.Node) int {
+	switch n := node.(type) {
+	case *parser.FuncDecl:
+		if len(w.frames) > 0 {
+			// 지역 함수는 재귀 호출을 위해 이름을 먼저 등록함
+			w.declare(n.Id, declFunc, nil)
+		}
+		// 매개변수와 본문은 하나의 스코프임
+		w.push()
+		kind, funcOrNil := declParam, n
+		if n.ReceiverOrNil != nil {
+			kind, funcOrNil = declFixedParam, nil
+			w.declare(n.ReceiverOrNil.Id, declFixedParam, nil)
+		}
+		for _, param := range n.ParamsOrNil {
+			w.declare(param.Id, kind, funcOrNil)
+		}
+		return 1
+	case *parser.Fexp:
+		w.push()
+		for _, param := range n.ParamsOrNil {
+			w.declare(param.Id, declFixedParam, nil)
+		}
+		return 1
+	case *parser.Block:
+		return w.enterBlock(n)
+	case *parser.If, *parser.ForBexp, *parser.ForWithAssign, *parser.Switch:
+		w.push()
+		return 1
+	}
+	return 0
+}
+
+// enterBlock은 블록의 스코프를 엶. 함수 본문은 매개변수의 스코프를 그대로 씀
+// range 변수는 본문 바깥의 스코프에, 타입 switch의 바인딩은 절의 스코프에 등록함
+func (w *walker) enterBlock(block *parser.Block) int {
+	if len(w.frames) == 0 {
+		w.push()
+		return 1
+	}
+	switch parent := w.frames[len(w.frames)-1].node.(type) {
+	case *parser.FuncDecl, *parser.Fexp:
+		return 0
+	case *parser.ForRange:
+		w.push()
+		for _, v := range parent.Vars {
+			w.declare(v, declVar, nil)
+		}
+		w.push()
+		return 2
+	case *parser.TypeSwitch:
+		w.push()
+		if parent.BindingOrNil != nil {
+			w.declare(*parent.BindingOrNil, declBinding, nil)
+		}
+		return 1
+	}
+	w.push()
+	return 1
+}
+
+// leave는 노드를 벗어나며 연 스코프를 닫고, 초기식을 모두 방문한 지역 선언의 이름을 등록함
+func (w *walker) leave() {
+	top := w.frames[len(w.frames)-1]
+	w.frames = w.frames[:len(w.frames)-1]
+	for i := 0; i < top.scopes; i++ {
+		w.pop()
+	}
+	if len(w.frames) == 0 {
+		// 패키지 레벨 선언의 이름은 walkPackage에서 미리 등록함
+		return
+	}
+	switch n := top.node.(type) {
+	case *parser.VarDecl:
+		for i, id := range n.Ids {
+			w.declareInit(id, declVar, nil, initAt(n.ExprsOrNil, len(n.Ids), i))
+		}
+	case *parser.ShortDecl:
+		w.declareShortDecl(n)
+	}
+}
+
+// declareShortDecl은 현재 스코프에 이미 있는 이름을 대입으로, 나머지를 선언으로 처리함
+// let은 언제나 새 이름을 선언함
+func (w *walker) declareShortDecl(n *parser.ShortDecl) {
+	for i, id := range n.Ids {
+		if _, ok := w.scopes[len(w.scopes)-1][id.Name]; ok && !n.Immutable {
+			continue
+		}
+		w.declareInit(id, declVar, nil, initAt(n.Exprs, len(n.Ids), i))
+	}
+}
+
+// initAt은 변수 count개를 선언하는 초기식들에서 i번째 변수의 초기식을 리턴함
+// 다중 리턴 호출처럼 개수가 맞지 않으면 nil임
+func initAt(exprs []parser.Expr, count int, i int) parser.Expr {
+	if len(exprs) != count {
+		return nil
+	}
+	return exprs[i]
+}
+
+// walkMatch는 대상을 방문한 뒤, 갈래마다 새 스코프에서 패턴의 이름을 등록하고 가드와 본문을 방문함
+func (w *walker) walkMatch(n *parser.Match) {
+	w.walk(n.Subject)
+	for i := range n.Arms {
+		arm := &n.Arms[i]
+		w.push()
+		w.walkPattern(&arm.Pattern)
+		if arm.GuardOrNil != nil {
+			w.walk(arm.GuardOrNil)
+		}
+		w.walk(arm.Body)
+		w.pop()
+	}
+}
+
+// walkPattern은 패턴이 묶는 이름을 현재 갈래 스코프에 등록하고 리터럴 패턴의 식을 방문함
+func (w *walker) walkPattern(pat *parser.Pattern) {
+	switch pat.PatternKind {
+	case parser.BindPattern:
+		w.declare(*pat.IdOrNil, declBinding, nil)
+	case parser.LiteralPattern:
+		w.walk(pat.LiteralOrNil)
+	case parser.VariantPattern:
+		for i := range pat.Args {
+			w.walkPattern(&pat.Args[i])
+		}
+	}
+}
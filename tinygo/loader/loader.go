@@ -0,0 +1,206 @@
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// 모듈 레이아웃
+// 루트 디렉토리가 main 패키지이며, import "geo/shapes"는 루트 기준 geo/shapes 디렉토리를 가리킴
+// 한 디렉토리의 모든 .tgo 파일은 하나의 패키지로 합쳐짐
+
+// SourceExt는 tiny go 소스 파일의 확장자임
+const SourceExt = ".tgo"
+
+// MainPath는 루트 디렉토리(main 패키지)의 경로임. 다른 패키지에서 임포트할 수 없음
+const MainPath = "main"
+
+type Loader struct {
+	fsys fs.FS
+}
+
+func NewLoader(fsys fs.FS) *Loader {
+	return &Loader{fsys: fsys}
+}
+
+// NewDirLoader는 로컬 파일시스템의 root 디렉토리를 모듈로 읽는 로더를 만듦
+func NewDirLoader(root string) *Loader {
+	return NewLoader(os.DirFS(root))
+}
+
+// Load는 main 패키지와 그것이 (재귀적으로) 임포트하는 패키지들을 읽음
+// 리턴되는 패키지들은 임포트되는 패키지가 먼저 오도록 정렬되어 있으며, 마지막이 main 패키지임
+func (l *Loader) Load() ([]resolver.PackageSource, error) {
+	// 1. 임포트 그래프를 따라가며 순환을 검사하고 초기화 순서를 정함
+	order, err := l.importOrder()
+	if err != nil {
+		return nil, err
+	}
+	// 2. IdId가 파일, 패키지 간에 겹치지 않도록 하나의 파서 체인으로 정렬된 순서대로 다시 파싱함
+	// 임포트되는 패키지의 IdId가 더 작으므로, 초기화 순서의 동률도 임포트 순서를 따름
+	var prev *parser.Parser
+	pkgs := make([]resolver.PackageSource, 0, len(order))
+	for _, pkgPath := range order {
+		files, err := l.sourceFiles(pkgPath)
+		if err != nil {
+			return nil, err
+		}
+		asts := make([]*parser.PackageAST, 0, len(files))
+		for _, file := range files {
+			ast, ps, err := l.parseFile(file, prev)
+			if err != nil {
+				return nil, err
+			}
+			prev = ps
+			asts = append(asts, ast)
+		}
+		merged, err := parser.MergeFiles(asts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dirOf(pkgPath), err)
+		}
+		if err := checkPackageName(pkgPath, merged.NameOrNil.Name); err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, resolver.PackageSource{Path: pkgPath, AST: merged})
+	}
+	return pkgs, nil
+}
+
+// importOrder는 main에서 시작해 임포트 그래프를 DFS로 돌며 후위 순서를 리턴함
+// 방문 중인 패키지를 다시 만나면 순환 임포트임
+func (l *Loader) importOrder() ([]string, error) {
+	// state: 0 = 미방문, 1 = 방문 중, 2 = 방문 완료
+	state := map[string]int{}
+	stack := []string{}
+	order := []string{}
+	var visit func(pkgPath string) error
+	visit = func(pkgPath string) error {
+		switch state[pkgPath] {
+		case 1:
+			// main부터의 임포트 경로 전체를 보여줌
+			cycle := append(append([]string{}, stack...), pkgPath)
+			return fmt.Errorf("import cycle not allowed: %s", strings.Join(cycle, " -> "))
+		case 2:
+			return nil
+		}
+		state[pkgPath] = 1
+		stack = append(stack, pkgPath)
+		imports, err := l.importsOf(pkgPath)
+		if err != nil {
+			return err
+		}
+		for _, imp := range imports {
+			if imp == MainPath {
+				return fmt.Errorf("%s: cannot import package main", dirOf(pkgPath))
+			}
+			if err := visit(imp); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[pkgPath] = 2
+		order = append(order, pkgPath)
+		return nil
+	}
+	if err := visit(MainPath); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// importsOf는 패키지의 모든 파일이 임포트하는 경로들을 등장 순서대로 중복 없이 리턴함
+func (l *Loader) importsOf(pkgPath string) ([]string, error) {
+	files, err := l.sourceFiles(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	imports := []string{}
+	for _, file := range files {
+		ast, _, err := l.parseFile(file, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range ast.Imports {
+			if seen[imp.Path] {
+				continue
+			}
+			seen[imp.Path] = true
+			imports = append(imports, imp.Path)
+		}
+	}
+	return imports, nil
+}
+
+// sourceFiles는 패키지 디렉토리의 .tgo 파일들을 이름 순으로 리턴함
+func (l *Loader) sourceFiles(pkgPath string) ([]string, error) {
+	dir := dirOf(pkgPath)
+	entries, err := fs.ReadDir(l.fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find package %s: %w", pkgPath, err)
+	}
+	files := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), SourceExt) {
+			continue
+		}
+		files = append(files, path.Join(dir, entry.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s files in %s", SourceExt, dir)
+	}
+	return files, nil
+}
+
+// parseFile은 prev가 있다면 prev에 이어서 IdId를 발급하는 파서로 file을 파싱함
+func (l *Loader) parseFile(file string, prev *parser.Parser) (*parser.PackageAST, *parser.Parser, error) {
+	src, err := fs.ReadFile(l.fsys, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	lx := lexer.NewLexer()
+	lx.Set(string(src))
+	var ps *parser.Parser
+	if prev == nil {
+		ps = parser.NewParser(lx)
+	} else {
+		ps = parser.NewParserFrom(lx, prev)
+	}
+	ast, err := ps.ParsePackage()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if ast.NameOrNil == nil {
+		return nil, nil, fmt.Errorf("%s: missing package clause", file)
+	}
+	ast.SetFile(file)
+	return ast, ps, nil
+}
+
+// checkPackageName은 루트만 package main이고, 나머지는 경로의 마지막 요소를 이름으로 쓰는지 검사함
+func checkPackageName(pkgPath string, name string) error {
+	if pkgPath == MainPath {
+		if name != "main" {
+			return fmt.Errorf("root directory must be package main, found package %s", name)
+		}
+		return nil
+	}
+	if want := path.Base(pkgPath); name != want {
+		return fmt.Errorf("%s: found package %s, expected %s", pkgPath, name, want)
+	}
+	return nil
+}
+
+func dirOf(pkgPath string) string {
+	if pkgPath == MainPath {
+		return "."
+	}
+	return pkgPath
+}
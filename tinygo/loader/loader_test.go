@@ -0,0 +1,155 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+func mapFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, src := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(src)}
+	}
+	return fsys
+}
+
+func TestLoader_OrdersImportsBeforeImporters(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"main.tgo":         "package main; import \"geo\"; import \"util\"; func main() {}",
+		"geo/area.tgo":     "package geo; import \"util/mathx\"; func Area() int { return 1; }",
+		"util/util.tgo":    "package util; func Id() int { return 1; }",
+		"util/mathx/m.tgo": "package mathx; func Sq(x int) int { return x * x; }",
+		"README.md":        "not a source file",
+	})
+	pkgs, err := NewLoader(fsys).Load()
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	got := []string{}
+	for _, pkg := range pkgs {
+		got = append(got, pkg.Path)
+	}
+	want := "util/mathx,geo,util,main"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected package order: got %v, want %s", got, want)
+	}
+}
+
+func TestLoader_MergesFilesOfOnePackage(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"a.tgo": "package main; var a int = b + 1;",
+		"b.tgo": "package main; var b int = 1; func main() {}",
+	})
+	pkgs, err := NewLoader(fsys).Load()
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if len(pkgs) != 1 || len(pkgs[0].AST.DeclsOrNil) != 3 {
+		t.Fatalf("expected one package with 3 decls, got %d packages", len(pkgs))
+	}
+	// 파일 간 IdId가 겹치지 않아야 함
+	a := pkgs[0].AST.DeclsOrNil[0].(*parser.VarDecl).Ids[0]
+	b := pkgs[0].AST.DeclsOrNil[1].(*parser.VarDecl).Ids[0]
+	if a.IdId == b.IdId {
+		t.Fatalf("duplicate IdId #%d across files", a.IdId)
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "import_cycle",
+			files: map[string]string{
+				"main.tgo": "package main; import \"a\"; func main() {}",
+				"a/a.tgo":  "package a; import \"b\";",
+				"b/b.tgo":  "package b; import \"a\";",
+			},
+			want: "import cycle not allowed: main -> a -> b -> a",
+		},
+		{
+			name: "missing_package",
+			files: map[string]string{
+				"main.tgo": "package main; import \"nope\"; func main() {}",
+			},
+			want: "cannot find package nope",
+		},
+		{
+			name: "mixed_package_names",
+			files: map[string]string{
+				"a.tgo": "package main; func main() {}",
+				"b.tgo": "package other;",
+			},
+			want: "found packages main and other in the same directory",
+		},
+		{
+			name: "missing_package_clause",
+			files: map[string]string{
+				"main.tgo": "func main() {}",
+			},
+			want: "main.tgo: missing package clause",
+		},
+		{
+			name: "root_must_be_main",
+			files: map[string]string{
+				"main.tgo": "package app; func main() {}",
+			},
+			want: "root directory must be package main, found package app",
+		},
+		{
+			name: "package_name_must_match_dir",
+			files: map[string]string{
+				"main.tgo":    "package main; import \"geo\"; func main() {}",
+				"geo/geo.tgo": "package shapes;",
+			},
+			want: "geo: found package shapes, expected geo",
+		},
+		{
+			name: "import_main",
+			files: map[string]string{
+				"main.tgo": "package main; import \"a\"; func main() {}",
+				"a/a.tgo":  "package a; import \"main\";",
+			},
+			want: "a: cannot import package main",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLoader(mapFS(tc.files)).Load()
+			if err == nil {
+				t.Fatalf("expected load error")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDirLoader_ReadsLocalFilesystem(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "geo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.tgo"), []byte("package main; import \"geo\"; func main() {}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "geo", "geo.tgo"), []byte("package geo; func Area() int { return 1; }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := NewDirLoader(root).Load()
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if len(pkgs) != 2 || pkgs[0].Path != "geo" || pkgs[1].Path != MainPath {
+		t.Fatalf("unexpected packages: %+v", pkgs)
+	}
+}
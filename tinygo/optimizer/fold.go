@@ -0,0 +1,132 @@
+package optimizer
+
+import (
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// 접기 규칙은 런타임의 연산 규칙과 같음
+// 런타임에 에러가 날 연산 (타입이 다른 피연산자, string의 대소 비교 등)은 접지 않고 그대로 둠
+
+// constOf는 expr이 int, bool, string 리터럴이라면 그 값을 리턴함
+func constOf(expr parser.Expr) (resolver.ConstValue, bool) {
+	primary, ok := expr.(*parser.Primary)
+	if !ok || primary.PrimaryKind != parser.ValuePrimary || primary.ValueOrNil == nil {
+		return resolver.ConstValue{}, false
+	}
+	value := primary.ValueOrNil
+	switch value.ValueKind {
+	case parser.NumberValue:
+		return resolver.ConstValue{Kind: parser.IntType, Int: int64(*value.NumberOrNil)}, true
+	case parser.BoolValue:
+		return resolver.ConstValue{Kind: parser.BoolType, Bool: *value.BoolOrNil}, true
+	case parser.StrLitValue:
+		return resolver.ConstValue{Kind: parser.StringType, Str: *value.StrLitOrNil}, true
+	}
+	return resolver.ConstValue{}, false
+}
+
+// literalOf는 상수 값을 리터럴 Primary로 만듦
+func literalOf(value resolver.ConstValue) *parser.Primary {
+	form := &parser.ValueForm{}
+	switch value.Kind {
+	case parser.IntType:
+		n := int(value.Int)
+		form.ValueKind, form.NumberOrNil = parser.NumberValue, &n
+	case parser.BoolType:
+		b := value.Bool
+		form.ValueKind, form.BoolOrNil = parser.BoolValue, &b
+	default:
+		s := value.Str
+		form.ValueKind, form.StrLitOrNil = parser.StrLitValue, &s
+	}
+	return &parser.Primary{PrimaryKind: parser.ValuePrimary, ValueOrNil: form}
+}
+
+func (o *Optimizer) foldUnary(node *parser.Unary) parser.Expr {
+	operand, ok := constOf(node.Object)
+	if !ok {
+		return node
+	}
+	switch {
+	case node.Op == parser.MinusUnary && operand.Kind == parser.IntType:
+		return literalOf(resolver.ConstValue{Kind: parser.IntType, Int: -operand.Int})
+	case node.Op == parser.Not && operand.Kind == parser.BoolType:
+		return literalOf(resolver.ConstValue{Kind: parser.BoolType, Bool: !operand.Bool})
+	}
+	return node
+}
+
+func (o *Optimizer) foldBinary(node *parser.Binary) parser.Expr {
+	if node.Op == parser.And || node.Op == parser.Or {
+		return simplifyLogical(node)
+	}
+	right, rok := constOf(node.RightExpr)
+	if node.Op == parser.Div && rok && right.Kind == parser.IntType && right.Int == 0 {
+		o.report("invalid operation: division by zero")
+		return node
+	}
+	left, lok := constOf(node.LeftExpr)
+	if !lok || !rok || left.Kind != right.Kind {
+		return node
+	}
+	if value, ok := foldConstBinary(node.Op, left, right); ok {
+		return literalOf(value)
+	}
+	return node
+}
+
+// simplifyLogical은 한쪽이 상수인 &&, || 를 줄임
+// 왼쪽이 상수라면 단락 평가의 결과가 정해지고, 오른쪽이 상수라면 항등원일 때만 왼쪽으로 줄임
+// x && false 처럼 결과가 정해지더라도 x의 평가를 지울 수는 없으므로 그대로 둠
+func simplifyLogical(node *parser.Binary) parser.Expr {
+	identity := node.Op == parser.And
+	if left, ok := constOf(node.LeftExpr); ok && left.Kind == parser.BoolType {
+		if left.Bool == identity {
+			return node.RightExpr
+		}
+		return node.LeftExpr
+	}
+	if right, ok := constOf(node.RightExpr); ok && right.Kind == parser.BoolType && right.Bool == identity {
+		return node.LeftExpr
+	}
+	return node
+}
+
+func foldConstBinary(op parser.BinaryKind, left, right resolver.ConstValue) (resolver.ConstValue, bool) {
+	switch op {
+	case parser.Equal, parser.NotEqual:
+		eq := left == right
+		if op == parser.NotEqual {
+			eq = !eq
+		}
+		return resolver.ConstValue{Kind: parser.BoolType, Bool: eq}, true
+	case parser.Plus:
+		if left.Kind == parser.StringType {
+			return resolver.ConstValue{Kind: parser.StringType, Str: left.Str + right.Str}, true
+		}
+	}
+	if left.Kind != parser.IntType {
+		return resolver.ConstValue{}, false
+	}
+	l, r := left.Int, right.Int
+	switch op {
+	case parser.Plus:
+		return resolver.ConstValue{Kind: parser.IntType, Int: l + r}, true
+	case parser.MinusBinary:
+		return resolver.ConstValue{Kind: parser.IntType, Int: l - r}, true
+	case parser.Mul:
+		return resolver.ConstValue{Kind: parser.IntType, Int: l * r}, true
+	case parser.Div:
+		return resolver.ConstValue{Kind: parser.IntType, Int: l / r}, true
+	case parser.GreaterThan:
+		return resolver.ConstValue{Kind: parser.BoolType, Bool: l > r}, true
+	case parser.GreaterOrEqual:
+		return resolver.ConstValue{Kind: parser.BoolType, Bool: l >= r}, true
+	case parser.LessThan:
+		return resolver.ConstValue{Kind: parser.BoolType, Bool: l < r}, true
+	case parser.LessOrEqual:
+		return resolver.ConstValue{Kind: parser.BoolType, Bool: l <= r}, true
+	}
+	return resolver.ConstValue{}, false
+}
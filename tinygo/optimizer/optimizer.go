@@ -0,0 +1,274 @@
+package optimizer
+
+import (
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+// Optimizer는 리졸빙이 끝난 PackageAST를 제자리에서 고쳐 씀
+// - 상수 산술, 비교, 문자열 연결을 리터럴로 접음. 상수 이름도 그 값으로 바꿔 접음
+// - true && x, false || x 처럼 결과가 정해지는 논리식을 줄임
+// - 조건이 상수인 if는 실행될 갈래만 남김
+// 리졸브 테이블의 슬롯, 거리가 그대로 유효하도록, 스코프 구조는 바꾸지 않음
+type Optimizer struct {
+	table resolver.ResolveTable
+	hoist *resolver.HoistInfo
+	// 진단 위치로 쓰는, 최적화 중인 가장 안쪽 선언의 id
+	declId      parser.Id
+	diagnostics []Diagnostic
+}
+
+// Diagnostic은 최적화 중 발견한 컴파일 시점의 문제임
+// 문제가 있는 식은 접지 않고 그대로 두므로, 실행하면 런타임 에러가 남
+type Diagnostic struct {
+	At  parser.Id
+	Msg string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("diagnostic at %s: %s", d.At.String(), d.Msg)
+}
+
+func NewOptimizer(table resolver.ResolveTable, hoist *resolver.HoistInfo) *Optimizer {
+	return &Optimizer{table: table, hoist: hoist}
+}
+
+// Optimize는 pkg를 최적화하고 발견한 진단들을 리턴함
+func Optimize(pkg *parser.PackageAST, table resolver.ResolveTable, hoist *resolver.HoistInfo) []Diagnostic {
+	o := NewOptimizer(table, hoist)
+	o.OptimizePackage(pkg)
+	return o.diagnostics
+}
+
+func (o *Optimizer) OptimizePackage(pkg *parser.PackageAST) {
+	for _, decl := range pkg.DeclsOrNil {
+		switch node := decl.(type) {
+		case *parser.VarDecl:
+			o.declId = node.Ids[0]
+			o.optimizeExprs(node.ExprsOrNil)
+		case *parser.FuncDecl:
+			o.declId = node.Id
+			o.optimizeBlock(&node.Block)
+		}
+		// const 선언은 리졸버가 이미 접었고, 타입 선언엔 식이 없음
+	}
+}
+
+func (o *Optimizer) Diagnostics() []Diagnostic {
+	return o.diagnostics
+}
+
+func (o *Optimizer) report(msg string) {
+	o.diagnostics = append(o.diagnostics, Diagnostic{At: o.declId, Msg: msg})
+}
+
+// optimizeBlock은 블록의 문장들을 최적화함. 실행되지 않는 문장은 블록에서 빠짐
+func (o *Optimizer) optimizeBlock(block *parser.Block) {
+	stmts := make([]parser.Stmt, 0, len(block.StmtsOrNil))
+	for _, stmt := range block.StmtsOrNil {
+		if optimized := o.optimizeStmt(stmt); optimized != nil {
+			stmts = append(stmts, optimized)
+		}
+	}
+	block.StmtsOrNil = stmts
+}
+
+// optimizeStmt는 문장을 최적화한 결과를 리턴함. nil이라면 문장이 지워진 것임
+func (o *Optimizer) optimizeStmt(stmt parser.Stmt) parser.Stmt {
+	switch node := stmt.(type) {
+	case *parser.Assign:
+		o.optimizeExprs(node.Exprs)
+	case *parser.CallStmt:
+		o.optimizeCall(&node.Call)
+	case *parser.TryStmt:
+		node.Try.Object = o.optimizeExpr(node.Try.Object)
+	case *parser.ShortDecl:
+		o.optimizeExprs(node.Exprs)
+	case *parser.VarDecl:
+		o.optimizeExprs(node.ExprsOrNil)
+	case *parser.FuncDecl:
+		o.optimizeBlock(&node.Block)
+	case *parser.Return:
+		o.optimizeExprs(node.ExprsOrNil)
+	case *parser.Yield:
+		o.optimizeExprs(node.Exprs)
+	case *parser.If:
+		return o.optimizeIf(node)
+	case *parser.ForBexp:
+		node.Bexp = o.optimizeExpr(node.Bexp)
+		o.optimizeBlock(&node.Block)
+	case *parser.ForWithAssign:
+		o.optimizeExprs(node.ShortDecl.Exprs)
+		node.Bexp = o.optimizeExpr(node.Bexp)
+		o.optimizeExprs(node.Assign.Exprs)
+		o.optimizeBlock(&node.Block)
+	case *parser.ForRange:
+		node.Expr = o.optimizeExpr(node.Expr)
+		o.optimizeBlock(&node.Block)
+	case *parser.TypeSwitch:
+		node.Subject = o.optimizeExpr(node.Subject)
+		for i := range node.Clauses {
+			o.optimizeBlock(&node.Clauses[i].Block)
+		}
+	case *parser.Switch:
+		if node.ShortDeclOrNil != nil {
+			o.optimizeExprs(node.ShortDeclOrNil.Exprs)
+		}
+		if node.TagOrNil != nil {
+			node.TagOrNil = o.optimizeExpr(node.TagOrNil)
+		}
+		for i := range node.Clauses {
+			o.optimizeExprs(node.Clauses[i].Exprs)
+			o.optimizeBlock(&node.Clauses[i].Block)
+		}
+	case *parser.Block:
+		o.optimizeBlock(node)
+	}
+	return stmt
+}
+
+// optimizeIf는 조건이 상수로 접힌 if를 실행될 갈래만 남긴 블록으로 바꿈
+// if는 헤더의 스코프 안에 갈래의 스코프를 만드므로, { ShortDecl; { 갈래 } } 형태로 바꿔 스코프 깊이를 유지함
+func (o *Optimizer) optimizeIf(node *parser.If) parser.Stmt {
+	if node.ShortDeclOrNil != nil {
+		o.optimizeExprs(node.ShortDeclOrNil.Exprs)
+	}
+	node.Bexp = o.optimizeExpr(node.Bexp)
+	o.optimizeBlock(&node.ThenBlock)
+	if node.ElseOrNil != nil {
+		o.optimizeBlock(node.ElseOrNil)
+	}
+	cond, ok := constOf(node.Bexp)
+	if !ok || cond.Kind != parser.BoolType {
+		return node
+	}
+	var taken *parser.Block
+	if cond.Bool {
+		taken = &node.ThenBlock
+	} else {
+		taken = node.ElseOrNil
+	}
+	header := []parser.Stmt{}
+	if node.ShortDeclOrNil != nil {
+		header = append(header, node.ShortDeclOrNil)
+	}
+	if taken != nil {
+		header = append(header, taken)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	return &parser.Block{StmtsOrNil: header}
+}
+
+func (o *Optimizer) optimizeExprs(exprs []parser.Expr) {
+	for i, expr := range exprs {
+		exprs[i] = o.optimizeExpr(expr)
+	}
+}
+
+// optimizeExpr는 식을 최적화한 결과를 리턴함
+func (o *Optimizer) optimizeExpr(expr parser.Expr) parser.Expr {
+	switch node := expr.(type) {
+	case *parser.Primary:
+		return o.optimizePrimary(node)
+	case *parser.Unary:
+		node.Object = o.optimizeExpr(node.Object)
+		return o.foldUnary(node)
+	case *parser.Binary:
+		node.LeftExpr = o.optimizeExpr(node.LeftExpr)
+		node.RightExpr = o.optimizeExpr(node.RightExpr)
+		return o.foldBinary(node)
+	case *parser.Call:
+		o.optimizeCall(node)
+	case *parser.Selector:
+		if value, ok := o.constValueOf(node.Field); ok {
+			return literalOf(value)
+		}
+		node.Object = o.optimizeExpr(node.Object)
+	case *parser.TypeAssert:
+		node.Object = o.optimizeExpr(node.Object)
+	case *parser.Try:
+		node.Object = o.optimizeExpr(node.Object)
+	case *parser.Index:
+		node.Object = o.optimizeExpr(node.Object)
+		if node.Index != nil {
+			node.Index = o.optimizeExpr(node.Index)
+		}
+	case *parser.Match:
+		node.Subject = o.optimizeExpr(node.Subject)
+		for i := range node.Arms {
+			if node.Arms[i].GuardOrNil != nil {
+				node.Arms[i].GuardOrNil = o.optimizeExpr(node.Arms[i].GuardOrNil)
+			}
+			node.Arms[i].Body = o.optimizeExpr(node.Arms[i].Body)
+		}
+	}
+	return expr
+}
+
+func (o *Optimizer) optimizeCall(call *parser.Call) {
+	o.optimizePrimaryInPlace(&call.PrimaryOrNil)
+	for _, args := range call.ArgsList {
+		o.optimizeExprs(args)
+	}
+}
+
+func (o *Optimizer) optimizePrimary(node *parser.Primary) parser.Expr {
+	switch node.PrimaryKind {
+	case parser.ExprPrimary:
+		inner := o.optimizeExpr(node.ExprOrNil)
+		// (60 * 60) 처럼 괄호 안이 리터럴로 접혔다면 괄호를 벗김
+		if _, ok := constOf(inner); ok {
+			return inner
+		}
+		node.ExprOrNil = inner
+	case parser.IdPrimary:
+		if value, ok := o.constValueOf(*node.IdOrNil); ok {
+			return literalOf(value)
+		}
+	case parser.ValuePrimary:
+		o.optimizeValueForm(node.ValueOrNil)
+	}
+	return node
+}
+
+// optimizePrimaryInPlace는 호출 대상처럼 Primary 값 자체가 필요한 자리의 Primary를 최적화함
+func (o *Optimizer) optimizePrimaryInPlace(node *parser.Primary) {
+	switch node.PrimaryKind {
+	case parser.ExprPrimary:
+		node.ExprOrNil = o.optimizeExpr(node.ExprOrNil)
+	case parser.ValuePrimary:
+		o.optimizeValueForm(node.ValueOrNil)
+	}
+}
+
+func (o *Optimizer) optimizeValueForm(value *parser.ValueForm) {
+	if value == nil {
+		return
+	}
+	switch value.ValueKind {
+	case parser.FexpValue:
+		o.optimizeBlock(&value.FexpOrNil.Block)
+	case parser.StructLitValue:
+		for i := range value.StructLitOrNil.Fields {
+			value.StructLitOrNil.Fields[i].Expr = o.optimizeExpr(value.StructLitOrNil.Fields[i].Expr)
+		}
+	case parser.SliceLitValue:
+		o.optimizeExprs(value.SliceLitOrNil.Elems)
+	case parser.TupleLitValue:
+		o.optimizeExprs(value.TupleLitOrNil.Elems)
+	}
+}
+
+// constValueOf는 id가 상수를 가리킨다면 그 접힌 값을 리턴함
+// 상수를 셰도잉한 지역 변수는 리졸브 테이블에서 지역 참조이므로 바뀌지 않음
+func (o *Optimizer) constValueOf(id parser.Id) (resolver.ConstValue, bool) {
+	ref, ok := o.table[id.IdId]
+	if !ok || ref.Kind != resolver.RefGlobal || o.hoist == nil {
+		return resolver.ConstValue{}, false
+	}
+	return o.hoist.GetConstValueById(ref.RefIdNodeId)
+}
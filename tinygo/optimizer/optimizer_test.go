@@ -0,0 +1,162 @@
+package optimizer
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/evaluator"
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/resolver"
+)
+
+func parseForTest(t *testing.T, input string) *parser.PackageAST {
+	t.Helper()
+	lx := lexer.NewLexer()
+	lx.Set(input)
+	pkg, err := parser.NewParser(lx).ParsePackage()
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	return pkg
+}
+
+func optimizeForTest(t *testing.T, input string) (*parser.PackageAST, []Diagnostic) {
+	t.Helper()
+	pkg := parseForTest(t, input)
+	table, hoist, _, _, err := resolver.Resolve(pkg)
+	if err != nil {
+		t.Fatalf("resolve error: %v", err)
+	}
+	return pkg, Optimize(pkg, table, hoist)
+}
+
+var idIdPattern = regexp.MustCompile(`\(#\d+\)`)
+
+// withoutIdIds는 AST 출력에서 id 번호를 지워, 서로 다른 입력에서 나온 AST를 비교할 수 있게 함
+func withoutIdIds(pkg *parser.PackageAST) string {
+	return idIdPattern.ReplaceAllString(pkg.String(), "")
+}
+
+func TestOptimize_Rewrites(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "fold_arithmetic",
+			input: "func f() int { return 60 * 60 * 24 + -(2 - 5) / 3; }",
+			want:  "func f() int { return 86401; }",
+		},
+		{
+			name:  "fold_strings_and_comparisons",
+			input: "func f() { s := \"a\" + \"b\" + \"c\"; b := 1 < 2; c := \"x\" == \"y\"; d := !(3 >= 3); }",
+			want:  "func f() { s := \"abc\"; b := true; c := false; d := false; }",
+		},
+		{
+			name:  "inline_constants",
+			input: "const Day = 24; const Name = \"d\"; func f(Day int) int { s := Name + \"!\"; return Day * 60; } func g() int { return Day * 60; }",
+			want:  "const Day = 24; const Name = \"d\"; func f(Day int) int { s := \"d!\"; return Day * 60; } func g() int { return 1440; }",
+		},
+		{
+			name:  "simplify_logical",
+			input: "func f(x bool) { a := true && x; b := false || x; c := x && true; d := false && x; e := true || x; g := x && false; }",
+			want:  "func f(x bool) { a := x; b := x; c := x; d := false; e := true; g := x && false; }",
+		},
+		{
+			name:  "partial_fold_keeps_variables",
+			input: "func f(n int) int { return n * (2 * 3) + (n + 1 * 2); }",
+			want:  "func f(n int) int { return n * 6 + (n + 2); }",
+		},
+		{
+			name:  "remove_if_false",
+			input: "func f() { if 1 > 2 { print(1); } print(2); }",
+			want:  "func f() { print(2); }",
+		},
+		{
+			// 갈래의 스코프 깊이를 유지하도록 블록으로 감쌈
+			name:  "keep_taken_branch",
+			input: "func f() { if n := 3; 1 > 2 { print(1); } else { print(n); } if true { print(2); } }",
+			want:  "func f() { { n := 3; { print(n); } } { { print(2); } } }",
+		},
+		{
+			name:  "fold_inside_closures_and_literals",
+			input: "func f() { g := func() []int { return []int{1 + 1, 2 * 2}; }; }",
+			want:  "func f() { g := func() []int { return []int{2, 4}; }; }",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, diags := optimizeForTest(t, tc.input)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			want := parseForTest(t, tc.want)
+			if withoutIdIds(got) != withoutIdIds(want) {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", withoutIdIds(got), withoutIdIds(want))
+			}
+		})
+	}
+}
+
+func TestOptimize_PreservesResults(t *testing.T) {
+	input := "const Base = 10; var out string = \"\"; var scaled int = Base * Base + 1; " +
+		"func pick(n int) string { if n := 2; Base > 5 { return \"big\" + strconv.Itoa(n); } return \"small\"; } " +
+		"func main() { total := 0; for i := 0; i < 2 * 2; i = i + 1; { if false { total = total + 100; } total = total + i * (3 - 1); } " +
+		"out = fmt.Sprintf(\"%d %d %s %t\", scaled, total, pick(0), true && total > 5); }"
+	run := func(optimize bool) string {
+		pkg := parseForTest(t, input)
+		table, hoist, order, builtins, err := resolver.Resolve(pkg)
+		if err != nil {
+			t.Fatalf("resolve error: %v", err)
+		}
+		if optimize {
+			if diags := Optimize(pkg, table, hoist); len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+		}
+		e, err := evaluator.Evaluate(*pkg, hoist, order, table, builtins)
+		if err != nil {
+			t.Fatalf("evaluate error: %v", err)
+		}
+		// 전역 out의 선언 id를 식으로 평가해 값을 읽음
+		outId := pkg.DeclsOrNil[1].(*parser.VarDecl).Ids[0]
+		values, _, err := e.Valuate(&parser.Primary{PrimaryKind: parser.IdPrimary, IdOrNil: &outId})
+		if err != nil || len(values) != 1 {
+			t.Fatalf("cannot read out: %v", err)
+		}
+		return values[0].Inspect()
+	}
+	plain, optimized := run(false), run(true)
+	if plain != optimized || optimized != "101 12 big2 true" {
+		t.Fatalf("optimized result differs: plain %s, optimized %s", plain, optimized)
+	}
+}
+
+func TestOptimize_DivisionByZero(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "literal_divisor",
+			input: "func f(n int) int { return n / 0; }",
+			want:  "diagnostic at f(#0): invalid operation: division by zero",
+		},
+		{
+			name:  "folded_divisor",
+			input: "const Z = 3; var q int = 1 / (Z - 3);",
+			want:  "diagnostic at q(#1): invalid operation: division by zero",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, diags := optimizeForTest(t, tc.input)
+			if len(diags) != 1 || diags[0].String() != tc.want {
+				t.Fatalf("expected diagnostic %q, got %v", tc.want, diags)
+			}
+		})
+	}
+}
@@ -3,13 +3,21 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/token"
 )
 
 // Node
 type PackageAST struct {
+	// package 절이 없다면 nil
+	NameOrNil  *Id
+	Imports    []Import
 	DeclsOrNil []Decl
+	// Positions는 IdId마다 id가 소스에서 시작하는 위치임
+	Positions map[IdId]Pos
+	// Comments는 소스의 주석들로, 소스 순서임
+	Comments []Comment
 }
 
 func newPackage(declsOrNil []Decl) *PackageAST {
@@ -22,6 +30,12 @@ func (p *PackageAST) Print(depth int) []string {
 	var pkgStrings []string
 	pkgStart := LineWithDepth("Package Start -------", depth)
 	pkgStrings = append(pkgStrings, pkgStart)
+	if p.NameOrNil != nil {
+		pkgStrings = append(pkgStrings, LineWithDepth("package "+p.NameOrNil.String(), depth))
+	}
+	for _, imp := range p.Imports {
+		pkgStrings = append(pkgStrings, imp.Print(depth)...)
+	}
 
 	for _, decl := range p.DeclsOrNil {
 		stmts := decl.Print(depth)
@@ -38,6 +52,23 @@ func (p *PackageAST) String() string {
 	return JoinLines(p.Print(0))
 }
 
+// Import는 import "geo/shapes" 형태의 임포트임
+// 패키지는 경로의 마지막 요소(shapes)로 참조됨
+type Import struct {
+	Path string
+	Name string
+}
+
+func newImport(path string) *Import {
+	return &Import{
+		Path: path,
+		Name: path[strings.LastIndex(path, "/")+1:],
+	}
+}
+func (i *Import) Print(depth int) []string {
+	return []string{LineWithDepth("import \""+i.Path+"\" as "+i.Name, depth)}
+}
+
 // Decl 
 // Stmt
 type VarDecl struct {
@@ -97,10 +128,16 @@ func (v *VarDecl) Stmt() string {
 // Decl
 // Stmt
 type FuncDecl struct {
+	// 메서드 선언일 때의 리시버. func (p Point) Dist() int 의 p Point
+	ReceiverOrNil    *Param
 	Id               Id
 	ParamsOrNil      []Param
 	ReturnTypesOrNil []Type
 	Block            Block
+	// 본문에 yield가 있는 제너레이터인지 여부. 리졸버가 채움
+	IsGenerator bool
+	// func Map[T, U any](...) 의 [T, U any]. 제네릭 함수가 아니면 nil
+	TypeParamsOrNil []TypeParam
 }
 
 func newFuncDecl(id Id, pOrNil []Param, rOrNil []Type, block Block) *FuncDecl {
@@ -112,13 +149,25 @@ func newFuncDecl(id Id, pOrNil []Param, rOrNil []Type, block Block) *FuncDecl {
 	}
 }
 
+func newMethodDecl(receiver Param, id Id, pOrNil []Param, rOrNil []Type, block Block) *FuncDecl {
+	f := newFuncDecl(id, pOrNil, rOrNil, block)
+	f.ReceiverOrNil = &receiver
+	return f
+}
+
 var _ Decl = (*FuncDecl)(nil)
 
 func (f *FuncDecl) Print(depth int) []string {
 	start := "FuncDecl("
 	lines := []string{}
 	lines = append(lines, LineWithDepth(start, depth))
+	if f.ReceiverOrNil != nil {
+		lines = append(lines, LineWithDepth("Receiver:"+f.ReceiverOrNil.String(), depth+1))
+	}
 	lines = append(lines, LineWithDepth("ID:"+f.Id.String(), depth+1))
+	if len(f.TypeParamsOrNil) > 0 {
+		lines = append(lines, LineWithDepth("TypeParams:["+JoinWithSepG(f.TypeParamsOrNil, ",")+"]", depth+1))
+	}
 	paramStart := "Type: ["
 	params := JoinWithSepG(f.ParamsOrNil, ",")
 	paramEnd := "]"
@@ -152,6 +201,23 @@ func (f *FuncDecl) Stmt() string {
 	return f.String()
 }
 
+// TypeParam은 제네릭 함수의 타입 매개변수 하나와 그 제약임. T any, K comparable
+// 제약은 interface 타입이며, any는 빈 interface임
+type TypeParam struct {
+	Id         Id
+	Constraint Type
+}
+
+func newTypeParam(id Id, constraint Type) *TypeParam {
+	return &TypeParam{
+		Id:         id,
+		Constraint: constraint,
+	}
+}
+func (tp TypeParam) String() string {
+	return tp.Id.String() + " " + tp.Constraint.String()
+}
+
 type Param struct {
 	Id   Id
 	Type Type
@@ -189,6 +255,18 @@ func (i Id) String() string {
 type Type struct {
 	TypeKind      TypeKind
 	FuncTypeOrNil *FuncType
+	// NamedType일 때의 타입 이름. 리졸버가 TypeDecl로 연결함
+	NameOrNil *Id
+	// StructType일 때의 필드 목록
+	StructOrNil *StructType
+	// InterfaceType일 때의 메서드 목록
+	InterfaceOrNil *InterfaceType
+	// SliceType일 때의 원소 타입
+	ElemOrNil *Type
+	// TupleType일 때의 원소 타입들
+	TupleOrNil []Type
+	// SumTypeKind일 때의 변형 목록
+	SumOrNil *SumType
 }
 
 func newType(kind TypeKind, funcTypeOrNil *FuncType) *Type {
@@ -197,6 +275,42 @@ func newType(kind TypeKind, funcTypeOrNil *FuncType) *Type {
 		FuncTypeOrNil: funcTypeOrNil,
 	}
 }
+func newNamedType(name Id) *Type {
+	return &Type{
+		TypeKind:  NamedType,
+		NameOrNil: &name,
+	}
+}
+func newStructTypeOf(structType *StructType) *Type {
+	return &Type{
+		TypeKind:    StructureType,
+		StructOrNil: structType,
+	}
+}
+func newInterfaceTypeOf(interfaceType *InterfaceType) *Type {
+	return &Type{
+		TypeKind:       InterfaceTypeKind,
+		InterfaceOrNil: interfaceType,
+	}
+}
+func newTupleTypeOf(elems []Type) *Type {
+	return &Type{
+		TypeKind:   TupleType,
+		TupleOrNil: elems,
+	}
+}
+func newSumTypeOf(sumType *SumType) *Type {
+	return &Type{
+		TypeKind: SumTypeKind,
+		SumOrNil: sumType,
+	}
+}
+func newSliceTypeOf(elem Type) *Type {
+	return &Type{
+		TypeKind:  SliceType,
+		ElemOrNil: &elem,
+	}
+}
 func (t Type) String() string {
 	switch t.TypeKind {
 	case IntType:
@@ -209,6 +323,22 @@ func (t Type) String() string {
 		return "error"
 	case FuncionType:
 		return t.FuncTypeOrNil.String()
+	case NamedType:
+		return t.NameOrNil.String()
+	case StructureType:
+		return t.StructOrNil.String()
+	case InterfaceTypeKind:
+		return t.InterfaceOrNil.String()
+	case SliceType:
+		return "[]" + t.ElemOrNil.String()
+	case TupleType:
+		elems := make([]string, len(t.TupleOrNil))
+		for i, elem := range t.TupleOrNil {
+			elems[i] = elem.String()
+		}
+		return "(" + strings.Join(elems, ", ") + ")"
+	case SumTypeKind:
+		return t.SumOrNil.String()
 	default:
 		panic("Type.String(): 스위치 미스매치")
 	}
@@ -222,6 +352,18 @@ const (
 	StringType
 	ErrorType
 	FuncionType
+	// type 선언으로 정의된 이름 있는 타입
+	NamedType
+	// struct { ... } 타입 리터럴
+	StructureType
+	// interface { ... } 타입 리터럴
+	InterfaceTypeKind
+	// []T 타입
+	SliceType
+	// (T1, T2) 타입
+	TupleType
+	// type 선언의 A(x int) | B 형태 합 타입
+	SumTypeKind
 )
 
 type FuncType struct {
@@ -250,10 +392,203 @@ func (ft FuncType) String() string {
 	return JoinBuilder(strings)
 }
 
+type StructType struct {
+	Fields []Field
+}
+
+func newStructType(fields []Field) *StructType {
+	return &StructType{Fields: fields}
+}
+func (st StructType) String() string {
+	return "struct{" + JoinWithSepG(st.Fields, ";") + "}"
+}
+
+type Field struct {
+	Id   Id
+	Type Type
+}
+
+func newField(id Id, t Type) *Field {
+	return &Field{
+		Id:   id,
+		Type: t,
+	}
+}
+func (f Field) String() string {
+	return f.Id.String() + " " + f.Type.String()
+}
+
+// SumType은 Circle(r int) | Rect(w int, h int) 형태의 합 타입임
+// type 선언의 우변에만 올 수 있음
+type SumType struct {
+	Variants []Variant
+}
+
+func newSumType(variants []Variant) *SumType {
+	return &SumType{Variants: variants}
+}
+func (st SumType) String() string {
+	return JoinWithSepG(st.Variants, " | ")
+}
+
+// Variant는 합 타입의 변형 하나임. 변형 이름은 생성자로 쓰임
+type Variant struct {
+	Id     Id
+	Fields []Field
+}
+
+func newVariant(id Id, fields []Field) *Variant {
+	return &Variant{
+		Id:     id,
+		Fields: fields,
+	}
+}
+func (v Variant) String() string {
+	if len(v.Fields) == 0 {
+		return v.Id.String()
+	}
+	return v.Id.String() + "(" + JoinWithSepG(v.Fields, ",") + ")"
+}
+
+type InterfaceType struct {
+	Methods []MethodSpec
+	// int | string 형태의 타입 원소. 비어있지 않다면 나열된 타입들만 이 interface를 충족함
+	// 타입 원소가 있는 interface는 타입 매개변수의 제약으로만 쓰일 수 있음
+	UnionOrNil []Type
+	// comparable 제약. == 로 비교할 수 있는 타입만 충족함
+	Comparable bool
+}
+
+func newInterfaceType(methods []MethodSpec) *InterfaceType {
+	return &InterfaceType{Methods: methods}
+}
+func (it InterfaceType) String() string {
+	elems := []string{}
+	if it.Comparable {
+		elems = append(elems, "comparable")
+	}
+	if len(it.UnionOrNil) > 0 {
+		elems = append(elems, JoinWithSepG(it.UnionOrNil, " | "))
+	}
+	if len(it.Methods) > 0 {
+		elems = append(elems, JoinWithSepG(it.Methods, ";"))
+	}
+	return "interface{" + strings.Join(elems, ";") + "}"
+}
+
+// IsConstraintOnly는 타입 원소나 comparable이 있어 제약 자리에만 올 수 있는 interface인지 여부임
+func (it InterfaceType) IsConstraintOnly() bool {
+	return it.Comparable || len(it.UnionOrNil) > 0
+}
+
+// MethodSpec은 interface 안의 메서드 시그니처임. Area() int
+type MethodSpec struct {
+	Id       Id
+	FuncType FuncType
+}
+
+func newMethodSpec(id Id, funcType FuncType) *MethodSpec {
+	return &MethodSpec{
+		Id:       id,
+		FuncType: funcType,
+	}
+}
+func (m MethodSpec) String() string {
+	return m.Id.String() + " " + m.FuncType.String()
+}
+
+// Decl
+type TypeDecl struct {
+	Id   Id
+	Type Type
+}
+
+func newTypeDecl(id Id, t Type) *TypeDecl {
+	return &TypeDecl{
+		Id:   id,
+		Type: t,
+	}
+}
+
+var _ Decl = (*TypeDecl)(nil)
+
+func (t *TypeDecl) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("TypeDecl(", depth))
+	lines = append(lines, LineWithDepth("ID:"+t.Id.String(), depth+1))
+	lines = append(lines, LineWithDepth("type "+t.Type.String(), depth+1))
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (t *TypeDecl) String() string {
+	return JoinLines(t.Print(0))
+}
+func (t *TypeDecl) Decl() string {
+	return t.String()
+}
+
+// ConstDecl은 패키지 레벨의 const 선언임
+// const X = 1; 처럼 단독이면 명세가 하나이고, const ( ... ) 묶음이면 명세마다 iota가 0부터 하나씩 증가함
+type ConstDecl struct {
+	Specs []ConstSpec
+}
+
+// ConstSpec은 묶음 안의 한 줄임. ExprsOrNil이 비어 있다면 바로 앞 명세의 타입과 식을 반복함
+type ConstSpec struct {
+	Ids        []Id
+	TypeOrNil  *Type
+	ExprsOrNil []Expr
+}
+
+func newConstDecl(specs []ConstSpec) *ConstDecl {
+	return &ConstDecl{Specs: specs}
+}
+
+func newConstSpec(ids []Id, typeOrNil *Type, exprsOrNil []Expr) *ConstSpec {
+	return &ConstSpec{
+		Ids:        ids,
+		TypeOrNil:  typeOrNil,
+		ExprsOrNil: exprsOrNil,
+	}
+}
+
+var _ Decl = (*ConstDecl)(nil)
+
+func (c *ConstDecl) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("ConstDecl(", depth))
+	for _, spec := range c.Specs {
+		head := "const " + JoinWithSepG(spec.Ids, ",")
+		if spec.TypeOrNil != nil {
+			head += " type " + spec.TypeOrNil.String()
+		}
+		lines = append(lines, LineWithDepth(head, depth+1))
+		if len(spec.ExprsOrNil) == 0 {
+			lines = append(lines, LineWithDepth("repeats previous spec", depth+2))
+			continue
+		}
+		lines = append(lines, LineWithDepth("=", depth+2))
+		for _, exp := range spec.ExprsOrNil {
+			lines = append(lines, exp.Print(depth+2)...)
+		}
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (c *ConstDecl) String() string {
+	return JoinLines(c.Print(0))
+}
+func (c *ConstDecl) Decl() string {
+	return c.String()
+}
+
 // stmt
 type Assign struct {
-	Ids   []Id
-	Exprs []Expr
+	Ids []Id
+	// FieldPathsOrNil[i]는 Ids[i]를 루트로 하는 필드 경로임. (p.x.y = 1 이면 [x, y])
+	// 필드 대입이 하나도 없다면 nil
+	FieldPathsOrNil [][]Id
+	Exprs           []Expr
 }
 
 func newAssign(ids []Id, exprs []Expr) *Assign {
@@ -263,6 +598,27 @@ func newAssign(ids []Id, exprs []Expr) *Assign {
 	}
 }
 
+func newAssignWithFields(ids []Id, fieldPaths [][]Id, exprs []Expr) *Assign {
+	for _, path := range fieldPaths {
+		if len(path) > 0 {
+			return &Assign{
+				Ids:             ids,
+				FieldPathsOrNil: fieldPaths,
+				Exprs:           exprs,
+			}
+		}
+	}
+	return newAssign(ids, exprs)
+}
+
+// FieldPathAt은 i번째 대입 대상의 필드 경로를 리턴함. 필드 대입이 아니면 nil
+func (a *Assign) FieldPathAt(i int) []Id {
+	if a.FieldPathsOrNil == nil || i >= len(a.FieldPathsOrNil) {
+		return nil
+	}
+	return a.FieldPathsOrNil[i]
+}
+
 var _ Stmt = (*Assign)(nil)
 
 func (a *Assign) Print(depth int) []string {
@@ -272,7 +628,15 @@ func (a *Assign) Print(depth int) []string {
 	lines := []string{}
 	lines = append(lines, LineWithDepth(aStart, depth))
 	idStart := "["
-	ids := JoinWithSepG(a.Ids, ",")
+	targets := make([]string, 0, len(a.Ids))
+	for i, id := range a.Ids {
+		target := id.String()
+		for _, field := range a.FieldPathAt(i) {
+			target += "." + field.Name
+		}
+		targets = append(targets, target)
+	}
+	ids := JoinWithSep(targets, ",")
 	idEnd := "]"
 	lines = append(lines, LineWithDepth(idStart+ids+idEnd, depth+1))
 	lines = append(lines, LineWithDepth("=", depth+1))
@@ -327,10 +691,38 @@ func (c *CallStmt) Stmt() string {
 	return c.String()
 }
 
+// Stmt
+// TryStmt는 check(x)?; 처럼 에러 전파를 문장으로 쓴 것임. 전파 후 남는 값은 버림
+type TryStmt struct {
+	Try *Try
+}
+
+func newTryStmt(try *Try) *TryStmt {
+	return &TryStmt{Try: try}
+}
+
+var _ Stmt = (*TryStmt)(nil)
+
+func (t *TryStmt) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("TryStmt(", depth))
+	lines = append(lines, t.Try.Print(depth+1)...)
+	lines = append(lines, LineWithDepth("and semicolon )", depth))
+	return lines
+}
+func (t *TryStmt) String() string {
+	return JoinLines(t.Print(0))
+}
+func (t *TryStmt) Stmt() string {
+	return t.String()
+}
+
 // stmt
 type ShortDecl struct {
 	Ids   []Id
 	Exprs []Expr
+	// let x = 1; 로 선언된 불변 바인딩인지 여부. 불변 바인딩엔 다시 대입할 수 없음
+	Immutable bool
 }
 
 func newShortDecl(ids []Id, exprs []Expr) *ShortDecl {
@@ -344,6 +736,11 @@ var _ Stmt = (*ShortDecl)(nil)
 
 func (s *ShortDecl) Print(depth int) []string {
 	sS := "ShortDecl("
+	declSign := ":="
+	if s.Immutable {
+		sS = "Let("
+		declSign = "="
+	}
 	lines := []string{}
 
 	lines = append(lines, LineWithDepth(sS, depth))
@@ -352,7 +749,6 @@ func (s *ShortDecl) Print(depth int) []string {
 	iE := "]"
 	lines = append(lines, LineWithDepth(iS+i+iE, depth+1))
 
-	declSign := ":="
 	lines = append(lines, LineWithDepth(declSign, depth+1))
 
 	for _, exp := range s.Exprs {
@@ -403,6 +799,40 @@ func (r *Return) Stmt() string {
 	return r.String()
 }
 
+// Stmt
+// Yield는 제너레이터 본문에서 값을 하나(또는 여러 개) 내보내고 멈추는 문장임
+type Yield struct {
+	Exprs []Expr
+}
+
+func newYield(exprs []Expr) *Yield {
+	return &Yield{
+		Exprs: exprs,
+	}
+}
+
+var _ Stmt = (*Yield)(nil)
+
+func (y *Yield) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("Yield(", depth))
+	for i, expr := range y.Exprs {
+		lines = append(lines, expr.Print(depth+1)...)
+		if i < len(y.Exprs)-1 {
+			lines = append(lines, LineWithDepth(",", depth+1))
+		}
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+
+func (y *Yield) String() string {
+	return JoinLines(y.Print(0))
+}
+func (y *Yield) Stmt() string {
+	return y.String()
+}
+
 // Stmt
 type Break struct {
 	isItBreak bool
@@ -424,6 +854,27 @@ func (b *Break) Stmt() string {
 	return b.String()
 }
 
+// Stmt
+// Fallthrough는 switch 절의 마지막 문장으로만 쓰일 수 있음
+// 파서가 절의 마지막에 온 fallthrough를 CaseClause.Fallthrough로 옮기므로
+// AST에 남아 있는 Fallthrough는 잘못된 위치에 쓰인 것임
+type Fallthrough struct{}
+
+var _ Stmt = (*Fallthrough)(nil)
+
+func newFallthrough() *Fallthrough {
+	return &Fallthrough{}
+}
+func (f *Fallthrough) Print(depth int) []string {
+	return []string{LineWithDepth("Fallthrough", depth)}
+}
+func (f *Fallthrough) String() string {
+	return JoinLines(f.Print(0))
+}
+func (f *Fallthrough) Stmt() string {
+	return f.String()
+}
+
 // Stmt
 type Continue struct {
 	isItContinue bool
@@ -528,12 +979,54 @@ func (f *ForBexp) Stmt() string {
 	return f.String()
 }
 
+// stmt
+// ForRange는 for k, v := range x { } 형태의 반복임
+// 변수는 0~2개이며, 변수가 없으면 for range x { } 형태임
+type ForRange struct {
+	Vars  []Id
+	Expr  Expr
+	Block Block
+	// 루프 변수를 본문 안의 클로저가 참조하는지 여부. 리졸버가 채움
+	// 참이라면 evaluator는 반복마다 새 변수를 만듦
+	LoopVarsCaptured bool
+}
+
+func newForRange(vars []Id, expr Expr, block Block) *ForRange {
+	return &ForRange{
+		Vars:  vars,
+		Expr:  expr,
+		Block: block,
+	}
+}
+
+var _ Stmt = (*ForRange)(nil)
+
+func (f *ForRange) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("For(", depth))
+	lines = append(lines, LineWithDepth("Vars:["+JoinWithSepG(f.Vars, ",")+"]", depth+1))
+	lines = append(lines, LineWithDepth("range", depth+1))
+	lines = append(lines, f.Expr.Print(depth+1)...)
+	lines = append(lines, f.Block.Print(depth+1)...)
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (f *ForRange) String() string {
+	return JoinLines(f.Print(0))
+}
+func (f *ForRange) Stmt() string {
+	return f.String()
+}
+
 // stmt
 type ForWithAssign struct {
 	ShortDecl ShortDecl
 	Bexp      Expr
 	Assign    Assign
 	Block     Block
+	// 루프 변수를 클로저가 참조하는지 여부. 리졸버가 채움
+	// 참이라면 evaluator는 go 1.22와 같이 반복마다 새 변수를 만듦
+	LoopVarsCaptured bool
 }
 
 func newForWithAssign(shortDecl ShortDecl, bexp Expr, assign Assign, block Block) *ForWithAssign {
@@ -690,6 +1183,8 @@ func (b *Binary) Print(depth int) []string {
 		op = "&&"
 	case Or:
 		op = "||"
+	case Pipe:
+		op = "|>"
 	}
 	lines := []string{}
 	lines = append(lines, LineWithDepth("Binary(", depth))
@@ -779,6 +1274,12 @@ type ValueForm struct {
 	StrLitOrNil  *string
 	ErrOrNilIfOk *string
 	FexpOrNil    *Fexp
+	// StructLitValue일 때의 합성 리터럴
+	StructLitOrNil *StructLit
+	// SliceLitValue일 때의 슬라이스 리터럴
+	SliceLitOrNil *SliceLit
+	// TupleLitValue일 때의 튜플 리터럴
+	TupleLitOrNil *TupleLit
 }
 
 func newValueForm(valueKind ValueType, numberOrNil *int, boolOrNil *bool, strLitOrNil *string, errOrOkOrNil *string, fexoOrNil *Fexp) *ValueForm {
@@ -791,6 +1292,24 @@ func newValueForm(valueKind ValueType, numberOrNil *int, boolOrNil *bool, strLit
 		FexpOrNil:    fexoOrNil,
 	}
 }
+func newStructLitValueForm(lit *StructLit) *ValueForm {
+	return &ValueForm{
+		ValueKind:      StructLitValue,
+		StructLitOrNil: lit,
+	}
+}
+func newSliceLitValueForm(lit *SliceLit) *ValueForm {
+	return &ValueForm{
+		ValueKind:     SliceLitValue,
+		SliceLitOrNil: lit,
+	}
+}
+func newTupleLitValueForm(lit *TupleLit) *ValueForm {
+	return &ValueForm{
+		ValueKind:     TupleLitValue,
+		TupleLitOrNil: lit,
+	}
+}
 func (v *ValueForm) Print(depth int) []string {
 	ss := func(s string) []string { return []string{LineWithDepth("valueForm<"+s+">", depth)} }
 	switch v.ValueKind {
@@ -819,6 +1338,24 @@ func (v *ValueForm) Print(depth int) []string {
 		lines = append(lines, v.FexpOrNil.Print(depth+1)...)
 		lines = append(lines, LineWithDepth(">", depth))
 		return lines
+	case StructLitValue:
+		lines := []string{}
+		lines = append(lines, LineWithDepth("valueForm<", depth))
+		lines = append(lines, v.StructLitOrNil.Print(depth+1)...)
+		lines = append(lines, LineWithDepth(">", depth))
+		return lines
+	case SliceLitValue:
+		lines := []string{}
+		lines = append(lines, LineWithDepth("valueForm<", depth))
+		lines = append(lines, v.SliceLitOrNil.Print(depth+1)...)
+		lines = append(lines, LineWithDepth(">", depth))
+		return lines
+	case TupleLitValue:
+		lines := []string{}
+		lines = append(lines, LineWithDepth("valueForm<", depth))
+		lines = append(lines, v.TupleLitOrNil.Print(depth+1)...)
+		lines = append(lines, LineWithDepth(">", depth))
+		return lines
 	default:
 		panic("ValueForm.String() switch missmatch")
 	}
@@ -836,6 +1373,9 @@ const (
 	StrLitValue
 	ErrValue
 	FexpValue
+	StructLitValue
+	SliceLitValue
+	TupleLitValue
 )
 
 type BinaryKind int
@@ -856,12 +1396,17 @@ const (
 
 	And = BinaryKind(token.AND)
 	Or  = BinaryKind(token.OR)
+
+	// x |> f(a) 는 f(x, a) 로 평가됨
+	Pipe = BinaryKind(token.PIPE)
 )
 
 // Expr
 type Call struct {
 	PrimaryOrNil Primary
 	ArgsList     []Args
+	// 제네릭 함수 호출일 때 리졸버가 인자로부터 추론한 타입 인자들. 추론하지 못했다면 nil
+	TypeArgsOrNil []Type
 }
 
 var _ Atom = (*Call)(nil)
@@ -958,6 +1503,8 @@ type Fexp struct {
 	ParamsOrNil      []Param
 	ReturnTypesOrNil []Type
 	Block            Block
+	// 본문에 yield가 있는 제너레이터인지 여부. 리졸버가 채움
+	IsGenerator bool
 }
 
 func newFexp(paramOrNil []Param, returnOrNil []Type, body Block) *Fexp {
@@ -986,3 +1533,461 @@ func (f *Fexp) Print(depth int) []string {
 	lines = append(lines, LineWithDepth(")", depth))
 	return lines
 }
+func (f *Fexp) String() string {
+	return JoinLines(f.Print(0))
+}
+
+// Expr
+// Selector는 x.f 형태의 필드 접근 표현임
+type Selector struct {
+	Object Expr
+	Field  Id
+}
+
+func newSelector(object Expr, field Id) *Selector {
+	return &Selector{
+		Object: object,
+		Field:  field,
+	}
+}
+
+var _ Atom = (*Selector)(nil)
+
+func (s *Selector) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("Selector(", depth))
+	lines = append(lines, s.Object.Print(depth+1)...)
+	lines = append(lines, LineWithDepth("."+s.Field.Name, depth+1))
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (s *Selector) String() string {
+	return JoinLines(s.Print(0))
+}
+func (s *Selector) Expr() string {
+	return s.String()
+}
+func (s *Selector) Atom() string {
+	return s.String()
+}
+
+// StructLit은 Point{x: 1, y: 2} 형태의 합성 리터럴임
+type StructLit struct {
+	TypeName Id
+	Fields   []FieldInit
+}
+
+type FieldInit struct {
+	Field Id
+	Expr  Expr
+}
+
+func newStructLit(typeName Id, fields []FieldInit) *StructLit {
+	return &StructLit{
+		TypeName: typeName,
+		Fields:   fields,
+	}
+}
+func (s *StructLit) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("StructLit("+s.TypeName.String(), depth))
+	for _, field := range s.Fields {
+		lines = append(lines, LineWithDepth(field.Field.Name+":", depth+1))
+		lines = append(lines, field.Expr.Print(depth+2)...)
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (s *StructLit) String() string {
+	return JoinLines(s.Print(0))
+}
+
+// Expr
+// TypeAssert는 v.(T) 형태의 타입 단언임
+// TypeOrNil이 nil이면 type switch 헤더에서만 쓰이는 v.(type)임
+type TypeAssert struct {
+	Object    Expr
+	TypeOrNil *Type
+}
+
+func newTypeAssert(object Expr, typeOrNil *Type) *TypeAssert {
+	return &TypeAssert{
+		Object:    object,
+		TypeOrNil: typeOrNil,
+	}
+}
+
+var _ Atom = (*TypeAssert)(nil)
+
+func (t *TypeAssert) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("TypeAssert(", depth))
+	lines = append(lines, t.Object.Print(depth+1)...)
+	if t.TypeOrNil == nil {
+		lines = append(lines, LineWithDepth(".(type)", depth+1))
+	} else {
+		lines = append(lines, LineWithDepth(".("+t.TypeOrNil.String()+")", depth+1))
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (t *TypeAssert) String() string {
+	return JoinLines(t.Print(0))
+}
+func (t *TypeAssert) Expr() string {
+	return t.String()
+}
+func (t *TypeAssert) Atom() string {
+	return t.String()
+}
+
+// stmt
+// TypeSwitch는 switch [id :=] x.(type) { case T1, T2: ... default: ... } 임
+type TypeSwitch struct {
+	BindingOrNil *Id
+	Subject      Expr
+	Clauses      []TypeCaseClause
+}
+
+// TypeCaseClause의 Types가 비어 있으면 default 절임
+// 각 절의 Block은 절마다의 스코프를 가짐
+type TypeCaseClause struct {
+	Types     []Type
+	IsDefault bool
+	Block     Block
+}
+
+func newTypeSwitch(bindingOrNil *Id, subject Expr, clauses []TypeCaseClause) *TypeSwitch {
+	return &TypeSwitch{
+		BindingOrNil: bindingOrNil,
+		Subject:      subject,
+		Clauses:      clauses,
+	}
+}
+
+var _ Stmt = (*TypeSwitch)(nil)
+
+func (t *TypeSwitch) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("TypeSwitch(", depth))
+	if t.BindingOrNil != nil {
+		lines = append(lines, LineWithDepth(t.BindingOrNil.String()+" :=", depth+1))
+	}
+	lines = append(lines, t.Subject.Print(depth+1)...)
+	for _, clause := range t.Clauses {
+		if clause.IsDefault {
+			lines = append(lines, LineWithDepth("default:", depth+1))
+		} else {
+			lines = append(lines, LineWithDepth("case "+JoinWithSepG(clause.Types, ",")+":", depth+1))
+		}
+		lines = append(lines, clause.Block.Print(depth+2)...)
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (t *TypeSwitch) String() string {
+	return JoinLines(t.Print(0))
+}
+func (t *TypeSwitch) Stmt() string {
+	return t.String()
+}
+
+// stmt
+// Switch는 switch [ShortDecl] [Expr] { case e1, e2: ... default: ... } 임
+// TagOrNil이 nil이면 tagless switch로, 각 case 식이 bool이어야 함
+type Switch struct {
+	ShortDeclOrNil *ShortDecl
+	TagOrNil       Expr
+	Clauses        []CaseClause
+}
+
+// CaseClause의 Exprs가 비어 있으면 default 절임
+// 각 절의 Block은 절마다의 스코프를 가짐
+type CaseClause struct {
+	Exprs       []Expr
+	IsDefault   bool
+	Block       Block
+	Fallthrough bool
+}
+
+func newSwitch(shortDeclOrNil *ShortDecl, tagOrNil Expr, clauses []CaseClause) *Switch {
+	return &Switch{
+		ShortDeclOrNil: shortDeclOrNil,
+		TagOrNil:       tagOrNil,
+		Clauses:        clauses,
+	}
+}
+
+var _ Stmt = (*Switch)(nil)
+
+func (s *Switch) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("Switch(", depth))
+	if s.ShortDeclOrNil != nil {
+		lines = append(lines, s.ShortDeclOrNil.Print(depth+1)...)
+	}
+	if s.TagOrNil != nil {
+		lines = append(lines, s.TagOrNil.Print(depth+1)...)
+	}
+	for _, clause := range s.Clauses {
+		if clause.IsDefault {
+			lines = append(lines, LineWithDepth("default:", depth+1))
+		} else {
+			lines = append(lines, LineWithDepth("case:", depth+1))
+			for _, expr := range clause.Exprs {
+				lines = append(lines, expr.Print(depth+2)...)
+			}
+		}
+		lines = append(lines, clause.Block.Print(depth+2)...)
+		if clause.Fallthrough {
+			lines = append(lines, LineWithDepth("fallthrough", depth+2))
+		}
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (s *Switch) String() string {
+	return JoinLines(s.Print(0))
+}
+func (s *Switch) Stmt() string {
+	return s.String()
+}
+
+// SliceLit은 []string{"a", "b"} 형태의 슬라이스 리터럴임
+type SliceLit struct {
+	ElemType Type
+	Elems    []Expr
+}
+
+func newSliceLit(elemType Type, elems []Expr) *SliceLit {
+	return &SliceLit{
+		ElemType: elemType,
+		Elems:    elems,
+	}
+}
+func (s *SliceLit) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("SliceLit([]"+s.ElemType.String(), depth))
+	for _, elem := range s.Elems {
+		lines = append(lines, elem.Print(depth+1)...)
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (s *SliceLit) String() string {
+	return JoinLines(s.Print(0))
+}
+
+// TupleLit은 (a, b) 형태의 튜플 리터럴임
+type TupleLit struct {
+	Elems []Expr
+}
+
+func newTupleLit(elems []Expr) *TupleLit {
+	return &TupleLit{Elems: elems}
+}
+func (t *TupleLit) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("TupleLit(", depth))
+	for _, elem := range t.Elems {
+		lines = append(lines, elem.Print(depth+1)...)
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (t *TupleLit) String() string {
+	return JoinLines(t.Print(0))
+}
+
+// Expr
+// Try는 f()? 형태의 에러 전파임
+// Object의 마지막 값이 ok가 아닌 error라면, 감싼 함수가 그 error로 즉시 리턴함
+type Try struct {
+	Object Expr
+}
+
+func newTry(object Expr) *Try {
+	return &Try{Object: object}
+}
+
+var _ Atom = (*Try)(nil)
+
+func (t *Try) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("Try(", depth))
+	lines = append(lines, t.Object.Print(depth+1)...)
+	lines = append(lines, LineWithDepth("?)", depth))
+	return lines
+}
+func (t *Try) String() string {
+	return JoinLines(t.Print(0))
+}
+func (t *Try) Expr() string {
+	return t.String()
+}
+func (t *Try) Atom() string {
+	return t.String()
+}
+
+// Expr
+// Match는 match x { Pat [if guard] => expr, ... } 형태의 패턴 매칭 식임
+// 위에서부터 처음으로 맞는 갈래의 본문이 식의 값이 됨
+type Match struct {
+	Subject Expr
+	Arms    []MatchArm
+}
+
+// MatchArm은 갈래마다의 스코프를 가지며, 패턴이 묶은 이름은 가드와 본문에서만 보임
+type MatchArm struct {
+	Pattern    Pattern
+	GuardOrNil Expr
+	Body       Expr
+}
+
+func newMatch(subject Expr, arms []MatchArm) *Match {
+	return &Match{
+		Subject: subject,
+		Arms:    arms,
+	}
+}
+
+var _ Expr = (*Match)(nil)
+
+func (m *Match) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("Match(", depth))
+	lines = append(lines, m.Subject.Print(depth+1)...)
+	for _, arm := range m.Arms {
+		lines = append(lines, LineWithDepth("arm "+arm.Pattern.String()+":", depth+1))
+		if arm.GuardOrNil != nil {
+			lines = append(lines, LineWithDepth("if", depth+2))
+			lines = append(lines, arm.GuardOrNil.Print(depth+3)...)
+		}
+		lines = append(lines, LineWithDepth("=>", depth+2))
+		lines = append(lines, arm.Body.Print(depth+3)...)
+	}
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (m *Match) String() string {
+	return JoinLines(m.Print(0))
+}
+func (m *Match) Expr() string {
+	return m.String()
+}
+
+type PatternKind int
+
+const (
+	// _
+	WildcardPattern PatternKind = iota
+	// 변형이 아닌 맨 식별자. 값을 그 이름에 묶음
+	BindPattern
+	// 숫자, 문자열, 불리언 리터럴
+	LiteralPattern
+	// Circle(r) 처럼 변형을 분해하는 패턴
+	// 인자 없는 맨 식별자가 변형이면 리졸버가 이 종류로 바꿈
+	VariantPattern
+)
+
+type Pattern struct {
+	PatternKind  PatternKind
+	IdOrNil      *Id
+	LiteralOrNil Expr
+	// VariantPattern일 때의 필드별 하위 패턴
+	Args []Pattern
+}
+
+func newWildcardPattern() *Pattern {
+	return &Pattern{PatternKind: WildcardPattern}
+}
+func newBindPattern(id Id) *Pattern {
+	return &Pattern{PatternKind: BindPattern, IdOrNil: &id}
+}
+func newLiteralPattern(literal Expr) *Pattern {
+	return &Pattern{PatternKind: LiteralPattern, LiteralOrNil: literal}
+}
+func newVariantPattern(id Id, args []Pattern) *Pattern {
+	return &Pattern{PatternKind: VariantPattern, IdOrNil: &id, Args: args}
+}
+func (p Pattern) String() string {
+	switch p.PatternKind {
+	case WildcardPattern:
+		return "_"
+	case BindPattern:
+		return p.IdOrNil.String()
+	case LiteralPattern:
+		return literalPatternText(p.LiteralOrNil)
+	case VariantPattern:
+		return p.IdOrNil.String() + "(" + JoinWithSepG(p.Args, ",") + ")"
+	default:
+		panic("Pattern.String(): 스위치 미스매치")
+	}
+}
+
+// literalPatternText는 리터럴 패턴을 소스에 쓰인 모양대로 출력함
+func literalPatternText(expr Expr) string {
+	if unary, ok := expr.(*Unary); ok {
+		return "-" + literalPatternText(unary.Object)
+	}
+	value := expr.(*Primary).ValueOrNil
+	switch {
+	case value.NumberOrNil != nil:
+		return strconv.Itoa(*value.NumberOrNil)
+	case value.BoolOrNil != nil:
+		return strconv.FormatBool(*value.BoolOrNil)
+	case value.StrLitOrNil != nil:
+		return strconv.Quote(*value.StrLitOrNil)
+	default:
+		panic("literalPatternText: 리터럴이 아닌 패턴")
+	}
+}
+
+// Index는 xs[i] 형태의 인덱스 접근임
+// Index는 x[i] 이거나, 제네릭 함수의 명시적 인스턴스화 f[int, string] 임
+// 인스턴스화라면 Index는 nil이고 TypeArgsOrNil에 타입 인자들이 있음
+type Index struct {
+	Object        Expr
+	Index         Expr
+	TypeArgsOrNil []Type
+}
+
+func newIndex(object Expr, index Expr) *Index {
+	return &Index{
+		Object: object,
+		Index:  index,
+	}
+}
+
+func newInstantiation(object Expr, typeArgs []Type) *Index {
+	return &Index{
+		Object:        object,
+		TypeArgsOrNil: typeArgs,
+	}
+}
+
+var _ Atom = (*Index)(nil)
+
+func (i *Index) Print(depth int) []string {
+	lines := []string{}
+	lines = append(lines, LineWithDepth("Index(", depth))
+	lines = append(lines, i.Object.Print(depth+1)...)
+	lines = append(lines, LineWithDepth("[", depth+1))
+	if i.TypeArgsOrNil != nil {
+		lines = append(lines, LineWithDepth("TypeArgs:"+JoinWithSepG(i.TypeArgsOrNil, ","), depth+1))
+	} else {
+		lines = append(lines, i.Index.Print(depth+1)...)
+	}
+	lines = append(lines, LineWithDepth("]", depth+1))
+	lines = append(lines, LineWithDepth(")", depth))
+	return lines
+}
+func (i *Index) String() string {
+	return JoinLines(i.Print(0))
+}
+func (i *Index) Expr() string {
+	return i.String()
+}
+func (i *Index) Atom() string {
+	return i.String()
+}
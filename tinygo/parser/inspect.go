@@ -0,0 +1,158 @@
+package parser
+
+// Inspect는 node와 그 아래의 선언, 문장, 식, 함수 리터럴을 깊이 우선으로 방문함
+// visit이 true를 리턴하면 자식들을 방문한 뒤 visit(nil)을 불러 그 노드를 벗어났음을 알림
+// visit이 false를 리턴하면 그 노드의 자식은 방문하지 않고, visit(nil)도 부르지 않음
+// 함수 선언, 함수 리터럴, if, for, switch 절의 본문도 *Block 노드로 방문하므로 스코프를 따라갈 수 있음
+// 매치 갈래의 패턴이 묶는 이름이나 range 변수처럼 식이 아닌 것은 방문하지 않음
+func Inspect(node Node, visit func(Node) bool) {
+	if node == nil || !visit(node) {
+		return
+	}
+	exprs := func(exprs []Expr) {
+		for _, expr := range exprs {
+			Inspect(expr, visit)
+		}
+	}
+	switch n := node.(type) {
+	case *PackageAST:
+		for _, decl := range n.DeclsOrNil {
+			Inspect(decl, visit)
+		}
+	case *Block:
+		for _, stmt := range n.StmtsOrNil {
+			Inspect(stmt, visit)
+		}
+	case *FuncDecl:
+		Inspect(&n.Block, visit)
+	case *Fexp:
+		Inspect(&n.Block, visit)
+	case *ConstDecl:
+		for _, spec := range n.Specs {
+			exprs(spec.ExprsOrNil)
+		}
+	case *Assign:
+		exprs(n.Exprs)
+	case *ShortDecl:
+		exprs(n.Exprs)
+	case *VarDecl:
+		exprs(n.ExprsOrNil)
+	case *CallStmt:
+		Inspect(&n.Call, visit)
+	case *TryStmt:
+		Inspect(n.Try, visit)
+	case *Return:
+		exprs(n.ExprsOrNil)
+	case *Yield:
+		exprs(n.Exprs)
+	case *If:
+		if n.ShortDeclOrNil != nil {
+			Inspect(n.ShortDeclOrNil, visit)
+		}
+		Inspect(n.Bexp, visit)
+		Inspect(&n.ThenBlock, visit)
+		if n.ElseOrNil != nil {
+			Inspect(n.ElseOrNil, visit)
+		}
+	case *ForBexp:
+		Inspect(n.Bexp, visit)
+		Inspect(&n.Block, visit)
+	case *ForWithAssign:
+		Inspect(&n.ShortDecl, visit)
+		Inspect(n.Bexp, visit)
+		Inspect(&n.Assign, visit)
+		Inspect(&n.Block, visit)
+	case *ForRange:
+		Inspect(n.Expr, visit)
+		Inspect(&n.Block, visit)
+	case *Switch:
+		if n.ShortDeclOrNil != nil {
+			Inspect(n.ShortDeclOrNil, visit)
+		}
+		if n.TagOrNil != nil {
+			Inspect(n.TagOrNil, visit)
+		}
+		for i := range n.Clauses {
+			exprs(n.Clauses[i].Exprs)
+			Inspect(&n.Clauses[i].Block, visit)
+		}
+	case *TypeSwitch:
+		Inspect(n.Subject, visit)
+		for i := range n.Clauses {
+			Inspect(&n.Clauses[i].Block, visit)
+		}
+	case *Primary:
+		switch n.PrimaryKind {
+		case ExprPrimary:
+			Inspect(n.ExprOrNil, visit)
+		case ValuePrimary:
+			inspectValueForm(n.ValueOrNil, visit)
+		}
+	case *Unary:
+		Inspect(n.Object, visit)
+	case *Binary:
+		Inspect(n.LeftExpr, visit)
+		Inspect(n.RightExpr, visit)
+	case *Call:
+		Inspect(&n.PrimaryOrNil, visit)
+		for _, args := range n.ArgsList {
+			exprs(args)
+		}
+	case *Selector:
+		Inspect(n.Object, visit)
+	case *TypeAssert:
+		Inspect(n.Object, visit)
+	case *Try:
+		Inspect(n.Object, visit)
+	case *Index:
+		Inspect(n.Object, visit)
+		if n.Index != nil {
+			Inspect(n.Index, visit)
+		}
+	case *Match:
+		Inspect(n.Subject, visit)
+		for i := range n.Arms {
+			arm := &n.Arms[i]
+			inspectPattern(&arm.Pattern, visit)
+			if arm.GuardOrNil != nil {
+				Inspect(arm.GuardOrNil, visit)
+			}
+			Inspect(arm.Body, visit)
+		}
+	}
+	visit(nil)
+}
+
+func inspectValueForm(value *ValueForm, visit func(Node) bool) {
+	if value == nil {
+		return
+	}
+	switch value.ValueKind {
+	case FexpValue:
+		Inspect(value.FexpOrNil, visit)
+	case StructLitValue:
+		for _, field := range value.StructLitOrNil.Fields {
+			Inspect(field.Expr, visit)
+		}
+	case SliceLitValue:
+		for _, elem := range value.SliceLitOrNil.Elems {
+			Inspect(elem, visit)
+		}
+	case TupleLitValue:
+		for _, elem := range value.TupleLitOrNil.Elems {
+			Inspect(elem, visit)
+		}
+	}
+}
+
+// inspectPattern은 리터럴 패턴의 식을 방문함
+func inspectPattern(pat *Pattern, visit func(Node) bool) {
+	switch pat.PatternKind {
+	case LiteralPattern:
+		Inspect(pat.LiteralOrNil, visit)
+	case VariantPattern:
+		for i := range pat.Args {
+			inspectPattern(&pat.Args[i], visit)
+		}
+	}
+}
@@ -11,7 +11,14 @@ import (
 
 func (p *Parser) ParsePackage() (*PackageAST, error) {
 	if !p.CheckProcessable() {
-		return newPackage(nil), nil
+		pkg := newPackage(nil)
+		p.recordPositions(pkg)
+		return pkg, nil
+	}
+	// package 절과 import는 선택적임. (단일 파일 실행 시엔 생략 가능)
+	nameOrNil, imports, err := p.parsePackageHeader()
+	if err != nil {
+		return nil, NewParseError("Package", err)
 	}
 	decls := []Decl{}
 	for {
@@ -27,7 +34,76 @@ func (p *Parser) ParsePackage() (*PackageAST, error) {
 		decls = append(decls, decl)
 	}
 
-	return newPackage(decls), nil
+	pkg := newPackage(decls)
+	pkg.NameOrNil = nameOrNil
+	pkg.Imports = imports
+	p.recordPositions(pkg)
+	return pkg, nil
+}
+
+// MergeFiles는 같은 디렉토리의 파일들을 하나의 패키지로 합침
+// 선언은 파일 순서를 유지하며, 임포트는 중복을 제거함
+// 파일들은 IdId가 겹치지 않도록 NewParserFrom으로 이어서 파싱되어야 함
+func MergeFiles(files []*PackageAST) (*PackageAST, error) {
+	merged := newPackage([]Decl{})
+	merged.Imports = []Import{}
+	merged.Positions = map[IdId]Pos{}
+	merged.Comments = []Comment{}
+	seenImports := map[string]bool{}
+	for _, file := range files {
+		if file.NameOrNil == nil {
+			return nil, fmt.Errorf("missing package clause")
+		}
+		if merged.NameOrNil == nil {
+			merged.NameOrNil = file.NameOrNil
+		} else if merged.NameOrNil.Name != file.NameOrNil.Name {
+			return nil, fmt.Errorf("found packages %s and %s in the same directory", merged.NameOrNil.Name, file.NameOrNil.Name)
+		}
+		for _, imp := range file.Imports {
+			if seenImports[imp.Path] {
+				continue
+			}
+			seenImports[imp.Path] = true
+			merged.Imports = append(merged.Imports, imp)
+		}
+		merged.DeclsOrNil = append(merged.DeclsOrNil, file.DeclsOrNil...)
+		for idId, pos := range file.Positions {
+			merged.Positions[idId] = pos
+		}
+		merged.Comments = append(merged.Comments, file.Comments...)
+	}
+	return merged, nil
+}
+
+// parsePackageHeader는 ["package" id End] {"import" strlit End} 를 파싱함
+func (p *Parser) parsePackageHeader() (*Id, []Import, error) {
+	var nameOrNil *Id
+	if p.match(token.PACKAGE) == nil {
+		id, err := p.parseId()
+		if err != nil {
+			return nil, nil, NewParseError("PackageClause", err)
+		}
+		if p.match(token.SEMICOLON) != nil {
+			return nil, nil, NewParseError("PackageClause", ErrMissingSemicolon)
+		}
+		nameOrNil = id
+	}
+	imports := []Import{}
+	for p.match(token.IMPORT) == nil {
+		if p.CurrentToken().Kind != token.STRLIT {
+			return nil, nil, NewParseError("Import", errors.New("import 뒤에는 문자열 경로가 와야 함"))
+		}
+		path := p.CurrentToken().Value
+		p.match(token.STRLIT)
+		if path == "" || strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+			return nil, nil, NewParseError("Import", fmt.Errorf("잘못된 import 경로: %q", path))
+		}
+		if p.match(token.SEMICOLON) != nil {
+			return nil, nil, NewParseError("Import", ErrMissingSemicolon)
+		}
+		imports = append(imports, *newImport(path))
+	}
+	return nameOrNil, imports, nil
 }
 
 func (p *Parser) parseDecl() (Decl, error) {
@@ -42,6 +118,20 @@ func (p *Parser) parseDecl() (Decl, error) {
 		}
 		return varDecl, nil
 	}
+	if p.CurrentToken().Kind == token.CONST {
+		constDecl, err := p.parseConstDecl()
+		if err != nil {
+			return nil, NewParseError("Decl", err)
+		}
+		return constDecl, nil
+	}
+	if p.CurrentToken().Kind == token.TYPE {
+		typeDecl, err := p.parseTypeDecl()
+		if err != nil {
+			return nil, NewParseError("Decl", err)
+		}
+		return typeDecl, nil
+	}
 
 	funcDecl, err := p.parseFuncDecl()
 	if err != nil {
@@ -84,6 +174,155 @@ func (p *Parser) parseVarDecl() (*VarDecl, error) {
 	return newVarDecl(ids, *typ, exprs), nil
 
 }
+
+// parseConstDecl은 const ConstSpec 또는 const "(" {ConstSpec} ")" 를 파싱함
+func (p *Parser) parseConstDecl() (*ConstDecl, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("ConstDecl", ErrNotProcesable)
+	}
+	if p.match(token.CONST) != nil {
+		return nil, NewParseError("ConstDecl", errors.New("ConstDecl은 const 키워드로 시작해야 함"))
+	}
+	if p.match(token.LPAREN) != nil {
+		spec, err := p.parseConstSpec()
+		if err != nil {
+			return nil, NewParseError("ConstDecl", err)
+		}
+		if len(spec.ExprsOrNil) == 0 {
+			return nil, NewParseError("ConstDecl", errors.New("const 선언은 값을 포함해야 함"))
+		}
+		return newConstDecl([]ConstSpec{*spec}), nil
+	}
+	specs := []ConstSpec{}
+	for p.match(token.RPAREN) != nil {
+		spec, err := p.parseConstSpec()
+		if err != nil {
+			return nil, NewParseError("ConstDecl", err)
+		}
+		// 묶음의 첫 명세는 반복할 앞 명세가 없음
+		if len(specs) == 0 && len(spec.ExprsOrNil) == 0 {
+			return nil, NewParseError("ConstDecl", errors.New("묶음의 첫 const 명세는 값을 포함해야 함"))
+		}
+		specs = append(specs, *spec)
+	}
+	if len(specs) == 0 {
+		return nil, NewParseError("ConstDecl", errors.New("빈 const 묶음"))
+	}
+	// ")" 뒤의 세미콜론은 생략 가능
+	p.match(token.SEMICOLON)
+	return newConstDecl(specs), nil
+}
+
+// parseConstSpec은 IdList [Type] ["=" ExprList] ";" 를 파싱함
+func (p *Parser) parseConstSpec() (*ConstSpec, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("ConstSpec", ErrNotProcesable)
+	}
+	ids, err := p.parseIdListLongerThan0()
+	if err != nil {
+		return nil, NewParseError("ConstSpec", err)
+	}
+	var typOrNil *Type
+	if kind := p.CurrentToken().Kind; kind != token.ASSIGN && kind != token.SEMICOLON {
+		typOrNil, err = p.parseType()
+		if err != nil {
+			return nil, NewParseError("ConstSpec", err)
+		}
+	}
+	if p.match(token.ASSIGN) != nil {
+		if typOrNil != nil {
+			return nil, NewParseError("ConstSpec", errors.New("타입을 적은 const 명세는 값을 포함해야 함"))
+		}
+		if p.match(token.SEMICOLON) != nil {
+			return nil, NewParseError("ConstSpec", ErrMissingSemicolon)
+		}
+		return newConstSpec(ids, nil, nil), nil
+	}
+	exprs, err := p.parseExprListLongerThan0()
+	if err != nil {
+		return nil, NewParseError("ConstSpec", err)
+	}
+	if p.match(token.SEMICOLON) != nil {
+		return nil, NewParseError("ConstSpec", ErrMissingSemicolon)
+	}
+	return newConstSpec(ids, typOrNil, exprs), nil
+}
+
+func (p *Parser) parseTypeDecl() (*TypeDecl, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("TypeDecl", ErrNotProcesable)
+	}
+	if p.match(token.TYPE) != nil {
+		return nil, NewParseError("TypeDecl", errors.New("TypeDecl은 type 키워드로 시작해야 함"))
+	}
+	id, err := p.parseId()
+	if err != nil {
+		return nil, NewParseError("TypeDecl", err)
+	}
+	// type Name = A(...) | B 는 합 타입 선언임
+	if p.match(token.ASSIGN) == nil {
+		sumType, err := p.parseSumType()
+		if err != nil {
+			return nil, NewParseError("TypeDecl", err)
+		}
+		if p.match(token.SEMICOLON) != nil {
+			return nil, NewParseError("TypeDecl", ErrMissingSemicolon)
+		}
+		return newTypeDecl(*id, *newSumTypeOf(sumType)), nil
+	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, NewParseError("TypeDecl", err)
+	}
+	// struct, interface 타입은 "}"로 끝나므로 세미콜론 생략 허용
+	endsWithBrace := typ.TypeKind == StructureType || typ.TypeKind == InterfaceTypeKind
+	if p.match(token.SEMICOLON) != nil && !endsWithBrace {
+		return nil, NewParseError("TypeDecl", ErrMissingSemicolon)
+	}
+	return newTypeDecl(*id, *typ), nil
+}
+
+// parseSumType은 Variant {"|" Variant} 를 파싱함
+func (p *Parser) parseSumType() (*SumType, error) {
+	variants := []Variant{}
+	for {
+		variant, err := p.parseVariant()
+		if err != nil {
+			return nil, NewParseError("SumType", err)
+		}
+		for _, prev := range variants {
+			if prev.Id.Name == variant.Id.Name {
+				return nil, NewParseError("SumType", fmt.Errorf("변형 이름 %s 중복", variant.Id.Name))
+			}
+		}
+		variants = append(variants, *variant)
+		if p.match(token.BAR) != nil {
+			break
+		}
+	}
+	return newSumType(variants), nil
+}
+
+// parseVariant는 id [Params] 를 파싱함. 필드가 없으면 괄호를 생략할 수 있음
+func (p *Parser) parseVariant() (*Variant, error) {
+	id, err := p.parseId()
+	if err != nil {
+		return nil, NewParseError("Variant", err)
+	}
+	fields := []Field{}
+	kind := p.CurrentToken().Kind
+	if kind == token.LPAREN || kind == token.OMIT {
+		params, err := p.parseParams()
+		if err != nil {
+			return nil, NewParseError("Variant", err)
+		}
+		for _, param := range params {
+			fields = append(fields, *newField(param.Id, param.Type))
+		}
+	}
+	return newVariant(*id, fields), nil
+}
+
 func (p *Parser) parseFuncDecl() (*FuncDecl, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("FuncDecl", ErrNotProcesable)
@@ -91,10 +330,33 @@ func (p *Parser) parseFuncDecl() (*FuncDecl, error) {
 	if p.match(token.FUNC) != nil {
 		return nil, NewParseError("FuncDecl", errors.New("FuncDecl에서 Func키워드 누락"))
 	}
+	// func 바로 뒤의 "(" 는 메서드의 리시버임
+	var receiverOrNil *Param
+	if p.match(token.LPAREN) == nil {
+		receiver, err := p.parseParam()
+		if err != nil {
+			return nil, NewParseError("FuncDecl", err)
+		}
+		if p.match(token.RPAREN) != nil {
+			return nil, NewParseError("FuncDecl", errors.New("리시버의 닫는 괄호 부재"))
+		}
+		receiverOrNil = receiver
+	}
 	id, err := p.parseId()
 	if err != nil {
 		return nil, NewParseError("FuncDecl", err)
 	}
+	// 이름 뒤의 "[" 는 타입 매개변수 목록임
+	var typeParamsOrNil []TypeParam
+	if p.CurrentToken().Kind == token.LBRACKET {
+		if receiverOrNil != nil {
+			return nil, NewParseError("FuncDecl", errors.New("메서드는 타입 매개변수를 가질 수 없음"))
+		}
+		typeParamsOrNil, err = p.parseTypeParams()
+		if err != nil {
+			return nil, NewParseError("FuncDecl", err)
+		}
+	}
 	params, err := p.parseParams()
 	if err != nil {
 		return nil, NewParseError("FuncDecl", err)
@@ -109,8 +371,86 @@ func (p *Parser) parseFuncDecl() (*FuncDecl, error) {
 	if err != nil {
 		return nil, NewParseError("FuncDecl", err)
 	}
+	if receiverOrNil != nil {
+		return newMethodDecl(*receiverOrNil, *id, params, returnTypesOrNil, *block), nil
+	}
+	funcDecl := newFuncDecl(*id, params, returnTypesOrNil, *block)
+	funcDecl.TypeParamsOrNil = typeParamsOrNil
+	return funcDecl, nil
+}
+
+// parseTypeParams는 "[" TypeParamGroup {"," TypeParamGroup} "]" 를 파싱함
+// TypeParamGroup은 IdList Constraint 이며, [T, U any] 처럼 제약을 공유하는 이름들을 묶어 쓸 수 있음
+func (p *Parser) parseTypeParams() ([]TypeParam, error) {
+	if p.match(token.LBRACKET) != nil {
+		return nil, NewParseError("TypeParams", errors.New("타입 매개변수 목록은 \"[\"로 시작해야 함"))
+	}
+	typeParams := []TypeParam{}
+	pending := []Id{}
+	for {
+		id, err := p.parseId()
+		if err != nil {
+			return nil, NewParseError("TypeParams", err)
+		}
+		pending = append(pending, *id)
+		if p.match(token.COMMA) == nil {
+			continue
+		}
+		if p.CurrentToken().Kind == token.RBRACKET {
+			return nil, NewParseError("TypeParams", fmt.Errorf("타입 매개변수 %s의 제약 누락", id.Name))
+		}
+		constraint, err := p.parseConstraint()
+		if err != nil {
+			return nil, NewParseError("TypeParams", err)
+		}
+		for _, pendingId := range pending {
+			typeParams = append(typeParams, *newTypeParam(pendingId, *constraint))
+		}
+		pending = []Id{}
+		if p.match(token.COMMA) != nil {
+			break
+		}
+	}
+	if p.match(token.RBRACKET) != nil {
+		return nil, NewParseError("TypeParams", errors.New("타입 매개변수 목록의 닫는 \"]\" 누락"))
+	}
+	return typeParams, nil
+}
+
+// parseConstraint는 타입 매개변수의 제약인 "comparable" | Type {"|" Type} 를 파싱함
+// 둘 이상의 타입을 "|"로 나열하면 그 타입들만 허용하는 interface가 됨
+func (p *Parser) parseConstraint() (*Type, error) {
+	if tok := p.CurrentToken(); tok.Kind == token.ID && tok.Value == "comparable" {
+		p.match(token.ID)
+		iface := newInterfaceType([]MethodSpec{})
+		iface.Comparable = true
+		return newInterfaceTypeOf(iface), nil
+	}
+	union, err := p.parseUnion()
+	if err != nil {
+		return nil, NewParseError("Constraint", err)
+	}
+	if len(union) == 1 {
+		return &union[0], nil
+	}
+	iface := newInterfaceType([]MethodSpec{})
+	iface.UnionOrNil = union
+	return newInterfaceTypeOf(iface), nil
+}
 
-	return newFuncDecl(*id, params, returnTypesOrNil, *block), nil
+// parseUnion은 Type {"|" Type} 를 파싱함
+func (p *Parser) parseUnion() ([]Type, error) {
+	union := []Type{}
+	for {
+		t, err := p.parseType()
+		if err != nil {
+			return nil, NewParseError("Union", err)
+		}
+		union = append(union, *t)
+		if p.match(token.BAR) != nil {
+			return union, nil
+		}
+	}
 }
 
 func (p *Parser) parseStmt() (Stmt, error) {
@@ -134,6 +474,8 @@ func (p *Parser) parseStmt() (Stmt, error) {
 		return p.parseCallStmt()
 	case token.VAR:
 		return p.parseVarDecl()
+	case token.LET:
+		return p.parseLet()
 	case token.FUNC:
 		return p.parseFuncDecl()
 	case token.RETURN:
@@ -144,7 +486,23 @@ func (p *Parser) parseStmt() (Stmt, error) {
 		return p.parseContinue()
 	case token.IF:
 		return p.parseIf()
+	case token.SWITCH:
+		typeSwitch, err := p.parseTypeSwitch()
+		if err == nil {
+			return typeSwitch, nil
+		}
+		rollBack()
+		return p.parseSwitch()
+	case token.FALLTHROUGH:
+		return p.parseFallthrough()
+	case token.YIELD:
+		return p.parseYield()
 	case token.FOR:
+		forRange, err := p.parseForRange()
+		if err == nil {
+			return forRange, nil
+		}
+		rollBack()
 		forBexp, err := p.parseForBexp()
 		if err == nil {
 			return forBexp, nil
@@ -163,7 +521,7 @@ func (p *Parser) parseAssign() (*Assign, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Assgin", ErrNotProcesable)
 	}
-	idList, err := p.parseIdListLongerThan0()
+	idList, fieldPaths, err := p.parseAssignTargets()
 	if err != nil {
 		return nil, NewParseError("Assign", err)
 	}
@@ -178,47 +536,56 @@ func (p *Parser) parseAssign() (*Assign, error) {
 	if p.match(token.SEMICOLON) != nil {
 		return nil, NewParseError("Assign", ErrMissingSemicolon)
 	}
-	return newAssign(idList, exprList), nil
+	return newAssignWithFields(idList, fieldPaths, exprList), nil
+}
+
+// parseAssignTargets는 id {"." id} {"," id {"." id}} 형태의 대입 대상을 파싱함
+func (p *Parser) parseAssignTargets() ([]Id, [][]Id, error) {
+	ids := []Id{}
+	fieldPaths := [][]Id{}
+	for {
+		id, err := p.parseId()
+		if err != nil {
+			return nil, nil, NewParseError("AssignTargets", err)
+		}
+		path := []Id{}
+		for p.match(token.DOT) == nil {
+			field, err := p.parseId()
+			if err != nil {
+				return nil, nil, NewParseError("AssignTargets", err)
+			}
+			path = append(path, *field)
+		}
+		ids = append(ids, *id)
+		fieldPaths = append(fieldPaths, path)
+		if p.match(token.COMMA) != nil {
+			return ids, fieldPaths, nil
+		}
+	}
 }
 
-func (p *Parser) parseCallStmt() (*CallStmt, error) {
+// parseCallStmt는 호출문 또는 f()? 형태의 에러 전파문을 파싱함
+func (p *Parser) parseCallStmt() (Stmt, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("CallStmt", ErrNotProcesable)
 	}
-	call, err := p.parseCall()
+	atom, err := p.parseAtom()
 	if err != nil {
 		return nil, NewParseError("CallStmt", err)
 	}
+	var stmt Stmt
+	switch node := atom.(type) {
+	case *Call:
+		stmt = newCallStmt(*node)
+	case *Try:
+		stmt = newTryStmt(node)
+	default:
+		return nil, NewParseError("CallStmt", errors.New("Call은 Primary이후 하나 이상의 args가 와야 합나디."))
+	}
 	if p.match(token.SEMICOLON) != nil {
 		return nil, NewParseError("CallStmt", ErrMissingSemicolon)
 	}
-	return newCallStmt(*call), nil
-}
-func (p *Parser) parseCall() (*Call, error) {
-	if !p.CheckProcessable() {
-		return nil, NewParseError("Call", ErrNotProcesable)
-	}
-
-	primary, err := p.parsePrimary()
-	if err != nil {
-
-		return nil, NewParseError("Call", err)
-	}
-	argsList := []Args{}
-	for {
-		rollBack2 := p.tape.GetRollback()
-		args, err := p.parseArgs()
-		if err != nil {
-			rollBack2()
-			break
-		}
-		argsList = append(argsList, *args)
-	}
-
-	if len(argsList) == 0 {
-		return nil, NewParseError("Call", errors.New("Call은 Primary이후 하나 이상의 args가 와야 합나디."))
-	}
-	return newCall(*primary, argsList), nil
+	return stmt, nil
 }
 
 func (p *Parser) parseShortDecl() (*ShortDecl, error) {
@@ -243,6 +610,33 @@ func (p *Parser) parseShortDecl() (*ShortDecl, error) {
 	return newShortDecl(idList, exprList), nil
 }
 
+// parseLet은 let IdList "=" ExprList ";" 를 파싱함. 새 이름만 선언하는 불변 ShortDecl이 됨
+func (p *Parser) parseLet() (*ShortDecl, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("Let", ErrNotProcesable)
+	}
+	if p.match(token.LET) != nil {
+		return nil, NewParseError("Let", errors.New("Let은 let 키워드로 시작해야 함"))
+	}
+	idList, err := p.parseIdListLongerThan0()
+	if err != nil {
+		return nil, NewParseError("Let", err)
+	}
+	if p.match(token.ASSIGN) != nil {
+		return nil, NewParseError("Let", errors.New("\"=\"기호 부재"))
+	}
+	exprList, err := p.parseExprListLongerThan0()
+	if err != nil {
+		return nil, NewParseError("Let", err)
+	}
+	if p.match(token.SEMICOLON) != nil {
+		return nil, NewParseError("Let", ErrMissingSemicolon)
+	}
+	let := newShortDecl(idList, exprList)
+	let.Immutable = true
+	return let, nil
+}
+
 func (p *Parser) parseIdListLongerThan0() ([]Id, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("IdListLongerThan0", ErrNotProcesable)
@@ -318,6 +712,23 @@ func (p *Parser) parseReturn() (*Return, error) {
 	}
 	return newReturn(exprs), nil
 }
+func (p *Parser) parseYield() (*Yield, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("Yield", ErrNotProcesable)
+	}
+	if p.match(token.YIELD) != nil {
+		return nil, NewParseError("Yield", errors.New("yield 키워드 누락"))
+	}
+	exprs, err := p.parseExprListLongerThan0()
+	if err != nil {
+		return nil, NewParseError("Yield", err)
+	}
+	if p.match(token.SEMICOLON) != nil {
+		return nil, NewParseError("Yield", ErrMissingSemicolon)
+	}
+	return newYield(exprs), nil
+}
+
 func (p *Parser) parseBreak() (*Break, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Break", ErrNotProcesable)
@@ -353,12 +764,12 @@ func (p *Parser) parseIf() (*If, error) {
 	}
 
 	rollBack := p.tape.GetRollback()
-	shortDeclOrNil, err := p.parseShortDecl()
+	shortDeclOrNil, err := withCompositeLit(p, false, p.parseShortDecl)
 	if err != nil {
 		rollBack()
 	}
 
-	bexp, err := p.parseExpr()
+	bexp, err := withCompositeLit(p, false, p.parseExpr)
 	if err != nil {
 		return nil, NewParseError("If", err)
 	}
@@ -386,7 +797,7 @@ func (p *Parser) parseForBexp() (*ForBexp, error) {
 		return nil, NewParseError("ForBexp", errors.New("for키워드 누락"))
 	}
 
-	bexp, err := p.parseBexp()
+	bexp, err := withCompositeLit(p, false, p.parseBexp)
 	if err != nil {
 		return nil, NewParseError("ForBexp", err)
 	}
@@ -397,6 +808,60 @@ func (p *Parser) parseForBexp() (*ForBexp, error) {
 	return newForBexp(bexp, *block), nil
 }
 
+// parseForRange는 for [RangeVar ["," RangeVar] ":="] range Expr Block 을 파싱함
+func (p *Parser) parseForRange() (*ForRange, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("ForRange", ErrNotProcesable)
+	}
+	if p.match(token.FOR) != nil {
+		return nil, NewParseError("ForRange", errors.New("for키워드 누락"))
+	}
+	vars := []Id{}
+	if p.CurrentToken().Kind != token.RANGE {
+		for {
+			v, err := p.parseRangeVar()
+			if err != nil {
+				return nil, NewParseError("ForRange", err)
+			}
+			vars = append(vars, *v)
+			if p.match(token.COMMA) != nil {
+				break
+			}
+		}
+		if len(vars) > 2 {
+			return nil, NewParseError("ForRange", errors.New("range 변수는 최대 2개입니다"))
+		}
+		if p.match(token.DECLSIGN) != nil {
+			return nil, NewParseError("ForRange", errors.New(":= 연산자 누락"))
+		}
+	}
+	if p.match(token.RANGE) != nil {
+		return nil, NewParseError("ForRange", errors.New("range 키워드 누락"))
+	}
+	expr, err := withCompositeLit(p, false, p.parseExpr)
+	if err != nil {
+		return nil, NewParseError("ForRange", err)
+	}
+	block, err := p.parseBlock()
+	if err != nil {
+		return nil, NewParseError("ForRange", err)
+	}
+	return newForRange(vars, expr, *block), nil
+}
+
+// parseRangeVar는 range 변수 하나를 파싱함. "_"는 값을 버리는 자리임
+func (p *Parser) parseRangeVar() (*Id, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("RangeVar", ErrNotProcesable)
+	}
+	if p.CurrentToken().Kind == token.UNDERSCORE {
+		id := p.newIdAt(p.CurrentToken())
+		p.match(token.UNDERSCORE)
+		return id, nil
+	}
+	return p.parseId()
+}
+
 func (p *Parser) parseForWithAssign() (*ForWithAssign, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("ForWithAssgin", ErrNotProcesable)
@@ -404,11 +869,11 @@ func (p *Parser) parseForWithAssign() (*ForWithAssign, error) {
 	if p.match(token.FOR) != nil {
 		return nil, NewParseError("ForWithAssign", errors.New("for키워드 누락"))
 	}
-	shortDecl, err := p.parseShortDecl()
+	shortDecl, err := withCompositeLit(p, false, p.parseShortDecl)
 	if err != nil {
 		return nil, NewParseError("ForWithAssign", err)
 	}
-	bexp, err := p.parseBexp()
+	bexp, err := withCompositeLit(p, false, p.parseBexp)
 	if err != nil {
 		return nil, NewParseError("ForWithAssign", err)
 	}
@@ -424,7 +889,7 @@ func (p *Parser) parseForWithAssign() (*ForWithAssign, error) {
 
 	}
 
-	expr, err := p.parseExpr()
+	expr, err := withCompositeLit(p, false, p.parseExpr)
 	if err != nil {
 		return nil, NewParseError("ForWithAssign", err)
 	}
@@ -529,7 +994,7 @@ func (p *Parser) parseBterm() (Expr, error) {
 		return newUnary(Not, bterm), nil
 	}
 
-	aexp, err := p.parseAexp()
+	aexp, err := p.parsePipe()
 
 	if err != nil {
 		return nil, NewParseError("Btem", err)
@@ -556,7 +1021,7 @@ func (p *Parser) parseBterm() (Expr, error) {
 
 	if relop, err := matchRelop(); err == nil {
 		p.match(p.CurrentToken().Kind)
-		secondAexp, err := p.parseAexp()
+		secondAexp, err := p.parsePipe()
 		if err != nil {
 			return nil, NewParseError("Bterm", err)
 		}
@@ -565,6 +1030,26 @@ func (p *Parser) parseBterm() (Expr, error) {
 	return aexp, nil
 }
 
+// parsePipe는 Aexp {"|>" Aexp} 를 왼쪽 결합으로 파싱함
+// 파이프는 산술 연산보다 느슨하고 비교 연산보다 강하게 묶임. (x + 1 |> f == y 는 f(x + 1) == y)
+func (p *Parser) parsePipe() (Expr, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("Pipe", ErrNotProcesable)
+	}
+	left, err := p.parseAexp()
+	if err != nil {
+		return nil, NewParseError("Pipe", err)
+	}
+	for p.match(token.PIPE) == nil {
+		right, err := p.parseAexp()
+		if err != nil {
+			return nil, NewParseError("Pipe", err)
+		}
+		left = newBinary(Pipe, left, right)
+	}
+	return left, nil
+}
+
 func (p *Parser) parseAexp() (Expr, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Aexp", ErrNotProcesable)
@@ -658,24 +1143,96 @@ func (p *Parser) parseAtom() (Atom, error) {
 		return nil, NewParseError("Atom", err)
 	}
 
-	argsOrZero := []Args{}
+	var atom Atom = primary
 	for {
-		rollBack := p.tape.GetRollback()
-		args, err := p.parseArgs()
-		if err != nil {
-			rollBack()
-			break
+		argsOrZero := []Args{}
+		for {
+			rollBack := p.tape.GetRollback()
+			args, err := p.parseArgs()
+			if err != nil {
+				rollBack()
+				break
+			}
+			argsOrZero = append(argsOrZero, *args)
 		}
-		argsOrZero = append(argsOrZero, *args)
+		// args >=1 인 경우 call로 감쌈
+		if len(argsOrZero) > 0 {
+			atom = newCall(*primaryOf(atom), argsOrZero)
+		}
+
+		// "[" 가 이어진다면 인덱스 접근으로 감싼 후 다시 args를 찾음
+		if p.match(token.LBRACKET) == nil {
+			rollBack := p.tape.GetRollback()
+			index, err := withCompositeLit(p, true, p.parseExpr)
+			if err == nil && p.match(token.RBRACKET) == nil {
+				atom = newIndex(atom, index)
+				continue
+			}
+			// 식으로 읽을 수 없다면 f[int, string] 처럼 타입 인자를 준 인스턴스화임
+			rollBack()
+			typeArgs, typeErr := p.parseTypeList()
+			if typeErr != nil || p.match(token.RBRACKET) != nil {
+				if err == nil {
+					err = errors.New("인덱스의 닫는 \"]\" 누락")
+				}
+				return nil, NewParseError("Atom", err)
+			}
+			atom = newInstantiation(atom, typeArgs)
+			continue
+		}
+		// "." 이 이어진다면 필드 접근으로 감싼 후 다시 args를 찾음
+		if p.match(token.DOT) == nil {
+			// ".(" 는 타입 단언임
+			if p.match(token.LPAREN) == nil {
+				typeOrNil, err := p.parseAssertedType()
+				if err != nil {
+					return nil, NewParseError("Atom", err)
+				}
+				atom = newTypeAssert(atom, typeOrNil)
+				continue
+			}
+			field, err := p.parseId()
+			if err != nil {
+				return nil, NewParseError("Atom", err)
+			}
+			atom = newSelector(atom, *field)
+			continue
+		}
+		// "?" 가 이어진다면 에러 전파로 감쌈
+		if p.match(token.QUESTION) == nil {
+			atom = newTry(atom)
+			continue
+		}
+		break
 	}
+	return atom, nil
+}
 
-	// args==0 인 경우 primary로 리턴
-	if len(argsOrZero) == 0 {
-		return primary, nil
+// parseAssertedType은 ".(" 이후의 Type ")" 또는 "type" ")" 을 파싱함
+// "type"인 경우엔 nil을 리턴함
+func (p *Parser) parseAssertedType() (*Type, error) {
+	if p.match(token.TYPE) == nil {
+		if p.match(token.RPAREN) != nil {
+			return nil, NewParseError("TypeAssert", errors.New(".(type)의 닫는 괄호 부재"))
+		}
+		return nil, nil
 	}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, NewParseError("TypeAssert", err)
+	}
+	if p.match(token.RPAREN) != nil {
+		return nil, NewParseError("TypeAssert", errors.New("타입 단언의 닫는 괄호 부재"))
+	}
+	return typ, nil
+}
 
-	// args >=1 인 경우 call로 리턴
-	return newCall(*primary, argsOrZero), nil
+// primaryOf는 call의 피호출자로 쓰기 위해 atom을 Primary로 맞춤
+func primaryOf(atom Atom) *Primary {
+	if primary, ok := atom.(*Primary); ok {
+		return primary
+	}
+	return newPrimary(ExprPrimary, atom, nil, nil)
 }
 
 // End
@@ -683,11 +1240,33 @@ func (p *Parser) parsePrimary() (*Primary, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Primary", ErrNotProcesable)
 	}
+	if p.CurrentToken().Kind == token.MATCH {
+		match, err := p.parseMatch()
+		if err != nil {
+			return nil, NewParseError("Primary", err)
+		}
+		return newPrimary(ExprPrimary, match, nil, nil), nil
+	}
 	if p.match(token.LPAREN) == nil {
-		expr, err := p.parseExpr()
+		expr, err := withCompositeLit(p, true, p.parseExpr)
 		if err != nil {
 			return nil, NewParseError("Primary", err)
 		}
+		// "(" Expr "," 라면 튜플 리터럴임
+		if p.CurrentToken().Kind == token.COMMA {
+			elems := []Expr{expr}
+			for p.match(token.COMMA) == nil {
+				elem, err := withCompositeLit(p, true, p.parseExpr)
+				if err != nil {
+					return nil, NewParseError("Primary", err)
+				}
+				elems = append(elems, elem)
+			}
+			if p.match(token.RPAREN) != nil {
+				return nil, NewParseError("Primary", errors.New("튜플 리터럴의 닫는 괄호 부재"))
+			}
+			return newPrimary(ValuePrimary, nil, nil, newTupleLitValueForm(newTupleLit(elems))), nil
+		}
 		if p.match(token.RPAREN) != nil {
 			return nil, NewParseError("Primary", p.match(token.RPAREN))
 		}
@@ -697,6 +1276,14 @@ func (p *Parser) parsePrimary() (*Primary, error) {
 	rb := p.tape.GetRollback()
 	id, err := p.parseId()
 	if err == nil {
+		if p.CurrentToken().Kind == token.LBRACE && !p.noCompositeLit {
+			rbLit := p.tape.GetRollback()
+			lit, err := p.parseStructLit(*id)
+			if err == nil {
+				return newPrimary(ValuePrimary, nil, nil, newStructLitValueForm(lit)), nil
+			}
+			rbLit()
+		}
 		return newPrimary(IdPrimary, nil, id, nil), nil
 	}
 	rb()
@@ -708,6 +1295,146 @@ func (p *Parser) parsePrimary() (*Primary, error) {
 	return newPrimary(ValuePrimary, nil, nil, valueForm), nil
 
 }
+
+// parseMatch는 match Expr "{" Arm {"," Arm} [","] "}" 를 파싱함
+// Arm은 Pattern ["if" Expr] "=>" Expr 임
+func (p *Parser) parseMatch() (*Match, error) {
+	if p.match(token.MATCH) != nil {
+		return nil, NewParseError("Match", errors.New("Match는 match 키워드로 시작해야 함"))
+	}
+	subject, err := withCompositeLit(p, false, p.parseExpr)
+	if err != nil {
+		return nil, NewParseError("Match", err)
+	}
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("Match", errors.New("match 본문은 \"{\"로 시작해야 함"))
+	}
+	arms := []MatchArm{}
+	for p.CurrentToken().Kind != token.RBRACE {
+		arm, err := p.parseMatchArm()
+		if err != nil {
+			return nil, NewParseError("Match", err)
+		}
+		arms = append(arms, *arm)
+		if p.match(token.COMMA) != nil {
+			break
+		}
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("Match", errors.New("match 본문의 닫는 중괄호 부재"))
+	}
+	if len(arms) == 0 {
+		return nil, NewParseError("Match", errors.New("match에는 갈래가 하나 이상 필요함"))
+	}
+	return newMatch(subject, arms), nil
+}
+
+func (p *Parser) parseMatchArm() (*MatchArm, error) {
+	pattern, err := p.parsePattern()
+	if err != nil {
+		return nil, NewParseError("MatchArm", err)
+	}
+	var guardOrNil Expr
+	if p.match(token.IF) == nil {
+		guard, err := withCompositeLit(p, true, p.parseExpr)
+		if err != nil {
+			return nil, NewParseError("MatchArm", err)
+		}
+		guardOrNil = guard
+	}
+	if p.match(token.ARROW) != nil {
+		return nil, NewParseError("MatchArm", errors.New("패턴 뒤에 \"=>\" 누락"))
+	}
+	body, err := withCompositeLit(p, true, p.parseExpr)
+	if err != nil {
+		return nil, NewParseError("MatchArm", err)
+	}
+	return &MatchArm{Pattern: *pattern, GuardOrNil: guardOrNil, Body: body}, nil
+}
+
+// parsePattern은 "_" | Literal | id ["(" Pattern {"," Pattern} ")"] 를 파싱함
+func (p *Parser) parsePattern() (*Pattern, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("Pattern", ErrNotProcesable)
+	}
+	switch p.CurrentToken().Kind {
+	case token.UNDERSCORE:
+		p.match(token.UNDERSCORE)
+		return newWildcardPattern(), nil
+	case token.NUMBER, token.STRLIT, token.TRUE, token.FALSE:
+		literal, err := p.parsePrimary()
+		if err != nil {
+			return nil, NewParseError("Pattern", err)
+		}
+		return newLiteralPattern(literal), nil
+	case token.MINUS:
+		p.match(token.MINUS)
+		if p.CurrentToken().Kind != token.NUMBER {
+			return nil, NewParseError("Pattern", errors.New("\"-\" 뒤에는 숫자 리터럴만 올 수 있음"))
+		}
+		literal, err := p.parsePrimary()
+		if err != nil {
+			return nil, NewParseError("Pattern", err)
+		}
+		return newLiteralPattern(newUnary(MinusUnary, literal)), nil
+	}
+	id, err := p.parseId()
+	if err != nil {
+		return nil, NewParseError("Pattern", errors.New("패턴은 _, 리터럴, 식별자 중 하나여야 함"))
+	}
+	// Circle() 처럼 빈 괄호는 OMIT으로 들어옴
+	if p.match(token.OMIT) == nil {
+		return newVariantPattern(*id, []Pattern{}), nil
+	}
+	if p.match(token.LPAREN) != nil {
+		return newBindPattern(*id), nil
+	}
+	args := []Pattern{}
+	for {
+		arg, err := p.parsePattern()
+		if err != nil {
+			return nil, NewParseError("Pattern", err)
+		}
+		args = append(args, *arg)
+		if p.match(token.COMMA) != nil {
+			break
+		}
+	}
+	if p.match(token.RPAREN) != nil {
+		return nil, NewParseError("Pattern", errors.New("변형 패턴의 닫는 괄호 부재"))
+	}
+	return newVariantPattern(*id, args), nil
+}
+
+// parseStructLit은 타입 이름 이후의 "{" id ":" Expr {"," id ":" Expr} [","] "}" 를 파싱함
+func (p *Parser) parseStructLit(typeName Id) (*StructLit, error) {
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("StructLit", errors.New("합성 리터럴은 \"{\"로 시작해야 함"))
+	}
+	fields := []FieldInit{}
+	for p.CurrentToken().Kind != token.RBRACE {
+		field, err := p.parseId()
+		if err != nil {
+			return nil, NewParseError("StructLit", err)
+		}
+		if p.match(token.COLON) != nil {
+			return nil, NewParseError("StructLit", errors.New("필드 이름 뒤에 \":\" 누락"))
+		}
+		expr, err := withCompositeLit(p, true, p.parseExpr)
+		if err != nil {
+			return nil, NewParseError("StructLit", err)
+		}
+		fields = append(fields, FieldInit{Field: *field, Expr: expr})
+		if p.match(token.COMMA) != nil {
+			break
+		}
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("StructLit", errors.New("합성 리터럴의 닫는 \"}\" 누락"))
+	}
+	return newStructLit(typeName, fields), nil
+}
+
 func (p *Parser) parseArgs() (*Args, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Args", ErrNotProcesable)
@@ -720,7 +1447,7 @@ func (p *Parser) parseArgs() (*Args, error) {
 		return nil, NewParseError("Args", errors.New("Args파싱 실패: Omit이 아닌 Args는 반드시 LPAREN과 EXPR이 필요함"))
 	}
 	// Omit이 아닌 한, 반드시 하나 이상의 expr은 있어야 함
-	args, err := p.parseExprListLongerThan0()
+	args, err := withCompositeLit(p, true, p.parseExprListLongerThan0)
 	if err != nil {
 		return nil, NewParseError("Args", err)
 	}
@@ -742,6 +1469,13 @@ func (p *Parser) parseValueForm() (*ValueForm, error) {
 		}
 		return newValueForm(FexpValue, nil, nil, nil, nil, fexp), nil
 	}
+	if currentToken.Kind == token.LBRACKET {
+		lit, err := p.parseSliceLit()
+		if err != nil {
+			return nil, NewParseError("ValueForm", err)
+		}
+		return newSliceLitValueForm(lit), nil
+	}
 	switch currentToken.Kind {
 	case token.NUMBER:
 		num, err := strconv.Atoi(currentToken.Value)
@@ -769,6 +1503,37 @@ func (p *Parser) parseValueForm() (*ValueForm, error) {
 		return nil, NewParseError("ValueForm", errors.New("ValueForm 파싱에서 케이스 미스매치 발생"))
 	}
 }
+
+// parseSliceLit은 "[" "]" Type "{" [Expr {"," Expr} [","]] "}" 를 파싱함
+// 타입이 "[]"로 시작해 블록과 헷갈릴 일이 없으므로 if, for 헤더에서도 쓸 수 있음
+func (p *Parser) parseSliceLit() (*SliceLit, error) {
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, NewParseError("SliceLit", err)
+	}
+	if typ.TypeKind != SliceType {
+		return nil, NewParseError("SliceLit", errors.New("슬라이스 리터럴은 []T 타입으로 시작해야 함"))
+	}
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("SliceLit", errors.New("슬라이스 리터럴은 타입 뒤에 \"{\"가 와야 함"))
+	}
+	elems := []Expr{}
+	for p.CurrentToken().Kind != token.RBRACE {
+		elem, err := withCompositeLit(p, true, p.parseExpr)
+		if err != nil {
+			return nil, NewParseError("SliceLit", err)
+		}
+		elems = append(elems, elem)
+		if p.match(token.COMMA) != nil {
+			break
+		}
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("SliceLit", errors.New("슬라이스 리터럴의 닫는 \"}\" 누락"))
+	}
+	return newSliceLit(*typ.ElemOrNil, elems), nil
+}
+
 func (p *Parser) parseFexp() (*Fexp, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Fexp", ErrNotProcesable)
@@ -851,7 +1616,7 @@ func (p *Parser) parseId() (*Id, error) {
 		return nil, NewParseError("Id", ErrNotProcesable)
 	}
 	if p.tape.CurrentToken().Kind == token.ID {
-		id := newId(p.tape.CurrentToken(), p.idIdCounter.GetNextID())
+		id := p.newIdAt(p.tape.CurrentToken())
 		p.match(token.ID)
 		return id, nil
 	}
@@ -862,38 +1627,43 @@ func (p *Parser) parseReturnTypes() ([]Type, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("ReturnTypes", ErrNotProcesable)
 	}
-	rollBack := p.tape.GetRollback()
-	if onlyType, err := p.parseType(); err == nil {
-		//단일 타입으로 잘 파싱될 경우
-		return []Type{*onlyType}, nil
-	}
-	rollBack()
-	types := []Type{}
-	if p.match(token.LPAREN) == nil {
-		typeOnce, err := p.parseType()
-		types = append(types, *typeOnce)
+	// "(" 로 시작하면 다중 리턴으로 해석함
+	// 튜플 하나를 리턴하려면 ((int, error)) 처럼 한 번 더 감싸야 함
+	if p.CurrentToken().Kind != token.LPAREN {
+		onlyType, err := p.parseType()
 		if err != nil {
-			return nil, NewParseError("ReturnTypes", errors.New("ReturnTypes. Omit이 아닌 경우, 괄호 안에는 리턴 타입 하나 이상 필수입니다"))
-		}
-		for {
-			if p.match(token.COMMA) != nil {
-				break
-			}
-			t, err := p.parseType()
-			if err != nil {
-				return nil, NewParseError("ReturnTypes", err)
-			}
-			types = append(types, *t)
+			return nil, NewParseError("ReturnTypes", errors.New("ReturnTypes의 값이 존재하지 않음"))
 		}
+		return []Type{*onlyType}, nil
+	}
+	p.match(token.LPAREN)
+	types, err := p.parseTypeList()
+	if err != nil {
+		return nil, NewParseError("ReturnTypes", errors.New("ReturnTypes. Omit이 아닌 경우, 괄호 안에는 리턴 타입 하나 이상 필수입니다"))
+	}
+	if p.match(token.RPAREN) != nil {
+		return nil, NewParseError("ReturnTypes", errors.New("리턴 타입의 닫는 괄호 부재"))
+	}
+	return types, nil
+}
 
-		if p.match(token.RPAREN) != nil {
-			return nil, NewParseError("ReturnTypes", errors.New("리턴 타입의 닫는 괄호 부재"))
+// parseTypeList는 Type {"," Type} 를 파싱함
+func (p *Parser) parseTypeList() ([]Type, error) {
+	first, err := p.parseType()
+	if err != nil {
+		return nil, NewParseError("TypeList", err)
+	}
+	types := []Type{*first}
+	for p.match(token.COMMA) == nil {
+		t, err := p.parseType()
+		if err != nil {
+			return nil, NewParseError("TypeList", err)
 		}
-
-		return types, nil
+		types = append(types, *t)
 	}
-	return nil, NewParseError("ReturnTypes", errors.New("ReturnTypes의 값이 존재하지 않음"))
+	return types, nil
 }
+
 func (p *Parser) parseType() (*Type, error) {
 	if !p.CheckProcessable() {
 		return nil, NewParseError("Type", ErrNotProcesable)
@@ -912,6 +1682,52 @@ func (p *Parser) parseType() (*Type, error) {
 	case token.ERROR:
 		p.match(currentToken.Kind)
 		return newType(ErrorType, nil), nil
+	case token.ID:
+		// any는 빈 interface의 다른 이름임
+		if currentToken.Value == "any" {
+			p.match(token.ID)
+			return newInterfaceTypeOf(newInterfaceType([]MethodSpec{})), nil
+		}
+		id, err := p.parseId()
+		if err != nil {
+			return nil, NewParseError("Type", err)
+		}
+		return newNamedType(*id), nil
+	case token.STRUCT:
+		structType, err := p.parseStructType()
+		if err != nil {
+			return nil, NewParseError("Type", err)
+		}
+		return newStructTypeOf(structType), nil
+	case token.INTERFACE:
+		interfaceType, err := p.parseInterfaceType()
+		if err != nil {
+			return nil, NewParseError("Type", err)
+		}
+		return newInterfaceTypeOf(interfaceType), nil
+	case token.LPAREN:
+		p.match(token.LPAREN)
+		elems, err := p.parseTypeList()
+		if err != nil {
+			return nil, NewParseError("Type", err)
+		}
+		if len(elems) < 2 {
+			return nil, NewParseError("Type", errors.New("튜플 타입은 두 개 이상의 원소 타입이 필요함"))
+		}
+		if p.match(token.RPAREN) != nil {
+			return nil, NewParseError("Type", errors.New("튜플 타입의 닫는 괄호 부재"))
+		}
+		return newTupleTypeOf(elems), nil
+	case token.LBRACKET:
+		p.match(token.LBRACKET)
+		if p.match(token.RBRACKET) != nil {
+			return nil, NewParseError("Type", errors.New("슬라이스 타입은 \"[]\"로 시작해야 함"))
+		}
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, NewParseError("Type", err)
+		}
+		return newSliceTypeOf(*elem), nil
 	}
 
 	funcType, err := p.parseFuncType()
@@ -928,7 +1744,12 @@ func (p *Parser) parseFuncType() (*FuncType, error) {
 	if p.match(token.FUNC) != nil {
 		return nil, NewParseError("FuncType", errors.New("FuncType파싱 중 Func키워드 미발견"))
 	}
+	return p.parseSignatureTypes()
+}
 
+// parseSignatureTypes는 ArgTypes [ReturnTypes] 를 파싱함
+// FuncType과 interface의 MethodSpec이 공유함
+func (p *Parser) parseSignatureTypes() (*FuncType, error) {
 	argTypes := []Type{}
 	if p.match(token.OMIT) != nil {
 		if p.match(token.LPAREN) != nil {
@@ -955,20 +1776,265 @@ func (p *Parser) parseFuncType() (*FuncType, error) {
 		}
 	}
 	rollBack := p.tape.GetRollback()
-	onlyType, err := p.parseType()
-	if err == nil {
-		return newFuncType(argTypes, []Type{*onlyType}), nil
-	}
-	// 단일 타입인 경우가 아니라면 롤백
-	rollBack()
-
-	returnTypes := []Type{}
-	rollback2 := p.tape.GetRollback()
-	returnTypes, err = p.parseReturnTypes()
+	returnTypes, err := p.parseReturnTypes()
 	if err != nil {
+		// 리턴 타입이 없는 함수 타입임
 		returnTypes = []Type{}
-		rollback2()
+		rollBack()
 	}
 	return newFuncType(argTypes, returnTypes), nil
 
 }
+
+// parseStructType은 "struct" "{" {id {"," id} Type ";"} "}" 를 파싱함
+// 마지막 필드의 세미콜론은 생략 가능
+func (p *Parser) parseStructType() (*StructType, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("StructType", ErrNotProcesable)
+	}
+	if p.match(token.STRUCT) != nil {
+		return nil, NewParseError("StructType", errors.New("StructType은 struct 키워드로 시작해야 함"))
+	}
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("StructType", errors.New("struct 이후 \"{\" 누락"))
+	}
+	fields := []Field{}
+	for p.CurrentToken().Kind != token.RBRACE {
+		ids, err := p.parseIdListLongerThan0()
+		if err != nil {
+			return nil, NewParseError("StructType", err)
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, NewParseError("StructType", err)
+		}
+		for _, id := range ids {
+			fields = append(fields, *newField(id, *typ))
+		}
+		if p.match(token.SEMICOLON) != nil {
+			break
+		}
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("StructType", errors.New("struct의 닫는 \"}\" 누락"))
+	}
+	return newStructType(fields), nil
+}
+
+// parseInterfaceType은 "interface" "{" {id ArgTypes [ReturnTypes] ";"} "}" 를 파싱함
+// 마지막 메서드의 세미콜론은 생략 가능
+func (p *Parser) parseInterfaceType() (*InterfaceType, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("InterfaceType", ErrNotProcesable)
+	}
+	if p.match(token.INTERFACE) != nil {
+		return nil, NewParseError("InterfaceType", errors.New("InterfaceType은 interface 키워드로 시작해야 함"))
+	}
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("InterfaceType", errors.New("interface 이후 \"{\" 누락"))
+	}
+	iface := newInterfaceType([]MethodSpec{})
+	for p.CurrentToken().Kind != token.RBRACE {
+		// 이름 뒤에 "(" 나 "()" 가 오면 메서드이고, 아니라면 comparable 또는 int | string 같은 타입 원소임
+		tok := p.CurrentToken()
+		switch {
+		case tok.Kind == token.ID && (p.tape.Peek(1).Kind == token.LPAREN || p.tape.Peek(1).Kind == token.OMIT):
+			id, err := p.parseId()
+			if err != nil {
+				return nil, NewParseError("InterfaceType", err)
+			}
+			funcType, err := p.parseSignatureTypes()
+			if err != nil {
+				return nil, NewParseError("InterfaceType", err)
+			}
+			iface.Methods = append(iface.Methods, *newMethodSpec(*id, *funcType))
+		case tok.Kind == token.ID && tok.Value == "comparable":
+			p.match(token.ID)
+			iface.Comparable = true
+		default:
+			if iface.UnionOrNil != nil {
+				return nil, NewParseError("InterfaceType", errors.New("interface에는 타입 원소 줄이 하나만 올 수 있음"))
+			}
+			union, err := p.parseUnion()
+			if err != nil {
+				return nil, NewParseError("InterfaceType", err)
+			}
+			iface.UnionOrNil = union
+		}
+		if p.match(token.SEMICOLON) != nil {
+			break
+		}
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("InterfaceType", errors.New("interface의 닫는 \"}\" 누락"))
+	}
+	return iface, nil
+}
+
+// parseTypeSwitch는 "switch" [id ":="] Atom".(type)" "{" {TypeCaseClause} "}" 를 파싱함
+func (p *Parser) parseTypeSwitch() (*TypeSwitch, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("TypeSwitch", ErrNotProcesable)
+	}
+	if p.match(token.SWITCH) != nil {
+		return nil, NewParseError("TypeSwitch", errors.New("switch키워드 누락"))
+	}
+	var bindingOrNil *Id
+	rollBack := p.tape.GetRollback()
+	if id, err := p.parseId(); err == nil && p.match(token.DECLSIGN) == nil {
+		bindingOrNil = id
+	} else {
+		rollBack()
+	}
+	guard, err := withCompositeLit(p, false, p.parseAtom)
+	if err != nil {
+		return nil, NewParseError("TypeSwitch", err)
+	}
+	assert, ok := guard.(*TypeAssert)
+	if !ok || assert.TypeOrNil != nil {
+		return nil, NewParseError("TypeSwitch", errors.New("type switch의 헤더는 x.(type) 형태여야 함"))
+	}
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("TypeSwitch", errors.New("switch 헤더 이후 \"{\" 누락"))
+	}
+	clauses := []TypeCaseClause{}
+	hasDefault := false
+	for p.CurrentToken().Kind != token.RBRACE {
+		clause := TypeCaseClause{}
+		switch p.CurrentToken().Kind {
+		case token.CASE:
+			p.match(token.CASE)
+			for {
+				typ, err := p.parseType()
+				if err != nil {
+					return nil, NewParseError("TypeSwitch", err)
+				}
+				clause.Types = append(clause.Types, *typ)
+				if p.match(token.COMMA) != nil {
+					break
+				}
+			}
+		case token.DEFAULT:
+			p.match(token.DEFAULT)
+			if hasDefault {
+				return nil, NewParseError("TypeSwitch", errors.New("default 절이 여러 번 나옴"))
+			}
+			hasDefault = true
+			clause.IsDefault = true
+		default:
+			return nil, NewParseError("TypeSwitch", errors.New("switch 본문에는 case 또는 default 절만 올 수 있음"))
+		}
+		if p.match(token.COLON) != nil {
+			return nil, NewParseError("TypeSwitch", errors.New("case 이후 \":\" 누락"))
+		}
+		body, err := p.parseClauseBody()
+		if err != nil {
+			return nil, NewParseError("TypeSwitch", err)
+		}
+		clause.Block = *body
+		clauses = append(clauses, clause)
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("TypeSwitch", errors.New("switch의 닫는 \"}\" 누락"))
+	}
+	return newTypeSwitch(bindingOrNil, assert.Object, clauses), nil
+}
+
+// parseSwitch는 switch [ShortDecl] [Expr] { {CaseClause} } 를 파싱함
+func (p *Parser) parseSwitch() (*Switch, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("Switch", ErrNotProcesable)
+	}
+	if p.match(token.SWITCH) != nil {
+		return nil, NewParseError("Switch", errors.New("switch키워드 누락"))
+	}
+	rollBack := p.tape.GetRollback()
+	shortDeclOrNil, err := withCompositeLit(p, false, p.parseShortDecl)
+	if err != nil {
+		rollBack()
+	}
+	var tagOrNil Expr
+	if p.CurrentToken().Kind != token.LBRACE {
+		tag, err := withCompositeLit(p, false, p.parseExpr)
+		if err != nil {
+			return nil, NewParseError("Switch", err)
+		}
+		tagOrNil = tag
+	}
+	if p.match(token.LBRACE) != nil {
+		return nil, NewParseError("Switch", errors.New("switch 헤더 이후 \"{\" 누락"))
+	}
+	clauses := []CaseClause{}
+	hasDefault := false
+	for p.CurrentToken().Kind != token.RBRACE {
+		clause := CaseClause{}
+		switch p.CurrentToken().Kind {
+		case token.CASE:
+			p.match(token.CASE)
+			exprs, err := p.parseExprListLongerThan0()
+			if err != nil {
+				return nil, NewParseError("Switch", err)
+			}
+			clause.Exprs = exprs
+		case token.DEFAULT:
+			p.match(token.DEFAULT)
+			if hasDefault {
+				return nil, NewParseError("Switch", errors.New("default 절이 여러 번 나옴"))
+			}
+			hasDefault = true
+			clause.IsDefault = true
+		default:
+			return nil, NewParseError("Switch", errors.New("switch 본문에는 case 또는 default 절만 올 수 있음"))
+		}
+		if p.match(token.COLON) != nil {
+			return nil, NewParseError("Switch", errors.New("case 이후 \":\" 누락"))
+		}
+		body, err := p.parseClauseBody()
+		if err != nil {
+			return nil, NewParseError("Switch", err)
+		}
+		// 절의 마지막 fallthrough는 절의 속성으로 옮김
+		if n := len(body.StmtsOrNil); n > 0 {
+			if _, ok := body.StmtsOrNil[n-1].(*Fallthrough); ok {
+				body.StmtsOrNil = body.StmtsOrNil[:n-1]
+				clause.Fallthrough = true
+			}
+		}
+		clause.Block = *body
+		clauses = append(clauses, clause)
+	}
+	if p.match(token.RBRACE) != nil {
+		return nil, NewParseError("Switch", errors.New("switch의 닫는 \"}\" 누락"))
+	}
+	// 마지막 절의 fallthrough는 위치를 담은 에러로 알릴 수 있도록 리졸버가 거부함
+	return newSwitch(shortDeclOrNil, tagOrNil, clauses), nil
+}
+
+func (p *Parser) parseFallthrough() (*Fallthrough, error) {
+	if !p.CheckProcessable() {
+		return nil, NewParseError("Fallthrough", ErrNotProcesable)
+	}
+	if p.match(token.FALLTHROUGH) != nil {
+		return nil, NewParseError("Fallthrough", errors.New("fallthrough키워드 누락"))
+	}
+	if p.match(token.SEMICOLON) != nil {
+		return nil, NewParseError("Fallthrough", ErrMissingSemicolon)
+	}
+	return newFallthrough(), nil
+}
+
+// parseClauseBody는 다음 case, default 혹은 "}" 직전까지의 문장들을 하나의 블록으로 파싱함
+func (p *Parser) parseClauseBody() (*Block, error) {
+	stmts := []Stmt{}
+	for {
+		switch p.CurrentToken().Kind {
+		case token.CASE, token.DEFAULT, token.RBRACE, token.EOF:
+			return newBlock(stmts), nil
+		}
+		stmt, err := p.parseStmt()
+		if err != nil {
+			return nil, NewParseError("ClauseBody", err)
+		}
+		stmts = append(stmts, stmt)
+	}
+}
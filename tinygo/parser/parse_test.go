@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
@@ -274,3 +275,730 @@ func TestParser_ParsePackage_Table(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_StructDeclAndLiteral(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *PackageAST
+	}{
+		{
+			name:  "type_decl_struct",
+			input: "type Point struct { x, y int; name string }",
+			want: newPackage([]Decl{
+				newTypeDecl(
+					*idPtr("Point", 0),
+					*newStructTypeOf(newStructType([]Field{
+						*newField(*idPtr("x", 1), Type{TypeKind: IntType}),
+						*newField(*idPtr("y", 2), Type{TypeKind: IntType}),
+						*newField(*idPtr("name", 3), Type{TypeKind: StringType}),
+					})),
+				),
+			}),
+		},
+		{
+			name:  "struct_lit_and_selector",
+			input: "func f() int { p := Point{x: 1, y: 2}; return p.x; }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{{TypeKind: IntType}},
+					Block{StmtsOrNil: []Stmt{
+						newShortDecl(
+							[]Id{*idPtr("p", 1)},
+							[]Expr{newPrimary(ValuePrimary, nil, nil, newStructLitValueForm(newStructLit(*idPtr("Point", 2), []FieldInit{
+								{Field: *idPtr("x", 3), Expr: numPrimary(1)},
+								{Field: *idPtr("y", 4), Expr: numPrimary(2)},
+							})))},
+						),
+						newReturn([]Expr{newSelector(idPrimary("p", 5), *idPtr("x", 6))}),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "field_assign",
+			input: "func f() { p.x.y = 3; }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newAssignWithFields(
+							[]Id{*idPtr("p", 1)},
+							[][]Id{{*idPtr("x", 2), *idPtr("y", 3)}},
+							[]Expr{numPrimary(3)},
+						),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "if_header_is_not_struct_lit",
+			input: "func f() { if a == b { return; } }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newIf(
+							nil,
+							newBinary(Equal, idPrimary("a", 1), idPrimary("b", 2)),
+							Block{StmtsOrNil: []Stmt{newReturn([]Expr{})}},
+							nil,
+						),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "selector_call_stmt",
+			input: "func f() { p.q.run(1); }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newCallStmt(*newCall(
+							*newPrimary(ExprPrimary, newSelector(newSelector(idPrimary("p", 1), *idPtr("q", 2)), *idPtr("run", 3)), nil, nil),
+							[]Args{{numPrimary(1)}},
+						)),
+					}},
+				),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePackageForTest(t, tt.input)
+			gotStr := got.String()
+			wantStr := tt.want.String()
+			if gotStr != wantStr {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", gotStr, wantStr)
+			}
+		})
+	}
+}
+
+func TestParser_MethodsAndInterfaces(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *PackageAST
+	}{
+		{
+			name:  "method_decl",
+			input: "func (p Point) Dist() int { return p.x; }",
+			want: newPackage([]Decl{
+				newMethodDecl(
+					*newParam(*idPtr("p", 0), *newNamedType(*idPtr("Point", 1))),
+					*idPtr("Dist", 2),
+					[]Param{},
+					[]Type{{TypeKind: IntType}},
+					Block{StmtsOrNil: []Stmt{
+						newReturn([]Expr{newSelector(idPrimary("p", 3), *idPtr("x", 4))}),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "interface_decl",
+			input: "type Shape interface { Area() int; Scale(int) Shape }",
+			want: newPackage([]Decl{
+				newTypeDecl(
+					*idPtr("Shape", 0),
+					*newInterfaceTypeOf(newInterfaceType([]MethodSpec{
+						*newMethodSpec(*idPtr("Area", 1), *newFuncType([]Type{}, []Type{{TypeKind: IntType}})),
+						*newMethodSpec(*idPtr("Scale", 2), *newFuncType([]Type{{TypeKind: IntType}}, []Type{*newNamedType(*idPtr("Shape", 3))})),
+					})),
+				),
+			}),
+		},
+		{
+			name:  "type_assert",
+			input: "func f() { n := s.(Circle).r; }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newShortDecl(
+							[]Id{*idPtr("n", 1)},
+							[]Expr{newSelector(newTypeAssert(idPrimary("s", 2), newNamedType(*idPtr("Circle", 3))), *idPtr("r", 4))},
+						),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "type_assert_comma_ok",
+			input: "func f() { c, found := s.(Circle); }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newShortDecl(
+							[]Id{*idPtr("c", 1), *idPtr("found", 2)},
+							[]Expr{newTypeAssert(idPrimary("s", 3), newNamedType(*idPtr("Circle", 4)))},
+						),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "type_switch",
+			input: "func f() { switch v := s.(type) { case int, string: print(v); default: } }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newTypeSwitch(idPtr("v", 1), idPrimary("s", 2), []TypeCaseClause{
+							{
+								Types: []Type{{TypeKind: IntType}, {TypeKind: StringType}},
+								Block: Block{StmtsOrNil: []Stmt{
+									newCallStmt(*newCall(*idPrimary("print", 3), []Args{{idPrimary("v", 4)}})),
+								}},
+							},
+							{IsDefault: true, Block: Block{StmtsOrNil: []Stmt{}}},
+						}),
+					}},
+				),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePackageForTest(t, tt.input)
+			gotStr := got.String()
+			wantStr := tt.want.String()
+			if gotStr != wantStr {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", gotStr, wantStr)
+			}
+		})
+	}
+}
+
+func TestParser_SlicesAndModuleCalls(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *PackageAST
+	}{
+		{
+			name:  "slice_type_and_literal",
+			input: "var xs []string = []string{\"a\", \"b\"};",
+			want: newPackage([]Decl{
+				newVarDecl(
+					[]Id{*idPtr("xs", 0)},
+					*newSliceTypeOf(Type{TypeKind: StringType}),
+					[]Expr{newPrimary(ValuePrimary, nil, nil, newSliceLitValueForm(newSliceLit(Type{TypeKind: StringType}, []Expr{strPrimary("a"), strPrimary("b")})))},
+				),
+			}),
+		},
+		{
+			name:  "index_and_module_call",
+			input: "func f() { n := xs[1]; s := strings.ToUpper(xs[0]); }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newShortDecl([]Id{*idPtr("n", 1)}, []Expr{newIndex(idPrimary("xs", 2), numPrimary(1))}),
+						newShortDecl([]Id{*idPtr("s", 3)}, []Expr{
+							newCall(*primaryOf(newSelector(idPrimary("strings", 4), *idPtr("ToUpper", 5))), []Args{{newIndex(idPrimary("xs", 6), numPrimary(0))}}),
+						}),
+					}},
+				),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePackageForTest(t, tt.input)
+			gotStr := got.String()
+			wantStr := tt.want.String()
+			if gotStr != wantStr {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", gotStr, wantStr)
+			}
+		})
+	}
+}
+
+func TestParser_Tuples(t *testing.T) {
+	intT := Type{TypeKind: IntType}
+	errT := Type{TypeKind: ErrorType}
+	pairT := *newTupleTypeOf([]Type{intT, errT})
+	tests := []struct {
+		name  string
+		input string
+		want  *PackageAST
+	}{
+		{
+			name:  "multi_return_vs_tuple_return",
+			input: "func f(r (int, error)) (int, error) { return r; } func g() ((int, error)) { return (1, ok); }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{*newParam(*idPtr("r", 1), pairT)},
+					[]Type{intT, errT},
+					Block{StmtsOrNil: []Stmt{newReturn([]Expr{idPrimary("r", 2)})}},
+				),
+				newFuncDecl(
+					*idPtr("g", 3),
+					[]Param{},
+					[]Type{pairT},
+					Block{StmtsOrNil: []Stmt{newReturn([]Expr{
+						newPrimary(ValuePrimary, nil, nil, newTupleLitValueForm(newTupleLit([]Expr{numPrimary(1), okPrimary()}))),
+					})}},
+				),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePackageForTest(t, tt.input)
+			gotStr := got.String()
+			wantStr := tt.want.String()
+			if gotStr != wantStr {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", gotStr, wantStr)
+			}
+		})
+	}
+}
+
+func TestParser_TryExprAndStmt(t *testing.T) {
+	got := parsePackageForTest(t, "func f() (int, error) { n := g(1)?; check(n)?; return n, ok; }")
+	want := newPackage([]Decl{
+		newFuncDecl(
+			*idPtr("f", 0),
+			[]Param{},
+			[]Type{{TypeKind: IntType}, {TypeKind: ErrorType}},
+			Block{StmtsOrNil: []Stmt{
+				newShortDecl([]Id{*idPtr("n", 1)}, []Expr{newTry(newCall(*idPrimary("g", 2), []Args{{numPrimary(1)}}))}),
+				newTryStmt(newTry(newCall(*idPrimary("check", 3), []Args{{idPrimary("n", 4)}}))),
+				newReturn([]Expr{idPrimary("n", 5), okPrimary()}),
+			}},
+		),
+	})
+	if got.String() != want.String() {
+		t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), want.String())
+	}
+}
+
+func TestParser_PipePrecedence(t *testing.T) {
+	// 파이프는 산술보다 느슨하고 비교보다 강하며, 왼쪽 결합임
+	got := parsePackageForTest(t, "var b bool = a + 1 |> f(2) |> g == c;")
+	pipe := newBinary(Pipe,
+		newBinary(Pipe,
+			newBinary(Plus, idPrimary("a", 1), numPrimary(1)),
+			newCall(*idPrimary("f", 2), []Args{{numPrimary(2)}}),
+		),
+		idPrimary("g", 3),
+	)
+	want := newPackage([]Decl{
+		newVarDecl([]Id{*idPtr("b", 0)}, Type{TypeKind: BoolType}, []Expr{newBinary(Equal, pipe, idPrimary("c", 4))}),
+	})
+	if got.String() != want.String() {
+		t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), want.String())
+	}
+}
+
+func TestParser_YieldAndForRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *PackageAST
+	}{
+		{
+			name:  "yield_multiple_values",
+			input: "func g() { yield 1, 2; }",
+			want: newPackage([]Decl{
+				newFuncDecl(*idPtr("g", 0), []Param{}, []Type{}, Block{StmtsOrNil: []Stmt{
+					newYield([]Expr{numPrimary(1), numPrimary(2)}),
+				}}),
+			}),
+		},
+		{
+			name:  "range_with_blank_and_value",
+			input: "func f() { for _, v := range xs { print(v); } }",
+			want: newPackage([]Decl{
+				newFuncDecl(*idPtr("f", 0), []Param{}, []Type{}, Block{StmtsOrNil: []Stmt{
+					newForRange(
+						[]Id{*idPtr("_", 1), *idPtr("v", 2)},
+						idPrimary("xs", 3),
+						*newBlock([]Stmt{newCallStmt(*newCall(*idPrimary("print", 4), []Args{{idPrimary("v", 5)}}))}),
+					),
+				}}),
+			}),
+		},
+		{
+			name:  "range_without_vars",
+			input: "func f() { for range 3 { } }",
+			want: newPackage([]Decl{
+				newFuncDecl(*idPtr("f", 0), []Param{}, []Type{}, Block{StmtsOrNil: []Stmt{
+					newForRange([]Id{}, numPrimary(3), *newBlock([]Stmt{})),
+				}}),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePackageForTest(t, tt.input)
+			if got.String() != tt.want.String() {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), tt.want.String())
+			}
+		})
+	}
+}
+
+func TestParser_ConstAndLet(t *testing.T) {
+	intType := newType(IntType, nil)
+	let := newShortDecl([]Id{*idPtr("x", 6), *idPtr("y", 7)}, []Expr{numPrimary(1), idPrimary("C", 8)})
+	let.Immutable = true
+	input := "const ( A = iota; B; ) const C int = A + 1; func f() { let x, y = 1, C; }"
+	want := newPackage([]Decl{
+		newConstDecl([]ConstSpec{
+			*newConstSpec([]Id{*idPtr("A", 0)}, nil, []Expr{idPrimary("iota", 1)}),
+			*newConstSpec([]Id{*idPtr("B", 2)}, nil, nil),
+		}),
+		newConstDecl([]ConstSpec{
+			*newConstSpec([]Id{*idPtr("C", 3)}, intType, []Expr{newBinary(Plus, idPrimary("A", 4), numPrimary(1))}),
+		}),
+		newFuncDecl(*idPtr("f", 5), []Param{}, []Type{}, Block{StmtsOrNil: []Stmt{let}}),
+	})
+	got := parsePackageForTest(t, input)
+	if got.String() != want.String() {
+		t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), want.String())
+	}
+}
+
+func TestParser_ConstAndLet_Errors(t *testing.T) {
+	inputs := []string{
+		"const A;",
+		"const A int;",
+		"const ( A; B = 1; )",
+		"const ( )",
+		"func f() { let x := 1; }",
+		"func f() { let x; }",
+	}
+	for _, input := range inputs {
+		lx := lexer.NewLexer()
+		lx.Set(input)
+		if _, err := NewParser(lx).ParsePackage(); err == nil {
+			t.Fatalf("expected parse error for %q", input)
+		}
+	}
+}
+
+func TestParser_ForRange_Errors(t *testing.T) {
+	inputs := []string{
+		"func f() { for a, b, c := range xs { } }",
+		"func f() { for v = range xs { } }",
+		"func f() { yield; }",
+	}
+	for _, input := range inputs {
+		lx := lexer.NewLexer()
+		lx.Set(input)
+		if _, err := NewParser(lx).ParsePackage(); err == nil {
+			t.Fatalf("expected parse error for %q", input)
+		}
+	}
+}
+
+func TestParser_ExprSwitch(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *PackageAST
+	}{
+		{
+			name:  "tag_switch_with_init_and_fallthrough",
+			input: "func f() { switch n := g(); n { case 1, 2: fallthrough; default: print(\"x\"); } }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newSwitch(
+							newShortDecl([]Id{*idPtr("n", 1)}, []Expr{newCall(*idPrimary("g", 2), []Args{{}})}),
+							idPrimary("n", 3),
+							[]CaseClause{
+								{Exprs: []Expr{numPrimary(1), numPrimary(2)}, Block: *newBlock([]Stmt{}), Fallthrough: true},
+								{IsDefault: true, Block: *newBlock([]Stmt{newCallStmt(*newCall(*idPrimary("print", 4), []Args{{strPrimary("x")}}))})},
+							},
+						),
+					}},
+				),
+			}),
+		},
+		{
+			// 마지막 절의 fallthrough는 리졸버가 위치와 함께 거부하도록 절의 속성으로 남김
+			name:  "final_clause_fallthrough_left_to_resolver",
+			input: "func f(n int) { switch n { case 1: fallthrough; } }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{{Id: *idPtr("n", 1), Type: Type{TypeKind: IntType}}},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newSwitch(nil, idPrimary("n", 2), []CaseClause{
+							{Exprs: []Expr{numPrimary(1)}, Block: *newBlock([]Stmt{}), Fallthrough: true},
+						}),
+					}},
+				),
+			}),
+		},
+		{
+			name:  "tagless_switch",
+			input: "func f(n int) { switch { case n < 0: break; } }",
+			want: newPackage([]Decl{
+				newFuncDecl(
+					*idPtr("f", 0),
+					[]Param{{Id: *idPtr("n", 1), Type: Type{TypeKind: IntType}}},
+					[]Type{},
+					Block{StmtsOrNil: []Stmt{
+						newSwitch(nil, nil, []CaseClause{
+							{Exprs: []Expr{newBinary(LessThan, idPrimary("n", 2), numPrimary(0))}, Block: *newBlock([]Stmt{newBreak()})},
+						}),
+					}},
+				),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePackageForTest(t, tt.input)
+			gotStr := got.String()
+			wantStr := tt.want.String()
+			if gotStr != wantStr {
+				t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", gotStr, wantStr)
+			}
+		})
+	}
+}
+
+func TestParser_ExprSwitch_Errors(t *testing.T) {
+	inputs := []string{
+		"func f(n int) { switch n { default: default: } }",
+		"func f(n int) { switch n { print(\"x\"); } }",
+	}
+	for _, input := range inputs {
+		lx := lexer.NewLexer()
+		lx.Set(input)
+		if _, err := NewParser(lx).ParsePackage(); err == nil {
+			t.Fatalf("expected parse error for %q", input)
+		}
+	}
+}
+
+func TestParser_SumTypeAndMatch(t *testing.T) {
+	input := "type Shape = Circle(r int) | Empty; func f(s Shape) int { return match s { Circle(r) if r > 0 => r, Circle(_) => -1, Empty => 0, }; }"
+	want := newPackage([]Decl{
+		newTypeDecl(*idPtr("Shape", 0), *newSumTypeOf(newSumType([]Variant{
+			*newVariant(*idPtr("Circle", 1), []Field{*newField(*idPtr("r", 2), Type{TypeKind: IntType})}),
+			*newVariant(*idPtr("Empty", 3), []Field{}),
+		}))),
+		newFuncDecl(
+			*idPtr("f", 4),
+			[]Param{{Id: *idPtr("s", 5), Type: *newNamedType(*idPtr("Shape", 6))}},
+			[]Type{{TypeKind: IntType}},
+			Block{StmtsOrNil: []Stmt{
+				newReturn([]Expr{newPrimary(ExprPrimary, newMatch(idPrimary("s", 7), []MatchArm{
+					{
+						Pattern:    *newVariantPattern(*idPtr("Circle", 8), []Pattern{*newBindPattern(*idPtr("r", 9))}),
+						GuardOrNil: newBinary(GreaterThan, idPrimary("r", 10), numPrimary(0)),
+						Body:       idPrimary("r", 11),
+					},
+					{
+						Pattern: *newVariantPattern(*idPtr("Circle", 12), []Pattern{*newWildcardPattern()}),
+						Body:    newUnary(MinusUnary, numPrimary(1)),
+					},
+					{Pattern: *newBindPattern(*idPtr("Empty", 13)), Body: numPrimary(0)},
+				}), nil, nil)}),
+			}},
+		),
+	})
+	got := parsePackageForTest(t, input)
+	if got.String() != want.String() {
+		t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), want.String())
+	}
+}
+
+func TestParser_SumTypeAndMatch_Errors(t *testing.T) {
+	inputs := []string{
+		"type S = A | A;",
+		"type S = A(x int) | ;",
+		"func f(n int) int { return match n { }; }",
+		"func f(n int) int { return match n { 1 -> 2 }; }",
+		"func f(n int) int { return match n { -x => 2 }; }",
+	}
+	for _, input := range inputs {
+		lx := lexer.NewLexer()
+		lx.Set(input)
+		if _, err := NewParser(lx).ParsePackage(); err == nil {
+			t.Fatalf("expected parse error for %q", input)
+		}
+	}
+}
+
+func TestParser_Generics(t *testing.T) {
+	input := "type Num interface { int | string; } func Max[K comparable, T, U Num](a T) T { return a; } func f() { g := Max[int, string]; n := xs[i]; }"
+	num := *newNamedType(*idPtr("Num", 5))
+	maxDecl := newFuncDecl(
+		*idPtr("Max", 1),
+		[]Param{{Id: *idPtr("a", 6), Type: *newNamedType(*idPtr("T", 7))}},
+		[]Type{*newNamedType(*idPtr("T", 8))},
+		Block{StmtsOrNil: []Stmt{newReturn([]Expr{idPrimary("a", 9)})}},
+	)
+	maxDecl.TypeParamsOrNil = []TypeParam{
+		*newTypeParam(*idPtr("K", 2), *newInterfaceTypeOf(&InterfaceType{Comparable: true})),
+		*newTypeParam(*idPtr("T", 3), num),
+		*newTypeParam(*idPtr("U", 4), num),
+	}
+	want := newPackage([]Decl{
+		newTypeDecl(*idPtr("Num", 0), *newInterfaceTypeOf(&InterfaceType{UnionOrNil: []Type{{TypeKind: IntType}, {TypeKind: StringType}}})),
+		maxDecl,
+		newFuncDecl(*idPtr("f", 10), []Param{}, []Type{}, Block{StmtsOrNil: []Stmt{
+			newShortDecl([]Id{*idPtr("g", 11)}, []Expr{newInstantiation(idPrimary("Max", 12), []Type{{TypeKind: IntType}, {TypeKind: StringType}})}),
+			newShortDecl([]Id{*idPtr("n", 13)}, []Expr{newIndex(idPrimary("xs", 14), idPrimary("i", 15))}),
+		}}),
+	})
+	got := parsePackageForTest(t, input)
+	if got.String() != want.String() {
+		t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), want.String())
+	}
+}
+
+func TestParser_Generics_Errors(t *testing.T) {
+	inputs := []string{
+		"func F[T](x T) {}",
+		"func F[](x int) {}",
+		"type P struct { n int; } func (p P) M[T any]() {}",
+		"type C interface { int; string; }",
+		"type C interface { int | ; }",
+	}
+	for _, input := range inputs {
+		lx := lexer.NewLexer()
+		lx.Set(input)
+		if _, err := NewParser(lx).ParsePackage(); err == nil {
+			t.Fatalf("expected parse error for %q", input)
+		}
+	}
+}
+
+func TestParser_PackageClauseAndImports(t *testing.T) {
+	got := parsePackageForTest(t, "package main; import \"geo\"; import \"util/mathx\"; var a int = geo.Area(1);")
+	want := newPackage([]Decl{
+		newVarDecl(
+			[]Id{*idPtr("a", 1)},
+			Type{TypeKind: IntType},
+			[]Expr{newCall(*primaryOf(newSelector(idPrimary("geo", 2), *idPtr("Area", 3))), []Args{{numPrimary(1)}})},
+		),
+	})
+	want.NameOrNil = idPtr("main", 0)
+	want.Imports = []Import{*newImport("geo"), *newImport("util/mathx")}
+	if got.String() != want.String() {
+		t.Fatalf("ast mismatch:\n--- got ---\n%s\n--- want ---\n%s", got.String(), want.String())
+	}
+	if got.Imports[1].Name != "mathx" {
+		t.Fatalf("expected import name mathx, got %s", got.Imports[1].Name)
+	}
+}
+
+func TestMergeFiles_RejectsMixedPackages(t *testing.T) {
+	a := parsePackageForTest(t, "package geo; var a int = 1;")
+	b := parsePackageForTest(t, "package shapes; var b int = 1;")
+	if _, err := MergeFiles([]*PackageAST{a, b}); err == nil {
+		t.Fatalf("expected merge error")
+	}
+}
+
+func TestParser_IdPositionsAndComments(t *testing.T) {
+	got := parsePackageForTest(t, "// 합계\nfunc sum(xs []int) int {\n\ttotal := 0; // 누적\n\treturn total;\n}")
+	got.SetFile("sum.tgo")
+	cases := []struct {
+		idId IdId
+		want string
+	}{
+		{0, "sum.tgo:2:6"},
+		{1, "sum.tgo:2:10"},
+		{2, "sum.tgo:3:2"},
+		{3, "sum.tgo:4:9"},
+	}
+	for _, tc := range cases {
+		if pos := got.Positions[tc.idId]; pos.String() != tc.want {
+			t.Fatalf("position of #%d: got %s, want %s", tc.idId, pos, tc.want)
+		}
+	}
+	if len(got.Comments) != 2 || got.Comments[1].Text != "// 누적" || got.Comments[1].Pos.String() != "sum.tgo:3:14" {
+		t.Fatalf("unexpected comments: %v", got.Comments)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// 함수 리터럴 안으로 들어가지 않을 때 방문하는 식별자들
+		want string
+	}{
+		{
+			name:  "decls_and_stmts",
+			input: "const C = a; var v int = b + c; func f(p int) { x := g(p); if y := x; y > 0 { h(y); } else { k(); } }",
+			want:  "a,b,c,g,p,x,y,h,y,k",
+		},
+		{
+			name:  "loops_and_switches",
+			input: "func f() { for i := range xs { i2 := i; } for j := cond; j; j = next(); {} switch t { case u: w(); } switch v := s.(type) { case int: print(v); } }",
+			want:  "xs,i,cond,j,next,t,u,w,s,print,v",
+		},
+		{
+			name:  "match_and_fexp_skipped",
+			input: "func f() { r := match m { 1 if gd => body, _ => func() int { return inner; }() }; }",
+			want:  "m,gd,body",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parsePackageForTest(t, tt.input)
+			got := []string{}
+			depth := 0
+			Inspect(pkg, func(node Node) bool {
+				if node == nil {
+					depth--
+					return true
+				}
+				if _, ok := node.(*Fexp); ok {
+					return false
+				}
+				depth++
+				if p, ok := node.(*Primary); ok && p.PrimaryKind == IdPrimary {
+					got = append(got, p.IdOrNil.Name)
+				}
+				return true
+			})
+			if depth != 0 {
+				t.Fatalf("unbalanced visit(nil) calls: depth %d", depth)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Fatalf("unexpected ids:\n got: %s\nwant: %s", strings.Join(got, ","), tt.want)
+			}
+		})
+	}
+}
@@ -11,6 +11,11 @@ import (
 type Parser struct {
 	tape        *TokenTape
 	idIdCounter *idIdCounter
+	// if, for 헤더에서는 "id {" 를 합성 리터럴이 아닌 블록 시작으로 해석해야 함
+	// (go와 동일하게, 헤더에서 합성 리터럴을 쓰려면 괄호로 감싸야 함)
+	noCompositeLit bool
+	// idOffsets는 발급한 IdId마다 id 토큰이 시작하는 바이트 위치임
+	idOffsets map[IdId]int
 }
 type idIdCounter struct {
 	currentID IdId
@@ -36,11 +41,28 @@ func NewParser(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		tape:        tokenTape,
 		idIdCounter: newIdCounter(-1),
+		idOffsets:   map[IdId]int{},
 	}
 	tokenTape.SetParser(p)
 	return p
 }
 
+// NewParserFrom은 prev가 마지막으로 발급한 IdId 다음부터 IdId를 발급하는 파서를 만듦
+// 여러 파일을 하나의 리졸브 테이블로 다루기 위해 IdId가 파일 간에 겹치지 않아야 하기 때문임
+func NewParserFrom(l *lexer.Lexer, prev *Parser) *Parser {
+	p := NewParser(l)
+	p.idIdCounter.SetCurrentId(prev.idIdCounter.ViewCurrentId())
+	return p
+}
+
+// newIdAt은 토큰 tok으로 새 IdId를 가진 id를 만들고, 그 시작 위치를 기록함
+// 토큰의 Pos는 토큰이 끝난 위치이므로 값의 길이만큼 뺀 것이 시작 위치임
+func (p *Parser) newIdAt(tok token.Token) *Id {
+	id := newId(tok, p.idIdCounter.GetNextID())
+	p.idOffsets[id.IdId] = tok.Pos - len(tok.Value)
+	return id
+}
+
 func (p *Parser) CurrentToken() token.Token {
 	return p.tape.CurrentToken()
 }
@@ -97,3 +119,11 @@ func IsEof(t token.Token) bool {
 func IsIllegal(t token.Token) bool {
 	return t.Kind == token.ILLLEGAL
 }
+
+// withCompositeLit은 합성 리터럴 허용 여부를 allowed로 바꾼 채 parse를 실행하고 원복함
+func withCompositeLit[T any](p *Parser, allowed bool, parse func() (T, error)) (T, error) {
+	saved := p.noCompositeLit
+	p.noCompositeLit = !allowed
+	defer func() { p.noCompositeLit = saved }()
+	return parse()
+}
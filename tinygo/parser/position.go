@@ -0,0 +1,63 @@
+package parser
+
+import "fmt"
+
+// Pos는 소스에서의 위치임. Line, Col은 1부터 셈
+// File은 파일 이름을 알 수 없으면 ""임
+type Pos struct {
+	File string
+	Line int
+	Col  int
+}
+
+// IsValid는 위치를 알고 있는지 리턴함
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Col)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
+
+// Comment는 소스의 // 주석임
+type Comment struct {
+	Pos  Pos
+	Text string
+}
+
+// PosOf는 id가 소스에서 시작하는 위치를 리턴함. 모르는 id라면 유효하지 않은 위치임
+func (p *PackageAST) PosOf(id Id) Pos {
+	return p.Positions[id.IdId]
+}
+
+// SetFile은 패키지의 모든 위치와 주석에 파일 이름을 붙임
+func (p *PackageAST) SetFile(name string) {
+	for idId, pos := range p.Positions {
+		pos.File = name
+		p.Positions[idId] = pos
+	}
+	for i := range p.Comments {
+		p.Comments[i].Pos.File = name
+	}
+}
+
+// recordPositions는 파서가 기록한 id의 시작 위치들과 렉서가 건너뛴 주석들을 pkg에 줄과 칸으로 옮김
+func (p *Parser) recordPositions(pkg *PackageAST) {
+	lx := p.tape.lexer
+	pkg.Positions = make(map[IdId]Pos, len(p.idOffsets))
+	for idId, offset := range p.idOffsets {
+		line, col := lx.Position(offset)
+		pkg.Positions[idId] = Pos{Line: line, Col: col}
+	}
+	pkg.Comments = []Comment{}
+	for _, comment := range lx.Comments() {
+		line, col := lx.Position(comment.Offset)
+		pkg.Comments = append(pkg.Comments, Comment{Pos: Pos{Line: line, Col: col}, Text: comment.Text})
+	}
+}
@@ -0,0 +1,225 @@
+package persistent
+
+import "math/bits"
+
+// Map은 해시 배열 매핑 트라이(HAMT)로 이루어진 영속 해시 맵임
+// 해시의 5비트씩을 한 단계의 인덱스로 쓰며, 각 노드는 비트맵으로 실제 있는 자리만 저장함
+// 해시가 완전히 같은 키들은 맨 아래의 충돌 노드에 모음
+// 키의 해시는 호출자가 계산해 넘기며, 같은 키는 항상 같은 해시를 가져야 함
+type Map[K any, V any] struct {
+	count int
+	root  *hnode[K, V]
+	equal func(a, b K) bool
+}
+
+// hnode는 비트맵 노드이거나, collision이 true라면 같은 해시를 가진 잎들의 충돌 노드임
+type hnode[K any, V any] struct {
+	bitmap    uint32
+	entries   []hentry[K, V]
+	collision bool
+}
+
+// hentry는 child가 nil이면 키, 값을 가진 잎이고, 아니면 하위 노드를 가리킴
+type hentry[K any, V any] struct {
+	hash  uint32
+	key   K
+	value V
+	child *hnode[K, V]
+}
+
+// 해시 32비트를 다 쓰고 나면 더 내려갈 수 없으므로 충돌 노드를 만듦
+const maxShift = 30
+
+func NewMap[K any, V any](equal func(a, b K) bool) *Map[K, V] {
+	return &Map[K, V]{root: &hnode[K, V]{}, equal: equal}
+}
+
+func (m *Map[K, V]) Len() int {
+	return m.count
+}
+
+func (m *Map[K, V]) Get(hash uint32, key K) (V, bool) {
+	node := m.root
+	for shift := uint(0); ; shift += levelBits {
+		if node.collision {
+			for _, entry := range node.entries {
+				if m.equal(entry.key, key) {
+					return entry.value, true
+				}
+			}
+			break
+		}
+		bit := bitFor(hash, shift)
+		if node.bitmap&bit == 0 {
+			break
+		}
+		entry := node.entries[indexFor(node.bitmap, bit)]
+		if entry.child == nil {
+			if entry.hash == hash && m.equal(entry.key, key) {
+				return entry.value, true
+			}
+			break
+		}
+		node = entry.child
+	}
+	var zero V
+	return zero, false
+}
+
+// Assoc은 key를 value로 묶은 새 맵을 리턴함
+func (m *Map[K, V]) Assoc(hash uint32, key K, value V) *Map[K, V] {
+	root, added := m.assocNode(m.root, 0, hentry[K, V]{hash: hash, key: key, value: value})
+	count := m.count
+	if added {
+		count++
+	}
+	return &Map[K, V]{count: count, root: root, equal: m.equal}
+}
+
+func (m *Map[K, V]) assocNode(node *hnode[K, V], shift uint, leaf hentry[K, V]) (*hnode[K, V], bool) {
+	if node.collision {
+		for i, entry := range node.entries {
+			if m.equal(entry.key, leaf.key) {
+				return &hnode[K, V]{entries: replaced(node.entries, i, leaf), collision: true}, false
+			}
+		}
+		return &hnode[K, V]{entries: inserted(node.entries, len(node.entries), leaf), collision: true}, true
+	}
+	bit := bitFor(leaf.hash, shift)
+	idx := indexFor(node.bitmap, bit)
+	if node.bitmap&bit == 0 {
+		return &hnode[K, V]{bitmap: node.bitmap | bit, entries: inserted(node.entries, idx, leaf)}, true
+	}
+	entry := node.entries[idx]
+	var next hentry[K, V]
+	added := true
+	switch {
+	case entry.child != nil:
+		child, childAdded := m.assocNode(entry.child, shift+levelBits, leaf)
+		next, added = hentry[K, V]{child: child}, childAdded
+	case entry.hash == leaf.hash && m.equal(entry.key, leaf.key):
+		next, added = leaf, false
+	default:
+		next = hentry[K, V]{child: mergeLeaves(shift+levelBits, entry, leaf)}
+	}
+	return &hnode[K, V]{bitmap: node.bitmap, entries: replaced(node.entries, idx, next)}, added
+}
+
+// mergeLeaves는 같은 자리에 온 두 잎을 담는 하위 노드를 만듦
+func mergeLeaves[K any, V any](shift uint, a, b hentry[K, V]) *hnode[K, V] {
+	if a.hash == b.hash || shift > maxShift {
+		return &hnode[K, V]{entries: []hentry[K, V]{a, b}, collision: true}
+	}
+	aBit, bBit := bitFor(a.hash, shift), bitFor(b.hash, shift)
+	if aBit == bBit {
+		return &hnode[K, V]{bitmap: aBit, entries: []hentry[K, V]{{child: mergeLeaves(shift+levelBits, a, b)}}}
+	}
+	if aBit > bBit {
+		a, b = b, a
+	}
+	return &hnode[K, V]{bitmap: aBit | bBit, entries: []hentry[K, V]{a, b}}
+}
+
+// Dissoc은 key를 뺀 새 맵을 리턴함. key가 없다면 원래 맵을 그대로 리턴함
+func (m *Map[K, V]) Dissoc(hash uint32, key K) *Map[K, V] {
+	root, removed := m.dissocNode(m.root, 0, hash, key)
+	if !removed {
+		return m
+	}
+	if root == nil {
+		root = &hnode[K, V]{}
+	}
+	return &Map[K, V]{count: m.count - 1, root: root, equal: m.equal}
+}
+
+// dissocNode는 key를 뺀 노드를 리턴하며, 노드가 비게 되면 nil을 리턴함
+func (m *Map[K, V]) dissocNode(node *hnode[K, V], shift uint, hash uint32, key K) (*hnode[K, V], bool) {
+	if node.collision {
+		for i, entry := range node.entries {
+			if m.equal(entry.key, key) {
+				return &hnode[K, V]{entries: removedAt(node.entries, i), collision: true}, true
+			}
+		}
+		return node, false
+	}
+	bit := bitFor(hash, shift)
+	if node.bitmap&bit == 0 {
+		return node, false
+	}
+	idx := indexFor(node.bitmap, bit)
+	entry := node.entries[idx]
+	if entry.child == nil {
+		if entry.hash != hash || !m.equal(entry.key, key) {
+			return node, false
+		}
+		if len(node.entries) == 1 {
+			return nil, true
+		}
+		return &hnode[K, V]{bitmap: node.bitmap &^ bit, entries: removedAt(node.entries, idx)}, true
+	}
+	child, removed := m.dissocNode(entry.child, shift+levelBits, hash, key)
+	if !removed {
+		return node, false
+	}
+	switch {
+	case child == nil:
+		if len(node.entries) == 1 {
+			return nil, true
+		}
+		return &hnode[K, V]{bitmap: node.bitmap &^ bit, entries: removedAt(node.entries, idx)}, true
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// 잎 하나만 남은 하위 노드는 잎으로 끌어올려 트라이를 얕게 유지함
+		return &hnode[K, V]{bitmap: node.bitmap, entries: replaced(node.entries, idx, child.entries[0])}, true
+	default:
+		return &hnode[K, V]{bitmap: node.bitmap, entries: replaced(node.entries, idx, hentry[K, V]{child: child})}, true
+	}
+}
+
+// Each는 모든 키, 값을 순회함. 순서는 해시에 따라 정해지며, f가 false를 리턴하면 멈춤
+func (m *Map[K, V]) Each(f func(key K, value V) bool) {
+	eachNode(m.root, f)
+}
+
+func eachNode[K any, V any](node *hnode[K, V], f func(key K, value V) bool) bool {
+	for _, entry := range node.entries {
+		if entry.child != nil {
+			if !eachNode(entry.child, f) {
+				return false
+			}
+			continue
+		}
+		if !f(entry.key, entry.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func bitFor(hash uint32, shift uint) uint32 {
+	return 1 << ((hash >> shift) & mask)
+}
+
+// indexFor는 비트맵에서 bit보다 아래에 있는 자리 수, 즉 entries 안의 인덱스를 리턴함
+func indexFor(bitmap uint32, bit uint32) int {
+	return bits.OnesCount32(bitmap & (bit - 1))
+}
+
+func inserted[E any](entries []E, idx int, entry E) []E {
+	out := make([]E, len(entries)+1)
+	copy(out, entries[:idx])
+	out[idx] = entry
+	copy(out[idx+1:], entries[idx:])
+	return out
+}
+
+func replaced[E any](entries []E, idx int, entry E) []E {
+	out := append([]E{}, entries...)
+	out[idx] = entry
+	return out
+}
+
+func removedAt[E any](entries []E, idx int) []E {
+	out := make([]E, 0, len(entries)-1)
+	out = append(out, entries[:idx]...)
+	return append(out, entries[idx+1:]...)
+}
@@ -0,0 +1,229 @@
+package persistent
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func vectorToSlice[T any](v *Vector[T]) []T {
+	out := []T{}
+	v.Each(func(_ int, x T) bool {
+		out = append(out, x)
+		return true
+	})
+	return out
+}
+
+func TestVector_MatchesSliceModel(t *testing.T) {
+	// 3단계 트라이가 만들어질 만큼 원소를 넣음 (32*32+32 를 넘김)
+	const n = 40000
+	v := NewVector[int]()
+	model := []int{}
+	versions := []*Vector[int]{}
+	for i := 0; i < n; i++ {
+		v = v.Conj(i)
+		model = append(model, i)
+		if i%5000 == 0 {
+			versions = append(versions, v)
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	for k := 0; k < 2000; k++ {
+		i := rng.Intn(n)
+		next, ok := v.Assoc(i, -i)
+		if !ok {
+			t.Fatalf("assoc %d failed", i)
+		}
+		v = next
+		model[i] = -i
+	}
+	if v.Len() != len(model) {
+		t.Fatalf("len mismatch: got %d want %d", v.Len(), len(model))
+	}
+	for i, want := range model {
+		got, ok := v.Get(i)
+		if !ok || got != want {
+			t.Fatalf("get(%d): got %d,%v want %d", i, got, ok, want)
+		}
+	}
+	got := vectorToSlice(v)
+	for i := range model {
+		if got[i] != model[i] {
+			t.Fatalf("each mismatch at %d", i)
+		}
+	}
+	// 예전 버전들은 갱신의 영향을 받지 않음
+	for vi, old := range versions {
+		if old.Len() != vi*5000+1 {
+			t.Fatalf("old version %d has len %d", vi, old.Len())
+		}
+		for i := 0; i < old.Len(); i++ {
+			if x, _ := old.Get(i); x != i {
+				t.Fatalf("old version %d changed at %d: %d", vi, i, x)
+			}
+		}
+	}
+}
+
+func TestVector_ZeroValue(t *testing.T) {
+	var zero Vector[int]
+	if zero.Len() != 0 {
+		t.Fatalf("zero vector must be empty, got len %d", zero.Len())
+	}
+	if _, ok := zero.Get(0); ok {
+		t.Fatalf("expected get on zero vector to fail")
+	}
+	// 꼬리를 넘겨 트라이가 만들어지고, 루트가 한 단계 높아질 만큼 붙임
+	v := &zero
+	const n = width*width + width + 1
+	for i := 0; i < n; i++ {
+		v = v.Conj(i)
+	}
+	next, ok := v.Assoc(0, -1)
+	if !ok {
+		t.Fatalf("assoc on vector grown from zero value failed")
+	}
+	for i := 0; i < n; i++ {
+		want := i
+		if i == 0 {
+			want = -1
+		}
+		if got, _ := next.Get(i); got != want {
+			t.Fatalf("get(%d): got %d want %d", i, got, want)
+		}
+	}
+	if zero.Len() != 0 {
+		t.Fatalf("zero vector changed to len %d", zero.Len())
+	}
+}
+
+func TestVector_Bounds(t *testing.T) {
+	v := NewVector(1, 2, 3)
+	if _, ok := v.Get(3); ok {
+		t.Fatalf("expected out of range get to fail")
+	}
+	if _, ok := v.Get(-1); ok {
+		t.Fatalf("expected negative get to fail")
+	}
+	if _, ok := v.Assoc(4, 0); ok {
+		t.Fatalf("expected assoc past end to fail")
+	}
+	appended, ok := v.Assoc(3, 4)
+	if !ok || appended.Len() != 4 || v.Len() != 3 {
+		t.Fatalf("assoc at len must append without touching original")
+	}
+}
+
+// weakHash는 충돌 노드를 시험하기 위해 일부러 많은 키가 같은 해시를 갖게 함
+func weakHash(k int) uint32 {
+	return uint32(k % 7)
+}
+
+func fullHash(k int) uint32 {
+	h := uint32(k) * 2654435761
+	return h ^ (h >> 16)
+}
+
+func TestMap_MatchesMapModel(t *testing.T) {
+	for name, hash := range map[string]func(int) uint32{"full": fullHash, "weak": weakHash} {
+		t.Run(name, func(t *testing.T) {
+			m := NewMap[int, int](func(a, b int) bool { return a == b })
+			model := map[int]int{}
+			rng := rand.New(rand.NewSource(2))
+			var snapshot *Map[int, int]
+			snapshotModel := map[int]int{}
+			for step := 0; step < 20000; step++ {
+				k := rng.Intn(3000)
+				if rng.Intn(3) == 0 {
+					m = m.Dissoc(hash(k), k)
+					delete(model, k)
+				} else {
+					m = m.Assoc(hash(k), k, step)
+					model[k] = step
+				}
+				if step == 10000 {
+					snapshot = m
+					for k, v := range model {
+						snapshotModel[k] = v
+					}
+				}
+			}
+			assertMapEquals(t, m, model, hash)
+			// 스냅숏 이후의 갱신은 스냅숏에 보이지 않음
+			assertMapEquals(t, snapshot, snapshotModel, hash)
+		})
+	}
+}
+
+func assertMapEquals(t *testing.T, m *Map[int, int], model map[int]int, hash func(int) uint32) {
+	t.Helper()
+	if m.Len() != len(model) {
+		t.Fatalf("len mismatch: got %d want %d", m.Len(), len(model))
+	}
+	for k, want := range model {
+		got, ok := m.Get(hash(k), k)
+		if !ok || got != want {
+			t.Fatalf("get(%d): got %d,%v want %d", k, got, ok, want)
+		}
+	}
+	seen := 0
+	m.Each(func(k, v int) bool {
+		seen++
+		if model[k] != v {
+			t.Fatalf("each yielded %d=%d, model has %d", k, v, model[k])
+		}
+		return true
+	})
+	if seen != len(model) {
+		t.Fatalf("each visited %d entries, want %d", seen, len(model))
+	}
+}
+
+func TestMap_DissocMissingKeyReturnsSameMap(t *testing.T) {
+	m := NewMap[int, int](func(a, b int) bool { return a == b }).Assoc(fullHash(1), 1, 1)
+	if m.Dissoc(fullHash(2), 2) != m {
+		t.Fatalf("dissoc of missing key must return the same map")
+	}
+}
+
+func TestPersistent_ConcurrentReaders(t *testing.T) {
+	// 공유된 버전에서 여러 고루틴이 각자 새 버전을 만들어도 서로 영향을 주지 않음
+	base := NewMap[int, int](func(a, b int) bool { return a == b })
+	vec := NewVector[int]()
+	for i := 0; i < 1000; i++ {
+		base = base.Assoc(fullHash(i), i, i)
+		vec = vec.Conj(i)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			m, v := base, vec
+			for i := 0; i < 1000; i++ {
+				m = m.Assoc(fullHash(i), i, g)
+				v, _ = v.Assoc(i, g)
+			}
+			for i := 0; i < 1000; i++ {
+				if x, _ := m.Get(fullHash(i), i); x != g {
+					t.Errorf("goroutine %d saw %d", g, x)
+					return
+				}
+				if x, _ := v.Get(i); x != g {
+					t.Errorf("goroutine %d saw %d in vector", g, x)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	for i := 0; i < 1000; i++ {
+		if x, _ := base.Get(fullHash(i), i); x != i {
+			t.Fatalf("base map changed at %d", i)
+		}
+		if x, _ := vec.Get(i); x != i {
+			t.Fatalf("base vector changed at %d", i)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package persistent
+
+// 불변(영속) 컬렉션들
+// 갱신 연산은 원본을 바꾸지 않고 새 컬렉션을 리턴하며, 바뀐 경로의 노드만 새로 만들고 나머지는 원본과 공유함
+// 만들어진 노드는 다시 바뀌지 않으므로, 락 없이 여러 고루틴에서 공유해도 안전함
+
+const (
+	levelBits = 5
+	width     = 1 << levelBits
+	mask      = width - 1
+)
+
+// Vector는 32갈래 트라이와 꼬리 버퍼로 이루어진 영속 벡터임 (Clojure의 PersistentVector와 같은 구조)
+// 마지막 32개 이하의 원소는 꼬리에 두어, 뒤에 붙이는 연산 대부분이 꼬리만 복사하고 끝나게 함
+// 조회, 갱신은 O(log32 n)이며, 제로값은 빈 벡터임
+type Vector[T any] struct {
+	count int
+	shift uint
+	root  *vnode[T]
+	tail  []T
+}
+
+// vnode는 내부 노드라면 children을, 잎 노드라면 values를 가짐
+type vnode[T any] struct {
+	children []*vnode[T]
+	values   []T
+}
+
+func NewVector[T any](elems ...T) *Vector[T] {
+	v := &Vector[T]{shift: levelBits, root: &vnode[T]{}}
+	for _, elem := range elems {
+		v = v.Conj(elem)
+	}
+	return v
+}
+
+func (v *Vector[T]) Len() int {
+	return v.count
+}
+
+// tailOffset은 꼬리에 담긴 첫 원소의 인덱스임
+func (v *Vector[T]) tailOffset() int {
+	if v.count < width {
+		return 0
+	}
+	return ((v.count - 1) >> levelBits) << levelBits
+}
+
+// leafFor는 i번째 원소를 담은 잎(또는 꼬리)을 리턴함
+func (v *Vector[T]) leafFor(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	node := v.root
+	for level := v.shift; level > 0; level -= levelBits {
+		node = node.children[(i>>level)&mask]
+	}
+	return node.values
+}
+
+// Get은 i번째 원소를 리턴함. 범위 밖이면 ok가 false임
+func (v *Vector[T]) Get(i int) (T, bool) {
+	if i < 0 || i >= v.count {
+		var zero T
+		return zero, false
+	}
+	return v.leafFor(i)[i&mask], true
+}
+
+// Conj는 x를 뒤에 붙인 새 벡터를 리턴함
+func (v *Vector[T]) Conj(x T) *Vector[T] {
+	if v.count-v.tailOffset() < width {
+		tail := make([]T, len(v.tail)+1)
+		copy(tail, v.tail)
+		tail[len(v.tail)] = x
+		return &Vector[T]{count: v.count + 1, shift: v.shift, root: v.root, tail: tail}
+	}
+	// 꼬리가 가득 찼으므로 트라이에 밀어 넣고 새 꼬리를 시작함
+	tailNode := &vnode[T]{values: v.tail}
+	shift, root := v.shift, v.root
+	if root == nil {
+		// 제로값에서 시작한 벡터는 처음 트라이가 필요할 때 빈 루트를 만듦
+		shift, root = levelBits, &vnode[T]{}
+	}
+	if (v.count >> levelBits) > (1 << shift) {
+		// 루트가 가득 찼으므로 한 단계 높은 루트를 만듦
+		root = &vnode[T]{children: []*vnode[T]{root, newPath(shift, tailNode)}}
+		shift += levelBits
+	} else {
+		root = v.pushTail(shift, root, tailNode)
+	}
+	return &Vector[T]{count: v.count + 1, shift: shift, root: root, tail: []T{x}}
+}
+
+func (v *Vector[T]) pushTail(level uint, parent *vnode[T], tailNode *vnode[T]) *vnode[T] {
+	subIdx := ((v.count - 1) >> level) & mask
+	var child *vnode[T]
+	if level == levelBits {
+		child = tailNode
+	} else if subIdx < len(parent.children) {
+		child = v.pushTail(level-levelBits, parent.children[subIdx], tailNode)
+	} else {
+		child = newPath(level-levelBits, tailNode)
+	}
+	return &vnode[T]{children: withChild(parent.children, subIdx, child)}
+}
+
+// newPath는 잎 하나만 매달린 level 높이의 경로를 만듦
+func newPath[T any](level uint, leaf *vnode[T]) *vnode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &vnode[T]{children: []*vnode[T]{newPath(level-levelBits, leaf)}}
+}
+
+// Assoc은 i번째 원소를 x로 바꾼 새 벡터를 리턴함. i가 길이와 같으면 Conj와 같음
+// 범위 밖이면 ok가 false임
+func (v *Vector[T]) Assoc(i int, x T) (*Vector[T], bool) {
+	if i == v.count {
+		return v.Conj(x), true
+	}
+	if i < 0 || i > v.count {
+		return v, false
+	}
+	if i >= v.tailOffset() {
+		tail := append([]T{}, v.tail...)
+		tail[i&mask] = x
+		return &Vector[T]{count: v.count, shift: v.shift, root: v.root, tail: tail}, true
+	}
+	return &Vector[T]{count: v.count, shift: v.shift, root: assocNode(v.shift, v.root, i, x), tail: v.tail}, true
+}
+
+func assocNode[T any](level uint, node *vnode[T], i int, x T) *vnode[T] {
+	if level == 0 {
+		values := append([]T{}, node.values...)
+		values[i&mask] = x
+		return &vnode[T]{values: values}
+	}
+	subIdx := (i >> level) & mask
+	child := assocNode(level-levelBits, node.children[subIdx], i, x)
+	return &vnode[T]{children: withChild(node.children, subIdx, child)}
+}
+
+// withChild는 children의 idx 자리를 child로 바꾸거나(idx가 길이와 같으면 덧붙여) 새 슬라이스로 리턴함
+func withChild[T any](children []*vnode[T], idx int, child *vnode[T]) []*vnode[T] {
+	size := len(children)
+	if idx == size {
+		size++
+	}
+	out := make([]*vnode[T], size)
+	copy(out, children)
+	out[idx] = child
+	return out
+}
+
+// Each는 앞에서부터 원소를 순회함. f가 false를 리턴하면 멈춤
+func (v *Vector[T]) Each(f func(i int, x T) bool) {
+	for i := 0; i < v.count; i += width {
+		leaf := v.leafFor(i)
+		for j, x := range leaf {
+			if !f(i+j, x) {
+				return
+			}
+		}
+	}
+}
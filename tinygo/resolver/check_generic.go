@@ -0,0 +1,858 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+	"github.com/rlaaudgjs5638/langTest/tinygo/token"
+)
+
+// 제네릭 함수의 인스턴스화를 정적으로 검사하는 함수들
+// tiny go는 일반적인 정적 타입 검사가 없으므로, 인자의 타입은 리터럴, 선언된 타입 등에서 알 수 있는 만큼만 추론함
+// 정적으로 알 수 없는 타입 인자는 evaluator가 호출 시점에 인자 값으로부터 추론함
+
+// recordFuncDecl은 함수 선언을 제네릭 검사에 쓰일 정보로 기록함
+func (r *Resolver) recordFuncDecl(node *parser.FuncDecl) {
+	if len(node.TypeParamsOrNil) > 0 {
+		r.genericFuncs[node.Id.IdId] = node
+		return
+	}
+	r.declTypes[node.Id.IdId] = *funcTypeOf(node.ParamsOrNil, node.ReturnTypesOrNil)
+}
+
+func funcTypeOf(params []parser.Param, returnTypes []parser.Type) *parser.Type {
+	argTypes := make([]parser.Type, len(params))
+	for i, param := range params {
+		argTypes[i] = param.Type
+	}
+	return &parser.Type{TypeKind: parser.FuncionType, FuncTypeOrNil: &parser.FuncType{ArgTypesOrNil: argTypes, ReturnTypesOrNil: returnTypes}}
+}
+
+// declareTypeParams는 타입 매개변수들을 함수 스코프에 선언한 뒤 제약을 리졸빙함
+// 모두 선언한 뒤에 제약을 리졸빙하므로, 제약 안에서 다른 타입 매개변수를 쓸 수 있음
+func (r *Resolver) declareTypeParams(typeParams []parser.TypeParam) error {
+	for _, tp := range typeParams {
+		sym, err := r.declare(tp.Id.Name, SymbolTypeParam, tp.Id.IdId)
+		if err != nil {
+			return newResolveErr(tp.Id, err.Error())
+		}
+		r.setResolved(tp.Id, r.refFromSymbol(sym))
+	}
+	for _, tp := range typeParams {
+		if err := r.resolveConstraint(tp.Constraint); err != nil {
+			return err
+		}
+		r.typeParamConstraints[tp.Id.IdId] = tp.Constraint
+	}
+	return nil
+}
+
+// isTypeName은 expr이 타입 이름 하나인지 여부임
+func (r *Resolver) isTypeName(expr parser.Expr) bool {
+	primary, ok := expr.(*parser.Primary)
+	if !ok || primary.PrimaryKind != parser.IdPrimary {
+		return false
+	}
+	sym := r.lookup(primary.IdOrNil.Name)
+	return sym != nil && (sym.kind == SymbolType || sym.kind == SymbolTypeParam)
+}
+
+// resolveInstantiation은 f[int, string] 의 타입 인자들을 리졸빙하고 f의 타입 매개변수에 맞는지 검사함
+// 호출되는 경우 모자란 타입 인자는 인자로부터 추론하므로, 개수가 모자란 것은 허용함
+func (r *Resolver) resolveInstantiation(node *parser.Index) error {
+	for _, typeArg := range node.TypeArgsOrNil {
+		if err := r.resolveType(typeArg); err != nil {
+			return err
+		}
+	}
+	decl, calleeId, ok := r.genericCalleeOf(node.Object)
+	if !ok {
+		if id, hasId := calleeIdOf(node.Object); hasId {
+			return newResolveErr(id, fmt.Sprintf("%s is not a generic function", id.Name))
+		}
+		return errors.New("cannot instantiate non-generic function")
+	}
+	if len(node.TypeArgsOrNil) > len(decl.TypeParamsOrNil) {
+		return newResolveErr(calleeId, fmt.Sprintf("got %d type arguments but %s has %d type parameters", len(node.TypeArgsOrNil), calleeId.Name, len(decl.TypeParamsOrNil)))
+	}
+	for i, typeArg := range node.TypeArgsOrNil {
+		if err := r.checkSatisfies(calleeId, typeArg, decl.TypeParamsOrNil[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGenericCall은 제네릭 함수 호출의 타입 인자를 명시된 것과 인자의 정적 타입으로부터 추론하고 제약을 검사함
+// 모든 타입 인자를 추론했다면 호출 노드에 기록하여 evaluator가 그대로 쓰게 함
+func (r *Resolver) checkGenericCall(call *parser.Call) error {
+	var target parser.Expr = &call.PrimaryOrNil
+	var explicit []parser.Type
+	if call.PrimaryOrNil.PrimaryKind == parser.ExprPrimary {
+		if index, ok := call.PrimaryOrNil.ExprOrNil.(*parser.Index); ok && index.TypeArgsOrNil != nil {
+			target, explicit = index.Object, index.TypeArgsOrNil
+		}
+	}
+	decl, calleeId, ok := r.genericCalleeOf(target)
+	if !ok || len(call.ArgsList) == 0 {
+		return nil
+	}
+	bound := map[parser.IdId]parser.Type{}
+	for i, typeArg := range explicit {
+		bound[decl.TypeParamsOrNil[i].Id.IdId] = typeArg
+	}
+	args := call.ArgsList[0]
+	// 다중 값 인자나 파이프로 인자 개수가 맞지 않는 호출은 런타임 추론에 맡김
+	if len(args) == len(decl.ParamsOrNil) {
+		for i, param := range decl.ParamsOrNil {
+			argType, ok := r.staticType(args[i])
+			if !ok {
+				continue
+			}
+			if err := r.unifyTypes(calleeId, i, param.Type, argType, decl, bound); err != nil {
+				return err
+			}
+		}
+	}
+	typeArgs := make([]parser.Type, len(decl.TypeParamsOrNil))
+	complete := true
+	for i, tp := range decl.TypeParamsOrNil {
+		typeArg, ok := bound[tp.Id.IdId]
+		if !ok {
+			if !r.paramsMention(decl.ParamsOrNil, tp.Id.IdId) {
+				return newResolveErr(calleeId, fmt.Sprintf("in call to %s, cannot infer %s", calleeId.Name, tp.Id.Name))
+			}
+			complete = false
+			continue
+		}
+		if err := r.checkSatisfies(calleeId, typeArg, tp); err != nil {
+			return err
+		}
+		typeArgs[i] = typeArg
+	}
+	if complete {
+		call.TypeArgsOrNil = typeArgs
+	}
+	return nil
+}
+
+// typeParamOps는 타입 매개변수 값에 쓸 때 타입 집합을 검사하는 연산자들과, 각 연산자를 지원하는 타입들임
+// evaluator와 같이 +는 int와 string, 나머지 산술과 대소 비교는 int만 지원함
+var typeParamOps = map[parser.BinaryKind][]parser.TypeKind{
+	parser.Plus:           {parser.IntType, parser.StringType},
+	parser.MinusBinary:    {parser.IntType},
+	parser.Mul:            {parser.IntType},
+	parser.Div:            {parser.IntType},
+	parser.LessThan:       {parser.IntType},
+	parser.LessOrEqual:    {parser.IntType},
+	parser.GreaterThan:    {parser.IntType},
+	parser.GreaterOrEqual: {parser.IntType},
+}
+
+// checkTypeParamOperands는 타입 매개변수 타입의 피연산자에 쓴 연산자를, 타입 집합의 모든 타입이 지원하는지 검사함
+// 제네릭 함수 본문은 인스턴스화와 따로 검사되므로, 제약이 허용하는 어떤 타입 인자로도 런타임 에러가 나지 않아야 함
+func (r *Resolver) checkTypeParamOperands(node *parser.Binary) error {
+	supported, ok := typeParamOps[node.Op]
+	if !ok {
+		return nil
+	}
+	op := token.StringSpec(token.TokenKind(node.Op))
+	for _, operand := range []parser.Expr{node.LeftExpr, node.RightExpr} {
+		t, ok := r.staticType(operand)
+		if !ok {
+			continue
+		}
+		id, ok := r.typeParamIdOf(t)
+		if !ok {
+			continue
+		}
+		anchor, ok := exprAnchorId(operand)
+		if !ok {
+			anchor = *t.NameOrNil
+		}
+		constraint := r.typeParamConstraints[id]
+		members, bounded := r.typeSetOf(t, map[parser.IdId]bool{})
+		if !bounded {
+			return newResolveErr(anchor, fmt.Sprintf("invalid operation: operator %s not defined on %s (type %s constrained by %s)", op, anchor.Name, t.NameOrNil.Name, r.constraintDisplay(constraint)))
+		}
+		for _, member := range members {
+			if !r.kindIn(member, supported) {
+				return newResolveErr(anchor, fmt.Sprintf("invalid operation: operator %s not defined on %s (type %s constrained by %s: %s does not support %s)", op, anchor.Name, t.NameOrNil.Name, r.constraintDisplay(constraint), r.typeDisplay(member), op))
+			}
+		}
+	}
+	return nil
+}
+
+// typeSetOf는 타입 매개변수 t의 타입 집합을 리턴함
+// 제약이 interface가 아니면 그 타입 하나, union이 있으면 그 타입들이며, union이 없는 interface(any 등)라면 bounded가 false임
+func (r *Resolver) typeSetOf(t parser.Type, seen map[parser.IdId]bool) ([]parser.Type, bool) {
+	id, ok := r.typeParamIdOf(t)
+	if !ok {
+		return []parser.Type{t}, true
+	}
+	if seen[id] {
+		return nil, false
+	}
+	seen[id] = true
+	constraint := r.typeParamConstraints[id]
+	iface, isIface := r.interfaceOf(constraint)
+	if !isIface {
+		return r.typeSetOf(constraint, seen)
+	}
+	if len(iface.UnionOrNil) == 0 {
+		return nil, false
+	}
+	members := []parser.Type{}
+	for _, member := range iface.UnionOrNil {
+		inner, ok := r.typeSetOf(member, seen)
+		if !ok {
+			return nil, false
+		}
+		members = append(members, inner...)
+	}
+	return members, true
+}
+
+// kindIn은 t(이름 있는 타입이라면 그 바탕 타입)의 종류가 kinds 중 하나인지 여부임
+func (r *Resolver) kindIn(t parser.Type, kinds []parser.TypeKind) bool {
+	if t.TypeKind == parser.NamedType {
+		if decl := r.typeDeclOf(r.table[t.NameOrNil.IdId]); decl != nil {
+			t = decl.Type
+		}
+	}
+	for _, kind := range kinds {
+		if t.TypeKind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// exprAnchorId는 에러 위치로 쓸 식 안의 식별자를 리턴함. 변수, 필드, 호출되는 함수, 인덱싱되는 값의 이름임
+func exprAnchorId(expr parser.Expr) (parser.Id, bool) {
+	switch node := expr.(type) {
+	case *parser.Call:
+		return exprAnchorId(&node.PrimaryOrNil)
+	case *parser.Index:
+		return exprAnchorId(node.Object)
+	}
+	return calleeIdOf(expr)
+}
+
+// genericCalleeOf는 expr이 제네릭 함수(또는 임포트한 패키지의 제네릭 함수)를 가리키면 그 선언을 리턴함
+func (r *Resolver) genericCalleeOf(expr parser.Expr) (*parser.FuncDecl, parser.Id, bool) {
+	switch node := expr.(type) {
+	case *parser.Primary:
+		switch node.PrimaryKind {
+		case parser.IdPrimary:
+			ref, ok := r.table[node.IdOrNil.IdId]
+			if !ok {
+				return nil, parser.Id{}, false
+			}
+			decl, ok := r.genericFuncs[ref.RefIdNodeId]
+			return decl, *node.IdOrNil, ok
+		case parser.ExprPrimary:
+			return r.genericCalleeOf(node.ExprOrNil)
+		}
+	case *parser.Selector:
+		ref, ok := r.table[node.Field.IdId]
+		if !ok {
+			return nil, parser.Id{}, false
+		}
+		decl, ok := r.genericFuncs[ref.RefIdNodeId]
+		return decl, node.Field, ok
+	}
+	return nil, parser.Id{}, false
+}
+
+func calleeIdOf(expr parser.Expr) (parser.Id, bool) {
+	switch node := expr.(type) {
+	case *parser.Primary:
+		if node.PrimaryKind == parser.IdPrimary {
+			return *node.IdOrNil, true
+		}
+		if node.PrimaryKind == parser.ExprPrimary {
+			return calleeIdOf(node.ExprOrNil)
+		}
+	case *parser.Selector:
+		return node.Field, true
+	}
+	return parser.Id{}, false
+}
+
+// ownTypeParam은 t가 decl의 타입 매개변수라면 그 선언 id를 리턴함
+func (r *Resolver) ownTypeParam(t parser.Type, decl *parser.FuncDecl) (parser.TypeParam, bool) {
+	if t.TypeKind != parser.NamedType {
+		return parser.TypeParam{}, false
+	}
+	ref := r.table[t.NameOrNil.IdId]
+	if ref.Kind != RefTypeParam {
+		return parser.TypeParam{}, false
+	}
+	for _, tp := range decl.TypeParamsOrNil {
+		if tp.Id.IdId == ref.RefIdNodeId {
+			return tp, true
+		}
+	}
+	return parser.TypeParam{}, false
+}
+
+// unifyTypes는 매개변수 타입 pattern과 인자 타입 actual을 맞춰 보며 타입 매개변수를 묶음
+// 모양이 다른 부분은 건너뛰며, 이미 묶인 타입 매개변수와 다른 타입을 만날 때만 에러임
+func (r *Resolver) unifyTypes(calleeId parser.Id, argIndex int, pattern, actual parser.Type, decl *parser.FuncDecl, bound map[parser.IdId]parser.Type) error {
+	if tp, ok := r.ownTypeParam(pattern, decl); ok {
+		prev, isBound := bound[tp.Id.IdId]
+		if !isBound {
+			bound[tp.Id.IdId] = actual
+			return nil
+		}
+		if !r.staticSameType(prev, actual) {
+			return newResolveErr(calleeId, fmt.Sprintf("type %s of argument %d does not match inferred type %s for %s", r.typeDisplay(actual), argIndex+1, r.typeDisplay(prev), tp.Id.Name))
+		}
+		return nil
+	}
+	if pattern.TypeKind != actual.TypeKind {
+		return nil
+	}
+	switch pattern.TypeKind {
+	case parser.SliceType:
+		return r.unifyTypes(calleeId, argIndex, *pattern.ElemOrNil, *actual.ElemOrNil, decl, bound)
+	case parser.TupleType:
+		if len(pattern.TupleOrNil) != len(actual.TupleOrNil) {
+			return nil
+		}
+		for i := range pattern.TupleOrNil {
+			if err := r.unifyTypes(calleeId, argIndex, pattern.TupleOrNil[i], actual.TupleOrNil[i], decl, bound); err != nil {
+				return err
+			}
+		}
+	case parser.FuncionType:
+		pf, af := pattern.FuncTypeOrNil, actual.FuncTypeOrNil
+		if len(pf.ArgTypesOrNil) != len(af.ArgTypesOrNil) || len(pf.ReturnTypesOrNil) != len(af.ReturnTypesOrNil) {
+			return nil
+		}
+		for i := range pf.ArgTypesOrNil {
+			if err := r.unifyTypes(calleeId, argIndex, pf.ArgTypesOrNil[i], af.ArgTypesOrNil[i], decl, bound); err != nil {
+				return err
+			}
+		}
+		for i := range pf.ReturnTypesOrNil {
+			if err := r.unifyTypes(calleeId, argIndex, pf.ReturnTypesOrNil[i], af.ReturnTypesOrNil[i], decl, bound); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// paramsMention은 매개변수 타입들 중 어딘가에 타입 매개변수 id가 쓰였는지 여부임
+// 매개변수에 쓰이지 않은 타입 매개변수는 명시하지 않는 한 추론할 수 없음
+func (r *Resolver) paramsMention(params []parser.Param, id parser.IdId) bool {
+	for _, param := range params {
+		if r.typeMentions(param.Type, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resolver) typeMentions(t parser.Type, id parser.IdId) bool {
+	switch t.TypeKind {
+	case parser.NamedType:
+		ref := r.table[t.NameOrNil.IdId]
+		return ref.Kind == RefTypeParam && ref.RefIdNodeId == id
+	case parser.SliceType:
+		return r.typeMentions(*t.ElemOrNil, id)
+	case parser.TupleType:
+		for _, elem := range t.TupleOrNil {
+			if r.typeMentions(elem, id) {
+				return true
+			}
+		}
+	case parser.FuncionType:
+		for _, arg := range t.FuncTypeOrNil.ArgTypesOrNil {
+			if r.typeMentions(arg, id) {
+				return true
+			}
+		}
+		for _, ret := range t.FuncTypeOrNil.ReturnTypesOrNil {
+			if r.typeMentions(ret, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// substType은 t 안의 타입 매개변수를 bound에 묶인 타입으로 바꾼 새 타입을 리턴함
+func (r *Resolver) substType(t parser.Type, bound map[parser.IdId]parser.Type) parser.Type {
+	switch t.TypeKind {
+	case parser.NamedType:
+		ref := r.table[t.NameOrNil.IdId]
+		if typeArg, ok := bound[ref.RefIdNodeId]; ok && ref.Kind == RefTypeParam {
+			return typeArg
+		}
+	case parser.SliceType:
+		elem := r.substType(*t.ElemOrNil, bound)
+		return parser.Type{TypeKind: parser.SliceType, ElemOrNil: &elem}
+	case parser.TupleType:
+		elems := make([]parser.Type, len(t.TupleOrNil))
+		for i, elem := range t.TupleOrNil {
+			elems[i] = r.substType(elem, bound)
+		}
+		return parser.Type{TypeKind: parser.TupleType, TupleOrNil: elems}
+	case parser.FuncionType:
+		args := make([]parser.Type, len(t.FuncTypeOrNil.ArgTypesOrNil))
+		for i, arg := range t.FuncTypeOrNil.ArgTypesOrNil {
+			args[i] = r.substType(arg, bound)
+		}
+		returns := make([]parser.Type, len(t.FuncTypeOrNil.ReturnTypesOrNil))
+		for i, ret := range t.FuncTypeOrNil.ReturnTypesOrNil {
+			returns[i] = r.substType(ret, bound)
+		}
+		return parser.Type{TypeKind: parser.FuncionType, FuncTypeOrNil: &parser.FuncType{ArgTypesOrNil: args, ReturnTypesOrNil: returns}}
+	}
+	return t
+}
+
+// checkSatisfies는 타입 인자 typeArg가 타입 매개변수 tp의 제약을 충족하는지 검사함
+func (r *Resolver) checkSatisfies(calleeId parser.Id, typeArg parser.Type, tp parser.TypeParam) error {
+	reason, ok := r.satisfies(typeArg, tp.Constraint)
+	if ok {
+		return nil
+	}
+	msg := fmt.Sprintf("%s does not satisfy %s", r.typeDisplay(typeArg), r.constraintDisplay(tp.Constraint))
+	if reason != "" {
+		msg += " (" + reason + ")"
+	}
+	return newResolveErr(calleeId, msg)
+}
+
+// satisfies는 t가 constraint를 충족하는지와, 충족하지 못한다면 그 이유를 리턴함
+func (r *Resolver) satisfies(t, constraint parser.Type) (string, bool) {
+	iface, ok := r.interfaceOf(constraint)
+	if !ok {
+		// interface가 아닌 제약은 그 타입 자체만 허용함
+		return "", r.staticSameType(t, constraint)
+	}
+	if iface.Comparable && !r.staticComparable(t, map[parser.IdId]bool{}) {
+		return "", false
+	}
+	if len(iface.UnionOrNil) > 0 {
+		if reason, ok := r.inUnion(t, iface.UnionOrNil); !ok {
+			return reason, false
+		}
+	}
+	if len(iface.Methods) > 0 {
+		methods := r.methodSetOf(t)
+		for _, spec := range iface.Methods {
+			method, ok := methods[spec.Id.Name]
+			if !ok {
+				return "missing method " + spec.Id.Name, false
+			}
+			if !r.staticSameFuncType(method, spec.FuncType) {
+				return "wrong type for method " + spec.Id.Name, false
+			}
+		}
+	}
+	return "", true
+}
+
+// inUnion은 t가 union의 타입 중 하나인지 검사함
+// t가 타입 매개변수라면 그 제약의 타입 원소가 모두 union에 들어 있어야 함
+func (r *Resolver) inUnion(t parser.Type, union []parser.Type) (string, bool) {
+	contains := func(t parser.Type) bool {
+		for _, member := range union {
+			if r.staticSameType(t, member) {
+				return true
+			}
+		}
+		return false
+	}
+	missing := fmt.Sprintf("%s missing in %s", r.typeDisplay(t), r.unionDisplay(union))
+	if id, ok := r.typeParamIdOf(t); ok {
+		own, isIface := r.interfaceOf(r.typeParamConstraints[id])
+		if !isIface || len(own.UnionOrNil) == 0 {
+			return missing, false
+		}
+		for _, member := range own.UnionOrNil {
+			if !contains(member) {
+				return fmt.Sprintf("%s missing in %s", r.typeDisplay(member), r.unionDisplay(union)), false
+			}
+		}
+		return "", true
+	}
+	if !contains(t) {
+		return missing, false
+	}
+	return "", true
+}
+
+func (r *Resolver) typeParamIdOf(t parser.Type) (parser.IdId, bool) {
+	if t.TypeKind != parser.NamedType {
+		return 0, false
+	}
+	ref := r.table[t.NameOrNil.IdId]
+	return ref.RefIdNodeId, ref.Kind == RefTypeParam
+}
+
+// interfaceOf는 t가 interface 타입(또는 interface 타입의 이름)이라면 그 본문을 리턴함
+func (r *Resolver) interfaceOf(t parser.Type) (*parser.InterfaceType, bool) {
+	if t.TypeKind == parser.InterfaceTypeKind {
+		return t.InterfaceOrNil, true
+	}
+	if t.TypeKind == parser.NamedType {
+		if decl := r.typeDeclOf(r.table[t.NameOrNil.IdId]); decl != nil && decl.Type.TypeKind == parser.InterfaceTypeKind {
+			return decl.Type.InterfaceOrNil, true
+		}
+	}
+	return nil, false
+}
+
+// methodSetOf는 t로 호출할 수 있는 메서드들의 시그니처를 리턴함
+// 이름 있는 struct는 선언된 메서드, interface와 타입 매개변수는 (제약) interface의 메서드임
+func (r *Resolver) methodSetOf(t parser.Type) map[string]parser.FuncType {
+	methods := map[string]parser.FuncType{}
+	if id, ok := r.typeParamIdOf(t); ok {
+		t = r.typeParamConstraints[id]
+	}
+	if iface, ok := r.interfaceOf(t); ok {
+		for _, spec := range iface.Methods {
+			methods[spec.Id.Name] = spec.FuncType
+		}
+		return methods
+	}
+	if t.TypeKind != parser.NamedType || r.hoist == nil {
+		return methods
+	}
+	ref := r.table[t.NameOrNil.IdId]
+	if ref.Kind != RefType {
+		return methods
+	}
+	for name, methodId := range r.hoist.methodsByType[ref.RefIdNodeId] {
+		decl := r.hoist.getMethodDeclById(methodId)
+		methods[name] = *funcTypeOf(decl.ParamsOrNil, decl.ReturnTypesOrNil).FuncTypeOrNil
+	}
+	return methods
+}
+
+// staticComparable은 t의 값들을 == 로 비교할 수 있는지 여부임
+func (r *Resolver) staticComparable(t parser.Type, seen map[parser.IdId]bool) bool {
+	switch t.TypeKind {
+	case parser.FuncionType, parser.SliceType:
+		return false
+	case parser.NamedType:
+		ref := r.table[t.NameOrNil.IdId]
+		if ref.Kind == RefTypeParam {
+			iface, ok := r.interfaceOf(r.typeParamConstraints[ref.RefIdNodeId])
+			if !ok {
+				return r.staticComparable(r.typeParamConstraints[ref.RefIdNodeId], seen)
+			}
+			if iface.Comparable {
+				return true
+			}
+			if len(iface.UnionOrNil) == 0 {
+				return false
+			}
+			for _, member := range iface.UnionOrNil {
+				if !r.staticComparable(member, seen) {
+					return false
+				}
+			}
+			return true
+		}
+		decl := r.typeDeclOf(ref)
+		if decl == nil || seen[ref.RefIdNodeId] {
+			return true
+		}
+		seen[ref.RefIdNodeId] = true
+		return r.staticComparable(decl.Type, seen)
+	case parser.StructureType:
+		for _, field := range t.StructOrNil.Fields {
+			if !r.staticComparable(field.Type, seen) {
+				return false
+			}
+		}
+	case parser.TupleType:
+		for _, elem := range t.TupleOrNil {
+			if !r.staticComparable(elem, seen) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// staticSameType은 두 타입 표현이 같은 타입인지 비교함. 이름 있는 타입은 가리키는 선언으로 비교함
+func (r *Resolver) staticSameType(left, right parser.Type) bool {
+	if left.TypeKind != right.TypeKind {
+		return false
+	}
+	switch left.TypeKind {
+	case parser.NamedType:
+		lref, rref := r.table[left.NameOrNil.IdId], r.table[right.NameOrNil.IdId]
+		return lref.Kind == rref.Kind && lref.RefIdNodeId == rref.RefIdNodeId
+	case parser.SliceType:
+		return r.staticSameType(*left.ElemOrNil, *right.ElemOrNil)
+	case parser.TupleType:
+		if len(left.TupleOrNil) != len(right.TupleOrNil) {
+			return false
+		}
+		for i := range left.TupleOrNil {
+			if !r.staticSameType(left.TupleOrNil[i], right.TupleOrNil[i]) {
+				return false
+			}
+		}
+		return true
+	case parser.FuncionType:
+		return r.staticSameFuncType(*left.FuncTypeOrNil, *right.FuncTypeOrNil)
+	case parser.StructureType:
+		if len(left.StructOrNil.Fields) != len(right.StructOrNil.Fields) {
+			return false
+		}
+		for i, field := range left.StructOrNil.Fields {
+			other := right.StructOrNil.Fields[i]
+			if field.Id.Name != other.Id.Name || !r.staticSameType(field.Type, other.Type) {
+				return false
+			}
+		}
+		return true
+	case parser.InterfaceTypeKind:
+		li, ri := left.InterfaceOrNil, right.InterfaceOrNil
+		if len(li.Methods) != len(ri.Methods) || len(li.UnionOrNil) != len(ri.UnionOrNil) || li.Comparable != ri.Comparable {
+			return false
+		}
+		for i, method := range li.Methods {
+			if method.Id.Name != ri.Methods[i].Id.Name || !r.staticSameFuncType(method.FuncType, ri.Methods[i].FuncType) {
+				return false
+			}
+		}
+		for i := range li.UnionOrNil {
+			if !r.staticSameType(li.UnionOrNil[i], ri.UnionOrNil[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+func (r *Resolver) staticSameFuncType(left, right parser.FuncType) bool {
+	if len(left.ArgTypesOrNil) != len(right.ArgTypesOrNil) || len(left.ReturnTypesOrNil) != len(right.ReturnTypesOrNil) {
+		return false
+	}
+	for i := range left.ArgTypesOrNil {
+		if !r.staticSameType(left.ArgTypesOrNil[i], right.ArgTypesOrNil[i]) {
+			return false
+		}
+	}
+	for i := range left.ReturnTypesOrNil {
+		if !r.staticSameType(left.ReturnTypesOrNil[i], right.ReturnTypesOrNil[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// staticType은 expr의 타입을 정적으로 알 수 있다면 이를 리턴함
+// 리터럴, 선언된 타입이 있는 이름, 리턴 타입이 하나인 함수의 호출, 연산 결과 정도만 알 수 있음
+func (r *Resolver) staticType(expr parser.Expr) (parser.Type, bool) {
+	switch node := expr.(type) {
+	case *parser.Primary:
+		return r.staticTypeOfPrimary(node)
+	case *parser.Unary:
+		if node.Op == parser.Not {
+			return parser.Type{TypeKind: parser.BoolType}, true
+		}
+		return parser.Type{TypeKind: parser.IntType}, true
+	case *parser.Binary:
+		switch node.Op {
+		case parser.Plus, parser.MinusBinary, parser.Mul, parser.Div:
+			return r.staticType(node.LeftExpr)
+		case parser.Pipe:
+			return parser.Type{}, false
+		default:
+			return parser.Type{TypeKind: parser.BoolType}, true
+		}
+	case *parser.Call:
+		return r.staticTypeOfCall(node)
+	case *parser.Index:
+		if node.TypeArgsOrNil != nil {
+			return parser.Type{}, false
+		}
+		object, ok := r.staticType(node.Object)
+		if ok && object.TypeKind == parser.SliceType {
+			return *object.ElemOrNil, true
+		}
+	case *parser.Selector:
+		if ref, ok := r.table[node.Field.IdId]; ok {
+			t, ok := r.declTypes[ref.RefIdNodeId]
+			return t, ok
+		}
+		object, ok := r.staticType(node.Object)
+		if !ok {
+			return parser.Type{}, false
+		}
+		if object.TypeKind == parser.NamedType {
+			if decl := r.typeDeclOf(r.table[object.NameOrNil.IdId]); decl != nil {
+				object = decl.Type
+			}
+		}
+		if object.TypeKind == parser.StructureType {
+			for _, field := range object.StructOrNil.Fields {
+				if field.Id.Name == node.Field.Name {
+					return field.Type, true
+				}
+			}
+		}
+	case *parser.TypeAssert:
+		if node.TypeOrNil != nil {
+			return *node.TypeOrNil, true
+		}
+	}
+	return parser.Type{}, false
+}
+
+func (r *Resolver) staticTypeOfPrimary(node *parser.Primary) (parser.Type, bool) {
+	switch node.PrimaryKind {
+	case parser.ExprPrimary:
+		return r.staticType(node.ExprOrNil)
+	case parser.IdPrimary:
+		ref, ok := r.table[node.IdOrNil.IdId]
+		if !ok {
+			return parser.Type{}, false
+		}
+		if entry, ok := r.consts[ref.RefIdNodeId]; ok && entry.state == constFolded {
+			return parser.Type{TypeKind: entry.value.Kind}, true
+		}
+		t, ok := r.declTypes[ref.RefIdNodeId]
+		return t, ok
+	case parser.ValuePrimary:
+		value := node.ValueOrNil
+		switch value.ValueKind {
+		case parser.NumberValue:
+			return parser.Type{TypeKind: parser.IntType}, true
+		case parser.BoolValue:
+			return parser.Type{TypeKind: parser.BoolType}, true
+		case parser.StrLitValue:
+			return parser.Type{TypeKind: parser.StringType}, true
+		case parser.ErrValue:
+			return parser.Type{TypeKind: parser.ErrorType}, true
+		case parser.FexpValue:
+			return *funcTypeOf(value.FexpOrNil.ParamsOrNil, value.FexpOrNil.ReturnTypesOrNil), true
+		case parser.StructLitValue:
+			return parser.Type{TypeKind: parser.NamedType, NameOrNil: &value.StructLitOrNil.TypeName}, true
+		case parser.SliceLitValue:
+			return parser.Type{TypeKind: parser.SliceType, ElemOrNil: &value.SliceLitOrNil.ElemType}, true
+		case parser.TupleLitValue:
+			elems := make([]parser.Type, len(value.TupleLitOrNil.Elems))
+			for i, elem := range value.TupleLitOrNil.Elems {
+				t, ok := r.staticType(elem)
+				if !ok {
+					return parser.Type{}, false
+				}
+				elems[i] = t
+			}
+			return parser.Type{TypeKind: parser.TupleType, TupleOrNil: elems}, true
+		}
+	}
+	return parser.Type{}, false
+}
+
+// staticTypeOfCall은 리턴 타입이 하나인 함수를 한 번 호출한 결과의 타입을 리턴함
+// 제네릭 함수라면 추론된 타입 인자로 리턴 타입을 바꿈
+func (r *Resolver) staticTypeOfCall(call *parser.Call) (parser.Type, bool) {
+	if len(call.ArgsList) != 1 {
+		return parser.Type{}, false
+	}
+	var target parser.Expr = &call.PrimaryOrNil
+	if call.PrimaryOrNil.PrimaryKind == parser.ExprPrimary {
+		if index, ok := call.PrimaryOrNil.ExprOrNil.(*parser.Index); ok && index.TypeArgsOrNil != nil {
+			target = index.Object
+		}
+	}
+	if decl, _, ok := r.genericCalleeOf(target); ok {
+		if call.TypeArgsOrNil == nil || len(decl.ReturnTypesOrNil) != 1 {
+			return parser.Type{}, false
+		}
+		bound := map[parser.IdId]parser.Type{}
+		for i, tp := range decl.TypeParamsOrNil {
+			bound[tp.Id.IdId] = call.TypeArgsOrNil[i]
+		}
+		return r.substType(decl.ReturnTypesOrNil[0], bound), true
+	}
+	callee, ok := r.staticType(&call.PrimaryOrNil)
+	if !ok || callee.TypeKind != parser.FuncionType || len(callee.FuncTypeOrNil.ReturnTypesOrNil) != 1 {
+		return parser.Type{}, false
+	}
+	return callee.FuncTypeOrNil.ReturnTypesOrNil[0], true
+}
+
+// typeDisplay는 에러 메시지에 쓰일 타입 이름임
+func (r *Resolver) typeDisplay(t parser.Type) string {
+	switch t.TypeKind {
+	case parser.NamedType:
+		return t.NameOrNil.Name
+	case parser.SliceType:
+		return "[]" + r.typeDisplay(*t.ElemOrNil)
+	case parser.TupleType:
+		elems := make([]string, len(t.TupleOrNil))
+		for i, elem := range t.TupleOrNil {
+			elems[i] = r.typeDisplay(elem)
+		}
+		return "(" + strings.Join(elems, ", ") + ")"
+	case parser.FuncionType:
+		args := make([]string, len(t.FuncTypeOrNil.ArgTypesOrNil))
+		for i, arg := range t.FuncTypeOrNil.ArgTypesOrNil {
+			args[i] = r.typeDisplay(arg)
+		}
+		returns := make([]string, len(t.FuncTypeOrNil.ReturnTypesOrNil))
+		for i, ret := range t.FuncTypeOrNil.ReturnTypesOrNil {
+			returns[i] = r.typeDisplay(ret)
+		}
+		display := "func(" + strings.Join(args, ", ") + ")"
+		if len(returns) == 1 {
+			return display + " " + returns[0]
+		}
+		if len(returns) > 1 {
+			return display + " (" + strings.Join(returns, ", ") + ")"
+		}
+		return display
+	case parser.InterfaceTypeKind:
+		return r.constraintDisplay(t)
+	case parser.StructureType:
+		return "struct"
+	default:
+		return t.String()
+	}
+}
+
+// constraintDisplay는 제약의 이름임. 빈 interface는 any, comparable만 있는 interface는 comparable임
+func (r *Resolver) constraintDisplay(t parser.Type) string {
+	if t.TypeKind != parser.InterfaceTypeKind {
+		return r.typeDisplay(t)
+	}
+	iface := t.InterfaceOrNil
+	switch {
+	case len(iface.Methods) == 0 && len(iface.UnionOrNil) == 0 && !iface.Comparable:
+		return "any"
+	case len(iface.Methods) == 0 && len(iface.UnionOrNil) == 0:
+		return "comparable"
+	case len(iface.Methods) == 0 && !iface.Comparable:
+		return r.unionDisplay(iface.UnionOrNil)
+	default:
+		return "interface"
+	}
+}
+
+func (r *Resolver) unionDisplay(union []parser.Type) string {
+	members := make([]string, len(union))
+	for i, member := range union {
+		members[i] = r.typeDisplay(member)
+	}
+	return strings.Join(members, " | ")
+}
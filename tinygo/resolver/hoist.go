@@ -14,8 +14,28 @@ type HoistInfo struct {
 	// var간 초기화 순서는 추후 initOrder에서 보장함
 	varOrder     []parser.IdId
 	funcOrder    []parser.IdId
+	typeOrder    []parser.IdId
 	varDeclById  map[parser.IdId]*parser.VarDecl
 	funcDeclById map[parser.IdId]*parser.FuncDecl
+	typeDeclById map[parser.IdId]*parser.TypeDecl
+	// 합 타입 변형의 선언 id -> 그 변형이 속한 TypeDecl
+	variantOrder      []parser.IdId
+	variantTypeDeclBy map[parser.IdId]*parser.TypeDecl
+	// 메서드는 글로벌 스코프에 이름을 등록하지 않음
+	// 리시버 타입의 TypeDecl id -> 메서드 이름 -> 메서드 FuncDecl id
+	methodOrder    []parser.IdId
+	methodDeclById map[parser.IdId]*parser.FuncDecl
+	methodsByType  map[parser.IdId]map[string]parser.IdId
+	// 상수는 리졸빙 시점에 값이 접히므로 초기화 순서에 들어가지 않음
+	constOrder     []parser.IdId
+	constValueById map[parser.IdId]ConstValue
+	// func init()은 여러 번 선언될 수 있고 참조할 수 없으므로 이름을 등록하지 않음
+	// 전역 변수 초기화가 끝난 뒤, main 직전에 선언 순서대로 실행됨
+	initOrder    []parser.IdId
+	initDeclById map[parser.IdId]*parser.FuncDecl
+	// main이 아닌 패키지의 전역 변수, 함수, 메서드 id -> 그 패키지 이름
+	// 여러 패키지의 전역이 한 HoistInfo에 모이므로, 이름을 패키지로 구분할 때 씀
+	packageNameById map[parser.IdId]string
 }
 
 func newHoistInfo() *HoistInfo {
@@ -24,6 +44,16 @@ func newHoistInfo() *HoistInfo {
 		globalsById:   map[parser.IdId]*Symbol{},
 		varDeclById:   map[parser.IdId]*parser.VarDecl{},
 		funcDeclById:  map[parser.IdId]*parser.FuncDecl{},
+		typeDeclById:  map[parser.IdId]*parser.TypeDecl{},
+
+		variantTypeDeclBy: map[parser.IdId]*parser.TypeDecl{},
+
+		methodDeclById: map[parser.IdId]*parser.FuncDecl{},
+		methodsByType:  map[parser.IdId]map[string]parser.IdId{},
+		constValueById: map[parser.IdId]ConstValue{},
+		initDeclById:   map[parser.IdId]*parser.FuncDecl{},
+
+		packageNameById: map[parser.IdId]string{},
 	}
 }
 
@@ -39,6 +69,26 @@ func (h *HoistInfo) getFuncDeclById(id parser.IdId) *parser.FuncDecl {
 	return h.funcDeclById[id]
 }
 
+func (h *HoistInfo) getTypeDeclById(id parser.IdId) *parser.TypeDecl {
+	return h.typeDeclById[id]
+}
+
+func (h *HoistInfo) getMethodDeclById(id parser.IdId) *parser.FuncDecl {
+	return h.methodDeclById[id]
+}
+
+// packageOf는 main이 아닌 패키지에 선언된 전역이라면 그 패키지 이름을, 아니면 빈 문자열을 리턴함
+func (h *HoistInfo) packageOf(id parser.IdId) string {
+	return h.packageNameById[id]
+}
+
+// recordPackage는 id가 main이 아닌 패키지 pkg에 선언되었음을 기록함
+func (h *HoistInfo) recordPackage(pkg *parser.PackageAST, id parser.IdId) {
+	if pkg.NameOrNil != nil && pkg.NameOrNil.Name != "main" {
+		h.packageNameById[id] = pkg.NameOrNil.Name
+	}
+}
+
 func (h *HoistInfo) varIds() []parser.IdId {
 	return h.varOrder
 }
@@ -55,6 +105,59 @@ func (h *HoistInfo) GetFuncDeclById(id parser.IdId) *parser.FuncDecl {
 	return h.getFuncDeclById(id)
 }
 
+func (h *HoistInfo) GetTypeDeclById(id parser.IdId) *parser.TypeDecl {
+	return h.getTypeDeclById(id)
+}
+
+func (h *HoistInfo) TypeIds() []parser.IdId {
+	return h.typeOrder
+}
+
+// VariantIds는 합 타입 변형들의 선언 id를 선언 순서대로 리턴함
+func (h *HoistInfo) VariantIds() []parser.IdId {
+	return h.variantOrder
+}
+
+// GetVariantTypeDeclById는 변형 id가 속한 합 타입의 TypeDecl을 리턴함
+func (h *HoistInfo) GetVariantTypeDeclById(id parser.IdId) *parser.TypeDecl {
+	return h.variantTypeDeclBy[id]
+}
+
+func (h *HoistInfo) GetMethodDeclById(id parser.IdId) *parser.FuncDecl {
+	return h.getMethodDeclById(id)
+}
+
+func (h *HoistInfo) MethodIds() []parser.IdId {
+	return h.methodOrder
+}
+
+// MethodsOfType은 typeId의 TypeDecl에 선언된 메서드들을 이름 -> 메서드 id로 리턴함
+func (h *HoistInfo) MethodsOfType(typeId parser.IdId) map[string]parser.IdId {
+	return h.methodsByType[typeId]
+}
+
+// ConstIds는 상수들의 선언 id를 선언 순서대로 리턴함
+func (h *HoistInfo) ConstIds() []parser.IdId {
+	return h.constOrder
+}
+
+// GetConstValueById는 상수 id의 접힌 값을 리턴함
+func (h *HoistInfo) GetConstValueById(id parser.IdId) (ConstValue, bool) {
+	value, ok := h.constValueById[id]
+	return value, ok
+}
+
+// InitFuncIds는 init 함수들의 선언 id를 실행 순서대로 리턴함
+// 여러 패키지라면 임포트되는 패키지의 init이 먼저 옴
+func (h *HoistInfo) InitFuncIds() []parser.IdId {
+	return h.initOrder
+}
+
+// GetInitFuncDeclById는 init 함수 id의 FuncDecl을 리턴함
+func (h *HoistInfo) GetInitFuncDeclById(id parser.IdId) *parser.FuncDecl {
+	return h.initDeclById[id]
+}
+
 func (h *HoistInfo) VarIds() []parser.IdId {
 	return h.varIds()
 }
@@ -99,38 +202,127 @@ func (h *HoistInfo) Print() string {
 		lines = append(lines, fmt.Sprintf("  #%d %s", id, name))
 	}
 
+	lines = append(lines, "types:")
+	for _, id := range h.typeOrder {
+		name := "<missing>"
+		if sym := h.getById(id); sym != nil {
+			name = sym.name
+		}
+		lines = append(lines, fmt.Sprintf("  #%d %s", id, name))
+	}
+
+	lines = append(lines, "methods:")
+	for _, id := range h.methodOrder {
+		decl := h.getMethodDeclById(id)
+		lines = append(lines, fmt.Sprintf("  #%d %s.%s", id, decl.ReceiverOrNil.Type.NameOrNil.Name, decl.Id.Name))
+	}
+
+	lines = append(lines, "consts:")
+	for _, id := range h.constOrder {
+		lines = append(lines, fmt.Sprintf("  #%d %s = %s", id, h.getById(id).name, h.constValueById[id].String()))
+	}
 	return parser.JoinLines(lines)
 }
 
-func (r *Resolver) collectPackageDecls(pkg *parser.PackageAST) (*HoistInfo, error) {
-	hoist := newHoistInfo()
+func (r *Resolver) collectPackageDecls(pkg *parser.PackageAST, hoist *HoistInfo) error {
 	for _, decl := range pkg.DeclsOrNil {
 		switch node := decl.(type) {
 		// 패키지 레벨의 선언들을 돌면서
 		// 좌변의 선언 인자들만 글로벌 스코프에 전부 등록
 		case *parser.VarDecl:
 			for _, id := range node.Ids {
+				if id.Name == "init" {
+					return newResolveErr(id, cannotDeclareInitMsg)
+				}
 				sym, err := r.declare(id.Name, SymbolVar, id.IdId)
 				if err != nil {
-					return nil, newResolveErr(id, err.Error())
+					return newResolveErr(id, err.Error())
 				}
 				hoist.globalsByName[id.Name] = sym
 				hoist.globalsById[id.IdId] = sym
 				hoist.varOrder = append(hoist.varOrder, id.IdId)
 				hoist.varDeclById[id.IdId] = node
+				hoist.recordPackage(pkg, id.IdId)
 				r.setResolved(id, r.refFromSymbol(sym))
 			}
+		case *parser.ConstDecl:
+			if err := r.collectConstDecl(node, hoist); err != nil {
+				return err
+			}
 		case *parser.FuncDecl:
+			// 메서드는 리시버 타입을 통해서만 접근되므로 이름을 등록하지 않음
+			// 리시버 타입과의 연결은 타입이 모두 호이스팅된 후에 collectMethods에서 함
+			if node.ReceiverOrNil != nil {
+				hoist.methodOrder = append(hoist.methodOrder, node.Id.IdId)
+				hoist.methodDeclById[node.Id.IdId] = node
+				hoist.recordPackage(pkg, node.Id.IdId)
+				continue
+			}
+			if node.Id.Name == "init" {
+				if err := collectInitFunc(node, hoist); err != nil {
+					return err
+				}
+				// 선언 id는 자기 자신을 가리키지만, 전역 슬롯은 갖지 않음
+				r.setResolved(node.Id, ResolvedRef{Kind: RefGlobal, Slot: -1, RefIdNodeId: node.Id.IdId, Name: node.Id.Name})
+				continue
+			}
 			sym, err := r.declare(node.Id.Name, SymbolFunc, node.Id.IdId)
 			if err != nil {
-				return nil, newResolveErr(node.Id, err.Error())
+				return newResolveErr(node.Id, err.Error())
 			}
 			hoist.globalsByName[node.Id.Name] = sym
 			hoist.globalsById[node.Id.IdId] = sym
 			hoist.funcOrder = append(hoist.funcOrder, node.Id.IdId)
 			hoist.funcDeclById[node.Id.IdId] = node
+			hoist.recordPackage(pkg, node.Id.IdId)
+			r.setResolved(node.Id, r.refFromSymbol(sym))
+			r.recordFuncDecl(node)
+		case *parser.TypeDecl:
+			// 타입 역시 선언 순서와 무관하게 참조 가능하도록 호이스팅
+			if node.Id.Name == "init" {
+				return newResolveErr(node.Id, cannotDeclareInitMsg)
+			}
+			sym, err := r.declare(node.Id.Name, SymbolType, node.Id.IdId)
+			if err != nil {
+				return newResolveErr(node.Id, err.Error())
+			}
+			hoist.globalsByName[node.Id.Name] = sym
+			hoist.globalsById[node.Id.IdId] = sym
+			hoist.typeOrder = append(hoist.typeOrder, node.Id.IdId)
+			hoist.typeDeclById[node.Id.IdId] = node
 			r.setResolved(node.Id, r.refFromSymbol(sym))
+			// 합 타입의 변형 이름은 생성자로서 전역 값 이름이 됨
+			if node.Type.TypeKind == parser.SumTypeKind {
+				for i, variant := range node.Type.SumOrNil.Variants {
+					sym, err := r.declare(variant.Id.Name, SymbolVariant, variant.Id.IdId)
+					if err != nil {
+						return newResolveErr(variant.Id, err.Error())
+					}
+					hoist.globalsByName[variant.Id.Name] = sym
+					hoist.globalsById[variant.Id.IdId] = sym
+					hoist.variantOrder = append(hoist.variantOrder, variant.Id.IdId)
+					hoist.variantTypeDeclBy[variant.Id.IdId] = node
+					r.variants[variant.Id.IdId] = variantInfo{decl: node, index: i}
+					r.setResolved(variant.Id, r.refFromSymbol(sym))
+				}
+			}
 		}
 	}
-	return hoist, nil
+	return nil
+}
+
+// cannotDeclareInitMsg는 패키지 레벨에서 init을 함수가 아닌 것으로 선언할 때의 에러임
+const cannotDeclareInitMsg = "cannot declare init - must be func"
+
+// collectInitFunc는 init 함수의 시그니처를 검사하고 hoist에 선언 순서대로 기록함
+func collectInitFunc(node *parser.FuncDecl, hoist *HoistInfo) error {
+	if len(node.TypeParamsOrNil) > 0 {
+		return newResolveErr(node.Id, "func init must have no type parameters")
+	}
+	if len(node.ParamsOrNil) > 0 || len(node.ReturnTypesOrNil) > 0 {
+		return newResolveErr(node.Id, "func init must have no arguments and no return values")
+	}
+	hoist.initOrder = append(hoist.initOrder, node.Id.IdId)
+	hoist.initDeclById[node.Id.IdId] = node
+	return nil
 }
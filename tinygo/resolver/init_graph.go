@@ -0,0 +1,321 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// InitNodeKind는 초기화 의존 그래프의 노드 종류임
+type InitNodeKind uint8
+
+const (
+	// 초기식이 없는 전역 변수. 의존이 없으며 가장 먼저 초기화됨
+	InitZeroVar InitNodeKind = iota
+	// 초기식이 함수 리터럴이 아닌 전역 변수
+	InitExprVar
+	// 초기식이 함수 리터럴인 전역 변수. 본문은 불릴 때 실행되므로 함수처럼 다룸
+	InitFexpVar
+	// 전역 함수나 메서드
+	InitFunc
+)
+
+func (k InitNodeKind) String() string {
+	switch k {
+	case InitZeroVar:
+		return "zero var"
+	case InitExprVar:
+		return "var"
+	case InitFexpVar:
+		return "closure var"
+	case InitFunc:
+		return "func"
+	default:
+		return "unknown"
+	}
+}
+
+// InitNode는 전역 변수나, 변수 초기식에서 닿는 함수 하나임
+type InitNode struct {
+	// 선언의 식별자
+	Id   parser.Id
+	Kind InitNodeKind
+	// 변수의 초기식. InitZeroVar, InitFunc라면 nil
+	ExprOrNil parser.Expr
+	// 메서드라면 리시버 타입 이름
+	recvOrEmpty string
+	// main이 아닌 패키지에 선언되었다면 그 패키지 이름
+	pkgOrEmpty string
+}
+
+// String은 사이클 경로와 tinygo deps에서 쓰는 이름이며, 함수는 f(), 메서드는 T.m() 임
+// main이 아닌 패키지의 노드는 callgraph처럼 pkg.Name, pkg.f(), pkg.T.m() 으로 한정함
+func (n *InitNode) String() string {
+	name := n.Id.Name
+	if n.Kind == InitFunc {
+		if n.recvOrEmpty != "" {
+			name = n.recvOrEmpty + "." + name
+		}
+		name += "()"
+	}
+	if n.pkgOrEmpty != "" {
+		return n.pkgOrEmpty + "." + name
+	}
+	return name
+}
+
+// InitEdge는 From의 초기식이나 본문이 To를 참조하는 것임. At은 참조한 식별자임
+type InitEdge struct {
+	From *InitNode
+	To   *InitNode
+	At   parser.Id
+}
+
+// InitGraph는 전역 초기화의 의존 그래프임
+// 노드는 모든 전역 변수(선언 순서)와, 변수 초기식에서 닿는 함수, 메서드(처음 닿은 순서)임
+type InitGraph struct {
+	Nodes []*InitNode
+	byId  map[parser.IdId]*InitNode
+	edges map[parser.IdId][]InitEdge
+}
+
+// Edges는 node가 참조하는 것들을 처음 참조한 순서대로 리턴함. 같은 대상은 한 번만 나옴
+func (g *InitGraph) Edges(node *InitNode) []InitEdge {
+	return g.edges[node.Id.IdId]
+}
+
+// Node는 선언 id가 id인 노드를 리턴함
+func (g *InitGraph) Node(id parser.IdId) (*InitNode, bool) {
+	node, ok := g.byId[id]
+	return node, ok
+}
+
+func (g *InitGraph) add(node *InitNode) {
+	g.Nodes = append(g.Nodes, node)
+	g.byId[node.Id.IdId] = node
+}
+
+// BuildInitGraph는 전역 변수 초기식들에서 시작해, 참조하는 변수와 함수를 따라가며 의존 그래프를 만듦
+// 함수 본문은 변수 초기식에서 (다른 함수를 거쳐서라도) 닿을 때만 방문함
+func BuildInitGraph(table ResolveTable, hoist *HoistInfo) (*InitGraph, error) {
+	if hoist == nil {
+		return nil, fmt.Errorf("hoist info is required")
+	}
+	g := &InitGraph{byId: map[parser.IdId]*InitNode{}, edges: map[parser.IdId][]InitEdge{}}
+	for _, varId := range sortedIds(hoist.varIds()) {
+		decl := hoist.getVarDeclById(varId)
+		if decl == nil {
+			return nil, fmt.Errorf("missing hoisted var decl for id #%d", varId)
+		}
+		node := &InitNode{Id: declIdOf(decl, varId), Kind: InitZeroVar, pkgOrEmpty: hoist.packageOf(varId)}
+		if len(decl.ExprsOrNil) > 0 {
+			expr, ok := exprForVarId(decl, varId)
+			if !ok {
+				return nil, fmt.Errorf("var decl entry not found for id #%d", varId)
+			}
+			node.ExprOrNil = expr
+			node.Kind = InitExprVar
+			if exprIsFexp(expr) {
+				node.Kind = InitFexpVar
+			}
+		}
+		g.add(node)
+	}
+
+	// 새로 닿은 함수는 Nodes 끝에 붙으므로, 끝까지 차례로 방문하면 닿는 함수를 모두 방문함
+	for i := 0; i < len(g.Nodes); i++ {
+		node := g.Nodes[i]
+		refs := newGlobalRefs()
+		switch node.Kind {
+		case InitZeroVar:
+			continue
+		case InitExprVar, InitFexpVar:
+			if err := walkExprRefs(node.ExprOrNil, table, hoist, refs); err != nil {
+				return nil, err
+			}
+		case InitFunc:
+			fn := hoist.getFuncDeclById(node.Id.IdId)
+			if fn == nil {
+				fn = hoist.getMethodDeclById(node.Id.IdId)
+			}
+			if err := walkBlockRefs(fn.Block, table, hoist, refs); err != nil {
+				return nil, err
+			}
+		}
+		for _, ref := range refs.list {
+			to, ok := g.byId[ref.id]
+			if !ok {
+				fn := hoist.getFuncDeclById(ref.id)
+				if fn == nil {
+					fn = hoist.getMethodDeclById(ref.id)
+				}
+				if fn == nil {
+					return nil, fmt.Errorf("missing hoisted func decl for id #%d", ref.id)
+				}
+				to = &InitNode{Id: fn.Id, Kind: InitFunc, recvOrEmpty: receiverName(fn), pkgOrEmpty: hoist.packageOf(ref.id)}
+				g.add(to)
+			}
+			g.edges[node.Id.IdId] = append(g.edges[node.Id.IdId], InitEdge{From: node, To: to, At: ref.at})
+		}
+	}
+	return g, nil
+}
+
+func declIdOf(decl *parser.VarDecl, id parser.IdId) parser.Id {
+	for _, vid := range decl.Ids {
+		if vid.IdId == id {
+			return vid
+		}
+	}
+	return parser.Id{IdId: id}
+}
+
+func receiverName(fn *parser.FuncDecl) string {
+	if fn.ReceiverOrNil == nil {
+		return ""
+	}
+	if t := fn.ReceiverOrNil.Type; t.NameOrNil != nil {
+		return t.NameOrNil.Name
+	}
+	return fn.ReceiverOrNil.Type.String()
+}
+
+// varDeps는 from의 초기식이 함수를 거쳐서라도 읽는 식 초기화 변수들과, 각 변수까지의 가장 짧은 참조 경로를 리턴함
+// 초기식이 없는 변수는 먼저 초기화되므로 의존으로 보지 않음
+func (g *InitGraph) varDeps(from *InitNode) ([]*InitNode, map[*InitNode][]InitEdge) {
+	paths := map[*InitNode][]InitEdge{}
+	deps := []*InitNode{}
+	visited := map[*InitNode]bool{from: true}
+	queue := []*InitNode{from}
+	pathTo := map[*InitNode][]InitEdge{from: nil}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, edge := range g.Edges(node) {
+			path := append(append([]InitEdge{}, pathTo[node]...), edge)
+			switch edge.To.Kind {
+			case InitExprVar:
+				if _, ok := paths[edge.To]; !ok {
+					paths[edge.To] = path
+					deps = append(deps, edge.To)
+				}
+			case InitFexpVar, InitFunc:
+				if !visited[edge.To] {
+					visited[edge.To] = true
+					pathTo[edge.To] = path
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Id.IdId < deps[j].Id.IdId })
+	return deps, paths
+}
+
+// InitCycleError는 전역 변수 초기화의 순환 의존임
+type InitCycleError struct {
+	// 순환을 이루는 참조들. 첫 참조는 변수에서 시작하며, 마지막 참조는 그 변수로 돌아옴
+	Path      []InitEdge
+	positions []*parser.PackageAST
+}
+
+// withPositions는 오류 메시지에 참조 위치를 적기 위해 파싱한 패키지들을 기억함
+func (e *InitCycleError) withPositions(pkgs ...*parser.PackageAST) *InitCycleError {
+	e.positions = pkgs
+	return e
+}
+
+func (e *InitCycleError) posOf(id parser.Id) parser.Pos {
+	for _, pkg := range e.positions {
+		if pos := pkg.PosOf(id); pos.IsValid() {
+			return pos
+		}
+	}
+	return parser.Pos{}
+}
+
+// Error는 "initialization cycle: a -> f() -> b -> a" 다음 줄부터 참조마다 위치를 적음
+func (e *InitCycleError) Error() string {
+	names := []string{e.Path[0].From.String()}
+	for _, edge := range e.Path {
+		names = append(names, edge.To.String())
+	}
+	lines := []string{"initialization cycle: " + strings.Join(names, " -> ")}
+	for _, edge := range e.Path {
+		line := fmt.Sprintf("%s refers to %s", edge.From, edge.To)
+		if pos := e.posOf(edge.At); pos.IsValid() {
+			line = pos.String() + ": " + line
+		}
+		lines = append(lines, "\t"+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attachPositions는 err가 초기화 순환이라면 메시지에 위치가 나오도록 패키지들을 붙임
+func attachPositions(err error, pkgs ...*parser.PackageAST) error {
+	if cycle, ok := err.(*InitCycleError); ok {
+		return cycle.withPositions(pkgs...)
+	}
+	return err
+}
+
+// sortVars는 식 초기화 변수들을, 의존하는 변수가 먼저 오도록 위상 정렬함
+// 의존이 없는 변수끼리는 선언 순서를 따르며, 순환 의존이 있으면 *InitCycleError를 리턴함
+func (g *InitGraph) sortVars() ([]parser.IdId, error) {
+	// state[node]:
+	// 0 = 아직 방문 안 함
+	// 1 = 방문 중 (DFS 스택에 있음)
+	// 2 = 방문 완료 (위상정렬 결과에 이미 반영됨)
+	state := map[*InitNode]int{}
+	stack := []*InitNode{}
+	pathsOf := map[*InitNode]map[*InitNode][]InitEdge{}
+	result := []parser.IdId{}
+
+	var visit func(node *InitNode) error
+	visit = func(node *InitNode) error {
+		if state[node] == 1 {
+			// 스택에서 node부터 끝까지가 순환이며, 각 변수 사이의 경로를 이어 붙임
+			start := 0
+			for stack[start] != node {
+				start++
+			}
+			path := []InitEdge{}
+			for k := start; k < len(stack); k++ {
+				next := node
+				if k+1 < len(stack) {
+					next = stack[k+1]
+				}
+				path = append(path, pathsOf[stack[k]][next]...)
+			}
+			return &InitCycleError{Path: path}
+		}
+		if state[node] == 2 {
+			return nil
+		}
+		state[node] = 1
+		stack = append(stack, node)
+		deps, paths := g.varDeps(node)
+		pathsOf[node] = paths
+		for _, dep := range deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = 2
+		result = append(result, node.Id.IdId)
+		return nil
+	}
+
+	for _, node := range g.Nodes {
+		if node.Kind != InitExprVar {
+			continue
+		}
+		if err := visit(node); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
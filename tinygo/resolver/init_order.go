@@ -15,154 +15,37 @@ type InitStep struct {
 
 type InitOrder []InitStep
 
+// BuildInitOrder는 전역 변수들의 초기화 순서를 정함
+// 초기식이 없는 변수, 함수 리터럴로 초기화되는 변수, 나머지 변수 순이며
+// 나머지 변수는 초기식이 (함수를 거쳐서라도) 읽는 변수가 먼저 오도록 위상 정렬함
 func BuildInitOrder(table ResolveTable, hoist *HoistInfo) (InitOrder, error) {
-	if hoist == nil {
-		return nil, fmt.Errorf("hoist info is required")
-	}
-	// varZeroInit: 초기화되지 않은 모든 VarDecl
-	varZeroInit := map[parser.IdId]bool{}
-	// varInitFexp: 초기화되었지만 Expr이 Fexp인 VarDecl
-	varInitFexp := map[parser.IdId]parser.Expr{}
-	// varInitExpr: 초기화되었으며 Expr이 Fexp가 아닌 VarDecl
-	varInitExpr := map[parser.IdId]parser.Expr{}
-
-	// VarDecl을 분해해 분류 집합을 만든다.
-	for _, varId := range sortedIds(hoist.varIds()) {
-		decl := hoist.getVarDeclById(varId)
-		if decl == nil {
-			return nil, fmt.Errorf("missing hoisted var decl for id #%d", varId)
-		}
-		if len(decl.ExprsOrNil) == 0 {
-			varZeroInit[varId] = true
-			continue
-		}
-		expr, ok := exprForVarId(decl, varId)
-		if !ok {
-			return nil, fmt.Errorf("var decl entry not found for id #%d", varId)
-		}
-		if exprIsFexp(expr) {
-			varInitFexp[varId] = expr
-			continue
-		}
-		varInitExpr[varId] = expr
-	}
-
-	// varInitExpr가 의존하는 전역 변수/함수/Fexp를 수집한다.
-	varInitExprToVarInitExprDependency := map[parser.IdId]map[parser.IdId]bool{} //의존하는 모든 초기화 && 값이 Fexp아닌 varDecl
-	varInitExprToVarInitFexpDependency := map[parser.IdId]map[parser.IdId]bool{} // 의존하는 모든 초기화 && 값이 Fexp인 VarDecl
-	varInitExprToFuncDeclDependency := map[parser.IdId]map[parser.IdId]bool{}    // 의존하는 모든 FunclDecl
-	for varId, expr := range varInitExpr {
-		// depVars는 해당 varId의 expr이 의존하는 모든 varDecl임
-		// 이 varDecl은 값이 fexp인 varDecl도 포함. 말 그대로 모든 varDecl임
-		// depFunc는 해당 expr이 의존하는 모든 funcDecl임
-		depVars, depFuncs, err := collectGlobalRefsExpr(expr, table, hoist)
-		if err != nil {
-			return nil, err
-		}
-		for depVar := range depVars {
-			//초기화되지 않은 변수는 무조건 최우선으로 init되며, 어떤 의존성도 없으므로
-			//굳이 의존성 리스트에 넣을 이유가 없음. 어차피 맨 앞에 위치함
-			if varZeroInit[depVar] {
-				continue
-			}
-
-			if varInitFexp[depVar] != nil {
-				addDependency(varInitExprToVarInitFexpDependency, varId, depVar)
-				continue
-			}
-			if varInitExpr[depVar] != nil {
-				addDependency(varInitExprToVarInitExprDependency, varId, depVar)
-			}
-		}
-		for depFunc := range depFuncs {
-			addDependency(varInitExprToFuncDeclDependency, varId, depFunc)
-		}
-	}
-
-	// varInitExpr들에 대해서만 위상정렬한다.
-	order, err := topoSortVars(sortedIds(hoist.varIds()), varInitExpr, varInitExprToVarInitExprDependency)
+	graph, err := BuildInitGraph(table, hoist)
 	if err != nil {
 		return nil, err
 	}
-	// varInitExpr이 의존하는 callable만 대상으로 분석한다.
-	referencedFuncs := map[parser.IdId]bool{}
-	referencedFexps := map[parser.IdId]bool{}
-	for _, deps := range varInitExprToFuncDeclDependency {
-		for funcId := range deps {
-			referencedFuncs[funcId] = true
-		}
-	}
-	for _, deps := range varInitExprToVarInitFexpDependency {
-		for fexpId := range deps {
-			referencedFexps[fexpId] = true
-		}
-	}
-
-	// 의존이 확정된 함수가 어떤 VarDecl에 의존하는지 분석한다.
-	funcToVarDep, err := collectFuncGlobalVarDeps(table, hoist, referencedFuncs)
-	if err != nil {
-		return nil, err
-	}
-
-	// 의존이 확정된 Fexp VarDecl이 어떤 VarDecl에 의존하는지 분석한다.
-	fexpToVarDep, err := collectFexpGlobalVarDeps(varInitFexp, table, hoist, referencedFexps)
+	order, err := graph.sortVars()
 	if err != nil {
 		return nil, err
 	}
 
-	// callable(함수/클로저) -> var 의존성 그래프를 합친다.
-	callableToVar := map[parser.IdId]map[parser.IdId]bool{}
-	for funcId, vars := range funcToVarDep {
-		callableToVar[funcId] = vars
-	}
-	for fexpId, vars := range fexpToVarDep {
-		callableToVar[fexpId] = vars
-	}
-
-	varToCallable := map[parser.IdId]map[parser.IdId]bool{}
-	for varId, funcsDep := range varInitExprToFuncDeclDependency {
-		for funcId := range funcsDep {
-			addDependency(varToCallable, varId, funcId)
-		}
-	}
-	for varId, fexpsDep := range varInitExprToVarInitFexpDependency {
-		for fexpId := range fexpsDep {
-			addDependency(varToCallable, varId, fexpId)
-		}
-	}
-
-	// varInitExpr <-> callable 간 순환 차단
-	if err := detectVarCallableCycle(varToCallable, callableToVar, varInitExprToVarInitExprDependency); err != nil {
-		return nil, err
-	}
-
 	initOrder := InitOrder{}
 	// zero-init은 항상 먼저
-	for _, idId := range sortedIdsFromMap(varZeroInit) {
-		initOrder = append(initOrder, InitStep{
-			VarId:     idId,
-			ExprOrNil: nil,
-			ZeroInit:  true,
-		})
+	for _, node := range graph.Nodes {
+		if node.Kind == InitZeroVar {
+			initOrder = append(initOrder, InitStep{VarId: node.Id.IdId, ZeroInit: true})
+		}
 	}
-
 	// fexp init은 zero-init 다음
-	for _, varId := range sortedIdsFromMapExpr(varInitFexp) {
-		fexp := varInitFexp[varId]
-		initOrder = append(initOrder, InitStep{
-			VarId:     varId,
-			ExprOrNil: fexp,
-		})
+	for _, node := range graph.Nodes {
+		if node.Kind == InitFexpVar {
+			initOrder = append(initOrder, InitStep{VarId: node.Id.IdId, ExprOrNil: node.ExprOrNil})
+		}
 	}
-
 	// 나머지 expr init은 위상정렬 순서
 	for _, varId := range order {
-		initOrder = append(initOrder, InitStep{
-			VarId:     varId,
-			ExprOrNil: varInitExpr[varId],
-		})
+		node, _ := graph.Node(varId)
+		initOrder = append(initOrder, InitStep{VarId: varId, ExprOrNil: node.ExprOrNil})
 	}
-
 	return initOrder, nil
 }
 
@@ -214,72 +97,146 @@ func exprForVarId(decl *parser.VarDecl, id parser.IdId) (parser.Expr, bool) {
 	return nil, false
 }
 
-// expr이 의존중인 idId를 모두 수합해서 리턴
-func collectGlobalRefsExpr(expr parser.Expr, table ResolveTable, hoist *HoistInfo) (map[parser.IdId]bool, map[parser.IdId]bool, error) {
-	vars := map[parser.IdId]bool{}
-	funcs := map[parser.IdId]bool{}
-	if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
-		return nil, nil, err
+// globalRef는 참조된 전역 변수, 함수의 선언 id와 그것을 처음 참조한 식별자임
+type globalRef struct {
+	id parser.IdId
+	at parser.Id
+}
+
+// globalRefs는 식이나 본문이 참조하는 전역 변수, 함수들을 참조 순서대로 모은 것임
+type globalRefs struct {
+	list []globalRef
+	seen map[parser.IdId]bool
+}
+
+func newGlobalRefs() *globalRefs {
+	return &globalRefs{seen: map[parser.IdId]bool{}}
+}
+
+func (r *globalRefs) add(id parser.IdId, at parser.Id) {
+	if r.seen[id] {
+		return
 	}
-	return vars, funcs, nil
+	r.seen[id] = true
+	r.list = append(r.list, globalRef{id: id, at: at})
 }
 
 // expr이 의존중인 idId를, Expr을 Walk하며 모두 수합하는 함수
-func walkExprRefs(expr parser.Expr, table ResolveTable, hoist *HoistInfo, vars, funcs map[parser.IdId]bool) error {
+func walkExprRefs(expr parser.Expr, table ResolveTable, hoist *HoistInfo, refs *globalRefs) error {
 	switch node := expr.(type) {
 	case *parser.Unary:
-		return walkExprRefs(node.Object, table, hoist, vars, funcs)
+		return walkExprRefs(node.Object, table, hoist, refs)
 	case *parser.Binary:
-		if err := walkExprRefs(node.LeftExpr, table, hoist, vars, funcs); err != nil {
+		if err := walkExprRefs(node.LeftExpr, table, hoist, refs); err != nil {
 			return err
 		}
-		return walkExprRefs(node.RightExpr, table, hoist, vars, funcs)
+		return walkExprRefs(node.RightExpr, table, hoist, refs)
 	case *parser.Primary:
-		return walkPrimaryRefs(node, table, hoist, vars, funcs)
+		return walkPrimaryRefs(node, table, hoist, refs)
 	case *parser.Call:
-		if err := walkPrimaryRefs(&node.PrimaryOrNil, table, hoist, vars, funcs); err != nil {
+		if err := walkPrimaryRefs(&node.PrimaryOrNil, table, hoist, refs); err != nil {
 			return err
 		}
 		for _, args := range node.ArgsList {
 			for _, arg := range args {
-				if err := walkExprRefs(arg, table, hoist, vars, funcs); err != nil {
+				if err := walkExprRefs(arg, table, hoist, refs); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case *parser.Selector:
+		// 다른 패키지의 전역 참조(pkg.Name)는 리졸브 테이블에 Name이 기록되어 있음
+		if ref, ok := table[node.Field.IdId]; ok && ref.Kind == RefGlobal {
+			return walkGlobalRef(ref, node.Field, hoist, refs)
+		}
+		// 메서드는 런타임 값의 타입으로 결정되므로, 같은 이름의 메서드 모두에 의존한다고 봄
+		for _, methodId := range hoist.methodOrder {
+			if hoist.getMethodDeclById(methodId).Id.Name == node.Field.Name {
+				refs.add(methodId, node.Field)
+			}
+		}
+		return walkExprRefs(node.Object, table, hoist, refs)
+	case *parser.TypeAssert:
+		return walkExprRefs(node.Object, table, hoist, refs)
+	case *parser.Try:
+		return walkExprRefs(node.Object, table, hoist, refs)
+	case *parser.Match:
+		if err := walkExprRefs(node.Subject, table, hoist, refs); err != nil {
+			return err
+		}
+		for _, arm := range node.Arms {
+			if arm.GuardOrNil != nil {
+				if err := walkExprRefs(arm.GuardOrNil, table, hoist, refs); err != nil {
 					return err
 				}
 			}
+			if err := walkExprRefs(arm.Body, table, hoist, refs); err != nil {
+				return err
+			}
 		}
 		return nil
+	case *parser.Index:
+		if err := walkExprRefs(node.Object, table, hoist, refs); err != nil {
+			return err
+		}
+		return walkExprRefs(node.Index, table, hoist, refs)
 	default:
 		return nil
 	}
 }
 
-func walkPrimaryRefs(node *parser.Primary, table ResolveTable, hoist *HoistInfo, vars, funcs map[parser.IdId]bool) error {
+// walkGlobalRef는 ref가 전역 변수, 함수를 가리키면 at에서 참조한 것으로 의존성에 추가함
+func walkGlobalRef(ref ResolvedRef, at parser.Id, hoist *HoistInfo, refs *globalRefs) error {
+	if ref.Kind != RefGlobal {
+		return nil
+	}
+	sym := hoist.getById(ref.RefIdNodeId)
+	if sym == nil {
+		return fmt.Errorf("missing hoist entry for id #%d", ref.RefIdNodeId)
+	}
+	switch sym.kind {
+	case SymbolVar, SymbolFunc:
+		refs.add(sym.idNodeId, at)
+	}
+	return nil
+}
+
+func walkPrimaryRefs(node *parser.Primary, table ResolveTable, hoist *HoistInfo, refs *globalRefs) error {
 	switch node.PrimaryKind {
 	case parser.ExprPrimary:
-		return walkExprRefs(node.ExprOrNil, table, hoist, vars, funcs)
+		return walkExprRefs(node.ExprOrNil, table, hoist, refs)
 	case parser.IdPrimary:
 		id := *node.IdOrNil
 		ref, ok := table[id.IdId]
 		if !ok {
 			return fmt.Errorf("missing resolve entry for id %s", id.String())
 		}
-		if ref.Kind != RefGlobal {
-			return nil
+		return walkGlobalRef(ref, id, hoist, refs)
+	case parser.ValuePrimary:
+		if node.ValueOrNil != nil && node.ValueOrNil.ValueKind == parser.FexpValue {
+			return walkBlockRefs(node.ValueOrNil.FexpOrNil.Block, table, hoist, refs)
 		}
-		sym := hoist.getById(ref.RefIdNodeId)
-		if sym == nil {
-			return fmt.Errorf("missing hoist entry for id #%d", ref.RefIdNodeId)
+		if node.ValueOrNil != nil && node.ValueOrNil.ValueKind == parser.TupleLitValue {
+			for _, elem := range node.ValueOrNil.TupleLitOrNil.Elems {
+				if err := walkExprRefs(elem, table, hoist, refs); err != nil {
+					return err
+				}
+			}
 		}
-		switch sym.kind {
-		case SymbolVar:
-			vars[sym.idNodeId] = true
-		case SymbolFunc:
-			funcs[sym.idNodeId] = true
+		if node.ValueOrNil != nil && node.ValueOrNil.ValueKind == parser.SliceLitValue {
+			for _, elem := range node.ValueOrNil.SliceLitOrNil.Elems {
+				if err := walkExprRefs(elem, table, hoist, refs); err != nil {
+					return err
+				}
+			}
 		}
-		return nil
-	case parser.ValuePrimary:
-		if node.ValueOrNil != nil && node.ValueOrNil.ValueKind == parser.FexpValue {
-			return walkBlockRefs(node.ValueOrNil.FexpOrNil.Block, table, hoist, vars, funcs)
+		if node.ValueOrNil != nil && node.ValueOrNil.ValueKind == parser.StructLitValue {
+			for _, field := range node.ValueOrNil.StructLitOrNil.Fields {
+				if err := walkExprRefs(field.Expr, table, hoist, refs); err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	default:
@@ -287,262 +244,135 @@ func walkPrimaryRefs(node *parser.Primary, table ResolveTable, hoist *HoistInfo,
 	}
 }
 
-func walkBlockRefs(block parser.Block, table ResolveTable, hoist *HoistInfo, vars, funcs map[parser.IdId]bool) error {
+func walkBlockRefs(block parser.Block, table ResolveTable, hoist *HoistInfo, refs *globalRefs) error {
 	for _, stmt := range block.StmtsOrNil {
-		if err := walkStmtRefs(stmt, table, hoist, vars, funcs); err != nil {
+		if err := walkStmtRefs(stmt, table, hoist, refs); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func walkStmtRefs(stmt parser.Stmt, table ResolveTable, hoist *HoistInfo, vars, funcs map[parser.IdId]bool) error {
+func walkStmtRefs(stmt parser.Stmt, table ResolveTable, hoist *HoistInfo, refs *globalRefs) error {
 	switch node := stmt.(type) {
 
 	case *parser.Assign:
 		for _, expr := range node.Exprs {
-			if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 				return err
 			}
 		}
 	case *parser.CallStmt:
-		return walkExprRefs(&node.Call, table, hoist, vars, funcs)
+		return walkExprRefs(&node.Call, table, hoist, refs)
+	case *parser.TryStmt:
+		return walkExprRefs(node.Try, table, hoist, refs)
 	case *parser.ShortDecl:
 		for _, expr := range node.Exprs {
-			if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 				return err
 			}
 		}
 	case *parser.VarDecl:
 		for _, expr := range node.ExprsOrNil {
-			if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 				return err
 			}
 		}
 	case *parser.FuncDecl:
-		return walkBlockRefs(node.Block, table, hoist, vars, funcs)
+		return walkBlockRefs(node.Block, table, hoist, refs)
 	case *parser.Return:
 		for _, expr := range node.ExprsOrNil {
-			if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 				return err
 			}
 		}
 	case *parser.If:
 		if node.ShortDeclOrNil != nil {
 			for _, expr := range node.ShortDeclOrNil.Exprs {
-				if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+				if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 					return err
 				}
 			}
 		}
-		if err := walkExprRefs(node.Bexp, table, hoist, vars, funcs); err != nil {
+		if err := walkExprRefs(node.Bexp, table, hoist, refs); err != nil {
 			return err
 		}
-		if err := walkBlockRefs(node.ThenBlock, table, hoist, vars, funcs); err != nil {
+		if err := walkBlockRefs(node.ThenBlock, table, hoist, refs); err != nil {
 			return err
 		}
 		if node.ElseOrNil != nil {
-			if err := walkBlockRefs(*node.ElseOrNil, table, hoist, vars, funcs); err != nil {
+			if err := walkBlockRefs(*node.ElseOrNil, table, hoist, refs); err != nil {
 				return err
 			}
 		}
 	case *parser.ForBexp:
-		if err := walkExprRefs(node.Bexp, table, hoist, vars, funcs); err != nil {
+		if err := walkExprRefs(node.Bexp, table, hoist, refs); err != nil {
 			return err
 		}
-		return walkBlockRefs(node.Block, table, hoist, vars, funcs)
+		return walkBlockRefs(node.Block, table, hoist, refs)
 	case *parser.ForWithAssign:
 		for _, expr := range node.ShortDecl.Exprs {
-			if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 				return err
 			}
 		}
-		if err := walkExprRefs(node.Bexp, table, hoist, vars, funcs); err != nil {
+		if err := walkExprRefs(node.Bexp, table, hoist, refs); err != nil {
 			return err
 		}
 		for _, expr := range node.Assign.Exprs {
-			if err := walkExprRefs(expr, table, hoist, vars, funcs); err != nil {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
 				return err
 			}
 		}
-		return walkBlockRefs(node.Block, table, hoist, vars, funcs)
-
-	case *parser.Block:
-		return walkBlockRefs(*node, table, hoist, vars, funcs)
-	}
-	return nil
-}
-
-func collectFuncGlobalVarDeps(table ResolveTable, hoist *HoistInfo, filter map[parser.IdId]bool) (map[parser.IdId]map[parser.IdId]bool, error) {
-	funcToVar := map[parser.IdId]map[parser.IdId]bool{}
-	for _, funcId := range hoist.funcIds() {
-		if len(filter) > 0 && !filter[funcId] {
-			continue
-		}
-		fn := hoist.getFuncDeclById(funcId)
-		if fn == nil {
-			return nil, fmt.Errorf("missing hoisted func decl for id #%d", funcId)
-		}
-		vars := map[parser.IdId]bool{}
-		funcs := map[parser.IdId]bool{}
-		if err := walkBlockRefs(fn.Block, table, hoist, vars, funcs); err != nil {
-			return nil, err
-		}
-		for varId := range vars {
-			addDependency(funcToVar, fn.Id.IdId, varId)
-		}
-	}
-	return funcToVar, nil
-}
-
-func collectFexpGlobalVarDeps(varInitFexp map[parser.IdId]parser.Expr, table ResolveTable, hoist *HoistInfo, filter map[parser.IdId]bool) (map[parser.IdId]map[parser.IdId]bool, error) {
-	fexpToVar := map[parser.IdId]map[parser.IdId]bool{}
-	for varId, expr := range varInitFexp {
-		if len(filter) > 0 && !filter[varId] {
-			continue
-		}
-		block := fexpBlockFromExpr(expr)
-		if block == nil {
-			continue
-		}
-		vars := map[parser.IdId]bool{}
-		funcs := map[parser.IdId]bool{}
-		if err := walkBlockRefs(*block, table, hoist, vars, funcs); err != nil {
-			return nil, err
-		}
-		for depVar := range vars {
-			addDependency(fexpToVar, varId, depVar)
+		return walkBlockRefs(node.Block, table, hoist, refs)
+	case *parser.ForRange:
+		if err := walkExprRefs(node.Expr, table, hoist, refs); err != nil {
+			return err
 		}
-	}
-	return fexpToVar, nil
-}
-
-func fexpBlockFromExpr(expr parser.Expr) *parser.Block {
-	primary, ok := expr.(*parser.Primary)
-	if !ok {
-		return nil
-	}
-	if primary.PrimaryKind != parser.ValuePrimary || primary.ValueOrNil == nil {
-		return nil
-	}
-	if primary.ValueOrNil.ValueKind != parser.FexpValue || primary.ValueOrNil.FexpOrNil == nil {
-		return nil
-	}
-	block := primary.ValueOrNil.FexpOrNil.Block
-	return &block
-}
-
-func detectVarCallableCycle(varToCallable, callableToVar, varDeps map[parser.IdId]map[parser.IdId]bool) error {
-	varClosure := map[parser.IdId]map[parser.IdId]bool{}
-	for varId := range varDeps {
-		if _, ok := varClosure[varId]; ok {
-			continue
+		return walkBlockRefs(node.Block, table, hoist, refs)
+	case *parser.Yield:
+		for _, expr := range node.Exprs {
+			if err := walkExprRefs(expr, table, hoist, refs); err != nil {
+				return err
+			}
 		}
-		closure, err := buildVarClosure(varId, varDeps, map[parser.IdId]int{})
-		if err != nil {
+	case *parser.TypeSwitch:
+		if err := walkExprRefs(node.Subject, table, hoist, refs); err != nil {
 			return err
 		}
-		varClosure[varId] = closure
-	}
-
-	callableReach := map[parser.IdId]map[parser.IdId]bool{}
-	for callId, vars := range callableToVar {
-		reach := map[parser.IdId]bool{}
-		for varId := range vars {
-			reach[varId] = true
-			if closure, ok := varClosure[varId]; ok {
-				for dep := range closure {
-					reach[dep] = true
-				}
+		for _, clause := range node.Clauses {
+			if err := walkBlockRefs(clause.Block, table, hoist, refs); err != nil {
+				return err
 			}
 		}
-		callableReach[callId] = reach
-	}
-
-	for varId, calls := range varToCallable {
-		for callId := range calls {
-			if callableReach[callId][varId] {
-				return fmt.Errorf("cycle detected between var #%d and callable #%d", varId, callId)
+	case *parser.Switch:
+		if node.ShortDeclOrNil != nil {
+			for _, expr := range node.ShortDeclOrNil.Exprs {
+				if err := walkExprRefs(expr, table, hoist, refs); err != nil {
+					return err
+				}
 			}
 		}
-	}
-	return nil
-}
-
-func buildVarClosure(start parser.IdId, varDeps map[parser.IdId]map[parser.IdId]bool, state map[parser.IdId]int) (map[parser.IdId]bool, error) {
-	if state[start] == 1 {
-		return nil, fmt.Errorf("cycle detected among vars at #%d", start)
-	}
-	if state[start] == 2 {
-		return map[parser.IdId]bool{}, nil
-	}
-	state[start] = 1
-	closure := map[parser.IdId]bool{}
-	for dep := range varDeps[start] {
-		closure[dep] = true
-		sub, err := buildVarClosure(dep, varDeps, state)
-		if err != nil {
-			return nil, err
-		}
-		for k := range sub {
-			closure[k] = true
-		}
-	}
-	state[start] = 2
-	return closure, nil
-}
-
-// topoSortVars는
-//   - 변수 초기화 간 의존성 그래프를 받아
-//   - 위상 정렬된 변수 ID 목록을 반환한다
-//   - 순환 의존이 있으면 에러를 반환한다
-func topoSortVars(varOrder []parser.IdId, varInitExpr map[parser.IdId]parser.Expr, varToVarDeps map[parser.IdId]map[parser.IdId]bool) ([]parser.IdId, error) {
-	// state[id]:
-	// 0 = 아직 방문 안 함
-	// 1 = 방문 중 (DFS 스택에 있음)(=체인에 올려져 있음)
-	// 2 = 방문 완료 (위상정렬 결과에 이미 반영됨)
-	state := map[parser.IdId]int{}
-	result := []parser.IdId{}
-
-	var visit func(parser.IdId) error
-	visit = func(id parser.IdId) error {
-		if state[id] == 1 {
-			return fmt.Errorf("cycle detected among vars at #%d", id)
-		}
-		if state[id] == 2 {
-			return nil
-		}
-		// 이 변수를 우선순위 체인에 올림
-		state[id] = 1
-		// 체인에 올린 상태로 의존성 목록에 대해 의존성 사이클 체크
-		// 가장 끄트머리에 있는 의존이, 재귀 함수 하에서 가장 먼저 결과에 append
-		for _, dep := range sortedIdsFromMap(varToVarDeps[id]) {
-			if err := visit(dep); err != nil {
+		if node.TagOrNil != nil {
+			if err := walkExprRefs(node.TagOrNil, table, hoist, refs); err != nil {
 				return err
 			}
 		}
-		//사이클 없다면 상태 바꾼 후 result에 포함
-		state[id] = 2
-		result = append(result, id)
-		return nil
-	}
-
-	for _, id := range varOrder {
-		if varInitExpr[id] == nil {
-			continue
-		}
-		if err := visit(id); err != nil {
-			return nil, err
+		for _, clause := range node.Clauses {
+			for _, expr := range clause.Exprs {
+				if err := walkExprRefs(expr, table, hoist, refs); err != nil {
+					return err
+				}
+			}
+			if err := walkBlockRefs(clause.Block, table, hoist, refs); err != nil {
+				return err
+			}
 		}
-	}
-	return result, nil
-}
 
-func addDependency[T comparable](deps map[T]map[T]bool, from, to T) {
-	if deps[from] == nil {
-		deps[from] = map[T]bool{}
+	case *parser.Block:
+		return walkBlockRefs(*node, table, hoist, refs)
 	}
-	deps[from][to] = true
+	return nil
 }
 
 func sortedIds(ids []parser.IdId) []parser.IdId {
@@ -551,27 +381,3 @@ func sortedIds(ids []parser.IdId) []parser.IdId {
 	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
 	return out
 }
-
-func sortedIdsFromMap(m map[parser.IdId]bool) []parser.IdId {
-	if len(m) == 0 {
-		return nil
-	}
-	out := make([]parser.IdId, 0, len(m))
-	for id := range m {
-		out = append(out, id)
-	}
-	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
-	return out
-}
-
-func sortedIdsFromMapExpr(m map[parser.IdId]parser.Expr) []parser.IdId {
-	if len(m) == 0 {
-		return nil
-	}
-	out := make([]parser.IdId, 0, len(m))
-	for id := range m {
-		out = append(out, id)
-	}
-	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
-	return out
-}
@@ -1,6 +1,13 @@
 package resolver
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
 
 func initOrderNames(order InitOrder, hoist *HoistInfo) []string {
 	names := make([]string, 0, len(order))
@@ -206,3 +213,108 @@ func TestInitOrder_ComplexDependencies(t *testing.T) {
 		}
 	}
 }
+
+func TestInitOrder_ThroughFuncCalls(t *testing.T) {
+	input := "var a int = f(); func f() int { return g(); } func g() int { return b; } var b int = 7;"
+	_, table, hoist, err := resolveFromInput(t, input)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	order, err := BuildInitOrder(table, hoist)
+	if err != nil {
+		t.Fatalf("unexpected init order error: %v", err)
+	}
+	names := initOrderNames(order, hoist)
+	want := []string{"b", "a"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("init order mismatch: got %v want %v", names, want)
+	}
+}
+
+func TestInitOrder_CycleErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "var_var",
+			input: "var a int = b;\nvar b int = a;",
+			want: "initialization cycle: a -> b -> a\n" +
+				"\t1:13: a refers to b\n" +
+				"\t2:13: b refers to a",
+		},
+		{
+			name:  "through_funcs",
+			input: "var a int = f();\nfunc f() int { return g(); }\nfunc g() int { return b; }\nvar b int = a + 1;",
+			want: "initialization cycle: a -> f() -> g() -> b -> a\n" +
+				"\t1:13: a refers to f()\n" +
+				"\t2:23: f() refers to g()\n" +
+				"\t3:23: g() refers to b\n" +
+				"\t4:13: b refers to a",
+		},
+		{
+			name:  "self_through_closure_var",
+			input: "var f func() int = func() int { return a; };\nvar a int = f();",
+			want: "initialization cycle: a -> f -> a\n" +
+				"\t2:13: a refers to f\n" +
+				"\t1:40: f refers to a",
+		},
+		{
+			// 메서드는 수신자 타입을 보지 않으므로 같은 이름의 메서드 모두를 따라감
+			name:  "through_method",
+			input: "type T struct { n int; }\nfunc (t T) Get() int { return total; }\nvar total int = T{n: 1}.Get();",
+			want: "initialization cycle: total -> T.Get() -> total\n" +
+				"\t3:25: total refers to T.Get()\n" +
+				"\t2:31: T.Get() refers to total",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lx := lexer.NewLexer()
+			lx.Set(tc.input)
+			pkg, err := parser.NewParser(lx).ParsePackage()
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			_, _, _, _, err = Resolve(pkg)
+			var cycle *InitCycleError
+			if !errors.As(err, &cycle) {
+				t.Fatalf("expected init cycle error, got %v", err)
+			}
+			if err.Error() != tc.want {
+				t.Fatalf("error mismatch:\n--- got ---\n%s\n--- want ---\n%s", err.Error(), tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildInitGraph(t *testing.T) {
+	input := "var z int; var a int = f(); func f() int { return z + helper(); } func helper() int { return 1; } func unused() int { return a; }"
+	_, table, hoist, err := resolveFromInput(t, input)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	graph, err := BuildInitGraph(table, hoist)
+	if err != nil {
+		t.Fatalf("unexpected init graph error: %v", err)
+	}
+	got := []string{}
+	for _, node := range graph.Nodes {
+		line := node.String() + " (" + node.Kind.String() + ")"
+		for _, edge := range graph.Edges(node) {
+			line += " -> " + edge.To.String()
+		}
+		got = append(got, line)
+	}
+	// 초기식에서 닿지 않는 함수는 그래프에 없음
+	want := []string{
+		"z (zero var)",
+		"a (var) -> f()",
+		"f() (func) -> z -> helper()",
+		"helper() (func)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("graph mismatch:\n--- got ---\n%s\n--- want ---\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
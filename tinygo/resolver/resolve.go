@@ -10,7 +10,7 @@ func Resolve(pkg *parser.PackageAST) (ResolveTable, *HoistInfo, InitOrder, map[s
 	}
 	order, ierr := BuildInitOrder(table, hoist)
 	if ierr != nil {
-		return table, hoist, order, nil, ierr
+		return table, hoist, order, nil, attachPositions(ierr, pkg)
 	}
 	return table, hoist, order, copyBuiltins(rs.builtins), nil
 }
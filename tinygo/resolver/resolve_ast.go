@@ -2,23 +2,42 @@ package resolver
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
 )
 
 func (r *Resolver) ResolvePackage(pkg *parser.PackageAST) (ResolveTable, *HoistInfo, error) {
-	// 패키지 레벨의 선언은 호이스팅함
-	hoist, err := r.collectPackageDecls(pkg)
-	if err != nil {
+	hoist := newHoistInfo()
+	if err := r.resolvePackageInto(pkg, hoist); err != nil {
 		return r.table, nil, err
 	}
+	return r.table, hoist, nil
+}
+
+// resolvePackageInto는 현재 전역 스코프에 pkg를 리졸빙하고, 그 선언들을 hoist에 누적함
+func (r *Resolver) resolvePackageInto(pkg *parser.PackageAST, hoist *HoistInfo) error {
+	// 임포트한 패키지 이름은 선언보다 먼저 등록해, 같은 이름의 선언과 충돌하게 함
+	if err := r.declareImports(pkg.Imports); err != nil {
+		return err
+	}
+	r.hoist = hoist
+	// 패키지 레벨의 선언은 호이스팅함
+	firstMethod := len(hoist.methodOrder)
+	if err := r.collectPackageDecls(pkg, hoist); err != nil {
+		return err
+	}
+	if err := r.collectMethods(hoist, hoist.methodOrder[firstMethod:]); err != nil {
+		return err
+	}
 	for _, decl := range pkg.DeclsOrNil {
 		// 호이스팅된 정보를 가지고 DFS식 리졸빙 시작
 		if err := r.resolveDeclWithHoist(decl, hoist); err != nil {
-			return r.table, nil, err
+			return err
 		}
 	}
-	return r.table, hoist, nil
+	// 타입 본문까지 리졸빙된 후에야 재귀 타입 여부를 판단할 수 있음
+	return r.checkRecursiveTypes(hoist)
 }
 
 // 패키지 레벨의 선언은 호이스팅됨
@@ -27,7 +46,14 @@ func (r *Resolver) resolveDeclWithHoist(decl parser.Decl, hoist *HoistInfo) erro
 	case *parser.VarDecl:
 		return r.resolveHoistedVarDecl(node, hoist)
 	case *parser.FuncDecl:
+		if node.ReceiverOrNil != nil {
+			return r.resolveMethodDecl(node, hoist)
+		}
 		return r.resolveHoistedFuncDecl(node, hoist)
+	case *parser.TypeDecl:
+		return r.resolveTypeDecl(node, hoist)
+	case *parser.ConstDecl:
+		return r.resolveConstDecl(node, hoist)
 	default:
 		return nil
 	}
@@ -38,7 +64,7 @@ func (r *Resolver) resolveStmt(stmt parser.Stmt) error {
 	case *parser.Assign:
 		return r.resolveAssign(node)
 	case *parser.CallStmt:
-		return r.resolveCall(node.Call)
+		return r.resolveCall(&node.Call)
 	case *parser.ShortDecl:
 		return r.resolveShortDecl(node)
 	case *parser.VarDecl:
@@ -57,6 +83,19 @@ func (r *Resolver) resolveStmt(stmt parser.Stmt) error {
 		return r.resolveForBexp(node)
 	case *parser.ForWithAssign:
 		return r.resolveForWithAssign(node)
+	case *parser.ForRange:
+		return r.resolveForRange(node)
+	case *parser.Yield:
+		return r.resolveYield(node)
+	case *parser.TypeSwitch:
+		return r.resolveTypeSwitch(node)
+	case *parser.Switch:
+		return r.resolveSwitch(node)
+	case *parser.Fallthrough:
+		// 올바른 위치의 fallthrough는 파서가 절의 속성으로 옮겼음
+		return r.funcErr(nil, "fallthrough statement out of place")
+	case *parser.TryStmt:
+		return r.resolveTry(node.Try)
 
 	case *parser.Block:
 		// 그냥 블록 시엔 새 스코프
@@ -74,11 +113,48 @@ func (r *Resolver) resolveExpr(expr parser.Expr) error {
 		if err := r.resolveExpr(node.LeftExpr); err != nil {
 			return err
 		}
-		return r.resolveExpr(node.RightExpr)
+		if err := r.resolveExpr(node.RightExpr); err != nil {
+			return err
+		}
+		return r.checkTypeParamOperands(node)
 	case *parser.Primary:
 		return r.resolvePrimary(node)
 	case *parser.Call:
-		return r.resolveCall(*node)
+		return r.resolveCall(node)
+	case *parser.Selector:
+		if module, ok := r.builtinModuleOf(node.Object); ok {
+			return r.resolveModuleMember(node, module)
+		}
+		if pkgSym, ok := r.importedPackageOf(node.Object); ok {
+			return r.resolvePackageMember(node, pkgSym)
+		}
+		// 필드 이름은 런타임 값의 타입에 따라 결정되므로 리졸빙하지 않음
+		return r.resolveExpr(node.Object)
+	case *parser.Index:
+		if err := r.resolveExpr(node.Object); err != nil {
+			return err
+		}
+		// f[T] 는 파서가 인덱스로 읽었더라도, T가 타입이라면 인스턴스화임
+		if node.TypeArgsOrNil == nil && r.isTypeName(node.Index) {
+			node.TypeArgsOrNil = []parser.Type{{TypeKind: parser.NamedType, NameOrNil: node.Index.(*parser.Primary).IdOrNil}}
+			node.Index = nil
+		}
+		if node.TypeArgsOrNil != nil {
+			return r.resolveInstantiation(node)
+		}
+		return r.resolveExpr(node.Index)
+	case *parser.Try:
+		return r.resolveTry(node)
+	case *parser.Match:
+		return r.resolveMatch(node)
+	case *parser.TypeAssert:
+		if node.TypeOrNil == nil {
+			return errors.New("use of .(type) outside type switch")
+		}
+		if err := r.resolveExpr(node.Object); err != nil {
+			return err
+		}
+		return r.resolveType(*node.TypeOrNil)
 	default:
 		return nil
 	}
@@ -93,6 +169,15 @@ func (r *Resolver) resolvePrimary(node *parser.Primary) error {
 		if err != nil {
 			return err
 		}
+		if ref.Kind == RefType || ref.Kind == RefTypeParam {
+			return newResolveErr(*node.IdOrNil, "type is not an expression")
+		}
+		if _, ok := builtinModuleByName(ref.Name); ok && ref.Kind == RefBuiltin {
+			return newResolveErr(*node.IdOrNil, fmt.Sprintf("use of module %s without selector", ref.Name))
+		}
+		if ref.Kind == RefPackage {
+			return newResolveErr(*node.IdOrNil, fmt.Sprintf("use of package %s without selector", ref.Name))
+		}
 		r.setResolved(*node.IdOrNil, ref)
 		return nil
 	case parser.ValuePrimary:
@@ -109,25 +194,78 @@ func (r *Resolver) resolveValueForm(v *parser.ValueForm) error {
 	if v.ValueKind == parser.FexpValue {
 		return r.resolveFexp(v.FexpOrNil)
 	}
+	if v.ValueKind == parser.StructLitValue {
+		return r.resolveStructLit(v.StructLitOrNil)
+	}
+	if v.ValueKind == parser.TupleLitValue {
+		for _, elem := range v.TupleLitOrNil.Elems {
+			if err := r.resolveExpr(elem); err != nil {
+				return err
+			}
+		}
+	}
+	if v.ValueKind == parser.SliceLitValue {
+		if err := r.resolveType(v.SliceLitOrNil.ElemType); err != nil {
+			return err
+		}
+		for _, elem := range v.SliceLitOrNil.Elems {
+			if err := r.resolveExpr(elem); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// builtinModuleOf는 expr이 셰도잉되지 않은 빌트인 모듈 이름이면 해당 모듈을 리턴함
+func (r *Resolver) builtinModuleOf(expr parser.Expr) (BuiltinModule, bool) {
+	primary, ok := expr.(*parser.Primary)
+	if !ok || primary.PrimaryKind != parser.IdPrimary {
+		return BuiltinModule{}, false
+	}
+	sym := r.lookup(primary.IdOrNil.Name)
+	if sym == nil || sym.kind != SymbolBuiltin {
+		return BuiltinModule{}, false
+	}
+	return builtinModuleByName(sym.name)
+}
+
+// resolveModuleMember는 모듈 이름을 빌트인으로 연결하고, 멤버가 실제로 존재하는지 검사함
+func (r *Resolver) resolveModuleMember(node *parser.Selector, module BuiltinModule) error {
+	id := *node.Object.(*parser.Primary).IdOrNil
+	ref, err := r.resolveID(id)
+	if err != nil {
+		return err
+	}
+	r.setResolved(id, ref)
+	if !module.hasMember(node.Field.Name) {
+		return newResolveErr(node.Field, fmt.Sprintf("undefined: %s.%s", module.Name, node.Field.Name))
+	}
 	return nil
 }
 
 func (r *Resolver) resolveFexp(f *parser.Fexp) error {
 	r.pushScope()
 	defer r.popScope()
+	r.pushFuncReturns(nil, f.ReturnTypesOrNil, &f.IsGenerator)
+	defer r.popFuncReturns()
 	// fexp: params이전부터 새 스코프
+	if err := r.resolveSignatureTypes(f.ParamsOrNil, f.ReturnTypesOrNil); err != nil {
+		return err
+	}
 	for _, param := range f.ParamsOrNil {
 		sym, err := r.declare(param.Id.Name, SymbolParam, param.Id.IdId)
 		if err != nil {
 			return newResolveErr(param.Id, err.Error())
 		}
 		r.setResolved(param.Id, r.refFromSymbol(sym))
+		r.declTypes[param.Id.IdId] = param.Type
 	}
 	// true에 의해 block은 스코프 재사용
 	return r.resolveBlock(f.Block, true)
 }
 
-func (r *Resolver) resolveCall(call parser.Call) error {
+func (r *Resolver) resolveCall(call *parser.Call) error {
 	if err := r.resolvePrimary(&call.PrimaryOrNil); err != nil {
 		return err
 	}
@@ -138,10 +276,13 @@ func (r *Resolver) resolveCall(call parser.Call) error {
 			}
 		}
 	}
-	return nil
+	return r.checkGenericCall(call)
 }
 
 func (r *Resolver) resolveVarDecl(node *parser.VarDecl) error {
+	if err := r.resolveType(node.Type); err != nil {
+		return err
+	}
 	// 우변 먼저 resolve (재귀적 정의 차단)
 	for _, expr := range node.ExprsOrNil {
 		if err := r.resolveExpr(expr); err != nil {
@@ -155,11 +296,15 @@ func (r *Resolver) resolveVarDecl(node *parser.VarDecl) error {
 			return newResolveErr(id, err.Error())
 		}
 		r.setResolved(id, r.refFromSymbol(sym))
+		r.declTypes[id.IdId] = node.Type
 	}
 	return nil
 }
 
 func (r *Resolver) resolveHoistedVarDecl(node *parser.VarDecl, hoist *HoistInfo) error {
+	if err := r.resolveType(node.Type); err != nil {
+		return err
+	}
 	//우변 리졸브
 	for _, expr := range node.ExprsOrNil {
 		if err := r.resolveExpr(expr); err != nil {
@@ -172,25 +317,39 @@ func (r *Resolver) resolveHoistedVarDecl(node *parser.VarDecl, hoist *HoistInfo)
 		if hoist.getVarDeclById(id.IdId) == nil {
 			return newResolveErr(id, "hoisted symbol not found")
 		}
+		r.declTypes[id.IdId] = node.Type
 	}
 	return nil
 }
 func (r *Resolver) resolveFuncDecl(node *parser.FuncDecl) error {
+	if node.ReceiverOrNil != nil {
+		return newResolveErr(node.Id, "method declaration must be at package level")
+	}
 	// 좌변 먼저 리졸브 (재귀 허용)
 	sym, err := r.declare(node.Id.Name, SymbolFunc, node.Id.IdId)
 	if err != nil {
 		return newResolveErr(node.Id, err.Error())
 	}
 	r.setResolved(node.Id, r.refFromSymbol(sym))
+	r.recordFuncDecl(node)
 	//이후 우변 리졸브
 	r.pushScope()
 	defer r.popScope()
+	r.pushFuncReturns(&node.Id, node.ReturnTypesOrNil, &node.IsGenerator)
+	defer r.popFuncReturns()
+	if err := r.declareTypeParams(node.TypeParamsOrNil); err != nil {
+		return err
+	}
+	if err := r.resolveSignatureTypes(node.ParamsOrNil, node.ReturnTypesOrNil); err != nil {
+		return err
+	}
 	for _, param := range node.ParamsOrNil {
 		psym, perr := r.declare(param.Id.Name, SymbolParam, param.Id.IdId)
 		if perr != nil {
 			return newResolveErr(param.Id, perr.Error())
 		}
 		r.setResolved(param.Id, r.refFromSymbol(psym))
+		r.declTypes[param.Id.IdId] = param.Type
 	}
 	return r.resolveBlock(node.Block, true)
 }
@@ -200,19 +359,28 @@ func (r *Resolver) resolveHoistedFuncDecl(node *parser.FuncDecl, hoist *HoistInf
 	// 좌변 먼저 리졸브
 	// But 패키지 레벨 호이스팅된 함수는
 	// 이미 수집되었으므로, 잘 수집되었나 검사만 하.ㅁ
-	if hoist.getFuncDeclById(node.Id.IdId) == nil {
+	if hoist.getFuncDeclById(node.Id.IdId) == nil && hoist.initDeclById[node.Id.IdId] == nil {
 		return newResolveErr(node.Id, "hoisted symbol not found")
 	}
 	r.pushScope()
 	defer r.popScope()
+	r.pushFuncReturns(&node.Id, node.ReturnTypesOrNil, &node.IsGenerator)
+	defer r.popFuncReturns()
 
 	//우변 리졸브
+	if err := r.declareTypeParams(node.TypeParamsOrNil); err != nil {
+		return err
+	}
+	if err := r.resolveSignatureTypes(node.ParamsOrNil, node.ReturnTypesOrNil); err != nil {
+		return err
+	}
 	for _, param := range node.ParamsOrNil {
 		psym, perr := r.declare(param.Id.Name, SymbolParam, param.Id.IdId)
 		if perr != nil {
 			return newResolveErr(param.Id, perr.Error())
 		}
 		r.setResolved(param.Id, r.refFromSymbol(psym))
+		r.declTypes[param.Id.IdId] = param.Type
 	}
 	return r.resolveBlock(node.Block, true)
 }
@@ -225,15 +393,32 @@ func (r *Resolver) resolveAssign(node *parser.Assign) error {
 		}
 	}
 	// 좌변 리졸브
-	for _, id := range node.Ids {
+	for i, id := range node.Ids {
 		ref, err := r.resolveID(id)
 		if err != nil {
 			return err
 		}
+		// 상수와 let 바인딩은 다시 대입할 수도, 필드를 바꿀 수도 없음
+		if sym := r.lookup(id.Name); sym.kind == SymbolConst || sym.immutable {
+			what := "cannot assign to"
+			if node.FieldPathsOrNil != nil && len(node.FieldPathsOrNil[i]) > 0 {
+				what = "cannot assign to field of"
+			}
+			return newResolveErr(id, immutableBindingMsg(sym, what))
+		}
 		//빌트인엔 할당 불가
 		if ref.Kind == RefBuiltin {
 			return newResolveErr(id, "cannot assign to builtin")
 		}
+		if ref.Kind == RefType || ref.Kind == RefTypeParam {
+			return newResolveErr(id, "cannot assign to type")
+		}
+		if ref.Kind == RefPackage {
+			return newResolveErr(id, "cannot assign to package")
+		}
+		if _, ok := r.variants[ref.RefIdNodeId]; ok && ref.Kind == RefGlobal {
+			return newResolveErr(id, "cannot assign to variant constructor")
+		}
 		r.setResolved(id, ref)
 	}
 	return nil
@@ -247,9 +432,16 @@ func (r *Resolver) resolveShortDecl(node *parser.ShortDecl) error {
 		}
 	}
 	newCount := 0
-	for _, id := range node.Ids {
+	for i, id := range node.Ids {
 		// 스코프에 존재 시 할당으로 처리
-		if sym, ok := r.currentScope.symbols[id.Name]; ok {
+		// 단 let은 새 이름만 선언하므로, 이미 있는 이름은 아래 declare에서 중복 선언 에러가 됨
+		if sym, ok := r.currentScope.symbols[id.Name]; ok && !node.Immutable {
+			if sym.kind == SymbolConst || sym.immutable {
+				return newResolveErr(id, immutableBindingMsg(sym, "cannot redeclare"))
+			}
+			if sym.kind == SymbolType || sym.kind == SymbolTypeParam {
+				return newResolveErr(id, "cannot assign to type")
+			}
 			ref, err := r.resolveID(id)
 			if err != nil {
 				return err
@@ -266,8 +458,14 @@ func (r *Resolver) resolveShortDecl(node *parser.ShortDecl) error {
 		if err != nil {
 			return newResolveErr(id, err.Error())
 		}
+		sym.immutable = node.Immutable
 		newCount++
 		r.setResolved(id, r.refFromSymbol(sym))
+		if len(node.Exprs) == len(node.Ids) {
+			if t, ok := r.staticType(node.Exprs[i]); ok {
+				r.declTypes[id.IdId] = t
+			}
+		}
 	}
 	// ShortDecl은 적어도 하나의 새 변수 필요
 	if newCount == 0 {
@@ -284,6 +482,42 @@ func (r *Resolver) resolveReturn(node *parser.Return) error {
 	}
 	return nil
 }
+
+// resolveTry는 ? 가 마지막 리턴 타입이 error인 함수 안에서만 쓰였는지 검사함
+// 에러가 전파될 때 나머지 리턴 값은 제로값으로 채워짐
+func (r *Resolver) resolveTry(node *parser.Try) error {
+	if len(r.funcReturns) == 0 {
+		return r.funcErr(node.Object, "? operator used outside function")
+	}
+	returnTypes := r.funcReturns[len(r.funcReturns)-1]
+	if len(returnTypes) == 0 || returnTypes[len(returnTypes)-1].TypeKind != parser.ErrorType {
+		return r.funcErr(node.Object, "? operator requires function whose last result is error")
+	}
+	return r.resolveExpr(node.Object)
+}
+
+// resolveYield는 yield가 리턴 타입 없는 함수 안에서만 쓰였는지 검사하고
+// 그 함수를 제너레이터로 표시함
+func (r *Resolver) resolveYield(node *parser.Yield) error {
+	var anchor parser.Expr
+	if len(node.Exprs) > 0 {
+		anchor = node.Exprs[0]
+	}
+	if len(r.funcReturns) == 0 {
+		return r.funcErr(anchor, "yield used outside function")
+	}
+	if len(r.funcReturns[len(r.funcReturns)-1]) != 0 {
+		return r.funcErr(anchor, "generator function cannot have result types")
+	}
+	*r.funcGenerators[len(r.funcGenerators)-1] = true
+	for _, expr := range node.Exprs {
+		if err := r.resolveExpr(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Resolver) resolveBreak(_ *parser.Break) error {
 	return nil
 }
@@ -328,7 +562,7 @@ func (r *Resolver) resolveForBexp(node *parser.ForBexp) error {
 }
 
 func (r *Resolver) resolveForWithAssign(node *parser.ForWithAssign) error {
-	r.pushScope()
+	r.pushLoopScope(&node.LoopVarsCaptured)
 	defer r.popScope()
 
 	if err := r.resolveShortDecl(&node.ShortDecl); err != nil {
@@ -344,6 +578,29 @@ func (r *Resolver) resolveForWithAssign(node *parser.ForWithAssign) error {
 	return r.resolveBlock(node.Block, false)
 }
 
+// resolveForRange는 range 대상을 바깥 스코프에서 리졸브한 뒤
+// 루프 스코프에 range 변수를 선언함. "_" 자리는 선언하지 않음
+func (r *Resolver) resolveForRange(node *parser.ForRange) error {
+	if err := r.resolveExpr(node.Expr); err != nil {
+		return err
+	}
+	r.pushLoopScope(&node.LoopVarsCaptured)
+	defer r.popScope()
+
+	for _, v := range node.Vars {
+		if v.Name == "_" {
+			continue
+		}
+		sym, err := r.declare(v.Name, SymbolVar, v.IdId)
+		if err != nil {
+			return newResolveErr(v, err.Error())
+		}
+		r.setResolved(v, r.refFromSymbol(sym))
+	}
+	// for역시 블록과 스코프 분리
+	return r.resolveBlock(node.Block, false)
+}
+
 func (r *Resolver) resolveBlock(block parser.Block, reuseCurrent bool) error {
 	// 스코프 합쳐달란 요청이 있었다면
 	// 기존의 스코프를 재사용해서 스코프 합침.
@@ -374,7 +631,16 @@ func (r *Resolver) refFromSymbol(sym *Symbol) ResolvedRef {
 		ref.Kind = RefBuiltin
 		ref.Distance = 0
 		ref.Slot = r.builtins[sym.name]
-	case SymbolFunc, SymbolVar:
+	case SymbolType:
+		ref.Kind = RefType
+		ref.Distance = 0
+	case SymbolTypeParam:
+		ref.Kind = RefTypeParam
+		ref.Distance = 0
+	case SymbolPackage:
+		ref.Kind = RefPackage
+		ref.Distance = 0
+	case SymbolFunc, SymbolVar, SymbolVariant, SymbolConst:
 		if sym.scope == r.global {
 			ref.Kind = RefGlobal
 			ref.Distance = 0
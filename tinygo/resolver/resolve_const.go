@@ -0,0 +1,272 @@
+package resolver
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// ConstValue는 리졸빙 시점에 접힌 상수의 값임
+// Kind는 IntType, BoolType, StringType 중 하나이며, 그에 맞는 필드 하나만 의미를 가짐
+type ConstValue struct {
+	Kind parser.TypeKind
+	Int  int64
+	Bool bool
+	Str  string
+}
+
+func (v ConstValue) String() string {
+	switch v.Kind {
+	case parser.IntType:
+		return strconv.FormatInt(v.Int, 10)
+	case parser.BoolType:
+		return strconv.FormatBool(v.Bool)
+	default:
+		return strconv.Quote(v.Str)
+	}
+}
+
+func constKindName(kind parser.TypeKind) string {
+	switch kind {
+	case parser.IntType:
+		return "int"
+	case parser.BoolType:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// constEntry는 const 명세에서 이름 하나에 해당하는 식과, 접힌 결과임
+// 묶음 안에서 식이 생략된 명세는 앞 명세의 식을 iota만 바꿔 다시 접음
+type constEntry struct {
+	id        parser.Id
+	expr      parser.Expr
+	typeOrNil *parser.Type
+	iota      int64
+	state     constState
+	value     ConstValue
+}
+
+type constState uint8
+
+const (
+	constUnfolded constState = iota
+	// 접는 중에 다시 만나면 상수 정의가 순환하는 것임
+	constFolding
+	constFolded
+)
+
+// collectConstDecl은 const 선언의 이름들을 전역 스코프에 호이스팅함
+// 상수끼리는 선언 순서와 무관하게 참조할 수 있으며, 값은 resolveConstDecl에서 접음
+func (r *Resolver) collectConstDecl(node *parser.ConstDecl, hoist *HoistInfo) error {
+	var lastExprs []parser.Expr
+	var lastType *parser.Type
+	for i, spec := range node.Specs {
+		if len(spec.ExprsOrNil) > 0 {
+			lastExprs, lastType = spec.ExprsOrNil, spec.TypeOrNil
+		}
+		if len(spec.Ids) != len(lastExprs) {
+			return newResolveErr(firstId(spec.Ids), fmt.Sprintf("const declaration has %d names but %d values", len(spec.Ids), len(lastExprs)))
+		}
+		for j, id := range spec.Ids {
+			if id.Name == "init" {
+				return newResolveErr(id, cannotDeclareInitMsg)
+			}
+			sym, err := r.declare(id.Name, SymbolConst, id.IdId)
+			if err != nil {
+				return newResolveErr(id, err.Error())
+			}
+			hoist.globalsByName[id.Name] = sym
+			hoist.globalsById[id.IdId] = sym
+			hoist.constOrder = append(hoist.constOrder, id.IdId)
+			r.consts[id.IdId] = &constEntry{id: id, expr: lastExprs[j], typeOrNil: lastType, iota: int64(i)}
+			r.setResolved(id, r.refFromSymbol(sym))
+		}
+	}
+	return nil
+}
+
+// resolveConstDecl은 선언된 상수들의 값을 접어 hoist에 기록함
+func (r *Resolver) resolveConstDecl(node *parser.ConstDecl, hoist *HoistInfo) error {
+	for _, spec := range node.Specs {
+		for _, id := range spec.Ids {
+			value, err := r.foldConst(r.consts[id.IdId])
+			if err != nil {
+				return err
+			}
+			hoist.constValueById[id.IdId] = value
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) foldConst(entry *constEntry) (ConstValue, error) {
+	switch entry.state {
+	case constFolded:
+		return entry.value, nil
+	case constFolding:
+		return ConstValue{}, newResolveErr(entry.id, "constant definition loop")
+	}
+	entry.state = constFolding
+	value, err := r.foldConstExpr(entry, entry.expr)
+	if err != nil {
+		return ConstValue{}, err
+	}
+	if entry.typeOrNil != nil {
+		if err := r.resolveType(*entry.typeOrNil); err != nil {
+			return ConstValue{}, err
+		}
+		kind := entry.typeOrNil.TypeKind
+		if kind != parser.IntType && kind != parser.BoolType && kind != parser.StringType {
+			return ConstValue{}, newResolveErr(entry.id, fmt.Sprintf("invalid constant type %s", entry.typeOrNil.String()))
+		}
+		if kind != value.Kind {
+			return ConstValue{}, newResolveErr(entry.id, fmt.Sprintf("cannot use %s constant %s as %s", constKindName(value.Kind), value.String(), constKindName(kind)))
+		}
+	}
+	entry.state = constFolded
+	entry.value = value
+	return value, nil
+}
+
+// foldConstExpr는 상수식을 리졸빙 시점에 계산함
+// 리터럴, iota, 다른 상수와 이들의 단항, 이항 연산만 상수식이며, 연산 규칙은 런타임과 같음
+func (r *Resolver) foldConstExpr(entry *constEntry, expr parser.Expr) (ConstValue, error) {
+	switch node := expr.(type) {
+	case *parser.Primary:
+		return r.foldConstPrimary(entry, node)
+	case *parser.Selector:
+		pkgSym, ok := r.importedPackageOf(node.Object)
+		if !ok {
+			break
+		}
+		if err := r.resolvePackageMember(node, pkgSym); err != nil {
+			return ConstValue{}, err
+		}
+		member := r.packageScopes[pkgSym.importPath].symbols[node.Field.Name]
+		if member.kind != SymbolConst {
+			return ConstValue{}, newResolveErr(node.Field, fmt.Sprintf("%s.%s is not a constant", pkgSym.name, node.Field.Name))
+		}
+		return r.foldConst(r.consts[member.idNodeId])
+	case *parser.Unary:
+		operand, err := r.foldConstExpr(entry, node.Object)
+		if err != nil {
+			return ConstValue{}, err
+		}
+		switch {
+		case node.Op == parser.MinusUnary && operand.Kind == parser.IntType:
+			return ConstValue{Kind: parser.IntType, Int: -operand.Int}, nil
+		case node.Op == parser.Not && operand.Kind == parser.BoolType:
+			return ConstValue{Kind: parser.BoolType, Bool: !operand.Bool}, nil
+		}
+		return ConstValue{}, newResolveErr(entry.id, fmt.Sprintf("invalid constant operation on %s", constKindName(operand.Kind)))
+	case *parser.Binary:
+		left, err := r.foldConstExpr(entry, node.LeftExpr)
+		if err != nil {
+			return ConstValue{}, err
+		}
+		right, err := r.foldConstExpr(entry, node.RightExpr)
+		if err != nil {
+			return ConstValue{}, err
+		}
+		return foldConstBinary(entry, node.Op, left, right)
+	}
+	return ConstValue{}, newResolveErr(entry.id, "const initializer is not a constant expression")
+}
+
+func (r *Resolver) foldConstPrimary(entry *constEntry, node *parser.Primary) (ConstValue, error) {
+	switch node.PrimaryKind {
+	case parser.ExprPrimary:
+		return r.foldConstExpr(entry, node.ExprOrNil)
+	case parser.IdPrimary:
+		id := *node.IdOrNil
+		sym := r.lookup(id.Name)
+		// iota는 셰도잉되지 않았다면 명세의 순번임
+		if sym == nil && id.Name == "iota" {
+			return ConstValue{Kind: parser.IntType, Int: entry.iota}, nil
+		}
+		ref, err := r.resolveID(id)
+		if err != nil {
+			return ConstValue{}, err
+		}
+		if sym.kind != SymbolConst {
+			return ConstValue{}, newResolveErr(id, fmt.Sprintf("%s is not a constant", id.Name))
+		}
+		r.setResolved(id, ref)
+		return r.foldConst(r.consts[sym.idNodeId])
+	case parser.ValuePrimary:
+		value := node.ValueOrNil
+		switch value.ValueKind {
+		case parser.NumberValue:
+			return ConstValue{Kind: parser.IntType, Int: int64(*value.NumberOrNil)}, nil
+		case parser.BoolValue:
+			return ConstValue{Kind: parser.BoolType, Bool: *value.BoolOrNil}, nil
+		case parser.StrLitValue:
+			return ConstValue{Kind: parser.StringType, Str: *value.StrLitOrNil}, nil
+		}
+	}
+	return ConstValue{}, newResolveErr(entry.id, "const initializer is not a constant expression")
+}
+
+func foldConstBinary(entry *constEntry, op parser.BinaryKind, left, right ConstValue) (ConstValue, error) {
+	if left.Kind != right.Kind {
+		return ConstValue{}, newResolveErr(entry.id, fmt.Sprintf("invalid constant operation: mismatched types %s and %s", constKindName(left.Kind), constKindName(right.Kind)))
+	}
+	switch op {
+	case parser.Equal, parser.NotEqual:
+		eq := left == right
+		if op == parser.NotEqual {
+			eq = !eq
+		}
+		return ConstValue{Kind: parser.BoolType, Bool: eq}, nil
+	case parser.Plus:
+		if left.Kind == parser.StringType {
+			return ConstValue{Kind: parser.StringType, Str: left.Str + right.Str}, nil
+		}
+	case parser.And, parser.Or:
+		if left.Kind == parser.BoolType {
+			if op == parser.And {
+				return ConstValue{Kind: parser.BoolType, Bool: left.Bool && right.Bool}, nil
+			}
+			return ConstValue{Kind: parser.BoolType, Bool: left.Bool || right.Bool}, nil
+		}
+	}
+	if left.Kind != parser.IntType {
+		return ConstValue{}, newResolveErr(entry.id, fmt.Sprintf("invalid constant operation on %s", constKindName(left.Kind)))
+	}
+	l, rv := left.Int, right.Int
+	switch op {
+	case parser.Plus:
+		return ConstValue{Kind: parser.IntType, Int: l + rv}, nil
+	case parser.MinusBinary:
+		return ConstValue{Kind: parser.IntType, Int: l - rv}, nil
+	case parser.Mul:
+		return ConstValue{Kind: parser.IntType, Int: l * rv}, nil
+	case parser.Div:
+		if rv == 0 {
+			return ConstValue{}, newResolveErr(entry.id, "division by zero in constant expression")
+		}
+		return ConstValue{Kind: parser.IntType, Int: l / rv}, nil
+	case parser.GreaterThan:
+		return ConstValue{Kind: parser.BoolType, Bool: l > rv}, nil
+	case parser.GreaterOrEqual:
+		return ConstValue{Kind: parser.BoolType, Bool: l >= rv}, nil
+	case parser.LessThan:
+		return ConstValue{Kind: parser.BoolType, Bool: l < rv}, nil
+	case parser.LessOrEqual:
+		return ConstValue{Kind: parser.BoolType, Bool: l <= rv}, nil
+	}
+	return ConstValue{}, newResolveErr(entry.id, "invalid constant operation on int")
+}
+
+// immutableBindingMsg는 다시 대입할 수 없는 심볼에 대입하려 할 때의 진단 메시지임
+// 선언 위치를 함께 알려 줌
+func immutableBindingMsg(sym *Symbol, what string) string {
+	kind := "let binding"
+	if sym.kind == SymbolConst {
+		kind = "constant"
+	}
+	return fmt.Sprintf("%s %s %s declared at %s(#%d)", what, kind, sym.name, sym.name, sym.idNodeId)
+}
@@ -1,6 +1,9 @@
 package resolver
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestResolveHoist_SuccessForwardRefs(t *testing.T) {
 	cases := []struct {
@@ -34,3 +37,74 @@ func TestResolveHoist_SuccessForwardRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveHoist_InitFuncs(t *testing.T) {
+	input := "var n int = 1; func init() { n = n + 1; } func main() { init := 3; print(init); } func init() { n = n * 2; }"
+	_, table, hoist, err := resolveFromInput(t, input)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	ids := hoist.InitFuncIds()
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 init funcs, got %d", len(ids))
+	}
+	// 선언 순서를 지키며, 전역 함수로는 등록되지 않음
+	if ids[0] >= ids[1] {
+		t.Fatalf("init funcs out of source order: %v", ids)
+	}
+	for _, id := range hoist.FuncIds() {
+		if hoist.GetFuncDeclById(id).Id.Name == "init" {
+			t.Fatalf("init must not be a hoisted func")
+		}
+	}
+	if ref := table[ids[0]]; ref.RefIdNodeId != ids[0] {
+		t.Fatalf("init decl id should resolve to itself, got %+v", ref)
+	}
+}
+
+func TestResolveHoist_InitFuncErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "call_init",
+			input: "func init() {} func main() { init(); }",
+			want:  "cannot refer to init function",
+		},
+		{
+			name:  "init_as_value",
+			input: "func init() {} var f func() = init;",
+			want:  "cannot refer to init function",
+		},
+		{
+			name:  "init_with_params",
+			input: "func init(n int) {}",
+			want:  "func init must have no arguments and no return values",
+		},
+		{
+			name:  "init_with_result",
+			input: "func init() int { return 1; }",
+			want:  "func init must have no arguments and no return values",
+		},
+		{
+			name:  "global_var_named_init",
+			input: "var init int = 1;",
+			want:  "cannot declare init - must be func",
+		},
+		{
+			name:  "const_named_init",
+			input: "const init = 1;",
+			want:  "cannot declare init - must be func",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := resolveFromInput(t, tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
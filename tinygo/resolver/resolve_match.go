@@ -0,0 +1,396 @@
+package resolver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// variantInfo는 합 타입 변형 하나가 어느 타입 선언의 몇 번째 변형인지를 기록함
+type variantInfo struct {
+	decl  *parser.TypeDecl
+	index int
+}
+
+func (info variantInfo) variant() parser.Variant {
+	return info.decl.Type.SumOrNil.Variants[info.index]
+}
+
+// variantOf는 현재 스코프에서 id가 합 타입 변형을 가리키면 그 심볼과 정보를 리턴함
+func (r *Resolver) variantOf(id parser.Id) (*Symbol, variantInfo, bool) {
+	sym := r.lookup(id.Name)
+	if sym == nil || sym.kind != SymbolVariant {
+		return nil, variantInfo{}, false
+	}
+	info, ok := r.variants[sym.idNodeId]
+	return sym, info, ok
+}
+
+func (r *Resolver) resolveMatch(node *parser.Match) error {
+	if err := r.resolveExpr(node.Subject); err != nil {
+		return err
+	}
+	for i := range node.Arms {
+		// 각 갈래는 자신만의 스코프를 가지며, 패턴이 묶은 이름은 가드와 본문에서만 보임
+		r.pushScope()
+		err := r.resolveMatchArm(&node.Arms[i])
+		r.popScope()
+		if err != nil {
+			return err
+		}
+	}
+	return r.checkExhaustive(node)
+}
+
+func (r *Resolver) resolveMatchArm(arm *parser.MatchArm) error {
+	if err := r.resolvePattern(&arm.Pattern, nil); err != nil {
+		return err
+	}
+	if arm.GuardOrNil != nil {
+		if err := r.resolveExpr(arm.GuardOrNil); err != nil {
+			return err
+		}
+	}
+	return r.resolveExpr(arm.Body)
+}
+
+// resolvePattern은 패턴의 이름들을 리졸빙함
+// 맨 식별자가 변형을 가리키면 변형 패턴으로 바꾸고, 아니면 현재 갈래 스코프에 변수로 선언함
+// fieldTypeOrNil은 변형 필드 자리의 선언 타입이며, 최상위 패턴이면 nil임
+func (r *Resolver) resolvePattern(pat *parser.Pattern, fieldTypeOrNil *parser.Type) error {
+	switch pat.PatternKind {
+	case parser.WildcardPattern:
+		return nil
+	case parser.LiteralPattern:
+		return r.resolveExpr(pat.LiteralOrNil)
+	case parser.BindPattern:
+		if _, _, ok := r.variantOf(*pat.IdOrNil); ok {
+			pat.PatternKind = parser.VariantPattern
+			pat.Args = []parser.Pattern{}
+			return r.resolveVariantPattern(pat, fieldTypeOrNil)
+		}
+		sym, err := r.declare(pat.IdOrNil.Name, SymbolVar, pat.IdOrNil.IdId)
+		if err != nil {
+			return newResolveErr(*pat.IdOrNil, err.Error())
+		}
+		r.setResolved(*pat.IdOrNil, r.refFromSymbol(sym))
+		return nil
+	case parser.VariantPattern:
+		return r.resolveVariantPattern(pat, fieldTypeOrNil)
+	default:
+		return nil
+	}
+}
+
+func (r *Resolver) resolveVariantPattern(pat *parser.Pattern, fieldTypeOrNil *parser.Type) error {
+	id := *pat.IdOrNil
+	sym, info, ok := r.variantOf(id)
+	if !ok {
+		return newResolveErr(id, "not a variant")
+	}
+	r.setResolved(id, r.refFromSymbol(sym))
+	if fieldTypeOrNil != nil && !r.fieldAcceptsVariant(*fieldTypeOrNil, info.decl) {
+		return newResolveErr(id, fmt.Sprintf("variant %s of %s cannot match field of type %s",
+			id.Name, info.decl.Id.Name, typeNameForMessage(*fieldTypeOrNil)))
+	}
+	fields := info.variant().Fields
+	if len(pat.Args) != len(fields) {
+		return newResolveErr(id, fmt.Sprintf("variant %s has %d field(s), pattern has %d", id.Name, len(fields), len(pat.Args)))
+	}
+	for i := range pat.Args {
+		if err := r.resolvePattern(&pat.Args[i], &fields[i].Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldAcceptsVariant는 fieldType 자리의 값이 decl 합 타입의 변형일 수 있는지 판단함
+// interface { ... } 타입 필드는 어떤 값이든 담을 수 있으므로 허용함
+func (r *Resolver) fieldAcceptsVariant(fieldType parser.Type, decl *parser.TypeDecl) bool {
+	switch fieldType.TypeKind {
+	case parser.InterfaceTypeKind:
+		return true
+	case parser.NamedType:
+		// 필드 타입의 이름은 그 합 타입과 같은 패키지에서 선언되었으므로 현재 전역 스코프에서 찾음
+		sym, ok := r.global.symbols[fieldType.NameOrNil.Name]
+		if !ok || sym.kind != SymbolType {
+			return false
+		}
+		return sym.idNodeId == decl.Id.IdId
+	default:
+		return false
+	}
+}
+
+func typeNameForMessage(t parser.Type) string {
+	if t.TypeKind == parser.NamedType {
+		return t.NameOrNil.Name
+	}
+	return t.String()
+}
+
+// 이하 완전성 검사
+// Maranget의 usefulness 알고리즘으로, 가드 없는 갈래들이 덮지 못하는 값이 있는지를 찾음
+
+// patSpace는 완전성 검사용으로 추상화한 패턴임
+// ctor가 비어 있으면 아무 값이나 받는 와일드카드임
+type patSpace struct {
+	ctor   string
+	family *patFamily
+	args   []patSpace
+}
+
+// patFamily는 한 자리에 올 수 있는 생성자 전체의 집합임
+// 정수, 문자열 리터럴처럼 생성자가 무한한 도메인은 literalFamily로 표시함
+type patFamily struct {
+	name  string
+	ctors []string
+	arity map[string]int
+}
+
+var boolFamily = &patFamily{
+	name:  "bool",
+	ctors: []string{"true", "false"},
+	arity: map[string]int{"true": 0, "false": 0},
+}
+
+var literalFamily = &patFamily{name: "literal"}
+
+var wildSpace = patSpace{}
+
+func (f *patFamily) isFinite() bool {
+	return f != literalFamily
+}
+
+type exhaustChecker struct {
+	families map[*parser.TypeDecl]*patFamily
+}
+
+func (c *exhaustChecker) familyOf(decl *parser.TypeDecl) *patFamily {
+	if family, ok := c.families[decl]; ok {
+		return family
+	}
+	family := &patFamily{name: decl.Id.Name, arity: map[string]int{}}
+	for _, variant := range decl.Type.SumOrNil.Variants {
+		family.ctors = append(family.ctors, variant.Id.Name)
+		family.arity[variant.Id.Name] = len(variant.Fields)
+	}
+	c.families[decl] = family
+	return family
+}
+
+func (c *exhaustChecker) spaceOf(r *Resolver, pat parser.Pattern) patSpace {
+	switch pat.PatternKind {
+	case parser.LiteralPattern:
+		if primary, ok := pat.LiteralOrNil.(*parser.Primary); ok && primary.ValueOrNil != nil && primary.ValueOrNil.BoolOrNil != nil {
+			return patSpace{ctor: pat.String(), family: boolFamily}
+		}
+		return patSpace{ctor: pat.String(), family: literalFamily}
+	case parser.VariantPattern:
+		_, info, _ := r.variantOf(*pat.IdOrNil)
+		args := make([]patSpace, len(pat.Args))
+		for i, arg := range pat.Args {
+			args[i] = c.spaceOf(r, arg)
+		}
+		return patSpace{ctor: pat.IdOrNil.Name, family: c.familyOf(info.decl), args: args}
+	default:
+		return wildSpace
+	}
+}
+
+// columnFamily는 첫 열에 쓰인 생성자들의 family와 그 생성자 집합을 리턴함
+// 첫 열이 모두 와일드카드이면 family는 nil임
+func columnFamily(rows [][]patSpace) (*patFamily, map[string]bool, error) {
+	var family *patFamily
+	used := map[string]bool{}
+	for _, row := range rows {
+		head := row[0]
+		if head.ctor == "" {
+			continue
+		}
+		if family != nil && family != head.family {
+			return nil, nil, fmt.Errorf("match mixes patterns of %s and %s", family.name, head.family.name)
+		}
+		family = head.family
+		used[head.ctor] = true
+	}
+	return family, used, nil
+}
+
+func wildSpaces(n int) []patSpace {
+	spaces := make([]patSpace, n)
+	for i := range spaces {
+		spaces[i] = wildSpace
+	}
+	return spaces
+}
+
+// specialize는 첫 열이 ctor에 맞는 행들만 남기고, 그 필드들을 앞으로 펼침
+func specialize(rows [][]patSpace, ctor string, arity int) [][]patSpace {
+	result := [][]patSpace{}
+	for _, row := range rows {
+		head := row[0]
+		switch head.ctor {
+		case "":
+			result = append(result, append(wildSpaces(arity), row[1:]...))
+		case ctor:
+			result = append(result, append(append([]patSpace{}, head.args...), row[1:]...))
+		}
+	}
+	return result
+}
+
+// defaultRows는 첫 열이 와일드카드인 행들에서 첫 열을 뗀 것임
+func defaultRows(rows [][]patSpace) [][]patSpace {
+	result := [][]patSpace{}
+	for _, row := range rows {
+		if row[0].ctor == "" {
+			result = append(result, row[1:])
+		}
+	}
+	return result
+}
+
+// useful은 rows가 덮지 못하고 q에는 맞는 값이 있으면 그 예시(witness)를 리턴함
+// 그런 값이 없으면 nil을 리턴함
+func useful(rows [][]patSpace, q []patSpace) ([]patSpace, error) {
+	if len(q) == 0 {
+		if len(rows) == 0 {
+			return []patSpace{}, nil
+		}
+		return nil, nil
+	}
+	head := q[0]
+	if head.ctor != "" {
+		arity := len(head.args)
+		witness, err := useful(specialize(rows, head.ctor, arity), append(append([]patSpace{}, head.args...), q[1:]...))
+		if err != nil || witness == nil {
+			return nil, err
+		}
+		return rebuild(head.ctor, head.family, arity, witness), nil
+	}
+	family, used, err := columnFamily(rows)
+	if err != nil {
+		return nil, err
+	}
+	// 쓰인 생성자가 family 전체를 덮으면 생성자마다 나눠서 검사함
+	if family != nil && family.isFinite() && len(used) == len(family.ctors) {
+		for _, ctor := range family.ctors {
+			arity := family.arity[ctor]
+			witness, err := useful(specialize(rows, ctor, arity), append(wildSpaces(arity), q[1:]...))
+			if err != nil {
+				return nil, err
+			}
+			if witness != nil {
+				return rebuild(ctor, family, arity, witness), nil
+			}
+		}
+		return nil, nil
+	}
+	witness, err := useful(defaultRows(rows), q[1:])
+	if err != nil || witness == nil {
+		return nil, err
+	}
+	// 빠진 생성자가 있으면 그것을 예시로 듦
+	missing := wildSpace
+	if family != nil && family.isFinite() {
+		for _, ctor := range family.ctors {
+			if !used[ctor] {
+				missing = patSpace{ctor: ctor, family: family, args: wildSpaces(family.arity[ctor])}
+				break
+			}
+		}
+	}
+	return append([]patSpace{missing}, witness...), nil
+}
+
+// rebuild는 펼쳐졌던 witness의 앞 arity개를 다시 ctor의 필드로 묶음
+func rebuild(ctor string, family *patFamily, arity int, witness []patSpace) []patSpace {
+	head := patSpace{ctor: ctor, family: family, args: witness[:arity]}
+	return append([]patSpace{head}, witness[arity:]...)
+}
+
+func (s patSpace) String() string {
+	if s.ctor == "" {
+		return "_"
+	}
+	allWild := true
+	for _, arg := range s.args {
+		if arg.ctor != "" {
+			allWild = false
+		}
+	}
+	// 필드가 모두 와일드카드이면 변형 이름만 보여줌
+	if allWild {
+		return s.ctor
+	}
+	args := make([]string, len(s.args))
+	for i, arg := range s.args {
+		args[i] = arg.String()
+	}
+	return s.ctor + "(" + strings.Join(args, ", ") + ")"
+}
+
+// checkExhaustive는 가드 없는 갈래들이 모든 값을 덮는지 검사하고, 빠진 경우를 에러에 나열함
+func (r *Resolver) checkExhaustive(node *parser.Match) error {
+	checker := &exhaustChecker{families: map[*parser.TypeDecl]*patFamily{}}
+	rows := [][]patSpace{}
+	allRows := [][]patSpace{}
+	for _, arm := range node.Arms {
+		row := []patSpace{checker.spaceOf(r, arm.Pattern)}
+		allRows = append(allRows, row)
+		// 가드가 있는 갈래는 실패할 수 있으므로 완전성에 기여하지 않음
+		if arm.GuardOrNil == nil {
+			rows = append(rows, row)
+		}
+	}
+	family, _, err := columnFamily(allRows)
+	if err != nil {
+		return r.matchErr(node, err.Error())
+	}
+	if family == nil || !family.isFinite() {
+		witness, err := useful(rows, []patSpace{wildSpace})
+		if err != nil {
+			return r.matchErr(node, err.Error())
+		}
+		if witness != nil {
+			return r.matchErr(node, "non-exhaustive match: missing _ arm")
+		}
+		return nil
+	}
+	missing := []string{}
+	for _, ctor := range family.ctors {
+		query := patSpace{ctor: ctor, family: family, args: wildSpaces(family.arity[ctor])}
+		witness, err := useful(rows, []patSpace{query})
+		if err != nil {
+			return r.matchErr(node, err.Error())
+		}
+		if witness != nil {
+			missing = append(missing, witness[0].String())
+		}
+	}
+	if len(missing) > 0 {
+		return r.matchErr(node, "non-exhaustive match: missing "+strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// matchErr은 match 전체에 대한 에러를 subject의 식별자 위치에 붙임
+// subject가 리터럴처럼 식별자가 없다면 첫 갈래의 패턴이나 본문의 식별자를 쓰고,
+// match 어디에도 식별자가 없다면 감싼 함수에 붙임
+func (r *Resolver) matchErr(node *parser.Match, msg string) error {
+	if id, ok := exprAnchorId(node.Subject); ok {
+		return newResolveErr(id, msg)
+	}
+	for _, arm := range node.Arms {
+		if arm.Pattern.IdOrNil != nil {
+			return newResolveErr(*arm.Pattern.IdOrNil, msg)
+		}
+		if id, ok := exprAnchorId(arm.Body); ok {
+			return newResolveErr(id, msg)
+		}
+	}
+	return r.funcErr(nil, msg)
+}
@@ -0,0 +1,132 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// collectMethods는 호이스팅된 메서드들(methodIds)을 리시버 타입의 TypeDecl에 연결함
+// 타입이 모두 호이스팅된 이후에 호출되어야 함
+func (r *Resolver) collectMethods(hoist *HoistInfo, methodIds []parser.IdId) error {
+	for _, methodId := range methodIds {
+		decl := hoist.getMethodDeclById(methodId)
+		receiverType := decl.ReceiverOrNil.Type
+		if receiverType.TypeKind != parser.NamedType {
+			return newResolveErr(decl.ReceiverOrNil.Id, "method receiver must be a named struct type")
+		}
+		typeName := *receiverType.NameOrNil
+		if err := r.resolveType(receiverType); err != nil {
+			return err
+		}
+		typeId := r.table[typeName.IdId].RefIdNodeId
+		typeDecl := hoist.getTypeDeclById(typeId)
+		if typeDecl == nil || typeDecl.Type.TypeKind != parser.StructureType {
+			return newResolveErr(typeName, "method receiver must be a named struct type")
+		}
+		for _, field := range typeDecl.Type.StructOrNil.Fields {
+			if field.Id.Name == decl.Id.Name {
+				return newResolveErr(decl.Id, fmt.Sprintf("field and method with the same name %s", decl.Id.Name))
+			}
+		}
+		methods, ok := hoist.methodsByType[typeId]
+		if !ok {
+			methods = map[string]parser.IdId{}
+			hoist.methodsByType[typeId] = methods
+		}
+		if _, exists := methods[decl.Id.Name]; exists {
+			return newResolveErr(decl.Id, fmt.Sprintf("method %s.%s already declared", typeDecl.Id.Name, decl.Id.Name))
+		}
+		methods[decl.Id.Name] = methodId
+	}
+	return nil
+}
+
+// resolveMethodDecl은 리시버를 첫 번째 매개변수처럼 함수 스코프에 선언함
+// 따라서 리시버는 항상 0번 슬롯을 차지함
+func (r *Resolver) resolveMethodDecl(node *parser.FuncDecl, hoist *HoistInfo) error {
+	if hoist.getMethodDeclById(node.Id.IdId) == nil {
+		return newResolveErr(node.Id, "hoisted symbol not found")
+	}
+	r.pushScope()
+	defer r.popScope()
+	r.pushFuncReturns(&node.Id, node.ReturnTypesOrNil, &node.IsGenerator)
+	defer r.popFuncReturns()
+
+	if err := r.resolveSignatureTypes(node.ParamsOrNil, node.ReturnTypesOrNil); err != nil {
+		return err
+	}
+	params := append([]parser.Param{*node.ReceiverOrNil}, node.ParamsOrNil...)
+	for _, param := range params {
+		psym, perr := r.declare(param.Id.Name, SymbolParam, param.Id.IdId)
+		if perr != nil {
+			return newResolveErr(param.Id, perr.Error())
+		}
+		r.setResolved(param.Id, r.refFromSymbol(psym))
+		r.declTypes[param.Id.IdId] = param.Type
+	}
+	return r.resolveBlock(node.Block, true)
+}
+
+// resolveSwitch는 init 문장을 위한 switch 스코프 아래에 절마다의 스코프를 만듦
+func (r *Resolver) resolveSwitch(node *parser.Switch) error {
+	r.pushScope()
+	defer r.popScope()
+	if node.ShortDeclOrNil != nil {
+		if err := r.resolveShortDecl(node.ShortDeclOrNil); err != nil {
+			return err
+		}
+	}
+	if node.TagOrNil != nil {
+		if err := r.resolveExpr(node.TagOrNil); err != nil {
+			return err
+		}
+	}
+	for _, clause := range node.Clauses {
+		for _, expr := range clause.Exprs {
+			if err := r.resolveExpr(expr); err != nil {
+				return err
+			}
+		}
+		if err := r.resolveBlock(clause.Block, false); err != nil {
+			return err
+		}
+	}
+	if n := len(node.Clauses); n > 0 && node.Clauses[n-1].Fallthrough {
+		anchor := node.TagOrNil
+		if anchor == nil && len(node.Clauses[n-1].Exprs) > 0 {
+			anchor = node.Clauses[n-1].Exprs[0]
+		}
+		return r.funcErr(anchor, "cannot fallthrough final case in switch")
+	}
+	return nil
+}
+
+func (r *Resolver) resolveTypeSwitch(node *parser.TypeSwitch) error {
+	if err := r.resolveExpr(node.Subject); err != nil {
+		return err
+	}
+	for _, clause := range node.Clauses {
+		for _, typ := range clause.Types {
+			if err := r.resolveType(typ); err != nil {
+				return err
+			}
+		}
+		// 각 절은 자신만의 스코프를 가지며, 바인딩은 그 스코프의 첫 변수로 선언됨
+		r.pushScope()
+		if node.BindingOrNil != nil {
+			sym, err := r.declare(node.BindingOrNil.Name, SymbolVar, node.BindingOrNil.IdId)
+			if err != nil {
+				r.popScope()
+				return newResolveErr(*node.BindingOrNil, err.Error())
+			}
+			r.setResolved(*node.BindingOrNil, r.refFromSymbol(sym))
+		}
+		err := r.resolveBlock(clause.Block, true)
+		r.popScope()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
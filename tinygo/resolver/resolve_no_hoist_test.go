@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
@@ -54,6 +55,52 @@ func TestResolveNoHoist_SuccessCases(t *testing.T) {
 			name:  "shadowing_allowed_in_inner_block",
 			input: "func f(){ a := 1; if true { a := 2; } }",
 		},
+		{
+			name:  "method_names_do_not_clash_across_types",
+			input: "type A struct { x int; } type B struct { x int; } func (a A) M() int { return a.x; } func (b B) M() int { return b.x; } func M() {}",
+		},
+		{
+			name:  "struct_type_used_before_decl",
+			input: "func f(p Point) Point { q := Point{x: p.x}; q.y = 1; return q; } type Point struct { x int; y int; }",
+		},
+		{
+			name:  "builtin_module_member",
+			input: "func f() string { xs := strings.Split(\"a,b\", \",\"); n, err := strconv.Atoi(xs[0]); return fmt.Sprintf(\"%d %s\", n, err); }",
+		},
+		{
+			name:  "try_in_error_returning_funcs",
+			input: "func f(s string) (int, error) { n := strconv.Atoi(s)?; g := func() error { check(n)?; return ok; }; g()?; return n, ok; } func check(n int) error { return ok; }",
+		},
+		{
+			name:  "switch_forms",
+			input: "func f(n int) string { switch m := n * 2; m { case 2, 4: s := \"small\"; return s; default: s := \"big\"; return s; } switch { case n < 0: return \"neg\"; } return \"\"; }",
+		},
+		{
+			name:  "tuple_type_and_literal",
+			input: "func f(r (Point, error)) ((int, error)) { p, err := r; t := (p.x, err); return t; } type Point struct { x int; }",
+		},
+		{
+			name:  "match_on_sum_types",
+			input: "func f(o Opt, b bool) int { x := match o { Some(Circle(r)) if r > 0 => r, Some(_) => 1, None => 0 }; return x + match b { true => 1, false => 0 }; } type Shape = Circle(r int) | Empty; type Opt = Some(s Shape) | None;",
+		},
+		{
+			name:  "recursive_sum_type",
+			input: "type List = Cons(head int, tail List) | Nil; func sum(l List) int { return match l { Cons(h, t) => h + sum(t), Nil => 0 }; }",
+		},
+		{
+			name:  "generator_and_range",
+			input: "func count(n int) { for i := 0; i < n; i = i + 1; { yield i; } } func main() { for v := range count(3) { print(v); } for _, c := range \"ab\" { print(c); } }",
+		},
+		{
+			// 안쪽 스코프에선 let 바인딩을 셰도잉한 새 변수에 대입할 수 있음
+			name:  "let_shadowed_in_inner_scope",
+			input: "const Max = 3; func main() { let x = Max; if x > 1 { x := 2; x = 3; print(x); } }",
+		},
+		{
+			// 컬렉션 함수는 모듈 아래에 있으므로 흔한 이름을 차지하지 않음
+			name:  "collection_names_free_for_users",
+			input: "func get(n int) int { return n; } func First[T any](xs []T) T { return xs[0]; } func main() { all := 1; any := seq.Any([]int{all}, func(n int) bool { return n > 0; }); v := coll.Conj(coll.Vector(), get(all)); }",
+		},
 	}
 
 	for _, tc := range cases {
@@ -92,6 +139,106 @@ func TestResolveNoHoist_FailureCases(t *testing.T) {
 			name:  "assign_to_builtin_forbidden",
 			input: "func f(){ print = 1; }",
 		},
+		{
+			name:  "type_is_not_expression",
+			input: "type P struct { x int; } func f(){ a := P; }",
+		},
+		{
+			name:  "undefined_field_type",
+			input: "type P struct { x Q; }",
+		},
+		{
+			name:  "duplicate_struct_field",
+			input: "type P struct { x int; x int; }",
+		},
+		{
+			name:  "recursive_struct_type",
+			input: "type P struct { q Q; } type Q struct { p P; }",
+		},
+		{
+			name:  "duplicate_method",
+			input: "type P struct { x int; } func (p P) M() {} func (p P) M() {}",
+		},
+		{
+			name:  "method_on_non_struct",
+			input: "type I interface { M(); } func (i I) M() {}",
+		},
+		{
+			name:  "field_and_method_same_name",
+			input: "type P struct { x int; } func (p P) x() int { return 1; }",
+		},
+		{
+			name:  "local_method_decl",
+			input: "type P struct { x int; } func f(){ func (p P) M() {} }",
+		},
+		{
+			name:  "type_switch_guard_outside_switch",
+			input: "func f(){ a := 1; b := a.(type); }",
+		},
+		{
+			name:  "struct_lit_of_non_type",
+			input: "var a int = 1; func f(){ b := a{x: 1}; }",
+		},
+		{
+			name:  "undefined_module_member",
+			input: "func f(){ s := strings.Reverse(\"ab\"); }",
+		},
+		{
+			name:  "module_without_selector",
+			input: "func f(){ s := strings; }",
+		},
+		{
+			name:  "module_name_is_reserved",
+			input: "func f(){ fmt := 1; }",
+		},
+		{
+			name:  "collection_function_without_module",
+			input: "func f(){ xs := map([]int{1}, func(n int) int { return n; }); }",
+		},
+		{
+			name:  "switch_init_scoped_to_switch",
+			input: "func f(n int) int { switch m := n; m { case 1: return m; } return m; }",
+		},
+		{
+			name:  "fallthrough_out_of_place",
+			input: "func f(n int) { switch n { case 1: if n > 0 { fallthrough; } case 2: } }",
+		},
+		{
+			name:  "try_without_error_result",
+			input: "func f(s string) int { n := strconv.Atoi(s)?; return n; }",
+		},
+		{
+			name:  "try_in_closure_without_error_result",
+			input: "func f(s string) (int, error) { g := func() int { return strconv.Atoi(s)?; }; return g(), ok; }",
+		},
+		{
+			name:  "try_outside_function",
+			input: "var n int = strconv.Atoi(\"1\")?;",
+		},
+		{
+			name:  "assign_to_variant",
+			input: "type Shape = Circle(r int) | Empty; func f(){ Empty = Circle(1); }",
+		},
+		{
+			name:  "pattern_binding_scoped_to_arm",
+			input: "type Shape = Circle(r int) | Empty; func f(s Shape) int { n := match s { Circle(r) => r, Empty => r }; return n; }",
+		},
+		{
+			name:  "variant_pattern_arity",
+			input: "type Shape = Rect(w int, h int) | Empty; func f(s Shape) int { return match s { Rect(w) => w, Empty => 0 }; }",
+		},
+		{
+			name:  "infinite_sum_type",
+			input: "type Loop = Wrap(l Loop);",
+		},
+		{
+			name:  "generator_with_result_types",
+			input: "func g() int { yield 1; return 0; }",
+		},
+		{
+			name:  "range_var_scoped_to_loop",
+			input: "func f() { for v := range 3 { } print(v); }",
+		},
 	}
 
 	for _, tc := range cases {
@@ -104,6 +251,96 @@ func TestResolveNoHoist_FailureCases(t *testing.T) {
 	}
 }
 
+func TestResolveNoHoist_ErrorPositions(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		// 에러가 붙는 식별자의 이름
+		at   string
+		want string
+	}{
+		{
+			name:  "try_in_func_without_error_result",
+			input: "func parse(s string) int { n := strconv.Atoi(s)?; return n; }",
+			at:    "parse",
+			want:  "? operator requires function whose last result is error",
+		},
+		{
+			// 함수 리터럴은 이름이 없으므로 ?가 붙은 호출에 붙음
+			name:  "try_in_fexp_without_error_result",
+			input: "func main() { f := func(s string) int { n := strconv.Atoi(s)?; return n; }; }",
+			at:    "Atoi",
+			want:  "? operator requires function whose last result is error",
+		},
+		{
+			name:  "try_outside_function",
+			input: "var n int = strconv.Atoi(\"1\")?;",
+			at:    "Atoi",
+			want:  "? operator used outside function",
+		},
+		{
+			name:  "generator_with_result_types",
+			input: "func count(n int) int { yield n; return 0; }",
+			at:    "count",
+			want:  "generator function cannot have result types",
+		},
+		{
+			name:  "generator_fexp_with_result_types",
+			input: "func main() { n := 1; g := func() int { yield n; return 0; }; }",
+			at:    "n",
+			want:  "generator function cannot have result types",
+		},
+		{
+			// yield한 값이 리터럴이면 바깥 함수 선언에 붙음
+			name:  "generator_fexp_with_literal_yield",
+			input: "func main() { f := func() int { yield 1; return 1; }; }",
+			at:    "main",
+			want:  "generator function cannot have result types",
+		},
+		{
+			name:  "fallthrough_in_final_clause",
+			input: "func main() { x := 1; switch x { case 1: fallthrough; } }",
+			at:    "main",
+			want:  "cannot fallthrough final case in switch",
+		},
+		{
+			name:  "fallthrough_in_final_clause_of_fexp",
+			input: "func main() { f := func(x int) { switch x { case 1: fallthrough; } }; }",
+			at:    "x",
+			want:  "cannot fallthrough final case in switch",
+		},
+		{
+			name:  "fallthrough_out_of_place",
+			input: "func f(n int) { switch n { case 1: if n > 0 { fallthrough; } case 2: } }",
+			at:    "f",
+			want:  "fallthrough statement out of place",
+		},
+		{
+			// 함수 리터럴 안에서 붙일 식별자가 없으면 바깥 함수 선언에 붙음
+			name:  "fallthrough_out_of_place_in_fexp",
+			input: "func main() { f := func() { switch 1 { case 1: fallthrough; print(\"x\"); case 2: } }; }",
+			at:    "main",
+			want:  "fallthrough statement out of place",
+		},
+		{
+			name:  "fallthrough_in_final_clause_of_fexp_with_literal_tag",
+			input: "func main() { f := func() { switch 1 { case 1: fallthrough; } }; }",
+			at:    "main",
+			want:  "cannot fallthrough final case in switch",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := resolveFromInput(t, tc.input)
+			rerr, ok := err.(*ResolveError)
+			if !ok || rerr.IdNode.Name != tc.at || rerr.Msg != tc.want {
+				t.Fatalf("expected %q at %s, got %v", tc.want, tc.at, err)
+			}
+		})
+	}
+}
+
 func TestResolveNoHoist_ResolvedKinds(t *testing.T) {
 	input := "var a int = 1; func f(){ a = 2; print(a); }"
 	pkg, table, _, err := resolveFromInput(t, input)
@@ -125,3 +362,327 @@ func TestResolveNoHoist_ResolvedKinds(t *testing.T) {
 		t.Fatalf("expected builtin print, got %v", printRef.Kind)
 	}
 }
+
+func TestResolveNoHoist_MarksGenerators(t *testing.T) {
+	// yield는 가장 안쪽 함수만 제너레이터로 만듦
+	pkg, _, _, err := resolveFromInput(t, "func outer() { g := func() { yield 1; }; for v := range g() { print(v); } }")
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	outer := pkg.DeclsOrNil[0].(*parser.FuncDecl)
+	if outer.IsGenerator {
+		t.Fatalf("outer function must not be a generator")
+	}
+	inner := outer.Block.StmtsOrNil[0].(*parser.ShortDecl).Exprs[0].(*parser.Primary).ValueOrNil.FexpOrNil
+	if !inner.IsGenerator {
+		t.Fatalf("function literal with yield must be a generator")
+	}
+}
+
+func TestResolveNoHoist_MarksCapturedLoopVars(t *testing.T) {
+	// 안쪽 함수가 참조하는 루프 변수만 반복마다 새로 만들도록 표시함
+	input := "func f() { for i := 0; i < 3; i = i + 1; { print(i); } " +
+		"for i := 0; i < 3; i = i + 1; { g := func() int { return i; }; } " +
+		"for _, v := range 3 { print(v); } " +
+		"for _, v := range 3 { g := func() { print(v); }; } }"
+	pkg, _, _, err := resolveFromInput(t, input)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	stmts := pkg.DeclsOrNil[0].(*parser.FuncDecl).Block.StmtsOrNil
+	got := []bool{
+		stmts[0].(*parser.ForWithAssign).LoopVarsCaptured,
+		stmts[1].(*parser.ForWithAssign).LoopVarsCaptured,
+		stmts[2].(*parser.ForRange).LoopVarsCaptured,
+		stmts[3].(*parser.ForRange).LoopVarsCaptured,
+	}
+	want := []bool{false, true, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("loop #%d: captured = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResolveNoHoist_MatchExhaustiveness(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		// 에러가 붙는 식별자의 이름
+		at   string
+		want string
+	}{
+		{
+			name:  "missing_variants_are_named",
+			input: "type Shape = Circle(r int) | Rect(w int, h int) | Empty; func f(s Shape) int { return match s { Circle(r) => r }; }",
+			at:    "s",
+			want:  "non-exhaustive match: missing Rect, Empty",
+		},
+		{
+			name:  "guarded_arm_does_not_count",
+			input: "type Shape = Circle(r int) | Empty; func f(s Shape) int { return match s { Circle(r) if r > 0 => r, Empty => 0 }; }",
+			at:    "s",
+			want:  "non-exhaustive match: missing Circle",
+		},
+		{
+			name:  "nested_variant_missing",
+			input: "type Shape = Circle(r int) | Empty; type Opt = Some(s Shape) | None; func f(o Opt) int { return match o { Some(Circle(r)) => r, None => 0 }; }",
+			at:    "o",
+			want:  "non-exhaustive match: missing Some(Empty)",
+		},
+		{
+			name:  "bool_missing_false",
+			input: "func f(b bool) int { return match b { true => 1 }; }",
+			at:    "b",
+			want:  "non-exhaustive match: missing false",
+		},
+		{
+			name:  "int_literals_need_wildcard",
+			input: "func f(n int) int { return match n { 0 => 1, 1 => 2 }; }",
+			at:    "n",
+			want:  "non-exhaustive match: missing _ arm",
+		},
+		{
+			// subject에 식별자가 없으면 첫 갈래에 붙음
+			name:  "literal_subject_anchors_at_arm",
+			input: "func f(n int) int { return match 1 { 0 => n }; }",
+			at:    "n",
+			want:  "non-exhaustive match: missing _ arm",
+		},
+		{
+			// match에 식별자가 하나도 없으면 감싼 함수에 붙음
+			name:  "match_without_identifiers_anchors_at_func",
+			input: "func main() { r := match 3 { 1 => \"a\", 2 => \"b\" }; }",
+			at:    "main",
+			want:  "non-exhaustive match: missing _ arm",
+		},
+		{
+			name:  "mixed_sum_types",
+			input: "type Shape = Circle(r int) | Empty; type Opt = Some(n int) | None; func f(s Shape) int { return match s { Circle(r) => r, None => 0 }; }",
+			at:    "s",
+			want:  "match mixes patterns of Shape and Opt",
+		},
+		{
+			name:  "variant_of_wrong_field_type",
+			input: "type Shape = Circle(r int) | Empty; type Opt = Some(s Shape) | None; func f(o Opt) int { return match o { Some(None) => 1, _ => 0 }; }",
+			at:    "None",
+			want:  "variant None of Opt cannot match field of type Shape",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := resolveFromInput(t, tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+			rerr, ok := err.(*ResolveError)
+			if !ok || rerr.IdNode.Name != tc.at {
+				t.Fatalf("expected error at %s, got %#v", tc.at, err)
+			}
+		})
+	}
+}
+
+func TestResolveNoHoist_ConstFolding(t *testing.T) {
+	// 상수끼리는 선언 순서와 무관하게 참조할 수 있고, 묶음에서 생략된 식은 iota만 바꿔 반복됨
+	input := "const Big = Last * 10; const ( Zero = iota; One; Two; Last; ) " +
+		"const ( A, B = iota * 2, \"x\" + \"y\"; C, D; ) const Neg int = -(One + Two) / 2; const Flag = One < Two && !(A == 1);"
+	_, _, hoist, err := resolveFromInput(t, input)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	want := []string{"30", "0", "1", "2", "3", "0", "\"xy\"", "2", "\"xy\"", "-1", "true"}
+	ids := hoist.ConstIds()
+	if len(ids) != len(want) {
+		t.Fatalf("const count mismatch: got %d want %d", len(ids), len(want))
+	}
+	for i, id := range ids {
+		value, ok := hoist.GetConstValueById(id)
+		if !ok || value.String() != want[i] {
+			t.Fatalf("const #%d: got %s want %s", id, value.String(), want[i])
+		}
+	}
+}
+
+func TestResolveNoHoist_ImmutableBindings(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "assign_to_const",
+			input: "const Max = 3; func f() { Max = 4; }",
+			want:  "resolve error at Max(#2): cannot assign to constant Max declared at Max(#0)",
+		},
+		{
+			name:  "assign_to_let",
+			input: "func f() { let x = 1; x = 2; }",
+			want:  "resolve error at x(#2): cannot assign to let binding x declared at x(#1)",
+		},
+		{
+			name:  "assign_to_let_field",
+			input: "type P struct { x int; } func f() { let p = P{x: 1}; p.x = 2; }",
+			want:  "cannot assign to field of let binding p declared at p(#",
+		},
+		{
+			name:  "short_decl_redeclares_let",
+			input: "func f() { let x = 1; x, y := 2, 3; }",
+			want:  "cannot redeclare let binding x declared at x(#1)",
+		},
+		{
+			name:  "let_redeclares_var",
+			input: "func f() { x := 1; let x = 2; }",
+			want:  "duplicate declaration: x",
+		},
+		{
+			name:  "const_from_var",
+			input: "var n int = 1; const M = n + 1;",
+			want:  "n is not a constant",
+		},
+		{
+			name:  "const_from_call",
+			input: "const L = len(\"abc\");",
+			want:  "const initializer is not a constant expression",
+		},
+		{
+			name:  "const_division_by_zero",
+			input: "const Z = 0; const Q = 1 / Z;",
+			want:  "division by zero in constant expression",
+		},
+		{
+			name:  "const_type_mismatch",
+			input: "const S int = \"a\";",
+			want:  "cannot use string constant \"a\" as int",
+		},
+		{
+			name:  "const_mismatched_operands",
+			input: "const S = 1 + \"a\";",
+			want:  "invalid constant operation: mismatched types int and string",
+		},
+		{
+			name:  "const_loop",
+			input: "const A = B; const B = A + 1;",
+			want:  "constant definition loop",
+		},
+		{
+			name:  "const_name_count",
+			input: "const ( A, B = 1, 2; C; )",
+			want:  "const declaration has 1 names but 2 values",
+		},
+		{
+			name:  "iota_outside_const",
+			input: "func f() { print(iota); }",
+			want:  "cannot use iota outside constant declaration",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := resolveFromInput(t, tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestResolveNoHoist_Generics(t *testing.T) {
+	// 제약 충족과 타입 인자 추론은 인자들의 정적 타입으로 검사함
+	prelude := "type Number interface { int; } type Key interface { int | string; } type Named interface { Name() string; } " +
+		"type P struct { n string; } func (p P) Name() string { return p.n; } " +
+		"func Sum[T Number](xs []T) T { var total T; return total; } " +
+		"func Find[K comparable](xs []K, k K) int { return 0; } " +
+		"func Pick[T Key](a T, b T) T { return a; } " +
+		"func Show[T Named](x T) string { return x.Name(); } " +
+		"func Zero[T any]() T { var z T; return z; } "
+	success := []string{
+		"func main() { n := Sum([]int{1}); i := Find([]string{\"a\"}, \"a\"); s := Pick(\"a\", \"b\"); v := Show(P{n: \"x\"}); z := Zero[[]int](); f := Pick[int]; m := f(1, 2); }",
+		"func Apply[T, U any](x T, f func(T) U) U { return f(x); } func main() { s := Apply(1, strconv.Itoa); }",
+		// 타입 집합의 모든 타입이 지원하는 연산자는 본문에서 쓸 수 있음
+		"func Max[T Number](a T, b T) T { if a > b { return a; } return b - a * 2 / 1; } func Cat[T Key](a T, b T) T { return a + b; } func main() {}",
+	}
+	for _, input := range success {
+		if _, _, _, err := resolveFromInput(t, prelude+input); err != nil {
+			t.Fatalf("unexpected resolve error for %q: %v", input, err)
+		}
+	}
+
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "cannot_infer",
+			input: "func main() { z := Zero(); }",
+			want:  "in call to Zero, cannot infer T",
+		},
+		{
+			name:  "union_missing",
+			input: "func main() { n := Sum([]string{\"a\"}); }",
+			want:  "string does not satisfy Number (string missing in int)",
+		},
+		{
+			name:  "not_comparable",
+			input: "func main() { i := Find([][]int{}, []int{}); }",
+			want:  "[]int does not satisfy comparable",
+		},
+		{
+			name:  "missing_method",
+			input: "func main() { s := Show(1); }",
+			want:  "int does not satisfy Named (missing method Name)",
+		},
+		{
+			name:  "conflicting_inference",
+			input: "func main() { s := Pick(1, \"a\"); }",
+			want:  "type string of argument 2 does not match inferred type int for T",
+		},
+		{
+			name:  "too_many_type_args",
+			input: "func main() { f := Zero[int, string]; }",
+			want:  "got 2 type arguments but Zero has 1 type parameters",
+		},
+		{
+			name:  "explicit_type_arg_unsatisfied",
+			input: "func main() { f := Pick[bool]; }",
+			want:  "bool does not satisfy Key (bool missing in int | string)",
+		},
+		{
+			name:  "instantiate_non_generic",
+			input: "func g() {} func main() { f := g[int]; }",
+			want:  "g is not a generic function",
+		},
+		{
+			name:  "constraint_as_type",
+			input: "var k Key;",
+			want:  "cannot use type Key outside a type constraint: interface contains type constraints",
+		},
+		{
+			// 제네릭 본문은 인스턴스화가 없어도 제약에 대해 검사함
+			name:  "operator_on_unbounded_type_param",
+			input: "func Add[T any](a T, b T) T { return a + b; }",
+			want:  "invalid operation: operator + not defined on a (type T constrained by any)",
+		},
+		{
+			// string은 대소 비교를 지원하지 않으므로 int | string 에는 > 를 쓸 수 없음
+			name:  "operator_unsupported_by_union_member",
+			input: "func Max[T int | string](a T, b T) T { if a > b { return a; } return b; } func main() { m := Max(\"a\", \"b\"); }",
+			want:  "invalid operation: operator > not defined on a (type T constrained by int | string: string does not support >)",
+		},
+		{
+			name:  "type_param_as_value",
+			input: "func Bad[T any](x T) T { y := T; return x; }",
+			want:  "type is not an expression",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := resolveFromInput(t, prelude+tc.input)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package resolver
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// PackageSource는 같은 디렉토리의 파일들을 합친 하나의 패키지와 그 임포트 경로임
+type PackageSource struct {
+	Path string
+	AST  *parser.PackageAST
+}
+
+// ResolveProgram은 여러 패키지를 하나의 리졸브 테이블, 하나의 전역 환경으로 리졸빙함
+// pkgs는 임포트되는 패키지가 임포트하는 패키지보다 먼저 오도록 정렬되어 있어야 함 (loader가 보장)
+// 패키지마다 전역 스코프(이름 공간)는 분리되지만, 전역 슬롯은 이어서 발급되므로
+// evaluator는 모든 패키지의 전역을 하나의 전역 환경에 담을 수 있음
+func ResolveProgram(pkgs []PackageSource) (ResolveTable, *HoistInfo, InitOrder, map[string]int, error) {
+	rs := NewResolver()
+	hoist := newHoistInfo()
+	for i, pkg := range pkgs {
+		if i > 0 {
+			rs.enterPackageScope()
+		}
+		if err := rs.checkImportNames(pkg); err != nil {
+			return rs.table, nil, nil, nil, err
+		}
+		if err := rs.resolvePackageInto(pkg.AST, hoist); err != nil {
+			return rs.table, nil, nil, nil, err
+		}
+		if pkg.AST.NameOrNil != nil {
+			rs.global.packageName = pkg.AST.NameOrNil.Name
+		}
+		rs.packageScopes[pkg.Path] = rs.global
+	}
+	// 패키지 간 참조(pkg.Name)도 리졸브 테이블에 기록되므로
+	// 초기화 순서는 모든 패키지의 전역을 한꺼번에 놓고 결정함
+	order, err := BuildInitOrder(rs.table, hoist)
+	if err != nil {
+		asts := make([]*parser.PackageAST, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			asts = append(asts, pkg.AST)
+		}
+		return rs.table, hoist, order, nil, attachPositions(err, asts...)
+	}
+	return rs.table, hoist, order, copyBuiltins(rs.builtins), nil
+}
+
+// enterPackageScope는 다음 패키지를 위한 새 전역 스코프를 만듦
+// 전역 슬롯은 이전 패키지에 이어서 발급함
+func (r *Resolver) enterPackageScope() {
+	nextSlot := r.global.nextSlot
+	r.global = newScope(nil)
+	r.global.nextSlot = nextSlot
+	r.currentScope = r.global
+	r.preludeBuiltins()
+}
+
+// checkImportNames는 임포트한 패키지의 package 절 이름이 임포트 경로의 마지막 요소와 같은지 검사함
+// 패키지는 경로의 마지막 요소로 참조되므로, 둘이 다르면 혼란스러운 코드가 됨
+func (r *Resolver) checkImportNames(pkg PackageSource) error {
+	for _, imp := range pkg.AST.Imports {
+		scope, ok := r.packageScopes[imp.Path]
+		if !ok {
+			continue
+		}
+		if name := scope.packageName; name != imp.Name {
+			return fmt.Errorf("import %q declares package %s, expected %s", imp.Path, name, imp.Name)
+		}
+	}
+	return nil
+}
+
+// declareImports는 임포트한 패키지 이름들을 현재 전역 스코프에 등록함
+// 임포트되는 패키지는 이미 리졸빙되어 있어야 함
+func (r *Resolver) declareImports(imports []parser.Import) error {
+	for _, imp := range imports {
+		if _, ok := r.packageScopes[imp.Path]; !ok {
+			return fmt.Errorf("package %s is not in the program", imp.Path)
+		}
+		sym, err := r.declare(imp.Name, SymbolPackage, parser.IdId(-1))
+		if err != nil {
+			return fmt.Errorf("import %q: %s", imp.Path, err.Error())
+		}
+		sym.importPath = imp.Path
+	}
+	return nil
+}
+
+// importedPackageOf는 expr이 셰도잉되지 않은 임포트 패키지 이름이면 해당 심볼을 리턴함
+func (r *Resolver) importedPackageOf(expr parser.Expr) (*Symbol, bool) {
+	primary, ok := expr.(*parser.Primary)
+	if !ok || primary.PrimaryKind != parser.IdPrimary {
+		return nil, false
+	}
+	sym := r.lookup(primary.IdOrNil.Name)
+	if sym == nil || sym.kind != SymbolPackage {
+		return nil, false
+	}
+	return sym, true
+}
+
+// resolvePackageMember는 pkg.Name의 Name을 임포트된 패키지의 전역 심볼로 연결함
+// 패키지 이름 자체는 런타임 값이 아니므로 리졸브 테이블에 기록하지 않음
+func (r *Resolver) resolvePackageMember(node *parser.Selector, pkgSym *Symbol) error {
+	field := node.Field
+	scope := r.packageScopes[pkgSym.importPath]
+	member, ok := scope.symbols[field.Name]
+	if !ok || member.kind == SymbolBuiltin || member.kind == SymbolPackage {
+		return newResolveErr(field, fmt.Sprintf("undefined: %s.%s", pkgSym.name, field.Name))
+	}
+	if !isExported(field.Name) {
+		return newResolveErr(field, fmt.Sprintf("name %s not exported by package %s", field.Name, pkgSym.name))
+	}
+	if member.kind == SymbolType {
+		return newResolveErr(field, fmt.Sprintf("type %s.%s is not an expression", pkgSym.name, field.Name))
+	}
+	r.setResolved(field, ResolvedRef{
+		Kind:        RefGlobal,
+		Distance:    0,
+		Slot:        member.slot,
+		RefIdNodeId: member.idNodeId,
+		Name:        member.name,
+	})
+	return nil
+}
+
+// isExported는 go와 같이 대문자로 시작하는 이름만 패키지 밖으로 공개함
+func isExported(name string) bool {
+	first, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(first)
+}
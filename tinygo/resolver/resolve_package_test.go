@@ -0,0 +1,172 @@
+package resolver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/lexer"
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// packageSourcesForTest는 (경로, 소스) 쌍들을 임포트 순서대로 파싱함
+// IdId가 겹치지 않도록 하나의 파서 체인으로 파싱함
+func packageSourcesForTest(t *testing.T, srcs [][2]string) []PackageSource {
+	t.Helper()
+	var prev *parser.Parser
+	pkgs := []PackageSource{}
+	for _, src := range srcs {
+		lx := lexer.NewLexer()
+		lx.Set(src[1])
+		var ps *parser.Parser
+		if prev == nil {
+			ps = parser.NewParser(lx)
+		} else {
+			ps = parser.NewParserFrom(lx, prev)
+		}
+		ast, err := ps.ParsePackage()
+		if err != nil {
+			t.Fatalf("parse error in %s: %v", src[0], err)
+		}
+		prev = ps
+		pkgs = append(pkgs, PackageSource{Path: src[0], AST: ast})
+	}
+	return pkgs
+}
+
+func TestResolveProgram_CrossPackageRefs(t *testing.T) {
+	pkgs := packageSourcesForTest(t, [][2]string{
+		{"geo", "package geo; var Origin int = base() + 1; func base() int { return 0; } func Area(w int) int { return w * Origin; }"},
+		{"main", "package main; import \"geo\"; var total int = geo.Area(2); func main() { f := geo.Area; total = f(3); }"},
+	})
+	table, _, order, _, err := ResolveProgram(pkgs)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	// 전역 슬롯은 패키지 간에 겹치지 않아야 함
+	slots := map[int]string{}
+	for _, ref := range table {
+		if ref.Kind != RefGlobal {
+			continue
+		}
+		if name, ok := slots[ref.Slot]; ok && name != ref.Name {
+			t.Fatalf("global slot %d shared by %s and %s", ref.Slot, name, ref.Name)
+		}
+		slots[ref.Slot] = ref.Name
+	}
+	// main의 total은 geo.Area -> Origin에 의존하므로 Origin 이후에 초기화되어야 함
+	names := []string{}
+	for _, step := range order {
+		names = append(names, table[step.VarId].Name)
+	}
+	if strings.Join(names, ",") != "Origin,total" {
+		t.Fatalf("unexpected init order: %v", names)
+	}
+}
+
+func TestResolveProgram_InitGraphQualifiesPackageNames(t *testing.T) {
+	pkgs := packageSourcesForTest(t, [][2]string{
+		{"shapes", "package shapes; type Box struct { w int; } func (b Box) Width() int { return b.w; } var Name string = label(); func label() string { return \"box\"; } var Size int = Box{w: 2}.Width();"},
+		{"main", "package main; import \"shapes\"; var Name string = shapes.Name + \"!\"; func main() {}"},
+	})
+	table, hoist, _, _, err := ResolveProgram(pkgs)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	graph, err := BuildInitGraph(table, hoist)
+	if err != nil {
+		t.Fatalf("unexpected init graph error: %v", err)
+	}
+	// 같은 이름의 전역이 두 패키지에 있어도 main이 아닌 쪽은 패키지 이름으로 구분되어야 함
+	got := []string{}
+	for _, node := range graph.Nodes {
+		for _, edge := range graph.Edges(node) {
+			got = append(got, node.String()+" -> "+edge.To.String())
+		}
+	}
+	want := "shapes.Name -> shapes.label(),shapes.Size -> shapes.Box.Width(),Name -> shapes.Name"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected init graph edges:\n got: %v\nwant: %v", strings.Join(got, ","), want)
+	}
+}
+
+func TestResolveProgram_CrossPackageConst(t *testing.T) {
+	pkgs := packageSourcesForTest(t, [][2]string{
+		{"geo", "package geo; const ( North = iota; East; )"},
+		{"main", "package main; import \"geo\"; const Turn = geo.East * 90; func main() {}"},
+	})
+	_, hoist, _, _, err := ResolveProgram(pkgs)
+	if err != nil {
+		t.Fatalf("unexpected resolve error: %v", err)
+	}
+	ids := hoist.ConstIds()
+	if value, _ := hoist.GetConstValueById(ids[len(ids)-1]); value.String() != "90" {
+		t.Fatalf("unexpected folded value: %s", value.String())
+	}
+}
+
+func TestResolveProgram_FailureCases(t *testing.T) {
+	geo := [2]string{"geo", "package geo; type Point struct { x int; } var Origin int = 0; func area() int { return 1; }"}
+	cases := []struct {
+		name string
+		main string
+		want string
+	}{
+		{
+			name: "unexported_name",
+			main: "package main; import \"geo\"; func main() { a := geo.area(); }",
+			want: "name area not exported by package geo",
+		},
+		{
+			name: "undefined_member",
+			main: "package main; import \"geo\"; func main() { a := geo.Volume; }",
+			want: "undefined: geo.Volume",
+		},
+		{
+			name: "package_without_selector",
+			main: "package main; import \"geo\"; func main() { a := geo; }",
+			want: "use of package geo without selector",
+		},
+		{
+			name: "assign_to_package",
+			main: "package main; import \"geo\"; func main() { geo = 1; }",
+			want: "cannot assign to package",
+		},
+		{
+			name: "import_name_clash",
+			main: "package main; import \"geo\"; var geo int = 1; func main() {}",
+			want: "duplicate declaration: geo",
+		},
+		{
+			name: "imported_type_is_not_expression",
+			main: "package main; import \"geo\"; func main() { a := geo.Point; }",
+			want: "type geo.Point is not an expression",
+		},
+		{
+			name: "package_not_in_program",
+			main: "package main; import \"shapes\"; func main() {}",
+			want: "package shapes is not in the program",
+		},
+		{
+			name: "imported_var_is_not_constant",
+			main: "package main; import \"geo\"; const C = geo.Origin + 1; func main() {}",
+			want: "geo.Origin is not a constant",
+		},
+		{
+			name: "imported_names_are_not_global",
+			main: "package main; import \"geo\"; func main() { a := Origin; }",
+			want: "undefined identifier",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pkgs := packageSourcesForTest(t, [][2]string{geo, {"main", tc.main}})
+			_, _, _, _, err := ResolveProgram(pkgs)
+			if err == nil {
+				t.Fatalf("expected resolve error but got nil")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -27,6 +27,13 @@ const (
 	RefLocal RefKind = iota
 	RefGlobal
 	RefBuiltin
+	// 타입 이름에 대한 참조. RefIdNodeId는 TypeDecl의 id를 가리킴
+	RefType
+	// 임포트한 패키지 이름에 대한 참조. 셀렉터의 대상으로만 쓰일 수 있음
+	RefPackage
+	// 제네릭 함수의 타입 매개변수에 대한 참조. RefIdNodeId는 타입 매개변수의 id를 가리킴
+	// 실제 타입은 호출마다 정해지므로 evaluator가 콜프레임의 타입 인자에서 찾음
+	RefTypeParam
 )
 
 func (k RefKind) String() string {
@@ -37,6 +44,12 @@ func (k RefKind) String() string {
 		return "Global"
 	case RefBuiltin:
 		return "Builtin"
+	case RefType:
+		return "Type"
+	case RefPackage:
+		return "Package"
+	case RefTypeParam:
+		return "TypeParam"
 	default:
 		return "Unknown"
 	}
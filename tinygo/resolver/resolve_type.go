@@ -0,0 +1,262 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
+)
+
+// resolveType은 타입 표현 안의 이름 있는 타입들을 TypeDecl에 연결함
+func (r *Resolver) resolveType(t parser.Type) error {
+	switch t.TypeKind {
+	case parser.NamedType:
+		id := *t.NameOrNil
+		ref, err := r.resolveID(id)
+		if err != nil {
+			return newResolveErr(id, "undefined type")
+		}
+		if ref.Kind != RefType && ref.Kind != RefTypeParam {
+			return newResolveErr(id, "not a type")
+		}
+		r.setResolved(id, ref)
+		// 타입 원소가 있는 interface는 값의 타입이 될 수 없음
+		if decl := r.typeDeclOf(ref); decl != nil && isConstraintInterface(decl.Type) {
+			return newResolveErr(id, fmt.Sprintf("cannot use type %s outside a type constraint: interface contains type constraints", id.Name))
+		}
+		return nil
+	case parser.StructureType:
+		seen := map[string]bool{}
+		for _, field := range t.StructOrNil.Fields {
+			if seen[field.Id.Name] {
+				return newResolveErr(field.Id, "duplicate field")
+			}
+			seen[field.Id.Name] = true
+			if err := r.resolveType(field.Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	case parser.FuncionType:
+		if t.FuncTypeOrNil == nil {
+			return nil
+		}
+		return r.resolveFuncType(*t.FuncTypeOrNil)
+	case parser.InterfaceTypeKind:
+		if t.InterfaceOrNil.IsConstraintOnly() {
+			return errors.New("cannot use interface with type constraints outside a type constraint")
+		}
+		return r.resolveInterfaceType(t.InterfaceOrNil)
+	case parser.SliceType:
+		return r.resolveType(*t.ElemOrNil)
+	case parser.SumTypeKind:
+		for _, variant := range t.SumOrNil.Variants {
+			seen := map[string]bool{}
+			for _, field := range variant.Fields {
+				if seen[field.Id.Name] {
+					return newResolveErr(field.Id, "duplicate field")
+				}
+				seen[field.Id.Name] = true
+				if err := r.resolveType(field.Type); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case parser.TupleType:
+		for _, elem := range t.TupleOrNil {
+			if err := r.resolveType(elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (r *Resolver) resolveInterfaceType(iface *parser.InterfaceType) error {
+	seen := map[string]bool{}
+	for _, method := range iface.Methods {
+		if seen[method.Id.Name] {
+			return newResolveErr(method.Id, "duplicate method")
+		}
+		seen[method.Id.Name] = true
+		if err := r.resolveFuncType(method.FuncType); err != nil {
+			return err
+		}
+	}
+	for _, elem := range iface.UnionOrNil {
+		if err := r.resolveType(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveConstraint는 타입 매개변수의 제약을 리졸빙함
+// 제약 자리에서는 타입 원소가 있는 interface도 쓸 수 있음
+func (r *Resolver) resolveConstraint(t parser.Type) error {
+	switch t.TypeKind {
+	case parser.NamedType:
+		id := *t.NameOrNil
+		ref, err := r.resolveID(id)
+		if err != nil {
+			return newResolveErr(id, "undefined type")
+		}
+		if ref.Kind != RefType && ref.Kind != RefTypeParam {
+			return newResolveErr(id, "not a type")
+		}
+		r.setResolved(id, ref)
+		return nil
+	case parser.InterfaceTypeKind:
+		return r.resolveInterfaceType(t.InterfaceOrNil)
+	default:
+		return r.resolveType(t)
+	}
+}
+
+// typeDeclOf는 ref가 패키지 레벨 타입 선언을 가리키면 그 선언을 리턴함
+func (r *Resolver) typeDeclOf(ref ResolvedRef) *parser.TypeDecl {
+	if ref.Kind != RefType || r.hoist == nil {
+		return nil
+	}
+	return r.hoist.getTypeDeclById(ref.RefIdNodeId)
+}
+
+func isConstraintInterface(t parser.Type) bool {
+	return t.TypeKind == parser.InterfaceTypeKind && t.InterfaceOrNil.IsConstraintOnly()
+}
+
+func (r *Resolver) resolveFuncType(ft parser.FuncType) error {
+	for _, arg := range ft.ArgTypesOrNil {
+		if err := r.resolveType(arg); err != nil {
+			return err
+		}
+	}
+	for _, ret := range ft.ReturnTypesOrNil {
+		if err := r.resolveType(ret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) resolveSignatureTypes(params []parser.Param, returnTypes []parser.Type) error {
+	for _, param := range params {
+		if err := r.resolveType(param.Type); err != nil {
+			return err
+		}
+	}
+	for _, ret := range returnTypes {
+		if err := r.resolveType(ret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) resolveTypeDecl(node *parser.TypeDecl, hoist *HoistInfo) error {
+	// 패키지 레벨의 TypeDecl은 이미 호이스팅되었으므로 본문만 리졸빙함
+	if hoist.getTypeDeclById(node.Id.IdId) == nil {
+		return newResolveErr(node.Id, "hoisted symbol not found")
+	}
+	// 타입 원소가 있는 interface도 이름을 붙여 제약으로 쓸 수 있음
+	if node.Type.TypeKind == parser.InterfaceTypeKind {
+		return r.resolveInterfaceType(node.Type.InterfaceOrNil)
+	}
+	return r.resolveType(node.Type)
+}
+
+func (r *Resolver) resolveStructLit(lit *parser.StructLit) error {
+	ref, err := r.resolveID(lit.TypeName)
+	if err != nil {
+		return err
+	}
+	if ref.Kind != RefType {
+		return newResolveErr(lit.TypeName, "not a type")
+	}
+	r.setResolved(lit.TypeName, ref)
+	seen := map[string]bool{}
+	for _, field := range lit.Fields {
+		if seen[field.Field.Name] {
+			return newResolveErr(field.Field, "duplicate field in struct literal")
+		}
+		seen[field.Field.Name] = true
+		if err := r.resolveExpr(field.Expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRecursiveTypes는 struct가 (함수 타입을 거치지 않고) 자기 자신을 필드로 품는 경우를 차단함
+// 이런 타입은 제로값조차 만들 수 없기 때문임
+func (r *Resolver) checkRecursiveTypes(hoist *HoistInfo) error {
+	// state: 0 = 미방문, 1 = 방문 중, 2 = 방문 완료
+	state := map[parser.IdId]int{}
+	var visit func(typeId parser.IdId) error
+	visit = func(typeId parser.IdId) error {
+		if state[typeId] == 1 {
+			decl := hoist.getTypeDeclById(typeId)
+			return newResolveErr(decl.Id, fmt.Sprintf("invalid recursive type %s", decl.Id.Name))
+		}
+		if state[typeId] == 2 {
+			return nil
+		}
+		state[typeId] = 1
+		decl := hoist.getTypeDeclById(typeId)
+		for _, dep := range r.embeddedTypeIds(decl.Type) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[typeId] = 2
+		return nil
+	}
+	for _, typeId := range hoist.typeOrder {
+		if err := visit(typeId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embeddedTypeIds는 t의 값 안에 직접 들어가는 이름 있는 타입의 선언 id들을 리턴함
+func (r *Resolver) embeddedTypeIds(t parser.Type) []parser.IdId {
+	switch t.TypeKind {
+	case parser.NamedType:
+		ref, ok := r.table[t.NameOrNil.IdId]
+		if !ok || ref.Kind != RefType {
+			return nil
+		}
+		return []parser.IdId{ref.RefIdNodeId}
+	case parser.StructureType:
+		ids := []parser.IdId{}
+		for _, field := range t.StructOrNil.Fields {
+			ids = append(ids, r.embeddedTypeIds(field.Type)...)
+		}
+		return ids
+	case parser.TupleType:
+		ids := []parser.IdId{}
+		for _, elem := range t.TupleOrNil {
+			ids = append(ids, r.embeddedTypeIds(elem)...)
+		}
+		return ids
+	case parser.SumTypeKind:
+		// 제로값은 필드 없는 변형이 있으면 그것이고, 없으면 첫 변형의 필드를 채워 만듦
+		// 따라서 첫 변형의 필드만 값 안에 직접 들어가는 것으로 봄
+		ids := []parser.IdId{}
+		for _, variant := range t.SumOrNil.Variants {
+			if len(variant.Fields) == 0 {
+				return nil
+			}
+		}
+		for _, field := range t.SumOrNil.Variants[0].Fields {
+			ids = append(ids, r.embeddedTypeIds(field.Type)...)
+		}
+		return ids
+	default:
+		return nil
+	}
+}
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/rlaaudgjs5638/langTest/tinygo/parser"
@@ -11,6 +12,26 @@ type Resolver struct {
 	global       *Scope
 	currentScope *Scope
 	builtins     map[string]int
+	// 이미 리졸빙된 패키지의 임포트 경로 -> 패키지 스코프
+	packageScopes map[string]*Scope
+	// 리졸빙 중인 함수들의 리턴 타입 스택. 가장 안쪽 함수가 마지막임
+	funcReturns [][]parser.Type
+	// funcReturns와 같은 순서의, 각 함수의 제너레이터 여부 표시 자리
+	funcGenerators []*bool
+	// funcReturns와 같은 순서의, 각 함수 선언의 이름. 함수 리터럴은 nil임
+	funcIds []*parser.Id
+	// 합 타입 변형의 선언 id -> 그 변형이 속한 타입 선언과 순번
+	variants map[parser.IdId]variantInfo
+	// 상수의 선언 id -> 상수식과 접힌 값
+	consts map[parser.IdId]*constEntry
+	// 리졸빙 중인 프로그램의 호이스팅 정보. 제네릭 검사에서 타입 선언, 메서드를 찾는 데 쓰임
+	hoist *HoistInfo
+	// 제네릭 함수 선언 id -> 선언. 호출마다 타입 인자를 추론, 검사하는 데 쓰임
+	genericFuncs map[parser.IdId]*parser.FuncDecl
+	// 타입 매개변수 선언 id -> 제약
+	typeParamConstraints map[parser.IdId]parser.Type
+	// 변수, 매개변수, 함수의 선언 id -> 정적으로 알 수 있는 타입. 제네릭 호출의 타입 인자 추론에 쓰임
+	declTypes map[parser.IdId]parser.Type
 }
 
 type Scope struct {
@@ -18,6 +39,12 @@ type Scope struct {
 	depth    int
 	symbols  map[string]*Symbol
 	nextSlot int
+	// 패키지 스코프일 때의 package 절 이름
+	packageName string
+	// 이 스코프가 선언된 함수의 중첩 깊이. 전역이라면 0
+	funcDepth int
+	// for 헤더의 스코프라면, 루프 변수가 클로저에 잡혔을 때 true로 채워질 루프 노드의 필드
+	loopCapturedOrNil *bool
 }
 
 func newScope(parent *Scope) *Scope {
@@ -38,6 +65,10 @@ type Symbol struct {
 	kind     SymbolKind
 	slot     int
 	scope    *Scope
+	// SymbolPackage일 때의 임포트 경로
+	importPath string
+	// let으로 선언되어 다시 대입할 수 없는지 여부
+	immutable bool
 }
 
 type SymbolKind uint8
@@ -47,12 +78,28 @@ const (
 	SymbolFunc
 	SymbolParam
 	SymbolBuiltin
+	SymbolType
+	// import로 들어온 패키지 이름
+	SymbolPackage
+	// 합 타입의 변형 생성자
+	SymbolVariant
+	// const로 선언된 전역 상수
+	SymbolConst
+	// 제네릭 함수의 타입 매개변수
+	SymbolTypeParam
 )
 
 func NewResolver() *Resolver {
 	r := &Resolver{
-		table:    ResolveTable{},
-		builtins: map[string]int{},
+		table:         ResolveTable{},
+		builtins:      map[string]int{},
+		packageScopes: map[string]*Scope{},
+		variants:      map[parser.IdId]variantInfo{},
+		consts:        map[parser.IdId]*constEntry{},
+
+		genericFuncs:         map[parser.IdId]*parser.FuncDecl{},
+		typeParamConstraints: map[parser.IdId]parser.Type{},
+		declTypes:            map[parser.IdId]parser.Type{},
 	}
 	r.global = newScope(nil)
 	r.currentScope = r.global
@@ -67,6 +114,110 @@ var Builtins = []string{
 	"scan",
 	"print",
 	"panic",
+	"exit",
+	"args",
+	"getenv",
+	"wrapError",
+	"unwrap",
+	"errorIs",
+	"errorWith",
+	"errorAs",
+	"compose",
+}
+
+// BuiltinModule은 strings.ToUpper처럼 모듈 이름으로 묶여 제공되는 빌트인 모음임
+// 모듈 이름 자체가 하나의 빌트인 심볼이 되므로, 멤버 이름이 전역 이름을 차지하지 않음
+type BuiltinModule struct {
+	Name    string
+	Members []BuiltinMember
+}
+
+// BuiltinMember는 빌트인 모듈의 멤버 함수 하나의 시그니처 정보임
+type BuiltinMember struct {
+	Name string
+	// 리턴 타입들. lint의 errcheck처럼 결과를 알아야 하는 정적 분석에 쓰임
+	// 인자에 따라 리턴 타입이 달라지는 멤버는 nil임
+	Returns []parser.Type
+}
+
+var (
+	intResult    = parser.Type{TypeKind: parser.IntType}
+	boolResult   = parser.Type{TypeKind: parser.BoolType}
+	stringResult = parser.Type{TypeKind: parser.StringType}
+	errorResult  = parser.Type{TypeKind: parser.ErrorType}
+)
+
+var BuiltinModules = []BuiltinModule{
+	{Name: "strconv", Members: []BuiltinMember{
+		{Name: "Itoa", Returns: []parser.Type{stringResult}},
+		{Name: "Atoi", Returns: []parser.Type{intResult, errorResult}},
+	}},
+	{Name: "strings", Members: []BuiltinMember{
+		{Name: "Substring", Returns: []parser.Type{stringResult}},
+		{Name: "Index", Returns: []parser.Type{intResult}},
+		{Name: "Split", Returns: []parser.Type{{TypeKind: parser.SliceType, ElemOrNil: &stringResult}}},
+		{Name: "Join", Returns: []parser.Type{stringResult}},
+		{Name: "Trim", Returns: []parser.Type{stringResult}},
+		{Name: "TrimSpace", Returns: []parser.Type{stringResult}},
+		{Name: "ToUpper", Returns: []parser.Type{stringResult}},
+		{Name: "ToLower", Returns: []parser.Type{stringResult}},
+		{Name: "Repeat", Returns: []parser.Type{stringResult}},
+		{Name: "Contains", Returns: []parser.Type{boolResult}},
+	}},
+	{Name: "fmt", Members: []BuiltinMember{
+		{Name: "Sprintf", Returns: []parser.Type{stringResult}},
+	}},
+	// 슬라이스의 고차 함수들
+	{Name: "seq", Members: []BuiltinMember{
+		{Name: "Map"},
+		{Name: "Filter"},
+		{Name: "Reduce"},
+		{Name: "FlatMap"},
+		{Name: "Zip"},
+		{Name: "SortBy"},
+		{Name: "GroupBy"},
+		{Name: "Any", Returns: []parser.Type{boolResult}},
+		{Name: "All", Returns: []parser.Type{boolResult}},
+	}},
+	// 영속 컬렉션
+	{Name: "coll", Members: []BuiltinMember{
+		{Name: "Vector"},
+		{Name: "HashMap"},
+		{Name: "Conj"},
+		{Name: "Assoc"},
+		{Name: "Dissoc"},
+		{Name: "Get"},
+	}},
+}
+
+// builtinModuleByName은 name이 빌트인 모듈이면 이를 리턴함
+func builtinModuleByName(name string) (BuiltinModule, bool) {
+	for _, module := range BuiltinModules {
+		if module.Name == name {
+			return module, true
+		}
+	}
+	return BuiltinModule{}, false
+}
+
+func (m BuiltinModule) hasMember(name string) bool {
+	_, ok := m.Member(name)
+	return ok
+}
+
+// Member는 이름이 name인 멤버를 리턴함
+func (m BuiltinModule) Member(name string) (BuiltinMember, bool) {
+	for _, member := range m.Members {
+		if member.Name == name {
+			return member, true
+		}
+	}
+	return BuiltinMember{}, false
+}
+
+// LookupBuiltinModule은 name이 빌트인 모듈의 이름이라면 그 모듈을 리턴함
+func LookupBuiltinModule(name string) (BuiltinModule, bool) {
+	return builtinModuleByName(name)
 }
 
 func (r *Resolver) preludeBuiltins() {
@@ -85,10 +236,29 @@ func (r *Resolver) preludeBuiltins() {
 			scope: r.global,
 		}
 	}
+	// 모듈은 빌트인 함수들 뒤의 슬롯을 차지함
+	for i, module := range BuiltinModules {
+		r.builtins[module.Name] = len(Builtins) + i
+		r.global.symbols[module.Name] = &Symbol{
+			name:     module.Name,
+			kind:     SymbolBuiltin,
+			idNodeId: parser.IdId(-1),
+			slot:     -1,
+			scope:    r.global,
+		}
+	}
 }
 
 func (r *Resolver) pushScope() {
 	r.currentScope = newScope(r.currentScope)
+	r.currentScope.funcDepth = len(r.funcReturns)
+}
+
+// pushLoopScope는 for 헤더의 변수들을 위한 스코프를 push함
+// captured는 루프 변수를 안쪽 함수가 참조하면 true로 채워질 루프 노드의 필드임
+func (r *Resolver) pushLoopScope(captured *bool) {
+	r.pushScope()
+	r.currentScope.loopCapturedOrNil = captured
 }
 
 func (r *Resolver) popScope() {
@@ -97,6 +267,39 @@ func (r *Resolver) popScope() {
 	}
 }
 
+// pushFuncReturns는 함수 본문에 들어갈 때 그 함수의 이름과 리턴 타입을 기록함
+// idOrNil은 함수 본문에 대한 에러가 붙을 함수 선언의 이름이며, 함수 리터럴은 nil임
+// isGenerator는 본문에서 yield를 만나면 true로 채워질 함수 노드의 필드임
+func (r *Resolver) pushFuncReturns(idOrNil *parser.Id, returnTypes []parser.Type, isGenerator *bool) {
+	r.funcReturns = append(r.funcReturns, returnTypes)
+	r.funcGenerators = append(r.funcGenerators, isGenerator)
+	r.funcIds = append(r.funcIds, idOrNil)
+}
+
+func (r *Resolver) popFuncReturns() {
+	r.funcReturns = r.funcReturns[:len(r.funcReturns)-1]
+	r.funcGenerators = r.funcGenerators[:len(r.funcGenerators)-1]
+	r.funcIds = r.funcIds[:len(r.funcIds)-1]
+}
+
+// funcErr은 지금 리졸빙 중인 함수 본문에 대한 에러를 그 함수 선언의 이름에 붙임
+// 함수 리터럴 안이거나 함수 밖이라면 fallback의 식별자에 붙이고,
+// fallback에 식별자가 없다면 바깥으로 나가며 가장 가까운 함수 선언의 이름에 붙임
+func (r *Resolver) funcErr(fallback parser.Expr, msg string) error {
+	if len(r.funcIds) > 0 && r.funcIds[len(r.funcIds)-1] != nil {
+		return newResolveErr(*r.funcIds[len(r.funcIds)-1], msg)
+	}
+	if id, ok := exprAnchorId(fallback); ok {
+		return newResolveErr(id, msg)
+	}
+	for i := len(r.funcIds) - 1; i >= 0; i-- {
+		if r.funcIds[i] != nil {
+			return newResolveErr(*r.funcIds[i], msg)
+		}
+	}
+	return errors.New(msg)
+}
+
 func (r *Resolver) isBuiltinName(name string) bool {
 	_, ok := r.builtins[name]
 	return ok
@@ -113,6 +316,12 @@ func (r *Resolver) declare(name string, kind SymbolKind, idnodeId parser.IdId) (
 	if _, exists := r.currentScope.symbols[name]; exists {
 		return nil, fmt.Errorf("duplicate declaration: %s", name)
 	}
+	// 타입, 패키지 이름은 런타임 값이 아니므로 슬롯을 차지하지 않음
+	if kind == SymbolType || kind == SymbolPackage || kind == SymbolTypeParam {
+		sym := &Symbol{name: name, kind: kind, idNodeId: idnodeId, slot: -1, scope: r.currentScope}
+		r.currentScope.symbols[name] = sym
+		return sym, nil
+	}
 	slot := r.currentScope.nextSlot
 	r.currentScope.nextSlot++
 	sym := &Symbol{name: name, kind: kind, idNodeId: idnodeId, slot: slot, scope: r.currentScope}
@@ -123,8 +332,19 @@ func (r *Resolver) declare(name string, kind SymbolKind, idnodeId parser.IdId) (
 func (r *Resolver) resolveID(id parser.Id) (ResolvedRef, error) {
 	sym := r.lookup(id.Name)
 	if sym == nil {
+		if id.Name == "iota" {
+			return ResolvedRef{}, &ResolveError{IdNode: id, Msg: "cannot use iota outside constant declaration"}
+		}
+		// init 함수는 전역 스코프에 이름이 없으므로, 참조하려 하면 여기에 닿음
+		if id.Name == "init" {
+			return ResolvedRef{}, &ResolveError{IdNode: id, Msg: "cannot refer to init function"}
+		}
 		return ResolvedRef{}, &ResolveError{IdNode: id, Msg: "undefined identifier"}
 	}
+	// 안쪽 함수가 루프 변수를 참조한다면, 루프는 반복마다 새 변수를 만들어야 함
+	if sym.scope.loopCapturedOrNil != nil && len(r.funcReturns) > sym.scope.funcDepth {
+		*sym.scope.loopCapturedOrNil = true
+	}
 	distance := r.currentScope.depth - sym.scope.depth
 	ref := ResolvedRef{
 		Kind:        RefLocal,
@@ -143,7 +363,16 @@ func (r *Resolver) resolveID(id parser.Id) (ResolvedRef, error) {
 		// 실행 시에는 symbolBuiltIn만나면
 		// 환경이 아닌 "빌트인 환경"의 "빌트인 기준 슬롯"에 접근하도록 함
 		ref.Slot = r.builtins[sym.name]
-	case SymbolFunc, SymbolVar:
+	case SymbolType:
+		ref.Kind = RefType
+		ref.Distance = 0
+	case SymbolTypeParam:
+		ref.Kind = RefTypeParam
+		ref.Distance = 0
+	case SymbolPackage:
+		ref.Kind = RefPackage
+		ref.Distance = 0
+	case SymbolFunc, SymbolVar, SymbolVariant, SymbolConst:
 		if sym.scope == r.global {
 			ref.Kind = RefGlobal
 			ref.Distance = 0
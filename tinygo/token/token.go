@@ -30,6 +30,8 @@ const (
 
 	// 선언 키워드
 	VAR
+	CONST
+	LET
 
 	//return 키워드
 	RETURN
@@ -42,6 +44,28 @@ const (
 	FOR
 	BREAK
 	CONTINUE
+	RANGE
+
+	// 타입 선언 키워드
+	TYPE
+	STRUCT
+	INTERFACE
+
+	// switch 키워드
+	SWITCH
+	CASE
+	DEFAULT
+	FALLTHROUGH
+
+	// 패턴 매칭 키워드
+	MATCH
+
+	// 제너레이터 키워드
+	YIELD
+
+	// 패키지 키워드
+	PACKAGE
+	IMPORT
 
 	END_OF_KEYWORD
 )
@@ -62,6 +86,10 @@ const (
 	RPAREN
 	SEMICOLON
 	COMMA
+	DOT
+	COLON
+	// 와일드카드 패턴
+	UNDERSCORE
 	END_OF_DELIMETER
 )
 const (
@@ -87,6 +115,14 @@ const (
 	MINUS
 	MUL
 	DIV
+	// 에러 전파
+	QUESTION
+	// 합 타입의 변형 구분
+	BAR
+	// 매치 갈래의 패턴과 본문 구분
+	ARROW
+	// 파이프
+	PIPE
 	END_OF_OPERATOR
 )
 const (
@@ -134,6 +170,10 @@ func StringSpec(t TokenKind) string {
 
 	case VAR:
 		return "var"
+	case CONST:
+		return "const"
+	case LET:
+		return "let"
 
 	case RETURN:
 		return "return"
@@ -148,6 +188,35 @@ func StringSpec(t TokenKind) string {
 		return "break"
 	case CONTINUE:
 		return "continue"
+	case RANGE:
+		return "range"
+
+	case TYPE:
+		return "type"
+	case STRUCT:
+		return "struct"
+	case INTERFACE:
+		return "interface"
+
+	case SWITCH:
+		return "switch"
+	case CASE:
+		return "case"
+	case DEFAULT:
+		return "default"
+	case FALLTHROUGH:
+		return "fallthrough"
+
+	case MATCH:
+		return "match"
+
+	case YIELD:
+		return "yield"
+
+	case PACKAGE:
+		return "package"
+	case IMPORT:
+		return "import"
 
 	case ID:
 		return ""
@@ -168,6 +237,12 @@ func StringSpec(t TokenKind) string {
 		return ";"
 	case COMMA:
 		return ","
+	case DOT:
+		return "."
+	case COLON:
+		return ":"
+	case UNDERSCORE:
+		return "_"
 
 	case ASSIGN:
 		return "="
@@ -201,6 +276,14 @@ func StringSpec(t TokenKind) string {
 		return "*"
 	case DIV:
 		return "/"
+	case QUESTION:
+		return "?"
+	case BAR:
+		return "|"
+	case ARROW:
+		return "=>"
+	case PIPE:
+		return "|>"
 
 	case EOF:
 		//EOF는 "EOF"를 EOF로 토크나이징 하지는 않음.